@@ -1,12 +1,20 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"time"
 
 	// "github.com/gin-gonic/gin" // Gin engine will be created by SetupRouter
 	"github.com/phone_management/configs"
+	"github.com/phone_management/internal/auth"
+	"github.com/phone_management/internal/models"
+	"github.com/phone_management/internal/repositories"
 	"github.com/phone_management/internal/routes"
+	"github.com/phone_management/internal/services"
 	"github.com/phone_management/pkg/db"
+	"github.com/phone_management/pkg/scheduler"
 )
 
 func main() {
@@ -14,15 +22,156 @@ func main() {
 	// 这应该在任何依赖配置的代码之前执行
 	configs.LoadConfig()
 
+	// 加载 JWT 签名密钥环，密钥配置有误时拒绝启动
+	if err := auth.InitKeyring(configs.AppConfig); err != nil {
+		log.Fatalf("加载JWT签名密钥失败: %v", err)
+	}
+
 	// 2. 初始化数据库连接
 	db.InitDB()        // 从 pkg/db 调用 InitDB
 	defer db.CloseDB() // 确保在 main 函数退出时关闭数据库连接
 
-	// 3. 初始化 Gin 引擎并设置API路由
+	// 将仅填写了部门名称的员工关联到部门，不存在的部门会自动创建
+	departmentRepo := repositories.NewGormDepartmentRepository(db.GetDB())
+	if linked, err := departmentRepo.NormalizeEmployeeDepartments(context.Background()); err != nil {
+		log.Printf("关联员工部门失败: %v", err)
+	} else if linked > 0 {
+		log.Printf("已将 %d 名员工关联到部门", linked)
+	}
+
+	// 3. 使用数据库持久化已登出的 Token，并定期清理过期的 Token 记录
+	tokenDenylist := repositories.NewGormRevokedTokenRepository(db.GetDB())
+	refreshTokenRepo := repositories.NewGormRefreshTokenRepository(db.GetDB())
+	auth.SetTokenDenylist(tokenDenylist)
+	// 部门负责人的数据范围按其所负责的部门确定
+	auth.SetDepartmentScopeResolver(repositories.NewGormUserRepository(db.GetDB()))
+	stopPurge := scheduler.Every("purge-expired-tokens", configs.AppConfig.TokenDenylistPurgeInterval, func() error {
+		purged, err := tokenDenylist.PurgeExpired()
+		if err != nil {
+			return err
+		}
+		purgedRefresh, err := refreshTokenRepo.PurgeExpired()
+		if err != nil {
+			return err
+		}
+		if purged+purgedRefresh > 0 {
+			log.Printf("已清理 %d 条过期的已登出Token记录，%d 条过期的刷新令牌", purged, purgedRefresh)
+		}
+		return nil
+	})
+	defer stopPurge()
+
+	// 定期注销计划注销日期已到的待注销号码，启动时先执行一次，避免频繁重启导致长时间不执行
+	mobileNumberRepo := repositories.NewGormMobileNumberRepository(db.GetDB())
+	// 早期版本变更为待注销的号码没有计划注销日期，按最后更新时间加宽限天数补充，避免其永远不被注销
+	if backfilled, err := mobileNumberRepo.BackfillDeactivationDates(context.Background(), configs.AppConfig.NumberDeactivationGraceDays); err != nil {
+		log.Printf("补充待注销号码的计划注销日期失败: %v", err)
+	} else if backfilled > 0 {
+		log.Printf("已为 %d 个待注销号码补充计划注销日期", backfilled)
+	}
+	deactivateDueNumbers := func() error {
+		deactivated, err := mobileNumberRepo.DeactivateDueNumbers(context.Background(), time.Now().UTC())
+		if deactivated > 0 {
+			log.Printf("已自动注销 %d 个到期的待注销号码", deactivated)
+		}
+		return err
+	}
+	if err := deactivateDueNumbers(); err != nil {
+		log.Printf("注销到期的待注销号码失败: %v", err)
+	}
+	stopDeactivation := scheduler.Every("deactivate-due-numbers", configs.AppConfig.NumberDeactivationCheckInterval, deactivateDueNumbers)
+	defer stopDeactivation()
+
+	// 定期办理离职日期已到的预约离职，并提前将提醒 IT 处理即将离职员工号码的邮件加入发送队列
+	employeeRepo := repositories.NewGormEmployeeRepository(db.GetDB())
+	outboundMessageRepo := repositories.NewGormOutboundMessageRepository(db.GetDB())
+	employeeService := services.NewEmployeeService(
+		employeeRepo,
+		mobileNumberRepo,
+		repositories.NewGormScheduledDepartureRepository(db.GetDB()),
+		departmentRepo,
+		outboundMessageRepo,
+	)
+	processDepartures := func() error {
+		ctx := context.Background()
+		now := time.Now().UTC()
+		processed, processErr := employeeService.ProcessScheduledDepartures(ctx, now)
+		if processed > 0 {
+			log.Printf("已办理 %d 个到期的预约离职", processed)
+		}
+		reminded, remindErr := employeeService.SendDepartureReminders(ctx, now)
+		if reminded > 0 {
+			log.Printf("已将 %d 个预约离职的提醒邮件加入发送队列", reminded)
+		}
+		return errors.Join(processErr, remindErr)
+	}
+	if err := processDepartures(); err != nil {
+		log.Printf("处理预约离职失败: %v", err)
+	}
+	stopDepartures := scheduler.Every("process-scheduled-departures", configs.AppConfig.DepartureCheckInterval, processDepartures)
+	defer stopDepartures()
+
+	// 通知发送队列：由工作协程发送到期的通知，失败时按指数退避重试
+	notifier := services.NewNotifier(configs.AppConfig)
+	stopOutbound := services.NewOutboundMessageService(outboundMessageRepo, notifier).Start()
+	defer stopOutbound()
+
+	// 继续生成上次退出时尚未完成的号码确认批次的通知
+	verificationService := services.NewVerificationService(
+		employeeRepo,
+		repositories.NewGormVerificationTokenRepository(db.GetDB()),
+		repositories.NewGormVerificationBatchTaskRepository(db.GetDB()),
+		mobileNumberRepo,
+		repositories.NewGormUserReportedIssueRepository(db.GetDB()),
+		repositories.NewGormVerificationSubmissionLogRepository(db.GetDB()),
+		departmentRepo,
+		outboundMessageRepo,
+		services.NewEmailTemplateService(repositories.NewGormEmailTemplateRepository(db.GetDB())),
+		db.GetDB(),
+	)
+	if resumed, err := verificationService.ResumePendingBatches(context.Background()); err != nil {
+		log.Printf("恢复未完成的号码确认批次失败: %v", err)
+	} else if resumed > 0 {
+		log.Printf("正在继续生成 %d 个未完成的号码确认批次的通知", resumed)
+	}
+
+	// 定期按确认批次的催办计划催办尚未提交确认结果的员工，启动时先执行一次
+	sendVerificationReminders := func() error {
+		reminded, err := verificationService.SendScheduledReminders(context.Background(), time.Now().UTC())
+		if reminded > 0 {
+			log.Printf("已将 %d 条号码确认催办通知加入发送队列", reminded)
+		}
+		return err
+	}
+	if err := sendVerificationReminders(); err != nil {
+		log.Printf("发送号码确认催办通知失败: %v", err)
+	}
+	stopVerificationReminders := scheduler.Every("send-verification-reminders", configs.AppConfig.VerificationReminderCheckInterval, sendVerificationReminders)
+	defer stopVerificationReminders()
+
+	// 配置了员工目录服务器时定期从目录同步员工，启动时不立即执行，避免频繁重启时反复同步
+	if directorySource := services.NewLDAPDirectorySource(configs.AppConfig); directorySource != nil {
+		directorySyncService := services.NewDirectorySyncService(
+			directorySource, employeeService, employeeRepo,
+			services.NewDepartmentService(departmentRepo, employeeRepo), departmentRepo,
+			repositories.NewGormDirectorySyncRunRepository(db.GetDB()),
+		)
+		stopDirectorySync := scheduler.Every("sync-employee-directory", configs.AppConfig.DirectorySyncInterval, func() error {
+			run, err := directorySyncService.Sync(context.Background(), models.DirectorySyncScheduled, false)
+			if run != nil {
+				log.Printf("员工目录同步 #%d 完成 (%s)：新建 %d，更新 %d，离职 %d，跳过 %d，失败 %d",
+					run.ID, run.Status, run.CreatedCount, run.UpdatedCount, run.DepartedCount, run.SkippedCount, run.FailedCount)
+			}
+			return err
+		})
+		defer stopDirectorySync()
+	}
+
+	// 4. 初始化 Gin 引擎并设置API路由
 	// 使用 SetupRouter 来获取配置好的 Gin 引擎
 	appRouter := routes.SetupRouter(db.GetDB()) // 调用路由设置函数
 
-	// 4. 从配置中获取端口号并启动服务器
+	// 5. 从配置中获取端口号并启动服务器
 	port := configs.AppConfig.ServerPort // 使用配置中的端口
 	log.Printf("服务器正在监听端口 %s...", port)
 	if err := appRouter.Run(":" + port); err != nil { // 使用从 SetupRouter 返回的引擎
@@ -3,7 +3,10 @@ package configs
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 )
 
 // AppConfig holds the application configuration.
@@ -13,28 +16,228 @@ var once sync.Once
 
 // Configuration defines the structure for application settings.
 type Configuration struct {
+	AppEnv          string // 运行环境，production 模式下会拒绝使用默认 JWT 密钥启动
 	JWTSecret       string
 	ServerPort      string
 	FrontendBaseURL string
+
+	// JWT 签名密钥环：使用当前密钥签名，同时接受当前密钥和上一个密钥签名的令牌
+	JWTSigningAlgorithm      string // 当前密钥的签名算法 (HS256, RS256, EdDSA)
+	JWTKeyID                 string // 当前密钥的 kid
+	JWTPrivateKeyFile        string // RS256/EdDSA 时当前私钥的 PEM 文件路径
+	JWTPreviousKeyID         string // 上一个密钥的 kid，为空表示没有上一个密钥
+	JWTPreviousSecret        string // 上一个密钥为 HMAC 时的密钥
+	JWTPreviousPublicKeyFile string // 上一个密钥为 RS256/EdDSA 时的公钥 PEM 文件路径
+
+	TokenDenylistPurgeInterval time.Duration // 清理过期的已登出 Token 记录的间隔
+	AccessTokenTTL             time.Duration // 访问令牌有效期
+	RefreshTokenTTL            time.Duration // 刷新令牌有效期
+
+	LoginMaxFailures     int           // 同一用户名连续登录失败达到该次数后锁定账号
+	LoginLockoutDuration time.Duration // 账号锁定时长，同时也是按IP统计失败次数的时间窗口
+	LoginIPMaxFailures   int           // 同一IP在时间窗口内允许的失败次数，超过后开始指数退避
+	LoginBackoffBase     time.Duration // 指数退避的基础等待时间
+	LoginBackoffMax      time.Duration // 指数退避的最长等待时间
+
+	NumberDeactivationGraceDays     int           // 号码变更为待注销时默认的宽限天数，到期后自动注销
+	NumberDeactivationCheckInterval time.Duration // 检查到期待注销号码的间隔
+
+	DepartureCheckInterval time.Duration // 检查到期预约离职及发送离职提醒的间隔
+	DepartureReminderDays  int           // 离职日期前多少天提醒 IT 处理号码
+	ITTeamEmails           []string      // 接收离职提醒的 IT 邮箱，为空时不发送提醒
+
+	// 员工目录同步：从 LDAP/AD 拉取用户并与员工档案对账，LDAPURL 为空时不启用定时同步
+	LDAPURL                    string        // 目录服务器地址，ldap:// 或 ldaps://
+	LDAPBindDN                 string        // 绑定使用的 DN，为空时匿名绑定
+	LDAPBindPassword           string        // 绑定密码
+	LDAPBaseDN                 string        // 搜索的起始 DN
+	LDAPUserFilter             string        // 用户过滤器 (RFC 4515)
+	LDAPMatchBy                string        // 目录用户与员工的匹配方式：email、employeeId 或 phoneNumber
+	LDAPAttrFullName           string        // 姓名属性，值为空时回退到 cn
+	LDAPAttrEmail              string        // 邮箱属性
+	LDAPAttrPhoneNumber        string        // 手机号码属性
+	LDAPAttrDepartment         string        // 部门属性
+	LDAPAttrEmployeeID         string        // 员工业务工号属性，LDAPMatchBy 为 employeeId 时使用
+	LDAPPageSize               int           // 分页搜索的页大小
+	LDAPTimeout                time.Duration // 连接及每个请求的超时
+	DirectorySyncInterval      time.Duration // 定时同步的间隔
+	DirectorySyncMaxDepartures int           // 单次同步最多办理的离职数，超过时不办理任何离职，避免目录配置错误导致批量离职
+
+	// HR 系统推送员工事件的 Webhook，请求须带有以 HRWebhookSecret 计算的 HMAC-SHA256 签名
+	HRWebhookSecret       string        // 签名密钥，为空时拒绝所有推送
+	HRWebhookMaxClockSkew time.Duration // 请求时间戳与服务器时间允许的最大偏差，超出视为重放
+
+	// 通知渠道：员工可选择通过邮件、企业 IM 或短信接收通知，邮件的 SMTP 配置由 pkg/email 读取
+	IMWebhookURL        string        // 企业 IM 群机器人 Webhook 地址，为空时不启用 IM 渠道。群消息全员可见，只发送不含链接的提醒
+	IMWebhookFlavor     string        // 机器人消息格式：wecom、dingtalk 或 feishu
+	IMWebhookSecret     string        // 机器人签名密钥（钉钉、飞书），为空时不签名
+	SMSGatewayURL       string        // 短信网关地址，为空时不启用短信渠道
+	SMSGatewayToken     string        // 短信网关的 Bearer Token
+	NotificationTimeout time.Duration // IM 和短信请求的超时
+
+	// 通知模板：数据库中保存的模板优先，其次为 EmailTemplateDir 中的模板文件，最后为内置模板
+	EmailTemplateDir    string // 模板文件目录，为空时只使用数据库中的模板和内置模板
+	VerificationContact string // 号码确认通知中列出的联系人
+
+	// 通知发送队列：待发送的通知保存在 outbound_messages 表中，由工作协程发送，失败时按指数退避重试
+	OutboundWorkers          int           // 并发发送的工作协程数
+	OutboundPollInterval     time.Duration // 查询到期消息的间隔
+	OutboundMaxAttempts      int           // 每条消息最多尝试发送的次数，达到后标记为失败
+	OutboundRetryBaseBackoff time.Duration // 第一次失败后的重试间隔，之后每次失败翻倍
+	OutboundRetryMaxBackoff  time.Duration // 重试间隔的上限
+	OutboundSendTimeout      time.Duration // 发送一条消息的超时，领取后超过该时间仍未完成的消息视为发送中断并重新排队
+
+	// 号码确认自动催办：发起确认时未指定催办计划的批次使用以下默认计划
+	VerificationRemindAfterDays        []int         // 发出确认通知后第几天催办，为空时不按此规则催办
+	VerificationRemindBeforeExpiryDays []int         // 链接过期前几天催办，为空时不按此规则催办
+	VerificationMaxReminders           int           // 每个令牌最多催办的次数，包括手动催办
+	VerificationReminderCheckInterval  time.Duration // 检查到期催办的间隔
 }
 
 const (
+	envAppEnvKey           = "APP_ENV"               // 运行环境环境变量名
+	appEnvProduction       = "production"            // 生产环境
 	defaultJWTSecret       = "mobile"                // Default JWT secret, used if env var is not set.
 	envJWTSecretKey        = "JWT_SECRET_KEY"        // Environment variable name for the JWT secret.
 	defaultServerPort      = "8081"                  // Default server port.
 	envServerPortKey       = "SERVER_PORT"           // Environment variable name for the server port.
 	defaultFrontendBaseURL = "http://localhost:3000" // 默认前端基础URL
 	envFrontendBaseURLKey  = "FRONTEND_BASE_URL"     // 前端基础URL环境变量名
+
+	defaultJWTSigningAlgorithm     = "HS256"                        // 默认签名算法
+	envJWTSigningAlgorithmKey      = "JWT_SIGNING_ALG"              // 签名算法环境变量名
+	defaultJWTKeyID                = "default"                      // 默认当前密钥的 kid
+	envJWTKeyIDKey                 = "JWT_KEY_ID"                   // 当前密钥 kid 环境变量名
+	envJWTPrivateKeyFileKey        = "JWT_PRIVATE_KEY_FILE"         // 当前私钥文件环境变量名
+	envJWTPreviousKeyIDKey         = "JWT_PREVIOUS_KEY_ID"          // 上一个密钥 kid 环境变量名
+	envJWTPreviousSecretKey        = "JWT_PREVIOUS_SECRET_KEY"      // 上一个 HMAC 密钥环境变量名
+	envJWTPreviousPublicKeyFileKey = "JWT_PREVIOUS_PUBLIC_KEY_FILE" // 上一个公钥文件环境变量名
+
+	defaultTokenDenylistPurgeInterval = time.Hour                       // 默认每小时清理一次过期的已登出Token
+	envTokenDenylistPurgeIntervalKey  = "TOKEN_DENYLIST_PURGE_INTERVAL" // 清理间隔环境变量名 (Go duration 格式，如 30m)
+	defaultAccessTokenTTL             = 15 * time.Minute                // 默认访问令牌有效期
+	envAccessTokenTTLKey              = "ACCESS_TOKEN_TTL"              // 访问令牌有效期环境变量名
+	defaultRefreshTokenTTL            = 7 * 24 * time.Hour              // 默认刷新令牌有效期
+	envRefreshTokenTTLKey             = "REFRESH_TOKEN_TTL"             // 刷新令牌有效期环境变量名
+
+	defaultLoginMaxFailures     = 5                        // 默认连续失败5次锁定账号
+	envLoginMaxFailuresKey      = "LOGIN_MAX_FAILURES"     // 账号锁定阈值环境变量名
+	defaultLoginLockoutDuration = 15 * time.Minute         // 默认锁定15分钟
+	envLoginLockoutDurationKey  = "LOGIN_LOCKOUT_DURATION" // 账号锁定时长环境变量名
+	defaultLoginIPMaxFailures   = 10                       // 默认同一IP失败10次后开始退避
+	envLoginIPMaxFailuresKey    = "LOGIN_IP_MAX_FAILURES"  // IP失败阈值环境变量名
+	defaultLoginBackoffBase     = time.Second              // 默认退避基础时间
+	envLoginBackoffBaseKey      = "LOGIN_BACKOFF_BASE"     // 退避基础时间环境变量名
+	defaultLoginBackoffMax      = 5 * time.Minute          // 默认最长退避时间
+	envLoginBackoffMaxKey       = "LOGIN_BACKOFF_MAX"      // 最长退避时间环境变量名
+
+	defaultNumberDeactivationGraceDays     = 30                                   // 默认待注销宽限期30天
+	envNumberDeactivationGraceDaysKey      = "NUMBER_DEACTIVATION_GRACE_DAYS"     // 待注销宽限天数环境变量名
+	defaultNumberDeactivationCheckInterval = 24 * time.Hour                       // 默认每天检查一次到期的待注销号码
+	envNumberDeactivationCheckIntervalKey  = "NUMBER_DEACTIVATION_CHECK_INTERVAL" // 到期注销检查间隔环境变量名
+
+	defaultDepartureCheckInterval = time.Hour                  // 默认每小时检查一次预约离职
+	envDepartureCheckIntervalKey  = "DEPARTURE_CHECK_INTERVAL" // 预约离职检查间隔环境变量名
+	defaultDepartureReminderDays  = 7                          // 默认离职前7天提醒
+	envDepartureReminderDaysKey   = "DEPARTURE_REMINDER_DAYS"  // 离职提醒提前天数环境变量名
+	envITTeamEmailsKey            = "IT_TEAM_EMAILS"           // IT 邮箱环境变量名，多个邮箱以逗号分隔
+
+	envLDAPURLKey                     = "LDAP_URL"                      // 目录服务器地址环境变量名
+	envLDAPBindDNKey                  = "LDAP_BIND_DN"                  // 绑定 DN 环境变量名
+	envLDAPBindPasswordKey            = "LDAP_BIND_PASSWORD"            // 绑定密码环境变量名
+	envLDAPBaseDNKey                  = "LDAP_BASE_DN"                  // 搜索起始 DN 环境变量名
+	defaultLDAPUserFilter             = "(objectClass=person)"          // 默认用户过滤器
+	envLDAPUserFilterKey              = "LDAP_USER_FILTER"              // 用户过滤器环境变量名
+	defaultLDAPMatchBy                = "email"                         // 默认按邮箱匹配员工
+	envLDAPMatchByKey                 = "LDAP_MATCH_BY"                 // 匹配方式环境变量名
+	defaultLDAPAttrFullName           = "displayName"                   // 默认姓名属性
+	envLDAPAttrFullNameKey            = "LDAP_ATTR_FULL_NAME"           // 姓名属性环境变量名
+	defaultLDAPAttrEmail              = "mail"                          // 默认邮箱属性
+	envLDAPAttrEmailKey               = "LDAP_ATTR_EMAIL"               // 邮箱属性环境变量名
+	defaultLDAPAttrPhoneNumber        = "mobile"                        // 默认手机号码属性
+	envLDAPAttrPhoneNumberKey         = "LDAP_ATTR_PHONE"               // 手机号码属性环境变量名
+	defaultLDAPAttrDepartment         = "department"                    // 默认部门属性
+	envLDAPAttrDepartmentKey          = "LDAP_ATTR_DEPARTMENT"          // 部门属性环境变量名
+	defaultLDAPAttrEmployeeID         = "employeeID"                    // 默认员工工号属性
+	envLDAPAttrEmployeeIDKey          = "LDAP_ATTR_EMPLOYEE_ID"         // 员工工号属性环境变量名
+	defaultLDAPPageSize               = 500                             // 默认每页 500 条
+	envLDAPPageSizeKey                = "LDAP_PAGE_SIZE"                // 分页大小环境变量名
+	defaultLDAPTimeout                = 30 * time.Second                // 默认超时30秒
+	envLDAPTimeoutKey                 = "LDAP_TIMEOUT"                  // 超时环境变量名
+	defaultDirectorySyncInterval      = 24 * time.Hour                  // 默认每天同步一次
+	envDirectorySyncIntervalKey       = "DIRECTORY_SYNC_INTERVAL"       // 同步间隔环境变量名
+	defaultDirectorySyncMaxDepartures = 20                              // 默认单次同步最多办理20个离职
+	envDirectorySyncMaxDeparturesKey  = "DIRECTORY_SYNC_MAX_DEPARTURES" // 单次同步离职上限环境变量名
+
+	envHRWebhookSecretKey        = "HR_WEBHOOK_SECRET"         // HR Webhook 签名密钥环境变量名
+	defaultHRWebhookMaxClockSkew = 5 * time.Minute             // 默认允许5分钟的时间偏差
+	envHRWebhookMaxClockSkewKey  = "HR_WEBHOOK_MAX_CLOCK_SKEW" // 允许的时间偏差环境变量名
+
+	envIMWebhookURLKey         = "IM_WEBHOOK_URL"       // IM 机器人 Webhook 地址环境变量名
+	defaultIMWebhookFlavor     = "wecom"                // 默认使用企业微信的消息格式
+	envIMWebhookFlavorKey      = "IM_WEBHOOK_FLAVOR"    // 机器人消息格式环境变量名
+	envIMWebhookSecretKey      = "IM_WEBHOOK_SECRET"    // 机器人签名密钥环境变量名
+	envSMSGatewayURLKey        = "SMS_GATEWAY_URL"      // 短信网关地址环境变量名
+	envSMSGatewayTokenKey      = "SMS_GATEWAY_TOKEN"    // 短信网关 Token 环境变量名
+	defaultNotificationTimeout = 10 * time.Second       // 默认请求超时10秒
+	envNotificationTimeoutKey  = "NOTIFICATION_TIMEOUT" // 请求超时环境变量名
+
+	envEmailTemplateDirKey     = "EMAIL_TEMPLATE_DIR"   // 通知模板文件目录环境变量名
+	defaultVerificationContact = ""                     // 默认不指定联系人，通知中写作“管理员”
+	envVerificationContactKey  = "VERIFICATION_CONTACT" // 号码确认联系人环境变量名
+
+	defaultOutboundWorkers          = 4                             // 默认4个发送协程
+	envOutboundWorkersKey           = "OUTBOUND_WORKERS"            // 发送协程数环境变量名
+	defaultOutboundPollInterval     = 5 * time.Second               // 默认每5秒查询一次到期消息
+	envOutboundPollIntervalKey      = "OUTBOUND_POLL_INTERVAL"      // 查询间隔环境变量名
+	defaultOutboundMaxAttempts      = 5                             // 默认最多尝试5次
+	envOutboundMaxAttemptsKey       = "OUTBOUND_MAX_ATTEMPTS"       // 最多尝试次数环境变量名
+	defaultOutboundRetryBaseBackoff = 30 * time.Second              // 默认首次重试间隔30秒
+	envOutboundRetryBaseBackoffKey  = "OUTBOUND_RETRY_BASE_BACKOFF" // 首次重试间隔环境变量名
+	defaultOutboundRetryMaxBackoff  = 30 * time.Minute              // 默认重试间隔最长30分钟
+	envOutboundRetryMaxBackoffKey   = "OUTBOUND_RETRY_MAX_BACKOFF"  // 重试间隔上限环境变量名
+	defaultOutboundSendTimeout      = 5 * time.Minute               // 默认发送超时5分钟
+	envOutboundSendTimeoutKey       = "OUTBOUND_SEND_TIMEOUT"       // 发送超时环境变量名
+
+	envVerificationRemindAfterDaysKey        = "VERIFICATION_REMIND_AFTER_DAYS"         // 发出后催办天数环境变量名
+	envVerificationRemindBeforeExpiryDaysKey = "VERIFICATION_REMIND_BEFORE_EXPIRY_DAYS" // 过期前催办天数环境变量名
+	defaultVerificationMaxReminders          = 2                                        // 默认每个令牌最多催办2次
+	envVerificationMaxRemindersKey           = "VERIFICATION_MAX_REMINDERS"             // 最多催办次数环境变量名
+	defaultVerificationReminderCheckInterval = time.Hour                                // 默认每小时检查一次
+	envVerificationReminderCheckIntervalKey  = "VERIFICATION_REMINDER_CHECK_INTERVAL"   // 检查间隔环境变量名
+)
+
+var (
+	defaultVerificationRemindAfterDays        = []int{3} // 默认发出确认通知3天后催办
+	defaultVerificationRemindBeforeExpiryDays = []int{1} // 默认链接过期前1天催办
 )
 
 // LoadConfig loads configuration from environment variables or defaults.
 // It should be called once at application startup.
 func LoadConfig() {
 	once.Do(func() {
+		appEnv := os.Getenv(envAppEnvKey)
+
+		jwtSigningAlgorithm := os.Getenv(envJWTSigningAlgorithmKey)
+		if jwtSigningAlgorithm == "" {
+			jwtSigningAlgorithm = defaultJWTSigningAlgorithm
+		}
+		jwtKeyID := os.Getenv(envJWTKeyIDKey)
+		if jwtKeyID == "" {
+			jwtKeyID = defaultJWTKeyID
+		}
+
 		jwtSecret := os.Getenv(envJWTSecretKey)
 		if jwtSecret == "" {
 			jwtSecret = defaultJWTSecret
-			log.Printf("警告: %s 环境变量未设置。正在使用默认的JWT密钥。请在生产环境中设置此变量以保证安全。", envJWTSecretKey)
+			if jwtSigningAlgorithm == defaultJWTSigningAlgorithm {
+				log.Printf("警告: %s 环境变量未设置。正在使用默认的JWT密钥。请在生产环境中设置此变量以保证安全。", envJWTSecretKey)
+			}
+		}
+		// 生产环境中禁止使用默认密钥签名或验证令牌
+		usesDefaultSecret := jwtSecret == defaultJWTSecret && jwtSigningAlgorithm == defaultJWTSigningAlgorithm
+		if appEnv == appEnvProduction && (usesDefaultSecret || os.Getenv(envJWTPreviousSecretKey) == defaultJWTSecret) {
+			log.Fatalf("错误: %s=%s 时不允许使用默认的JWT密钥，请设置 %s。", envAppEnvKey, appEnvProduction, envJWTSecretKey)
 		}
 
 		serverPort := os.Getenv(envServerPortKey)
@@ -49,12 +252,161 @@ func LoadConfig() {
 			log.Printf("信息: %s 环境变量未设置。正在使用默认前端URL %s。这在生产环境中可能不正确。", envFrontendBaseURLKey, defaultFrontendBaseURL)
 		}
 
+		tokenDenylistPurgeInterval := getDurationEnv(envTokenDenylistPurgeIntervalKey, defaultTokenDenylistPurgeInterval)
+		accessTokenTTL := getDurationEnv(envAccessTokenTTLKey, defaultAccessTokenTTL)
+		refreshTokenTTL := getDurationEnv(envRefreshTokenTTLKey, defaultRefreshTokenTTL)
+		loginMaxFailures := getIntEnv(envLoginMaxFailuresKey, defaultLoginMaxFailures)
+		loginLockoutDuration := getDurationEnv(envLoginLockoutDurationKey, defaultLoginLockoutDuration)
+		loginIPMaxFailures := getIntEnv(envLoginIPMaxFailuresKey, defaultLoginIPMaxFailures)
+		loginBackoffBase := getDurationEnv(envLoginBackoffBaseKey, defaultLoginBackoffBase)
+		loginBackoffMax := getDurationEnv(envLoginBackoffMaxKey, defaultLoginBackoffMax)
+		numberDeactivationGraceDays := getIntEnv(envNumberDeactivationGraceDaysKey, defaultNumberDeactivationGraceDays)
+		numberDeactivationCheckInterval := getDurationEnv(envNumberDeactivationCheckIntervalKey, defaultNumberDeactivationCheckInterval)
+		departureCheckInterval := getDurationEnv(envDepartureCheckIntervalKey, defaultDepartureCheckInterval)
+		departureReminderDays := getIntEnv(envDepartureReminderDaysKey, defaultDepartureReminderDays)
+		itTeamEmails := getListEnv(envITTeamEmailsKey)
+		if len(itTeamEmails) == 0 {
+			log.Printf("信息: %s 环境变量未设置。预约离职的提醒邮件将不会发送。", envITTeamEmailsKey)
+		}
+		ldapMatchBy := getStringEnv(envLDAPMatchByKey, defaultLDAPMatchBy)
+		if ldapMatchBy != "email" && ldapMatchBy != "employeeId" && ldapMatchBy != "phoneNumber" {
+			log.Printf("警告: %s 环境变量的值 %q 无效，正在使用默认值 %s。", envLDAPMatchByKey, ldapMatchBy, defaultLDAPMatchBy)
+			ldapMatchBy = defaultLDAPMatchBy
+		}
+		imWebhookFlavor := getStringEnv(envIMWebhookFlavorKey, defaultIMWebhookFlavor)
+		if imWebhookFlavor != "wecom" && imWebhookFlavor != "dingtalk" && imWebhookFlavor != "feishu" {
+			log.Printf("警告: %s 环境变量的值 %q 无效，正在使用默认值 %s。", envIMWebhookFlavorKey, imWebhookFlavor, defaultIMWebhookFlavor)
+			imWebhookFlavor = defaultIMWebhookFlavor
+		}
+
 		AppConfig = Configuration{
-			JWTSecret:       jwtSecret,
-			ServerPort:      serverPort,
-			FrontendBaseURL: frontendBaseURL,
+			AppEnv:                             appEnv,
+			JWTSecret:                          jwtSecret,
+			JWTSigningAlgorithm:                jwtSigningAlgorithm,
+			JWTKeyID:                           jwtKeyID,
+			JWTPrivateKeyFile:                  os.Getenv(envJWTPrivateKeyFileKey),
+			JWTPreviousKeyID:                   os.Getenv(envJWTPreviousKeyIDKey),
+			JWTPreviousSecret:                  os.Getenv(envJWTPreviousSecretKey),
+			JWTPreviousPublicKeyFile:           os.Getenv(envJWTPreviousPublicKeyFileKey),
+			ServerPort:                         serverPort,
+			FrontendBaseURL:                    frontendBaseURL,
+			TokenDenylistPurgeInterval:         tokenDenylistPurgeInterval,
+			AccessTokenTTL:                     accessTokenTTL,
+			RefreshTokenTTL:                    refreshTokenTTL,
+			LoginMaxFailures:                   loginMaxFailures,
+			LoginLockoutDuration:               loginLockoutDuration,
+			LoginIPMaxFailures:                 loginIPMaxFailures,
+			LoginBackoffBase:                   loginBackoffBase,
+			LoginBackoffMax:                    loginBackoffMax,
+			NumberDeactivationGraceDays:        numberDeactivationGraceDays,
+			NumberDeactivationCheckInterval:    numberDeactivationCheckInterval,
+			DepartureCheckInterval:             departureCheckInterval,
+			DepartureReminderDays:              departureReminderDays,
+			ITTeamEmails:                       itTeamEmails,
+			LDAPURL:                            os.Getenv(envLDAPURLKey),
+			LDAPBindDN:                         os.Getenv(envLDAPBindDNKey),
+			LDAPBindPassword:                   os.Getenv(envLDAPBindPasswordKey),
+			LDAPBaseDN:                         os.Getenv(envLDAPBaseDNKey),
+			LDAPUserFilter:                     getStringEnv(envLDAPUserFilterKey, defaultLDAPUserFilter),
+			LDAPMatchBy:                        ldapMatchBy,
+			LDAPAttrFullName:                   getStringEnv(envLDAPAttrFullNameKey, defaultLDAPAttrFullName),
+			LDAPAttrEmail:                      getStringEnv(envLDAPAttrEmailKey, defaultLDAPAttrEmail),
+			LDAPAttrPhoneNumber:                getStringEnv(envLDAPAttrPhoneNumberKey, defaultLDAPAttrPhoneNumber),
+			LDAPAttrDepartment:                 getStringEnv(envLDAPAttrDepartmentKey, defaultLDAPAttrDepartment),
+			LDAPAttrEmployeeID:                 getStringEnv(envLDAPAttrEmployeeIDKey, defaultLDAPAttrEmployeeID),
+			LDAPPageSize:                       getIntEnv(envLDAPPageSizeKey, defaultLDAPPageSize),
+			LDAPTimeout:                        getDurationEnv(envLDAPTimeoutKey, defaultLDAPTimeout),
+			DirectorySyncInterval:              getDurationEnv(envDirectorySyncIntervalKey, defaultDirectorySyncInterval),
+			DirectorySyncMaxDepartures:         getIntEnv(envDirectorySyncMaxDeparturesKey, defaultDirectorySyncMaxDepartures),
+			HRWebhookSecret:                    os.Getenv(envHRWebhookSecretKey),
+			HRWebhookMaxClockSkew:              getDurationEnv(envHRWebhookMaxClockSkewKey, defaultHRWebhookMaxClockSkew),
+			IMWebhookURL:                       os.Getenv(envIMWebhookURLKey),
+			IMWebhookFlavor:                    imWebhookFlavor,
+			IMWebhookSecret:                    os.Getenv(envIMWebhookSecretKey),
+			SMSGatewayURL:                      os.Getenv(envSMSGatewayURLKey),
+			SMSGatewayToken:                    os.Getenv(envSMSGatewayTokenKey),
+			NotificationTimeout:                getDurationEnv(envNotificationTimeoutKey, defaultNotificationTimeout),
+			EmailTemplateDir:                   os.Getenv(envEmailTemplateDirKey),
+			VerificationContact:                getStringEnv(envVerificationContactKey, defaultVerificationContact),
+			OutboundWorkers:                    getIntEnv(envOutboundWorkersKey, defaultOutboundWorkers),
+			OutboundPollInterval:               getDurationEnv(envOutboundPollIntervalKey, defaultOutboundPollInterval),
+			OutboundMaxAttempts:                getIntEnv(envOutboundMaxAttemptsKey, defaultOutboundMaxAttempts),
+			OutboundRetryBaseBackoff:           getDurationEnv(envOutboundRetryBaseBackoffKey, defaultOutboundRetryBaseBackoff),
+			OutboundRetryMaxBackoff:            getDurationEnv(envOutboundRetryMaxBackoffKey, defaultOutboundRetryMaxBackoff),
+			OutboundSendTimeout:                getDurationEnv(envOutboundSendTimeoutKey, defaultOutboundSendTimeout),
+			VerificationRemindAfterDays:        getDaysListEnv(envVerificationRemindAfterDaysKey, defaultVerificationRemindAfterDays),
+			VerificationRemindBeforeExpiryDays: getDaysListEnv(envVerificationRemindBeforeExpiryDaysKey, defaultVerificationRemindBeforeExpiryDays),
+			VerificationMaxReminders:           getIntEnv(envVerificationMaxRemindersKey, defaultVerificationMaxReminders),
+			VerificationReminderCheckInterval:  getDurationEnv(envVerificationReminderCheckIntervalKey, defaultVerificationReminderCheckInterval),
 		}
 
 		log.Println("应用配置已加载。")
 	})
 }
+
+// getStringEnv 读取字符串环境变量，未设置时返回默认值
+func getStringEnv(key, defaultValue string) string {
+	if value := strings.TrimSpace(os.Getenv(key)); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// getDurationEnv 读取 Go duration 格式的环境变量，未设置或无效时返回默认值
+func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		log.Printf("警告: %s 环境变量的值 %q 无效，正在使用默认值 %s。", key, value, defaultValue)
+		return defaultValue
+	}
+	return d
+}
+
+// getIntEnv 读取正整数环境变量，未设置或无效时返回默认值
+func getIntEnv(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		log.Printf("警告: %s 环境变量的值 %q 无效，正在使用默认值 %d。", key, value, defaultValue)
+		return defaultValue
+	}
+	return n
+}
+
+// getDaysListEnv 读取以逗号分隔的天数列表，未设置或含有无效值时返回默认值；设为 0 表示空列表
+func getDaysListEnv(key string, defaultValue []int) []int {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return defaultValue
+	}
+	days := []int{}
+	for _, item := range getListEnv(key) {
+		n, err := strconv.Atoi(item)
+		if err != nil || n < 0 {
+			log.Printf("警告: %s 环境变量的值 %q 无效，正在使用默认值 %v。", key, value, defaultValue)
+			return defaultValue
+		}
+		if n > 0 {
+			days = append(days, n)
+		}
+	}
+	return days
+}
+
+// getListEnv 读取以逗号分隔的环境变量，忽略空白项
+func getListEnv(key string) []string {
+	var values []string
+	for _, v := range strings.Split(os.Getenv(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
@@ -15,122 +15,21 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/auth/login": {
-            "post": {
-                "description": "验证管理员凭证并返回 JWT",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "auth"
-                ],
-                "summary": "管理员登录",
-                "parameters": [
-                    {
-                        "description": "登录凭证",
-                        "name": "credentials",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.LoginRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "登录成功，返回 Token 和用户信息",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/utils.SuccessResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/handlers.LoginResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/utils.APIErrorResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "无效的用户名或密码",
-                        "schema": {
-                            "$ref": "#/definitions/utils.APIErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "无法生成Token",
-                        "schema": {
-                            "$ref": "#/definitions/utils.APIErrorResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/auth/logout": {
-            "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Logs out the current user by invalidating their token.",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "auth"
-                ],
-                "summary": "User logout",
-                "responses": {
-                    "200": {
-                        "description": "成功登出",
-                        "schema": {
-                            "$ref": "#/definitions/utils.SuccessResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "错误的请求 (例如，上下文中缺少JTI或EXP)",
-                        "schema": {
-                            "$ref": "#/definitions/utils.APIErrorResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/employees": {
+        "/audit-events": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "根据查询参数获取员工列表，支持分页、搜索和筛选",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "分页查询操作审计事件，按发生时间倒序。可按操作者、操作类型、实体类型和标识、来源以及时间范围筛选。\n实体标识：号码为手机号，员工为工号，用户为用户ID，确认批次为批次ID。\nfrom/to 支持 RFC3339 时间或 YYYY-MM-DD 日期；to 为日期时包含当天。",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Employees"
+                    "AuditEvents"
                 ],
-                "summary": "获取员工列表",
+                "summary": "查询操作审计日志",
                 "parameters": [
                     {
                         "type": "integer",
@@ -141,40 +40,57 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "default": 10,
+                        "default": 20,
                         "description": "每页数量",
                         "name": "limit",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "排序字段 (例如: employeeId, fullName, createdAt)",
-                        "name": "sortBy",
+                        "description": "操作者用户名",
+                        "name": "actor",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "default": "\"desc\"",
-                        "description": "排序顺序 ('asc'或'desc')",
-                        "name": "sortOrder",
+                        "description": "操作类型，如 mobile_number.update",
+                        "name": "action",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "搜索关键词 (匹配姓名、工号)",
-                        "name": "search",
+                        "description": "实体类型 (mobile_number, employee, user, verification_batch)",
+                        "name": "entityType",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "在职状态筛选 ('Active'或'Departed')",
-                        "name": "employmentStatus",
+                        "description": "实体标识",
+                        "name": "entityId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "来源 (api, import, bulk, verification, system, directory, hr)",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "起始时间",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "结束时间",
+                        "name": "to",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "成功响应，包含员工列表和分页信息",
+                        "description": "成功响应，包含审计事件列表和分页信息",
                         "schema": {
                             "allOf": [
                                 {
@@ -184,7 +100,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/handlers.PagedEmployeesData"
+                                            "$ref": "#/definitions/handlers.PagedAuditEventsData"
                                         }
                                     }
                                 }
@@ -203,6 +119,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/utils.APIErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "权限不足",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "服务器内部错误",
                         "schema": {
@@ -210,14 +132,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/2fa/disable": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "从请求体绑定数据并验证，数据保存到数据库。员工工号由系统自动生成。支持设置可选的入职日期（格式：YYYY-MM-DD）。",
+                "description": "校验当前密码和验证码（或恢复码）后关闭两步验证，恢复码全部作废。",
                 "consumes": [
                     "application/json"
                 ],
@@ -225,41 +149,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Employees"
+                    "auth"
                 ],
-                "summary": "新增一个员工",
+                "summary": "关闭两步验证",
                 "parameters": [
                     {
-                        "description": "员工信息。包含必填的姓名，可选的手机号、邮箱、部门和入职日期",
-                        "name": "employee",
+                        "description": "密码和验证码",
+                        "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.CreateEmployeePayload"
+                            "$ref": "#/definitions/models.DisableTOTPPayload"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "创建成功的员工对象",
+                    "200": {
+                        "description": "两步验证已关闭",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/utils.SuccessResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/models.Employee"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/utils.SuccessResponse"
                         }
                     },
                     "400": {
-                        "description": "请求参数错误、数据校验失败或入职日期格式无效",
+                        "description": "请求参数错误、密码或验证码不正确、两步验证未启用",
                         "schema": {
                             "$ref": "#/definitions/utils.APIErrorResponse"
                         }
@@ -270,12 +182,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/utils.APIErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "手机号或邮箱已存在",
-                        "schema": {
-                            "$ref": "#/definitions/utils.APIErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "服务器内部错误",
                         "schema": {
@@ -285,36 +191,38 @@ const docTemplate = `{
                 }
             }
         },
-        "/employees/import": {
+        "/auth/2fa/enable": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "通过上传 CSV 文件批量导入员工。CSV文件必须包含表头：fullName,phoneNumber,email,department,hireDate。列顺序必须一致。fullName为必填，其他字段可为空。hireDate格式为YYYY-MM-DD。支持GBK和UTF-8编码。",
+                "description": "提交验证器 App 生成的验证码以确认密钥并启用两步验证，返回一组一次性恢复码。",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Employees"
+                    "auth"
                 ],
-                "summary": "批量导入员工数据 (CSV)",
+                "summary": "启用两步验证",
                 "parameters": [
                     {
-                        "type": "file",
-                        "description": "包含员工数据的 CSV 文件。表头: fullName,phoneNumber,email,department,hireDate",
-                        "name": "file",
-                        "in": "formData",
-                        "required": true
+                        "description": "验证码",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TOTPCodePayload"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "导入结果摘要，包含成功和失败的详细信息",
+                        "description": "启用成功，返回恢复码",
                         "schema": {
                             "allOf": [
                                 {
@@ -324,7 +232,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/handlers.BatchImportResponse"
+                                            "$ref": "#/definitions/handlers.RecoveryCodesResponse"
                                         }
                                     }
                                 }
@@ -332,7 +240,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "请求错误，例如文件未提供、文件格式错误、CSV表头不匹配或数据格式错误",
+                        "description": "请求参数错误、验证码无效或尚未生成密钥",
                         "schema": {
                             "$ref": "#/definitions/utils.APIErrorResponse"
                         }
@@ -343,6 +251,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/utils.APIErrorResponse"
                         }
                     },
+                    "409": {
+                        "description": "两步验证已启用",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "服务器内部错误",
                         "schema": {
@@ -352,14 +266,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/employees/{employeeId}": {
-            "get": {
+        "/auth/2fa/recovery-codes": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "根据路径参数员工业务工号获取单个员工的完整信息，包含其作为\"办卡人\"和\"当前使用人\"的号码简要列表。",
+                "description": "校验验证码后生成一组新的恢复码，旧恢复码全部作废。",
                 "consumes": [
                     "application/json"
                 ],
@@ -367,21 +281,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Employees"
+                    "auth"
                 ],
-                "summary": "获取指定业务工号的员工详情",
+                "summary": "重新生成恢复码",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "员工业务工号",
-                        "name": "employeeId",
-                        "in": "path",
-                        "required": true
+                        "description": "验证码",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TOTPCodePayload"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "成功响应，包含员工详情",
+                        "description": "新的恢复码",
                         "schema": {
                             "allOf": [
                                 {
@@ -391,7 +307,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.EmployeeDetailResponse"
+                                            "$ref": "#/definitions/handlers.RecoveryCodesResponse"
                                         }
                                     }
                                 }
@@ -399,7 +315,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "无效的员工工号格式 (保留，以防未来有格式校验)",
+                        "description": "请求参数错误、验证码无效或两步验证未启用",
                         "schema": {
                             "$ref": "#/definitions/utils.APIErrorResponse"
                         }
@@ -410,12 +326,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/utils.APIErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "员工未找到",
-                        "schema": {
-                            "$ref": "#/definitions/utils.APIErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "服务器内部错误",
                         "schema": {
@@ -425,45 +335,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/employees/{employeeId}/update": {
+        "/auth/2fa/setup": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "根据员工业务工号更新员工的部门、入职日期、在职状态或离职日期。所有字段都是可选的，至少需要提供一个字段进行更新。入职日期和离职日期格式为 YYYY-MM-DD。在职状态允许值为 'Active' 或 'Departed'。",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "为当前用户生成新的 TOTP 密钥和 otpauth URI（用于生成二维码）。需调用 /auth/2fa/enable 提交验证码后才会生效。",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Employees"
-                ],
-                "summary": "更新指定业务工号的员工信息",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "员工业务工号",
-                        "name": "employeeId",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "要更新的员工字段。可包含部门、入职日期、在职状态、离职日期",
-                        "name": "employeeUpdate",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.UpdateEmployeePayload"
-                        }
-                    }
+                    "auth"
                 ],
+                "summary": "生成两步验证密钥",
                 "responses": {
                     "200": {
-                        "description": "更新后的员工对象",
+                        "description": "密钥和 otpauth URI",
                         "schema": {
                             "allOf": [
                                 {
@@ -473,27 +362,21 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.Employee"
+                                            "$ref": "#/definitions/services.TOTPSetupResult"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "400": {
-                        "description": "请求参数错误、数据校验失败、日期格式无效或业务逻辑错误",
-                        "schema": {
-                            "$ref": "#/definitions/utils.APIErrorResponse"
-                        }
-                    },
                     "401": {
                         "description": "未认证或 Token 无效/过期",
                         "schema": {
                             "$ref": "#/definitions/utils.APIErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "员工未找到",
+                    "409": {
+                        "description": "两步验证已启用",
                         "schema": {
                             "$ref": "#/definitions/utils.APIErrorResponse"
                         }
@@ -507,14 +390,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/mobilenumbers": {
-            "get": {
+        "/auth/change-password": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "根据查询参数获取手机号码列表，支持分页、搜索和筛选。注意：风险号码(risk_pending)通过专门的风险号码接口获取，此接口不返回风险号码。",
+                "description": "当前登录用户校验原密码后修改自己的密码。",
                 "consumes": [
                     "application/json"
                 ],
@@ -522,76 +405,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "MobileNumbers"
+                    "auth"
                 ],
-                "summary": "获取手机号码列表",
+                "summary": "修改当前用户密码",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "页码",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "每页数量",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "排序字段 (例如: phoneNumber, applicationDate)",
-                        "name": "sortBy",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "排序顺序 ('asc'或'desc')",
-                        "name": "sortOrder",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "搜索关键词 (匹配手机号、使用人、办卡人)",
-                        "name": "search",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "号码状态筛选 (例如: 闲置, 使用中，不包括风险号码)",
-                        "name": "status",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "办卡人当前在职状态筛选 ('Active'或'Departed')",
-                        "name": "applicantStatus",
-                        "in": "query"
+                        "description": "原密码和新密码",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ChangePasswordPayload"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "成功响应，包含号码列表和分页信息",
+                        "description": "密码修改成功",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/utils.SuccessResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/handlers.PagedMobileNumbersData"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/utils.SuccessResponse"
                         }
                     },
                     "400": {
-                        "description": "请求参数错误",
+                        "description": "请求参数错误或原密码不正确",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未认证或 Token 无效/过期",
                         "schema": {
                             "$ref": "#/definitions/utils.APIErrorResponse"
                         }
@@ -603,14 +445,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/login": {
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "从请求体绑定数据并验证，数据保存到 SQLite 的 MobileNumbers 表中，进行手机号码唯一性校验。",
+                "description": "验证管理员凭证，返回短期访问令牌和刷新令牌。启用了两步验证的账号只返回 mfaRequired=true 和临时令牌 mfaToken，需继续调用 /auth/login/2fa。",
                 "consumes": [
                     "application/json"
                 ],
@@ -618,23 +457,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "MobileNumbers"
+                    "auth"
                 ],
-                "summary": "新增一个手机号码",
+                "summary": "管理员登录",
                 "parameters": [
                     {
-                        "description": "手机号码信息 (日期格式 YYYY-MM-DD)",
-                        "name": "mobileNumber",
+                        "description": "登录凭证",
+                        "name": "credentials",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.CreateMobileNumberPayload"
+                            "$ref": "#/definitions/handlers.LoginRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "创建成功的号码对象",
+                    "200": {
+                        "description": "登录成功，返回 Token 和用户信息",
                         "schema": {
                             "allOf": [
                                 {
@@ -644,7 +483,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.MobileNumber"
+                                            "$ref": "#/definitions/handlers.LoginResponse"
                                         }
                                     }
                                 }
@@ -652,25 +491,61 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "请求参数错误或数据校验失败",
+                        "description": "请求参数错误",
                         "schema": {
                             "$ref": "#/definitions/utils.APIErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "未认证或 Token 无效/过期",
+                        "description": "无效的用户名或密码",
                         "schema": {
                             "$ref": "#/definitions/utils.APIErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "手机号码已存在",
+                    "403": {
+                        "description": "账号已被禁用",
                         "schema": {
                             "$ref": "#/definitions/utils.APIErrorResponse"
                         }
                     },
+                    "423": {
+                        "description": "登录失败次数过多，账号已被临时锁定",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.APIErrorResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "details": {
+                                            "$ref": "#/definitions/handlers.LoginBlockedDetails"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "429": {
+                        "description": "登录尝试过于频繁",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.APIErrorResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "details": {
+                                            "$ref": "#/definitions/handlers.LoginBlockedDetails"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
                     "500": {
-                        "description": "服务器内部错误",
+                        "description": "无法生成Token",
                         "schema": {
                             "$ref": "#/definitions/utils.APIErrorResponse"
                         }
@@ -678,36 +553,33 @@ const docTemplate = `{
                 }
             }
         },
-        "/mobilenumbers/import": {
+        "/auth/login/2fa": {
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "通过上传 CSV 文件批量导入手机号码。CSV文件应包含表头：phoneNumber,applicantName,applicationDate,vendor",
+                "description": "使用第一步登录返回的临时令牌和 TOTP 验证码（或恢复码）完成登录，返回访问令牌和刷新令牌。临时令牌只能成功使用一次。",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "MobileNumbers"
+                    "auth"
                 ],
-                "summary": "批量导入手机号码数据 (CSV)",
+                "summary": "两步验证登录",
                 "parameters": [
                     {
-                        "type": "file",
-                        "description": "包含手机号码数据的 CSV 文件 (表头: phoneNumber,applicantName,applicationDate,vendor)",
-                        "name": "file",
-                        "in": "formData",
-                        "required": true
+                        "description": "临时令牌和验证码",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MFALoginRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "导入结果摘要",
+                        "description": "登录成功，返回 Token 和用户信息",
                         "schema": {
                             "allOf": [
                                 {
@@ -717,7 +589,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/handlers.BatchImportMobileNumbersResponse"
+                                            "$ref": "#/definitions/handlers.LoginResponse"
                                         }
                                     }
                                 }
@@ -725,118 +597,61 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "请求错误，例如文件未提供、文件格式错误或CSV表头不匹配",
+                        "description": "请求参数错误",
                         "schema": {
                             "$ref": "#/definitions/utils.APIErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "未认证或 Token 无效/过期",
+                        "description": "临时令牌无效或验证码错误",
                         "schema": {
                             "$ref": "#/definitions/utils.APIErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "服务器内部错误",
+                    "403": {
+                        "description": "账号已被禁用",
                         "schema": {
                             "$ref": "#/definitions/utils.APIErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/mobilenumbers/risk-pending": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "获取状态为risk_pending的手机号码列表，支持分页、搜索和筛选",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "MobileNumbers"
-                ],
-                "summary": "获取风险号码列表",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "页码",
-                        "name": "page",
-                        "in": "query"
                     },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "每页数量",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "排序字段 (例如: phoneNumber, applicationDate, status)",
-                        "name": "sortBy",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "default": "\"desc\"",
-                        "description": "排序顺序 ('asc'或'desc')",
-                        "name": "sortOrder",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "搜索关键词 (匹配手机号、办卡人姓名、当前使用人姓名)",
-                        "name": "search",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "办卡人在职状态筛选 ('Active'或'Departed')",
-                        "name": "applicantStatus",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "成功响应，包含风险号码列表和分页信息",
+                    "423": {
+                        "description": "登录失败次数过多，账号已被临时锁定",
                         "schema": {
                             "allOf": [
                                 {
-                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                    "$ref": "#/definitions/utils.APIErrorResponse"
                                 },
                                 {
                                     "type": "object",
                                     "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/handlers.PagedRiskNumbersData"
+                                        "details": {
+                                            "$ref": "#/definitions/handlers.LoginBlockedDetails"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/utils.APIErrorResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "未认证或 Token 无效/过期",
+                    "429": {
+                        "description": "登录尝试过于频繁",
                         "schema": {
-                            "$ref": "#/definitions/utils.APIErrorResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.APIErrorResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "details": {
+                                            "$ref": "#/definitions/handlers.LoginBlockedDetails"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "500": {
-                        "description": "服务器内部错误",
+                        "description": "无法生成Token",
                         "schema": {
                             "$ref": "#/definitions/utils.APIErrorResponse"
                         }
@@ -844,14 +659,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/mobilenumbers/{phoneNumber}": {
-            "get": {
+        "/auth/logout": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "根据路径参数手机号码字符串获取单个手机号码的完整信息，包括其使用历史。",
+                "description": "Logs out the current user by invalidating their token. If a refresh token is provided, its whole session is revoked as well.",
                 "consumes": [
                     "application/json"
                 ],
@@ -859,21 +674,68 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "MobileNumbers"
+                    "auth"
                 ],
-                "summary": "获取指定手机号码的详情",
+                "summary": "User logout",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "手机号码字符串",
-                        "name": "phoneNumber",
-                        "in": "path",
-                        "required": true
+                        "description": "要一并吊销的刷新令牌",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.LogoutRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "成功响应，包含号码详情及其使用历史",
+                        "description": "成功登出",
+                        "schema": {
+                            "$ref": "#/definitions/utils.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "错误的请求 (例如，上下文中缺少JTI或EXP)",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "登出失败",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/refresh": {
+            "post": {
+                "description": "使用刷新令牌换取新的访问令牌和刷新令牌，旧刷新令牌随即失效。已失效的刷新令牌被再次使用时，该登录会话的所有刷新令牌都会被吊销。",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "刷新访问令牌",
+                "parameters": [
+                    {
+                        "description": "刷新令牌",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RefreshTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "刷新成功，返回新的令牌",
                         "schema": {
                             "allOf": [
                                 {
@@ -883,7 +745,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.MobileNumberResponse"
+                                            "$ref": "#/definitions/handlers.LoginResponse"
                                         }
                                     }
                                 }
@@ -891,19 +753,25 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "无效的手机号码格式 (保留，以防未来有格式校验)",
+                        "description": "请求参数错误",
                         "schema": {
                             "$ref": "#/definitions/utils.APIErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "号码未找到",
+                    "401": {
+                        "description": "刷新令牌无效、已过期或被重复使用",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "账号已被禁用",
                         "schema": {
                             "$ref": "#/definitions/utils.APIErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "服务器内部错误",
+                        "description": "无法生成Token",
                         "schema": {
                             "$ref": "#/definitions/utils.APIErrorResponse"
                         }
@@ -911,14 +779,70 @@ const docTemplate = `{
                 }
             }
         },
-        "/mobilenumbers/{phoneNumber}/assign": {
+        "/departments": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "返回全部部门（平铺），按名称升序，包含上级部门名称、负责人姓名、别名和直属员工数量。",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Departments"
+                ],
+                "summary": "获取部门列表",
+                "responses": {
+                    "200": {
+                        "description": "成功响应，包含部门列表",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.DepartmentResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "未认证或 Token 无效/过期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "权限不足",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            },
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "校验目标号码是否为\"闲置\"状态，目标员工是否为\"在职\"状态。更新号码记录，关联当前使用人员工ID，将号码状态改为\"使用中\"。创建一条新的号码使用历史记录。",
+                "description": "创建部门，可指定上级部门、部门负责人和成本中心。部门名称会去除多余空白，且不能与已有部门名称或别名重复。",
                 "consumes": [
                     "application/json"
                 ],
@@ -926,30 +850,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "MobileNumbers"
+                    "Departments"
                 ],
-                "summary": "将指定手机号码分配给一个员工",
+                "summary": "新增部门",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "手机号码字符串",
-                        "name": "phoneNumber",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "分配信息 (员工业务工号和分配日期和用途 YYYY-MM-DD)",
-                        "name": "assignPayload",
+                        "description": "部门信息",
+                        "name": "department",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.MobileNumberAssignPayload"
+                            "$ref": "#/definitions/models.CreateDepartmentPayload"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "成功分配后的号码对象",
+                    "201": {
+                        "description": "创建成功的部门",
                         "schema": {
                             "allOf": [
                                 {
@@ -959,7 +876,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.MobileNumber"
+                                            "$ref": "#/definitions/models.Department"
                                         }
                                     }
                                 }
@@ -967,7 +884,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "请求参数错误 / 无效的日期格式 / 无效的手机号码格式\" // 更新了描述",
+                        "description": "请求参数错误、上级部门或负责人不存在",
                         "schema": {
                             "$ref": "#/definitions/utils.APIErrorResponse"
                         }
@@ -978,14 +895,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/utils.APIErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "手机号码或目标员工工号未找到",
+                    "403": {
+                        "description": "权限不足",
                         "schema": {
                             "$ref": "#/definitions/utils.APIErrorResponse"
                         }
                     },
                     "409": {
-                        "description": "操作冲突 (例如：号码非闲置，员工非在职)",
+                        "description": "部门名称已存在",
                         "schema": {
                             "$ref": "#/definitions/utils.APIErrorResponse"
                         }
@@ -999,45 +916,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/mobilenumbers/{phoneNumber}/handle-risk": {
-            "post": {
+        "/departments/tree": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "处理状态为risk_pending的号码，支持变更办卡人、回收号码、注销号码三种操作",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "按上级部门关系返回部门树，顶级部门及各级下级部门均按名称升序。",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "MobileNumbers"
-                ],
-                "summary": "处理风险号码",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "手机号码字符串",
-                        "name": "phoneNumber",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "处理风险号码的请求体",
-                        "name": "handleRisk",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.HandleRiskNumberPayload"
-                        }
-                    }
+                    "Departments"
                 ],
+                "summary": "获取部门树",
                 "responses": {
                     "200": {
-                        "description": "处理成功的号码对象",
+                        "description": "成功响应，包含部门树",
                         "schema": {
                             "allOf": [
                                 {
@@ -1047,27 +943,24 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.MobileNumber"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.DepartmentResponse"
+                                            }
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "400": {
-                        "description": "请求参数错误、数据校验失败或业务逻辑错误",
-                        "schema": {
-                            "$ref": "#/definitions/utils.APIErrorResponse"
-                        }
-                    },
                     "401": {
                         "description": "未认证或 Token 无效/过期",
                         "schema": {
                             "$ref": "#/definitions/utils.APIErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "手机号码未找到或员工未找到",
+                    "403": {
+                        "description": "权限不足",
                         "schema": {
                             "$ref": "#/definitions/utils.APIErrorResponse"
                         }
@@ -1081,62 +974,39 @@ const docTemplate = `{
                 }
             }
         },
-        "/mobilenumbers/{phoneNumber}/unassign": {
-            "post": {
+        "/departments/{id}": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "回收手机号码，支持在用(in_use)、风险待核实(risk_pending)、用户报告(user_reported)状态的号码。对于有当前使用人的号码，会更新使用历史记录的结束时间；对于没有当前使用人的号码，直接设为闲置状态。",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "删除没有下级部门和员工的部门，部门的别名一并删除。",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "MobileNumbers"
+                    "Departments"
                 ],
-                "summary": "从当前使用人处回收指定手机号码",
+                "summary": "删除部门",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "手机号码字符串",
-                        "name": "phoneNumber",
+                        "type": "integer",
+                        "description": "部门ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "回收信息 (可选，包含回收日期 YYYY-MM-DD)",
-                        "name": "unassignPayload",
-                        "in": "body",
-                        "schema": {
-                            "$ref": "#/definitions/models.MobileNumberUnassignPayload"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "成功回收后的号码对象",
+                        "description": "删除成功",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/utils.SuccessResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/models.MobileNumber"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/utils.SuccessResponse"
                         }
                     },
                     "400": {
-                        "description": "请求参数错误 / 无效的日期格式 / 无效的手机号码格式",
+                        "description": "无效的部门ID",
                         "schema": {
                             "$ref": "#/definitions/utils.APIErrorResponse"
                         }
@@ -1147,14 +1017,20 @@ const docTemplate = `{
                             "$ref": "#/definitions/utils.APIErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "权限不足",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
                     "404": {
-                        "description": "手机号码未找到",
+                        "description": "部门未找到",
                         "schema": {
                             "$ref": "#/definitions/utils.APIErrorResponse"
                         }
                     },
                     "409": {
-                        "description": "操作冲突 (例如：号码状态不允许回收，或未找到有效的分配记录)",
+                        "description": "部门仍有下级部门或员工",
                         "schema": {
                             "$ref": "#/definitions/utils.APIErrorResponse"
                         }
@@ -1168,14 +1044,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/mobilenumbers/{phoneNumber}/update": {
+        "/departments/{id}/merge": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "更新指定手机号码的信息 (主要用于更新状态、用途、供应商、备注)。当号码状态变更为\"已注销\"时，自动记录注销时间。注意：风险号码(risk_pending)不允许通过此接口更新，请使用专门的风险处理接口。",
+                "description": "将同一部门的不同写法合并到目标部门：被合并部门的员工和下级部门转到目标部门，其名称和别名记为目标部门的别名，随后删除被合并部门。",
                 "consumes": [
                     "application/json"
                 ],
@@ -1183,30 +1059,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "MobileNumbers"
+                    "Departments"
                 ],
-                "summary": "更新指定手机号码的信息",
+                "summary": "合并部门",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "手机号码字符串",
-                        "name": "phoneNumber",
+                        "type": "integer",
+                        "description": "目标部门ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "要更新的手机号码字段",
-                        "name": "mobileNumberUpdate",
+                        "description": "被合并的部门ID列表",
+                        "name": "merge",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.MobileNumberUpdatePayload"
+                            "$ref": "#/definitions/models.MergeDepartmentsPayload"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "更新后的号码对象",
+                        "description": "合并后的目标部门",
                         "schema": {
                             "allOf": [
                                 {
@@ -1216,7 +1092,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.MobileNumber"
+                                            "$ref": "#/definitions/models.Department"
                                         }
                                     }
                                 }
@@ -1224,7 +1100,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "请求参数错误或数据校验失败 / 没有提供任何更新字段 / 无效的手机号码格式",
+                        "description": "请求参数错误，或被合并部门不存在、是目标部门本身或其上级部门",
                         "schema": {
                             "$ref": "#/definitions/utils.APIErrorResponse"
                         }
@@ -1236,13 +1112,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "风险号码不允许通过常规接口更新",
+                        "description": "权限不足",
                         "schema": {
                             "$ref": "#/definitions/utils.APIErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "号码未找到",
+                        "description": "目标部门未找到",
                         "schema": {
                             "$ref": "#/definitions/utils.APIErrorResponse"
                         }
@@ -1256,14 +1132,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/verification/admin/phone-status": {
-            "get": {
+        "/departments/{id}/update": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "获取基于手机号码维度的确认流程状态，包括统计摘要和详细信息",
+                "description": "更新部门名称、上级部门、负责人或成本中心，所有字段均可选。修改名称时原名称记为别名，并同步更新该部门员工的部门名称。\nparentId 为 0 表示改为顶级部门；不能将部门移到自身或其下级部门之下。managerEmployeeId、costCenter 为空字符串表示清除。",
                 "consumes": [
                     "application/json"
                 ],
@@ -1271,38 +1147,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Verification"
+                    "Departments"
                 ],
-                "summary": "获取基于手机号码维度的确认流程状态",
+                "summary": "更新部门",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "员工业务工号，用于筛选",
-                        "name": "employee_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "员工业务工号，用于筛选（兼容旧版本）",
-                        "name": "employeeId",
-                        "in": "query"
+                        "type": "integer",
+                        "description": "部门ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "部门名称，用于筛选",
+                        "description": "要更新的部门字段",
                         "name": "department",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "部门名称，用于筛选（兼容旧版本）",
-                        "name": "departmentName",
-                        "in": "query"
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateDepartmentPayload"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "成功响应",
+                        "description": "更新后的部门",
                         "schema": {
                             "allOf": [
                                 {
@@ -1312,7 +1180,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.PhoneVerificationStatusResponse"
+                                            "$ref": "#/definitions/models.Department"
                                         }
                                     }
                                 }
@@ -1320,13 +1188,31 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "请求参数错误",
+                        "description": "请求参数错误、上级部门无效或负责人不存在",
                         "schema": {
                             "$ref": "#/definitions/utils.APIErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "未授权",
+                        "description": "未认证或 Token 无效/过期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "权限不足",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "部门未找到",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "部门名称已存在",
                         "schema": {
                             "$ref": "#/definitions/utils.APIErrorResponse"
                         }
@@ -1340,33 +1226,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/verification/batch/{batchId}/status": {
+        "/email-templates": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "获取指定号码确认批处理任务的当前状态、整体进度（包括已处理员工数、令牌生成情况、邮件发送统计：尝试数、成功数、失败数）以及详细的错误报告（例如邮件发送失败的原因）。",
+                "description": "返回数据库中保存的全部通知模板，按名称、语言升序。未保存到数据库的模板使用 EMAIL_TEMPLATE_DIR 中的模板文件或内置模板，可通过预览接口查看。",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Verification"
-                ],
-                "summary": "获取号码确认批处理任务的状态",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "批处理任务ID",
-                        "name": "batchId",
-                        "in": "path",
-                        "required": true
-                    }
+                    "EmailTemplates"
                 ],
+                "summary": "获取通知模板列表",
                 "responses": {
                     "200": {
-                        "description": "成功响应，包含批处理任务详情",
+                        "description": "成功响应，包含模板列表",
                         "schema": {
                             "allOf": [
                                 {
@@ -1376,21 +1253,24 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.VerificationBatchTask"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.EmailTemplate"
+                                            }
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "400": {
-                        "description": "无效的批处理ID格式",
+                    "401": {
+                        "description": "未认证或 Token 无效/过期",
                         "schema": {
                             "$ref": "#/definitions/utils.APIErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "批处理任务未找到",
+                    "403": {
+                        "description": "权限不足",
                         "schema": {
                             "$ref": "#/definitions/utils.APIErrorResponse"
                         }
@@ -1402,30 +1282,38 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/verification/info": {
-            "get": {
-                "description": "用户点击邮件链接后，前端页面调用此接口获取该用户需确认的号码信息",
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "保存通知模板，覆盖同名称、同语言的模板文件和内置模板。subject 和 textBody 为 Go text/template 模板，htmlBody 为 html/template 模板。textBody 必须提供，短信使用纯文本正文；htmlBody 可选。IM 只发送标题，subject 中不能引用 .Link 和 .Numbers。\nverification 模板可使用的变量：.EmployeeName、.EmployeeID、.Link、.Numbers（每项含 .PhoneNumber、.Status）、.ExpiresAt、.ValidDays、.Contact、.Reminder（自动或手动催办时为 true）。保存前会以示例数据渲染，引用不存在的变量时返回 400。",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Verification"
+                    "EmailTemplates"
                 ],
-                "summary": "获取待确认的号码信息",
+                "summary": "新增通知模板",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "验证令牌",
-                        "name": "token",
-                        "in": "query",
-                        "required": true
+                        "description": "模板内容",
+                        "name": "template",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateEmailTemplatePayload"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "成功响应，包含员工姓名、令牌有效期、待验证的号码列表及其状态",
+                    "201": {
+                        "description": "创建成功的模板",
                         "schema": {
                             "allOf": [
                                 {
@@ -1435,15 +1323,33 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.VerificationInfo"
+                                            "$ref": "#/definitions/models.EmailTemplate"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
+                    "400": {
+                        "description": "请求参数错误、未知的模板名称或模板无效",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未认证或 Token 无效/过期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
                     "403": {
-                        "description": "令牌无效或已过期",
+                        "description": "权限不足",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "该名称和语言的模板已存在",
                         "schema": {
                             "$ref": "#/definitions/utils.APIErrorResponse"
                         }
@@ -1457,14 +1363,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/verification/initiate": {
+        "/email-templates/preview": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "管理员调用此接口后，系统创建一个批处理任务来为目标员工生成 VerificationTokens 并发送邮件。接口立即返回批处理ID。",
+                "description": "以示例数据渲染通知模板，返回标题、HTML 正文和纯文本正文。subject、htmlBody、textBody 均为空时预览当前生效的模板（该语言没有模板时为 zh-CN 模板），否则预览提交的模板，不会保存。",
                 "consumes": [
                     "application/json"
                 ],
@@ -1472,23 +1378,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Verification"
+                    "EmailTemplates"
                 ],
-                "summary": "发起号码使用确认流程 (异步)",
+                "summary": "预览通知模板",
                 "parameters": [
                     {
-                        "description": "请求体",
-                        "name": "body",
+                        "description": "模板名称、语言及可选的待预览模板",
+                        "name": "preview",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.InitiateVerificationRequest"
+                            "$ref": "#/definitions/models.PreviewEmailTemplatePayload"
                         }
                     }
                 ],
                 "responses": {
-                    "202": {
-                        "description": "请求已接受，批处理任务已创建",
+                    "200": {
+                        "description": "渲染结果",
                         "schema": {
                             "allOf": [
                                 {
@@ -1498,7 +1404,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/handlers.InitiateVerificationResponse"
+                                            "$ref": "#/definitions/models.EmailTemplatePreview"
                                         }
                                     }
                                 }
@@ -1506,7 +1412,19 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "请求参数无效或错误",
+                        "description": "请求参数错误、未知的模板名称或模板无效",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未认证或 Token 无效/过期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "权限不足",
                         "schema": {
                             "$ref": "#/definitions/utils.APIErrorResponse"
                         }
@@ -1520,52 +1438,130 @@ const docTemplate = `{
                 }
             }
         },
-        "/verification/submit": {
-            "post": {
-                "description": "用户提交其号码确认结果，包括\"确认使用\"或\"报告问题\"的号码，以及可能上报的未在系统中列出但实际在使用的号码",
-                "consumes": [
-                    "application/json"
+        "/email-templates/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Verification"
+                    "EmailTemplates"
                 ],
-                "summary": "提交号码确认结果",
+                "summary": "获取通知模板详情",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "验证令牌 - 从邮件链接中获取的token参数",
-                        "name": "token",
-                        "in": "query",
+                        "type": "integer",
+                        "description": "模板ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "通知模板",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.EmailTemplate"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
                     },
-                    {
-                        "description": "请求体，包含 verifiedNumbers（必填，号码ID、动作类型、用途purpose、可选的备注）和 unlistedNumbersReported（可选，用户报告的未列出号码，需包含phoneNumber和必填的purpose）",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
+                    "400": {
+                        "description": "无效的模板ID",
                         "schema": {
-                            "$ref": "#/definitions/models.VerificationSubmission"
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未认证或 Token 无效/过期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "权限不足",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "通知模板未找到",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
                         }
                     }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "删除数据库中的模板，之后恢复使用模板文件或内置模板。",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "EmailTemplates"
+                ],
+                "summary": "删除通知模板",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "模板ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
                 "responses": {
                     "200": {
-                        "description": "提交成功",
+                        "description": "删除成功",
                         "schema": {
                             "$ref": "#/definitions/utils.SuccessResponse"
                         }
                     },
                     "400": {
-                        "description": "请求参数无效",
+                        "description": "无效的模板ID",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未认证或 Token 无效/过期",
                         "schema": {
                             "$ref": "#/definitions/utils.APIErrorResponse"
                         }
                     },
                     "403": {
-                        "description": "令牌无效或已过期",
+                        "description": "权限不足",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "通知模板未找到",
                         "schema": {
                             "$ref": "#/definitions/utils.APIErrorResponse"
                         }
@@ -1578,310 +1574,4876 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "handlers.BatchImportErrorDetail": {
-            "type": "object",
-            "properties": {
-                "reason": {
-                    "description": "错误原因",
-                    "type": "string"
-                },
-                "rowData": {
-                    "description": "可选，原始行数据",
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "rowNumber": {
-                    "description": "CSV中的原始行号 (从1开始计数，包括表头)",
-                    "type": "integer"
-                }
-            }
         },
-        "handlers.BatchImportMobileNumberErrorDetail": {
-            "type": "object",
-            "properties": {
-                "reason": {
-                    "description": "错误原因",
-                    "type": "string"
-                },
-                "rowData": {
-                    "description": "可选，原始行数据",
-                    "type": "array",
-                    "items": {
-                        "type": "string"
+        "/email-templates/{id}/update": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
                     }
-                },
-                "rowNumber": {
+                ],
+                "description": "更新模板的标题或正文，所有字段均可选，正文为空字符串表示清除。更新后的模板会先以示例数据渲染校验。",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "EmailTemplates"
+                ],
+                "summary": "更新通知模板",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "模板ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "要更新的模板字段",
+                        "name": "template",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateEmailTemplatePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "更新后的模板",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.EmailTemplate"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误或模板无效",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未认证或 Token 无效/过期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "权限不足",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "通知模板未找到",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/employees": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "根据查询参数获取员工列表，支持分页、搜索和筛选",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Employees"
+                ],
+                "summary": "获取员工列表",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "每页数量",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "排序字段 (例如: employeeId, fullName, createdAt)",
+                        "name": "sortBy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "\"desc\"",
+                        "description": "排序顺序 ('asc'或'desc')",
+                        "name": "sortOrder",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "搜索关键词 (匹配姓名、工号)",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "在职状态筛选 ('Active'或'Departed')",
+                        "name": "employmentStatus",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "部门ID筛选，优先于部门名称",
+                        "name": "departmentId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "部门名称或别名筛选",
+                        "name": "department",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "按部门筛选时是否包含下级部门",
+                        "name": "includeSubDepartments",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功响应，包含员工列表和分页信息",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.PagedEmployeesData"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未认证或 Token 无效/过期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "从请求体绑定数据并验证，数据保存到数据库。员工工号由系统自动生成。支持设置可选的入职日期（格式：YYYY-MM-DD）。\n部门按名称或别名匹配已有部门，员工保存规范的部门名称并关联部门ID。",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Employees"
+                ],
+                "summary": "新增一个员工",
+                "parameters": [
+                    {
+                        "description": "员工信息。包含必填的姓名，可选的手机号、邮箱、部门和入职日期",
+                        "name": "employee",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreateEmployeePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "创建成功的员工对象",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Employee"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误、数据校验失败、入职日期格式无效或部门不存在",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未认证或 Token 无效/过期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "手机号或邮箱已存在",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/employees/directory-sync": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "从 LDAP/Active Directory 读取用户，按配置的属性（默认邮箱）与员工匹配：目录中新增的用户新建为员工，姓名、手机号码、邮箱或部门有变化的员工更新信息，目录账号已禁用或已从目录删除的员工办理离职。\n员工的新建、更新和离职与手动操作的校验一致；仍在使用号码的员工不会自动离职，在报告中记为失败，需要手动指定号码处置方式。目录中不存在的部门会自动创建。\n需办理的离职数超过上限时不办理任何离职。dryRun 为 true 时只生成报告，不修改员工数据。同一时间只能进行一次同步。",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Employees"
+                ],
+                "summary": "从员工目录同步员工",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "是否只生成报告",
+                        "name": "dryRun",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "同步报告",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.DirectorySyncRun"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误或未配置员工目录服务器",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未认证或 Token 无效/过期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "权限不足",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "已有同步正在进行",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "读取员工目录失败",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/employees/directory-sync/runs": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "按开始时间倒序返回最近的目录同步记录及各类计数，不含报告条目。",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Employees"
+                ],
+                "summary": "获取员工目录同步记录",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "返回的记录数 (1-100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功响应，包含同步记录列表",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.DirectorySyncRun"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未认证或 Token 无效/过期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "权限不足",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/employees/directory-sync/runs/{runId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "返回同步记录及报告条目。报告列出新建、更新、离职、跳过和失败的员工，未变更的员工只计数。",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Employees"
+                ],
+                "summary": "获取一次员工目录同步的报告",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "同步记录ID",
+                        "name": "runId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "同步报告",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.DirectorySyncRun"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "无效的同步记录ID",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未认证或 Token 无效/过期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "权限不足",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "同步记录未找到",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/employees/import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "通过上传 CSV 文件批量导入员工。CSV文件必须包含表头：fullName,phoneNumber,email,department,hireDate。列顺序必须一致。fullName为必填，其他字段可为空。hireDate格式为YYYY-MM-DD。支持GBK和UTF-8编码。",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Employees"
+                ],
+                "summary": "批量导入员工数据 (CSV)",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "包含员工数据的 CSV 文件。表头: fullName,phoneNumber,email,department,hireDate",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "导入结果摘要，包含成功和失败的详细信息",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.BatchImportResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求错误，例如文件未提供、文件格式错误、CSV表头不匹配或数据格式错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未认证或 Token 无效/过期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/employees/scheduled-departures": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "获取所有尚未办理的预约离职，按离职日期升序。lastError 为定时任务最近一次办理失败的原因，失败后每次检查时会重试。",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Employees"
+                ],
+                "summary": "获取预约离职列表",
+                "responses": {
+                    "200": {
+                        "description": "预约离职列表",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.ScheduledDepartureResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "未认证或 Token 无效/过期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "权限不足",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/employees/{employeeId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "根据路径参数员工业务工号获取单个员工的完整信息，包含其作为\"办卡人\"和\"当前使用人\"的号码简要列表。",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Employees"
+                ],
+                "summary": "获取指定业务工号的员工详情",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "员工业务工号",
+                        "name": "employeeId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功响应，包含员工详情",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.EmployeeDetailResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "无效的员工工号格式 (保留，以防未来有格式校验)",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未认证或 Token 无效/过期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "员工未找到",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/employees/{employeeId}/departure-preview": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "按号码处置方案模拟办理离职，返回员工每个使用中号码的处置结果以及将变为风险待核实的办卡人号码，不修改任何数据。\n未提供处置方式或无法处置的号码在结果中带有 error，canDepart 表示按当前方案能否办理离职。",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Employees"
+                ],
+                "summary": "预览员工离职的号码处置结果",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "员工业务工号",
+                        "name": "employeeId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "离职日期及号码处置方案",
+                        "name": "departure",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.DeparturePreviewPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "离职预览结果",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.DeparturePreviewResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误、日期格式无效或员工已离职",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未认证或 Token 无效/过期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "权限不足",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "员工未找到",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/employees/{employeeId}/number-history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "汇总员工使用过的号码、作为原办卡人或新办卡人的变更记录以及其提交的号码确认，按时间升序排列。",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Employees"
+                ],
+                "summary": "获取员工的号码历史",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "员工业务工号",
+                        "name": "employeeId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功响应，包含员工号码历史",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.EmployeeNumberHistoryResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "未认证或 Token 无效/过期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "权限不足",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "员工未找到",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/employees/{employeeId}/rehire": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "将离职员工恢复为在职，员工业务工号不变。原入职、离职日期记入任职历史（见员工详情的 employmentPeriods），复职日期作为新的入职日期，离职日期清空。\n可同时恢复其作为办卡人的风险待核实号码：restoreAllNumbers 恢复全部，restorePhoneNumbers 恢复指定号码。号码恢复为进入风险待核实前的状态，原为待注销的号码按宽限期重新计算计划注销日期。\n未恢复的号码保持风险待核实，仍可通过风险号码处理。",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Employees"
+                ],
+                "summary": "办理离职员工复职",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "员工业务工号",
+                        "name": "employeeId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "复职日期及需要恢复的号码",
+                        "name": "rehire",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RehirePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "复职后的员工信息及各号码的恢复情况",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.RehireResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误、复职日期早于离职日期或指定的号码不可恢复",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未认证或 Token 无效/过期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "权限不足",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "员工未找到",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "员工不是离职状态",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/employees/{employeeId}/rehire-preview": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "列出离职员工作为办卡人的风险待核实号码，以及复职时恢复各号码将回到的状态（即号码进入风险待核实前的状态），不修改任何数据。",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Employees"
+                ],
+                "summary": "预览离职员工复职可恢复的号码",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "员工业务工号",
+                        "name": "employeeId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "员工当前信息及可恢复的号码",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.RehireResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "未认证或 Token 无效/过期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "权限不足",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "员工未找到",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "员工不是离职状态",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/employees/{employeeId}/update": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "根据员工业务工号更新员工的姓名、手机号码、邮箱、部门、入职日期、在职状态或离职日期。所有字段都是可选的，至少需要提供一个字段进行更新。手机号码和邮箱为空字符串时清除，不能与其他员工重复。入职日期和离职日期格式为 YYYY-MM-DD。在职状态允许值为 'Active' 或 'Departed'。\n办理离职时，员工每个使用中的号码都必须在 numberDispositions 中提供处置方式: reclaim（回收）、transfer（转移给 transferToEmployeeId）或 schedule_deactivation（回收并计划注销）。号码处置、办卡人号码转为风险号码与状态变更在同一事务中完成，任一号码处置失败则全部回滚。\n离职日期在今天之后时只登记预约离职（员工保持在职，返回的员工带有离职日期），由定时任务在离职日期按处置方案办理，登记时按当前号码校验处置方案，使用中的号码缺少或有无效的处置方式时返回 400；将员工更新为在职即取消预约。\n已离职的员工不能通过本接口更新为在职，须通过 POST /employees/{employeeId}/rehire 办理复职，否则返回 409。",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Employees"
+                ],
+                "summary": "更新指定业务工号的员工信息",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "员工业务工号",
+                        "name": "employeeId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "要更新的员工字段。可包含姓名、手机号码、邮箱、部门、入职日期、在职状态、离职日期",
+                        "name": "employeeUpdate",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateEmployeePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "更新后的员工对象",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Employee"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误、数据校验失败、日期格式无效、部门不存在或业务逻辑错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未认证或 Token 无效/过期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "员工未找到",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "手机号码或邮箱已被其他员工使用，号码无法按处置方案处置，或离职员工须办理复职",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/integrations/hr/dead-letters": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "按创建时间倒序返回处理失败的 HR 事件（死信），包含原始事件、失败原因和失败次数。默认只返回尚未解决的死信。",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Integrations"
+                ],
+                "summary": "获取处理失败的 HR 事件",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "是否包含已解决的死信",
+                        "name": "includeResolved",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功响应，包含死信列表",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.HREventDeadLetter"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未认证或 Token 无效/过期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "权限不足",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/integrations/hr/dead-letters/{id}/replay": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "重新处理死信中保存的原始事件，用于排除问题（如补建部门、先处置离职员工的号码）之后。处理成功后死信标记为已解决；再次失败时更新死信的失败原因和次数，返回 422。",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Integrations"
+                ],
+                "summary": "重放处理失败的 HR 事件",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "死信ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "事件处理成功，或该事件此前已处理",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.HREventResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "无效的死信ID",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未认证或 Token 无效/过期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "权限不足",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "死信未找到",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "死信已解决，或事件正在处理",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "事件再次处理失败，details 为处理结果",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/integrations/hr/events": {
+            "post": {
+                "description": "接收 HR 系统推送的入职（employee.hired）、调岗（employee.department_changed）和离职（employee.terminated）事件，经由员工服务新建或更新员工，校验规则与手动操作一致。\n请求须携带 X-HR-Timestamp（Unix 秒）和 X-HR-Signature（sha256= 加上以共享密钥对 \"时间戳.请求体\" 计算的 HMAC-SHA256 十六进制值），时间戳与服务器时间的偏差不能超过 HR_WEBHOOK_MAX_CLOCK_SKEW。\n同一事件ID只处理一次，重复推送返回 duplicate；多个实例并发收到同一事件时只有一个处理，其余返回 409，HR 系统稍后重试即可。处理失败的事件保存到死信并返回 202，排除问题后可通过死信重放接口重新处理。\n处理在完成前中断（如服务崩溃）的事件再次推送时不会自动重新处理，而是转入死信，由管理员核对员工数据后重放，避免重复新建员工。",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Integrations"
+                ],
+                "summary": "接收 HR 系统推送的员工事件",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "签名时间戳（Unix 秒）",
+                        "name": "X-HR-Timestamp",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "请求签名，格式 sha256=<hex>",
+                        "name": "X-HR-Signature",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "HR 事件",
+                        "name": "event",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.HREventPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "事件已处理或此前已处理",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.HREventResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "202": {
+                        "description": "事件处理失败，已转入死信",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.HREventResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "事件格式无效",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "签名无效或时间戳超出允许范围",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "请求体过大",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "未配置签名密钥",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "事件正在处理，details 为处理结果",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/mobilenumbers": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "根据查询参数获取手机号码列表，支持分页、搜索和筛选。注意：风险号码(risk_pending)通过专门的风险号码接口获取，此接口不返回风险号码。",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "MobileNumbers"
+                ],
+                "summary": "获取手机号码列表",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "每页数量",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "排序字段 (例如: phoneNumber, applicationDate)",
+                        "name": "sortBy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "排序顺序 ('asc'或'desc')",
+                        "name": "sortOrder",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "搜索关键词 (匹配手机号、使用人、办卡人)",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "号码状态筛选 (例如: 闲置, 使用中，不包括风险号码)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "办卡人当前在职状态筛选 ('Active'或'Departed')",
+                        "name": "applicantStatus",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功响应，包含号码列表和分页信息",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.PagedMobileNumbersData"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "从请求体绑定数据并验证，数据保存到 SQLite 的 MobileNumbers 表中，进行手机号码唯一性校验。",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "MobileNumbers"
+                ],
+                "summary": "新增一个手机号码",
+                "parameters": [
+                    {
+                        "description": "手机号码信息 (日期格式 YYYY-MM-DD)",
+                        "name": "mobileNumber",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreateMobileNumberPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "创建成功的号码对象",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.MobileNumber"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误或数据校验失败",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未认证或 Token 无效/过期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "手机号码已存在",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/mobilenumbers/bulk": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "对一组手机号码（phoneNumbers）或按筛选条件（filter，含义同列表接口，包括风险号码）匹配的号码执行同一操作，单次最多 500 个。\n支持的操作: status_change（需 status）, unassign（可选 reclaimDate）, change_applicant（需 newApplicantEmployeeId）, deactivate, set_vendor（需 vendor）。风险号码的变更办卡人和注销按风险号码处理流程执行。\n每个号码单独执行、单独成败，错误明细与批量导入格式相同。dryRun=true 时执行全部校验后回滚，用于预览；atomic=true 时任一号码失败则整批回滚。",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "MobileNumbers"
+                ],
+                "summary": "批量操作手机号码",
+                "parameters": [
+                    {
+                        "description": "批量操作请求体",
+                        "name": "bulk",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.MobileNumberBulkPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "批量操作结果摘要",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.BulkMobileNumbersResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误、操作类型无效或没有匹配的号码",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未认证或 Token 无效/过期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "权限不足",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/mobilenumbers/import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "通过上传 CSV 文件批量导入手机号码。CSV文件应包含表头：phoneNumber,applicantName,applicationDate,vendor",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "MobileNumbers"
+                ],
+                "summary": "批量导入手机号码数据 (CSV)",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "包含手机号码数据的 CSV 文件 (表头: phoneNumber,applicantName,applicationDate,vendor)",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "导入结果摘要",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.BatchImportMobileNumbersResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求错误，例如文件未提供、文件格式错误或CSV表头不匹配",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未认证或 Token 无效/过期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/mobilenumbers/pending-deactivation": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "列出计划在未来 N 天内（含今天）注销的待注销号码，按计划注销日期升序，便于财务提前准备运营商注销手续。已到期但尚未被定时任务处理的号码也会列出。",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "MobileNumbers"
+                ],
+                "summary": "获取即将注销的号码列表",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 30,
+                        "description": "未来天数 (0-366)",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功响应，包含即将注销的号码列表",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.UpcomingDeactivationsData"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未认证或 Token 无效/过期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/mobilenumbers/risk-pending": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "获取状态为risk_pending的手机号码列表，支持分页、搜索和筛选",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "MobileNumbers"
+                ],
+                "summary": "获取风险号码列表",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "每页数量",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "排序字段 (例如: phoneNumber, applicationDate, status)",
+                        "name": "sortBy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "\"desc\"",
+                        "description": "排序顺序 ('asc'或'desc')",
+                        "name": "sortOrder",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "搜索关键词 (匹配手机号、办卡人姓名、当前使用人姓名)",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "办卡人在职状态筛选 ('Active'或'Departed')",
+                        "name": "applicantStatus",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功响应，包含风险号码列表和分页信息",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.PagedRiskNumbersData"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未认证或 Token 无效/过期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/mobilenumbers/{phoneNumber}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "根据路径参数手机号码字符串获取单个手机号码的完整信息，包括其使用历史。",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "MobileNumbers"
+                ],
+                "summary": "获取指定手机号码的详情",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "手机号码字符串",
+                        "name": "phoneNumber",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功响应，包含号码详情及其使用历史",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.MobileNumberResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "无效的手机号码格式 (保留，以防未来有格式校验)",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "号码未找到",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/mobilenumbers/{phoneNumber}/assign": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "校验目标号码是否为\"闲置\"状态，目标员工是否为\"在职\"状态。更新号码记录，关联当前使用人员工ID，将号码状态改为\"使用中\"。创建一条新的号码使用历史记录。",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "MobileNumbers"
+                ],
+                "summary": "将指定手机号码分配给一个员工",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "手机号码字符串",
+                        "name": "phoneNumber",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "分配信息 (员工业务工号和分配日期和用途 YYYY-MM-DD)",
+                        "name": "assignPayload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.MobileNumberAssignPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功分配后的号码对象",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.MobileNumber"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误 / 无效的日期格式 / 无效的手机号码格式\" // 更新了描述",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未认证或 Token 无效/过期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "手机号码或目标员工工号未找到",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "操作冲突 (例如：号码非闲置，员工非在职)",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/mobilenumbers/{phoneNumber}/cancel-deactivation": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "在计划注销日期到达前取消注销，号码恢复为闲置(idle)状态并清空计划注销日期。只适用于待注销(pending_deactivation)状态的号码。",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "MobileNumbers"
+                ],
+                "summary": "取消待注销号码的注销计划",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "手机号码字符串",
+                        "name": "phoneNumber",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "取消注销后的号码对象",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.MobileNumber"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "无效的手机号码格式",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未认证或 Token 无效/过期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "权限不足",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "手机号码未找到",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "号码不是待注销状态",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/mobilenumbers/{phoneNumber}/handle-risk": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "处理状态为risk_pending的号码，支持变更办卡人、回收号码、注销号码三种操作",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "MobileNumbers"
+                ],
+                "summary": "处理风险号码",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "手机号码字符串",
+                        "name": "phoneNumber",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "处理风险号码的请求体",
+                        "name": "handleRisk",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.HandleRiskNumberPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "处理成功的号码对象",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.MobileNumber"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误、数据校验失败或业务逻辑错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未认证或 Token 无效/过期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "手机号码未找到或员工未找到",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/mobilenumbers/{phoneNumber}/timeline": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "将号码的使用记录、办卡人变更、状态变更和员工确认提交汇总为一个按时间升序排列的事件列表。\n事件类型: usage_started, usage_ended, applicant_changed, status_changed, verification_submission。状态变更来自操作审计日志。",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "MobileNumbers"
+                ],
+                "summary": "获取手机号码的时间线",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "手机号码字符串",
+                        "name": "phoneNumber",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功响应，包含号码时间线",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.NumberTimelineResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "未认证或 Token 无效/过期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "权限不足",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "号码未找到",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/mobilenumbers/{phoneNumber}/transfer": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "在一个事务中结束当前使用人的使用历史，并从生效日期开始为目标员工创建新的使用历史，号码保持\"使用中\"状态，避免先回收再分配造成的使用历史中断。\n号码必须为\"使用中\"状态，目标员工必须在职且不是当前使用人；生效日期不能早于当前使用人的开始使用日期。转移原因记录在新的使用历史中。",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "MobileNumbers"
+                ],
+                "summary": "将使用中的号码转移给另一名员工",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "手机号码字符串",
+                        "name": "phoneNumber",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "转移信息 (目标员工业务工号、生效日期 YYYY-MM-DD 和转移原因)",
+                        "name": "transferPayload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.MobileNumberTransferPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "转移后的号码对象",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.MobileNumber"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误 / 无效的日期格式 / 无效的手机号码格式 / 生效日期早于当前使用开始日期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未认证或 Token 无效/过期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "手机号码或目标员工工号未找到",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "操作冲突 (例如：号码非使用中，员工非在职，目标员工已是当前使用人)",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/mobilenumbers/{phoneNumber}/unassign": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "回收手机号码，即将号码流转为闲置(idle)状态，支持生命周期规则允许进入闲置状态的号码（在用、待注销、风险待核实、用户报告）。对于有当前使用人的号码，会更新使用历史记录的结束时间；对于没有当前使用人的号码，直接设为闲置状态。",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "MobileNumbers"
+                ],
+                "summary": "从当前使用人处回收指定手机号码",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "手机号码字符串",
+                        "name": "phoneNumber",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "回收信息 (可选，包含回收日期 YYYY-MM-DD)",
+                        "name": "unassignPayload",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.MobileNumberUnassignPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功回收后的号码对象",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.MobileNumber"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误 / 无效的日期格式 / 无效的手机号码格式",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未认证或 Token 无效/过期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "手机号码未找到",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "操作冲突 (例如：号码状态不允许回收，或未找到有效的分配记录)",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/mobilenumbers/{phoneNumber}/update": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "更新指定手机号码的信息 (主要用于更新状态、用途、供应商、备注)。状态变更须符合号码生命周期流转规则，不允许时返回 409 及允许的下一状态；变更为闲置、待注销或已注销时会结束当前使用人的使用历史，变更为\"已注销\"时自动记录注销时间。\n变更为\"待注销\"时可指定计划注销日期(scheduledDeactivationDate)，不指定则按宽限期自动计算；待注销号码也可单独修改计划注销日期。到期后由定时任务自动注销。\n注意：风险号码(risk_pending)不允许通过此接口更新，请使用专门的风险处理接口。",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "MobileNumbers"
+                ],
+                "summary": "更新指定手机号码的信息",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "手机号码字符串",
+                        "name": "phoneNumber",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "要更新的手机号码字段",
+                        "name": "mobileNumberUpdate",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.MobileNumberUpdatePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "更新后的号码对象",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.MobileNumber"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误或数据校验失败 / 没有提供任何更新字段 / 无效的手机号码格式",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未认证或 Token 无效/过期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "风险号码不允许通过常规接口更新",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "号码未找到",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "状态流转不被允许",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.APIErrorResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "details": {
+                                            "$ref": "#/definitions/models.InvalidStatusTransitionError"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/outbound-messages": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "分页查询待发送和已发送的通知，按创建时间倒序。可按状态、确认批次和员工工号筛选。\n状态：pending 等待发送或等待重试，sending 正在发送，sent 发送成功，failed 达到最多尝试次数或员工缺少联系方式，可重试。",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "OutboundMessages"
+                ],
+                "summary": "查询通知发送队列",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "每页数量",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "状态 (pending, sending, sent, failed)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "确认批次ID",
+                        "name": "batchId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "员工业务工号",
+                        "name": "employeeId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功响应，包含通知列表和分页信息",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.PagedOutboundMessagesData"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未认证或 Token 无效/过期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "权限不足",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/outbound-messages/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "返回通知的收件人、状态、尝试次数、下次尝试时间和最近一次失败原因，不包含正文。",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "OutboundMessages"
+                ],
+                "summary": "获取通知详情",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "通知ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "通知详情",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.OutboundMessage"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "无效的通知ID",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未认证或 Token 无效/过期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "权限不足",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "通知未找到",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/outbound-messages/{id}/retry": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "将发送失败的通知重新排队，尝试次数从零开始计算，由发送队列尽快发送。所属确认批次会重新变为 InProgress，直到通知发送完成。\n生成时就失败（如通知模板无效）的通知没有可发送的内容，不能重试。",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "OutboundMessages"
+                ],
+                "summary": "重试发送失败的通知",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "通知ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "重新排队后的通知",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.OutboundMessage"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "无效的通知ID或通知没有可发送的内容",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未认证或 Token 无效/过期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "权限不足",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "通知未找到",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "通知不是发送失败状态",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "获取后台管理账号列表，支持分页、按用户名搜索以及按角色和状态筛选。",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "获取系统用户列表",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "每页数量",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "搜索关键词 (匹配用户名)",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "角色筛选",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "状态筛选 ('active'或'disabled')",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功响应，包含用户列表和分页信息",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.PagedUsersData"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未认证或 Token 无效/过期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "创建一个新的后台管理账号，密码使用 bcrypt 哈希存储。角色为 department_manager 时须通过 departmentIds 指定所负责的部门。",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "新增系统用户",
+                "parameters": [
+                    {
+                        "description": "用户信息",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateUserPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "创建成功的用户对象",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.User"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误、角色无效或所负责的部门无效",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未认证或 Token 无效/过期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "用户名已存在",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "获取系统用户详情",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "用户ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "用户详情",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.User"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "无效的用户ID",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未认证或 Token 无效/过期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "用户未找到",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "删除后台管理账号。不允许删除当前登录的账号或最后一个启用状态的管理员。",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "删除系统用户",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "用户ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "删除成功",
+                        "schema": {
+                            "$ref": "#/definitions/utils.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "无效的用户ID或不能删除自己",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未认证或 Token 无效/过期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "用户未找到",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "不能删除最后一个管理员",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/reset-2fa": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "管理员清除指定用户的两步验证设置，用于用户丢失验证设备的情况。",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "重置系统用户的两步验证",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "用户ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "两步验证已重置",
+                        "schema": {
+                            "$ref": "#/definitions/utils.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "无效的用户ID",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未认证或 Token 无效/过期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "用户未找到",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/reset-password": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "管理员为指定用户设置新密码。",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "重置系统用户密码",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "用户ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "新密码",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ResetPasswordPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "密码重置成功",
+                        "schema": {
+                            "$ref": "#/definitions/utils.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未认证或 Token 无效/过期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "用户未找到",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/unlock": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "清除账号因连续登录失败产生的锁定状态和失败计数。",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "解除系统用户登录锁定",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "用户ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "解锁后的用户对象",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.User"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "无效的用户ID",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未认证或 Token 无效/过期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "用户未找到",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/update": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "更新用户的角色、状态（启用/禁用）或所负责的部门。不允许降级或禁用最后一个启用状态的管理员。\n角色为 department_manager 时至少负责一个部门；角色改为其他角色时自动清除所负责的部门。",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "更新系统用户",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "用户ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "要更新的字段",
+                        "name": "userUpdate",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateUserPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "更新后的用户对象",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.User"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误、角色无效或所负责的部门无效",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未认证或 Token 无效/过期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "用户未找到",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "不能降级或禁用最后一个管理员",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/verification/admin/phone-status": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "获取基于手机号码维度的确认流程状态，包括统计摘要和详细信息。部门负责人只能看到所负责部门（含下级部门）员工的数据",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Verification"
+                ],
+                "summary": "获取基于手机号码维度的确认流程状态",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "员工业务工号，用于筛选",
+                        "name": "employee_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "员工业务工号，用于筛选（兼容旧版本）",
+                        "name": "employeeId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "部门ID，用于筛选，优先于部门名称",
+                        "name": "departmentId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "部门名称或别名，用于筛选",
+                        "name": "department",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "部门名称或别名，用于筛选（兼容旧版本）",
+                        "name": "departmentName",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "按部门筛选时是否包含下级部门",
+                        "name": "includeSubDepartments",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功响应",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.PhoneVerificationStatusResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/verification/batch/{batchId}/status": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "获取指定号码确认批处理任务的当前状态、整体进度（包括已处理员工数、令牌生成情况、邮件发送统计：尝试数、成功数、失败数）以及详细的错误报告（例如邮件发送失败的原因）。\n通知由发送队列发送，失败时自动重试；令牌数和发送统计（尝试数、成功数、失败数、待发送数）由发送队列中该批次的通知计算得出，服务重启后不会丢失。\n状态：Pending 正在生成通知，InProgress 通知已全部进入发送队列、正在发送，Completed/CompletedWithErrors 队列中已没有待发送的通知。失败的通知可通过 /outbound-messages/{id}/retry 重试。",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Verification"
+                ],
+                "summary": "获取号码确认批处理任务的状态",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "批处理任务ID",
+                        "name": "batchId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功响应，包含批处理任务详情",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.VerificationBatchTask"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "无效的批处理ID格式",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "批处理任务未找到",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/verification/info": {
+            "get": {
+                "description": "用户点击邮件链接后，前端页面调用此接口获取该用户需确认的号码信息",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Verification"
+                ],
+                "summary": "获取待确认的号码信息",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "验证令牌",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功响应，包含员工姓名、令牌有效期、待验证的号码列表及其状态",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.VerificationInfo"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "令牌无效或已过期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/verification/initiate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "管理员调用此接口后，系统创建一个批处理任务来为目标员工生成 VerificationTokens 并发送邮件。接口立即返回批处理ID。\n批次按 remindAfterDays、remindBeforeExpiryDays 和 maxReminders 自动催办尚未提交确认结果的员工，催办通知的标题带有催办标记；未提供时使用服务端配置的默认值。",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Verification"
+                ],
+                "summary": "发起号码使用确认流程 (异步)",
+                "parameters": [
+                    {
+                        "description": "请求体",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.InitiateVerificationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "请求已接受，批处理任务已创建",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.InitiateVerificationResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数无效或错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/verification/pending/{employeeId}/remind": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "使用员工最近一次未过期的待确认令牌生成催办通知，与催办时间和次数一起加入通知发送队列后返回。通知与自动催办一样由队列按员工偏好的渠道发送（渠道不可用时改用邮件），失败时重试，可通过 GET /outbound-messages 查看发送结果。部门负责人只能催办所负责部门（含下级部门）的员工",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Verification"
+                ],
+                "summary": "催办未确认的员工",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "员工业务工号",
+                        "name": "employeeId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "催办后的待确认信息",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.PendingUserDetail"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "员工未登记可用的邮箱或手机号码",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未认证或 Token 无效/过期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "权限不足",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "该员工没有待确认的号码确认请求",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/verification/submit": {
+            "post": {
+                "description": "用户提交其号码确认结果，包括\"确认使用\"或\"报告问题\"的号码，以及可能上报的未在系统中列出但实际在使用的号码",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Verification"
+                ],
+                "summary": "提交号码确认结果",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "验证令牌 - 从邮件链接中获取的token参数",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "description": "请求体，包含 verifiedNumbers（必填，号码ID、动作类型、用途purpose、可选的备注）和 unlistedNumbersReported（可选，用户报告的未列出号码，需包含phoneNumber和必填的purpose）",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.VerificationSubmission"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "提交成功",
+                        "schema": {
+                            "$ref": "#/definitions/utils.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数无效",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "令牌无效或已过期",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "handlers.BatchImportErrorDetail": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "description": "错误原因",
+                    "type": "string"
+                },
+                "rowData": {
+                    "description": "可选，原始行数据",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "rowNumber": {
+                    "description": "CSV中的原始行号 (从1开始计数，包括表头)",
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.BatchImportMobileNumberErrorDetail": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "description": "错误原因",
+                    "type": "string"
+                },
+                "rowData": {
+                    "description": "可选，原始行数据",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "rowNumber": {
                     "description": "CSV中的原始行号 (从1开始计数，包括表头)",
                     "type": "integer"
                 }
             }
         },
-        "handlers.BatchImportMobileNumbersResponse": {
+        "handlers.BatchImportMobileNumbersResponse": {
+            "type": "object",
+            "properties": {
+                "errorCount": {
+                    "type": "integer"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.BatchImportMobileNumberErrorDetail"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "successCount": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.BatchImportResponse": {
+            "type": "object",
+            "properties": {
+                "errorCount": {
+                    "type": "integer"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.BatchImportErrorDetail"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "successCount": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.BulkMobileNumbersResponse": {
+            "type": "object",
+            "properties": {
+                "atomic": {
+                    "description": "是否整批原子执行",
+                    "type": "boolean"
+                },
+                "dryRun": {
+                    "description": "是否为预览",
+                    "type": "boolean"
+                },
+                "errorCount": {
+                    "type": "integer"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.BatchImportMobileNumberErrorDetail"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "rolledBack": {
+                    "description": "操作是否已回滚（预览或原子执行失败）",
+                    "type": "boolean"
+                },
+                "successCount": {
+                    "type": "integer"
+                },
+                "totalCount": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.CreateEmployeePayload": {
+            "type": "object",
+            "required": [
+                "fullName"
+            ],
+            "properties": {
+                "department": {
+                    "description": "部门名称或别名，必须是已有部门",
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "email": {
+                    "description": "可选，需要是合法的email格式，最大长度255",
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "fullName": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "hireDate": {
+                    "description": "入职日期，可选，格式 YYYY-MM-DD",
+                    "type": "string"
+                },
+                "locale": {
+                    "description": "接收通知的语言，可选 zh-CN（默认）、en",
+                    "type": "string",
+                    "enum": [
+                        "zh-CN",
+                        "en"
+                    ]
+                },
+                "notificationChannel": {
+                    "description": "接收通知的渠道，可选 email（默认）、im、sms；im 和 sms 需要登记手机号码，未登记时改用邮件。im 只在群内发送不含链接的提醒，完整通知仍发送到邮箱",
+                    "type": "string",
+                    "enum": [
+                        "email",
+                        "im",
+                        "sms"
+                    ]
+                },
+                "phoneNumber": {
+                    "description": "手机号：可选，但如果提供，必须是11位数字",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.CreateMobileNumberPayload": {
+            "type": "object",
+            "required": [
+                "applicantEmployeeId",
+                "applicationDate",
+                "phoneNumber"
+            ],
+            "properties": {
+                "applicantEmployeeId": {
+                    "description": "代表员工工号",
+                    "type": "string"
+                },
+                "applicationDate": {
+                    "type": "string"
+                },
+                "phoneNumber": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "purpose": {
+                    "description": "号码用途，可选",
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "remarks": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "status": {
+                    "description": "移除状态验证，在业务层处理",
+                    "type": "string"
+                },
+                "vendor": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "handlers.InitiateVerificationRequest": {
+            "type": "object",
+            "required": [
+                "durationDays",
+                "scope"
+            ],
+            "properties": {
+                "durationDays": {
+                    "type": "integer",
+                    "maximum": 30,
+                    "minimum": 1
+                },
+                "includeSubDepartments": {
+                    "description": "IncludeSubDepartments 仅在 scope 为 department 时有效，为 true 时同时向下级部门的员工发起确认",
+                    "type": "boolean"
+                },
+                "maxReminders": {
+                    "description": "MaxReminders 每位员工最多催办次数（包括手动催办），未提供时使用服务端配置的默认值，0 表示该批次不自动催办",
+                    "type": "integer",
+                    "maximum": 10,
+                    "minimum": 0
+                },
+                "remindAfterDays": {
+                    "description": "RemindAfterDays 发出确认通知后第几天自动催办尚未提交确认结果的员工，未提供时使用服务端配置的默认值，传空数组表示不按此方式催办",
+                    "type": "array",
+                    "maxItems": 10,
+                    "items": {
+                        "type": "integer",
+                        "maximum": 30,
+                        "minimum": 1
+                    }
+                },
+                "remindBeforeExpiryDays": {
+                    "description": "RemindBeforeExpiryDays 链接过期前几天自动催办尚未提交确认结果的员工，未提供时使用服务端配置的默认值，传空数组表示不按此方式催办",
+                    "type": "array",
+                    "maxItems": 10,
+                    "items": {
+                        "type": "integer",
+                        "maximum": 30,
+                        "minimum": 1
+                    }
+                },
+                "scope": {
+                    "type": "string",
+                    "enum": [
+                        "all_users",
+                        "department",
+                        "employee_ids"
+                    ]
+                },
+                "scopeValues": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.InitiateVerificationResponse": {
+            "type": "object",
+            "properties": {
+                "batchId": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.LoginBlockedDetails": {
+            "type": "object",
+            "properties": {
+                "retryAt": {
+                    "description": "允许再次尝试的时间",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.LoginRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "expiresAt": {
+                    "description": "访问令牌过期时间",
+                    "type": "string"
+                },
+                "mfaRequired": {
+                    "description": "用户启用了两步验证时，密码校验通过后只返回以下字段，需携带 MFAToken 调用 /auth/login/2fa",
+                    "type": "boolean"
+                },
+                "mfaToken": {
+                    "type": "string"
+                },
+                "mfaTokenExpiresAt": {
+                    "type": "string"
+                },
+                "refreshToken": {
+                    "description": "刷新令牌，每次刷新后轮换",
+                    "type": "string"
+                },
+                "token": {
+                    "description": "短期访问令牌",
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/handlers.UserInfo"
+                }
+            }
+        },
+        "handlers.LogoutRequest": {
+            "type": "object",
+            "properties": {
+                "refreshToken": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.MFALoginRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "mfaToken"
+            ],
+            "properties": {
+                "code": {
+                    "description": "TOTP 验证码或恢复码",
+                    "type": "string"
+                },
+                "mfaToken": {
+                    "description": "第一步登录返回的临时令牌",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.PagedAuditEventsData": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AuditEvent"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/handlers.PaginationInfo"
+                }
+            }
+        },
+        "handlers.PagedEmployeesData": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Employee"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/handlers.PaginationInfo"
+                }
+            }
+        },
+        "handlers.PagedMobileNumbersData": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MobileNumberResponse"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/handlers.PaginationInfo"
+                }
+            }
+        },
+        "handlers.PagedOutboundMessagesData": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OutboundMessage"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/handlers.PaginationInfo"
+                }
+            }
+        },
+        "handlers.PagedRiskNumbersData": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RiskNumberResponse"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/handlers.PaginationInfo"
+                }
+            }
+        },
+        "handlers.PagedUsersData": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.User"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/handlers.PaginationInfo"
+                }
+            }
+        },
+        "handlers.PaginationInfo": {
+            "type": "object",
+            "properties": {
+                "currentPage": {
+                    "type": "integer"
+                },
+                "pageSize": {
+                    "type": "integer"
+                },
+                "totalItems": {
+                    "type": "integer"
+                },
+                "totalPages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.RecoveryCodesResponse": {
+            "type": "object",
+            "properties": {
+                "recoveryCodes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.RefreshTokenRequest": {
             "type": "object",
+            "required": [
+                "refreshToken"
+            ],
             "properties": {
-                "errorCount": {
-                    "type": "integer"
-                },
-                "errors": {
+                "refreshToken": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.UpcomingDeactivationsData": {
+            "type": "object",
+            "properties": {
+                "items": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/handlers.BatchImportMobileNumberErrorDetail"
+                        "$ref": "#/definitions/models.MobileNumberResponse"
                     }
                 },
-                "message": {
+                "until": {
+                    "description": "统计截止日期（含）",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.UserInfo": {
+            "type": "object",
+            "properties": {
+                "role": {
                     "type": "string"
                 },
-                "successCount": {
-                    "type": "integer"
+                "username": {
+                    "type": "string"
                 }
             }
         },
-        "handlers.BatchImportResponse": {
+        "models.AuditEvent": {
             "type": "object",
             "properties": {
-                "errorCount": {
+                "action": {
+                    "description": "操作类型，如 mobile_number.assign",
+                    "type": "string"
+                },
+                "actorUserId": {
+                    "description": "操作者系统用户ID，系统任务或员工自助操作时为空",
                     "type": "integer"
                 },
-                "errors": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/handlers.BatchImportErrorDetail"
-                    }
+                "actorUsername": {
+                    "description": "操作者用户名（员工自助操作时为员工工号）",
+                    "type": "string"
                 },
-                "message": {
+                "after": {
+                    "description": "变更后的实体快照 (JSON)",
                     "type": "string"
                 },
-                "successCount": {
+                "before": {
+                    "description": "变更前的实体快照 (JSON)",
+                    "type": "string"
+                },
+                "clientIp": {
+                    "description": "请求来源IP",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "description": "发生时间",
+                    "type": "string"
+                },
+                "entityId": {
+                    "description": "实体标识：号码为手机号，员工为工号，用户为ID，确认批次为批次ID",
+                    "type": "string"
+                },
+                "entityType": {
+                    "type": "string"
+                },
+                "id": {
                     "type": "integer"
+                },
+                "source": {
+                    "description": "来源: api, import, bulk, verification, system, directory, hr",
+                    "type": "string"
                 }
             }
         },
-        "handlers.CreateEmployeePayload": {
+        "models.ChangePasswordPayload": {
             "type": "object",
             "required": [
-                "fullName"
+                "newPassword",
+                "oldPassword"
             ],
             "properties": {
-                "department": {
+                "newPassword": {
                     "type": "string",
-                    "maxLength": 255
+                    "maxLength": 72,
+                    "minLength": 8
                 },
-                "email": {
-                    "description": "可选，需要是合法的email格式，最大长度255",
-                    "type": "string",
-                    "maxLength": 255
+                "oldPassword": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ConfirmedPhoneDetail": {
+            "type": "object",
+            "properties": {
+                "confirmedAt": {
+                    "type": "string"
                 },
-                "fullName": {
-                    "type": "string",
-                    "maxLength": 255
+                "confirmedBy": {
+                    "type": "string"
                 },
-                "hireDate": {
-                    "description": "入职日期，可选，格式 YYYY-MM-DD",
+                "currentUser": {
                     "type": "string"
                 },
+                "department": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
                 "phoneNumber": {
-                    "description": "手机号：可选，但如果提供，必须是11位数字",
+                    "type": "string"
+                },
+                "purpose": {
                     "type": "string"
                 }
             }
         },
-        "handlers.CreateMobileNumberPayload": {
+        "models.CreateDepartmentPayload": {
             "type": "object",
             "required": [
-                "applicantEmployeeId",
-                "applicationDate",
-                "phoneNumber"
+                "name"
             ],
             "properties": {
-                "applicantEmployeeId": {
-                    "description": "代表员工工号",
-                    "type": "string"
+                "costCenter": {
+                    "description": "成本中心编码",
+                    "type": "string",
+                    "maxLength": 50
                 },
-                "applicationDate": {
+                "managerEmployeeId": {
+                    "description": "部门负责人员工业务工号",
                     "type": "string"
                 },
-                "phoneNumber": {
+                "name": {
                     "type": "string",
-                    "maxLength": 50
+                    "maxLength": 255
                 },
-                "purpose": {
-                    "description": "号码用途，可选",
+                "parentId": {
+                    "description": "上级部门ID，不提供则为顶级部门",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CreateEmailTemplatePayload": {
+            "type": "object",
+            "required": [
+                "locale",
+                "name",
+                "subject"
+            ],
+            "properties": {
+                "htmlBody": {
+                    "description": "HTML 正文模板",
+                    "type": "string"
+                },
+                "locale": {
+                    "description": "语言",
                     "type": "string",
-                    "maxLength": 255
+                    "enum": [
+                        "zh-CN",
+                        "en"
+                    ]
                 },
-                "remarks": {
+                "name": {
+                    "description": "模板名称，目前支持 verification",
                     "type": "string",
-                    "maxLength": 255
+                    "maxLength": 50
                 },
-                "status": {
-                    "description": "移除状态验证，在业务层处理",
+                "subject": {
+                    "description": "邮件标题模板",
                     "type": "string"
                 },
-                "vendor": {
-                    "type": "string",
-                    "maxLength": 100
+                "textBody": {
+                    "description": "纯文本正文模板",
+                    "type": "string"
                 }
             }
         },
-        "handlers.InitiateVerificationRequest": {
+        "models.CreateUserPayload": {
             "type": "object",
             "required": [
-                "durationDays",
-                "scope"
+                "password",
+                "role",
+                "username"
             ],
             "properties": {
-                "durationDays": {
-                    "type": "integer",
-                    "maximum": 30,
-                    "minimum": 1
+                "departmentIds": {
+                    "description": "所负责的部门ID，角色为 department_manager 时必填，其他角色不可提供",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 },
-                "scope": {
+                "password": {
+                    "description": "bcrypt 最多处理72字节",
                     "type": "string",
-                    "enum": [
-                        "all_users",
-                        "department",
-                        "employee_ids"
-                    ]
+                    "maxLength": 72,
+                    "minLength": 8
                 },
-                "scopeValues": {
+                "role": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "models.Department": {
+            "type": "object",
+            "properties": {
+                "costCenter": {
+                    "description": "成本中心编码",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "managerEmployeeId": {
+                    "description": "部门负责人员工业务工号",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "部门名称，全局唯一",
+                    "type": "string"
+                },
+                "parentId": {
+                    "description": "上级部门ID，顶级部门为空",
+                    "type": "integer"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DepartmentResponse": {
+            "type": "object",
+            "properties": {
+                "aliases": {
+                    "description": "部门别名",
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
+                },
+                "children": {
+                    "description": "下级部门，仅部门树中返回",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DepartmentResponse"
+                    }
+                },
+                "costCenter": {
+                    "description": "成本中心编码",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "employeeCount": {
+                    "description": "直属员工数量（不含下级部门）",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "managerEmployeeId": {
+                    "description": "部门负责人员工业务工号",
+                    "type": "string"
+                },
+                "managerName": {
+                    "description": "部门负责人姓名",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "部门名称，全局唯一",
+                    "type": "string"
+                },
+                "parentId": {
+                    "description": "上级部门ID，顶级部门为空",
+                    "type": "integer"
+                },
+                "parentName": {
+                    "description": "上级部门名称",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
                 }
             }
         },
-        "handlers.InitiateVerificationResponse": {
+        "models.DepartureNumberAction": {
+            "type": "string",
+            "enum": [
+                "reclaim",
+                "transfer",
+                "schedule_deactivation"
+            ],
+            "x-enum-comments": {
+                "DepartureReclaim": "回收为闲置",
+                "DepartureScheduleDeactivation": "回收并计划注销",
+                "DepartureTransfer": "转移给其他在职员工"
+            },
+            "x-enum-varnames": [
+                "DepartureReclaim",
+                "DepartureTransfer",
+                "DepartureScheduleDeactivation"
+            ]
+        },
+        "models.DepartureNumberOutcome": {
             "type": "object",
             "properties": {
-                "batchId": {
+                "action": {
+                    "description": "方案中的处置方式，未提供时为空",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.DepartureNumberAction"
+                        }
+                    ]
+                },
+                "error": {
+                    "description": "无法按方案处置的原因",
+                    "type": "string"
+                },
+                "phoneNumber": {
+                    "type": "string"
+                },
+                "resultStatus": {
+                    "description": "处置后的号码状态",
+                    "type": "string"
+                },
+                "transferToEmployeeId": {
+                    "description": "接收号码的员工业务工号",
                     "type": "string"
                 }
             }
         },
-        "handlers.LoginRequest": {
+        "models.DeparturePreviewPayload": {
             "type": "object",
-            "required": [
-                "password",
-                "username"
-            ],
             "properties": {
-                "password": {
-                    "type": "string"
+                "numberDispositions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.NumberDisposition"
+                    }
                 },
-                "username": {
+                "terminationDate": {
+                    "description": "离职日期，默认当天",
                     "type": "string"
                 }
             }
         },
-        "handlers.LoginResponse": {
+        "models.DeparturePreviewResponse": {
             "type": "object",
             "properties": {
-                "token": {
+                "canDepart": {
+                    "description": "按当前方案能否办理离职",
+                    "type": "boolean"
+                },
+                "employeeId": {
+                    "type": "string"
+                },
+                "fullName": {
                     "type": "string"
                 },
-                "user": {
-                    "$ref": "#/definitions/handlers.UserInfo"
-                }
-            }
-        },
-        "handlers.PagedEmployeesData": {
-            "type": "object",
-            "properties": {
-                "items": {
+                "numbers": {
+                    "description": "员工使用中的号码及方案中的其他号码",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DepartureNumberOutcome"
+                    }
+                },
+                "riskPendingNumbers": {
+                    "description": "因办卡人离职将变为风险待核实的号码",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Employee"
+                        "type": "string"
                     }
                 },
-                "pagination": {
-                    "$ref": "#/definitions/handlers.PaginationInfo"
+                "terminationDate": {
+                    "description": "离职日期，也是号码回收、转移的生效日期",
+                    "type": "string"
                 }
             }
         },
-        "handlers.PagedMobileNumbersData": {
+        "models.DirectorySyncAction": {
+            "type": "string",
+            "enum": [
+                "create",
+                "update",
+                "depart",
+                "skip",
+                "fail"
+            ],
+            "x-enum-comments": {
+                "DirectorySyncCreate": "新建员工",
+                "DirectorySyncDepart": "办理离职",
+                "DirectorySyncFail": "处理失败",
+                "DirectorySyncSkip": "需要人工处理，未做变更",
+                "DirectorySyncUpdate": "更新员工信息"
+            },
+            "x-enum-varnames": [
+                "DirectorySyncCreate",
+                "DirectorySyncUpdate",
+                "DirectorySyncDepart",
+                "DirectorySyncSkip",
+                "DirectorySyncFail"
+            ]
+        },
+        "models.DirectorySyncItem": {
             "type": "object",
             "properties": {
-                "items": {
+                "action": {
+                    "$ref": "#/definitions/models.DirectorySyncAction"
+                },
+                "changes": {
+                    "description": "变更的字段",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.MobileNumberResponse"
+                        "type": "string"
                     }
                 },
-                "pagination": {
-                    "$ref": "#/definitions/handlers.PaginationInfo"
+                "directoryDn": {
+                    "description": "目录用户 DN，员工已不在目录中时为空",
+                    "type": "string"
+                },
+                "employeeId": {
+                    "description": "员工业务工号，新建失败时为空",
+                    "type": "string"
+                },
+                "fullName": {
+                    "description": "姓名",
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "跳过或失败的原因",
+                    "type": "string"
                 }
             }
         },
-        "handlers.PagedRiskNumbersData": {
+        "models.DirectorySyncRun": {
             "type": "object",
             "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "createdCount": {
+                    "type": "integer"
+                },
+                "departedCount": {
+                    "type": "integer"
+                },
+                "dryRun": {
+                    "type": "boolean"
+                },
+                "errorMessage": {
+                    "description": "同步整体失败或未办理离职的原因",
+                    "type": "string"
+                },
+                "failedCount": {
+                    "type": "integer"
+                },
+                "finishedAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
                 "items": {
+                    "description": "报告条目，仅在查询单次同步详情时返回",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.RiskNumberResponse"
+                        "$ref": "#/definitions/models.DirectorySyncItem"
                     }
                 },
-                "pagination": {
-                    "$ref": "#/definitions/handlers.PaginationInfo"
-                }
-            }
-        },
-        "handlers.PaginationInfo": {
-            "type": "object",
-            "properties": {
-                "currentPage": {
+                "scannedCount": {
+                    "description": "从目录读取的用户数",
                     "type": "integer"
                 },
-                "pageSize": {
+                "skippedCount": {
                     "type": "integer"
                 },
-                "totalItems": {
+                "startedAt": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.DirectorySyncStatus"
+                },
+                "trigger": {
+                    "$ref": "#/definitions/models.DirectorySyncTrigger"
+                },
+                "triggeredBy": {
+                    "description": "触发同步的用户名，定时任务为 system",
+                    "type": "string"
+                },
+                "unchangedCount": {
                     "type": "integer"
                 },
-                "totalPages": {
+                "updatedCount": {
                     "type": "integer"
                 }
             }
         },
-        "handlers.UserInfo": {
+        "models.DirectorySyncStatus": {
+            "type": "string",
+            "enum": [
+                "running",
+                "succeeded",
+                "partial",
+                "failed"
+            ],
+            "x-enum-comments": {
+                "DirectorySyncFailed": "读取目录失败，未处理任何条目",
+                "DirectorySyncPartial": "部分条目处理失败，或因超过离职上限未办理离职",
+                "DirectorySyncRunning": "同步中",
+                "DirectorySyncSucceeded": "全部条目处理成功"
+            },
+            "x-enum-varnames": [
+                "DirectorySyncRunning",
+                "DirectorySyncSucceeded",
+                "DirectorySyncPartial",
+                "DirectorySyncFailed"
+            ]
+        },
+        "models.DirectorySyncTrigger": {
+            "type": "string",
+            "enum": [
+                "manual",
+                "scheduled"
+            ],
+            "x-enum-comments": {
+                "DirectorySyncManual": "管理员手动触发",
+                "DirectorySyncScheduled": "定时任务触发"
+            },
+            "x-enum-varnames": [
+                "DirectorySyncManual",
+                "DirectorySyncScheduled"
+            ]
+        },
+        "models.DisableTOTPPayload": {
             "type": "object",
+            "required": [
+                "code",
+                "password"
+            ],
             "properties": {
-                "role": {
+                "code": {
+                    "description": "TOTP 验证码或恢复码",
                     "type": "string"
                 },
-                "username": {
+                "password": {
                     "type": "string"
                 }
             }
         },
-        "models.ConfirmedPhoneDetail": {
+        "models.EmailTemplate": {
             "type": "object",
             "properties": {
-                "confirmedAt": {
+                "createdAt": {
                     "type": "string"
                 },
-                "confirmedBy": {
+                "htmlBody": {
+                    "description": "HTML 正文模板",
                     "type": "string"
                 },
-                "currentUser": {
+                "id": {
+                    "type": "integer"
+                },
+                "locale": {
+                    "description": "语言 ('zh-CN', 'en')",
                     "type": "string"
                 },
-                "department": {
+                "name": {
+                    "description": "模板名称，如 verification",
                     "type": "string"
                 },
-                "id": {
-                    "type": "integer"
+                "subject": {
+                    "description": "邮件标题模板",
+                    "type": "string"
                 },
-                "phoneNumber": {
+                "textBody": {
+                    "description": "纯文本正文模板，作为邮件的纯文本版本，也用于 IM 和短信",
                     "type": "string"
                 },
-                "purpose": {
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.EmailTemplatePreview": {
+            "type": "object",
+            "properties": {
+                "html": {
+                    "type": "string"
+                },
+                "locale": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "source": {
+                    "description": "模板来源: database, file, builtin, draft",
+                    "type": "string"
+                },
+                "subject": {
+                    "type": "string"
+                },
+                "text": {
                     "type": "string"
                 }
             }
@@ -1897,7 +6459,15 @@ const docTemplate = `{
                     "format": "date-time"
                 },
                 "department": {
-                    "description": "部门",
+                    "description": "部门名称，与 DepartmentID 对应的部门保持一致",
+                    "type": "string"
+                },
+                "departmentId": {
+                    "description": "所属部门ID",
+                    "type": "integer"
+                },
+                "directoryDn": {
+                    "description": "关联的目录用户 DN，由目录同步写入",
                     "type": "string"
                 },
                 "email": {
@@ -1923,6 +6493,14 @@ const docTemplate = `{
                 "id": {
                     "type": "integer"
                 },
+                "locale": {
+                    "description": "接收通知的语言 ('zh-CN', 'en')",
+                    "type": "string"
+                },
+                "notificationChannel": {
+                    "description": "接收通知的渠道 ('email', 'im', 'sms')",
+                    "type": "string"
+                },
                 "phoneNumber": {
                     "description": "员工手机号码, 11位, 可选, 唯一 (NULLS NOT DISTINCT)",
                     "type": "string"
@@ -1945,9 +6523,19 @@ const docTemplate = `{
                 "department": {
                     "type": "string"
                 },
+                "departmentId": {
+                    "type": "integer"
+                },
                 "employeeId": {
                     "type": "string"
                 },
+                "employmentPeriods": {
+                    "description": "复职前已结束的任职期间，按离职日期升序",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.EmploymentPeriod"
+                    }
+                },
                 "employmentStatus": {
                     "type": "string"
                 },
@@ -1961,27 +6549,220 @@ const docTemplate = `{
                         "$ref": "#/definitions/models.MobileNumberBasicInfo"
                     }
                 },
-                "hireDate": {
+                "hireDate": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "terminationDate": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "usingMobileNumbers": {
+                    "description": "作为当前使用人的号码列表",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MobileNumberBasicInfo"
+                    }
+                }
+            }
+        },
+        "models.EmployeeNumberHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "employeeId": {
+                    "type": "string"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.NumberTimelineEvent"
+                    }
+                },
+                "fullName": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.EmploymentPeriod": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "employeeId": {
+                    "description": "员工业务工号",
+                    "type": "string"
+                },
+                "hireDate": {
+                    "description": "该期间的入职日期，未登记时为空",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "terminationDate": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.HREventData": {
+            "type": "object",
+            "properties": {
+                "department": {
+                    "description": "部门名称或别名，调岗事件必填",
+                    "type": "string"
+                },
+                "email": {
+                    "description": "邮箱，未提供 employeeId 时用于确定员工",
+                    "type": "string"
+                },
+                "employeeId": {
+                    "description": "员工业务工号",
+                    "type": "string"
+                },
+                "fullName": {
+                    "description": "姓名，入职事件必填",
+                    "type": "string"
+                },
+                "hireDate": {
+                    "description": "入职日期，格式 YYYY-MM-DD",
+                    "type": "string"
+                },
+                "phoneNumber": {
+                    "description": "手机号码，仅入职事件使用",
+                    "type": "string"
+                },
+                "terminationDate": {
+                    "description": "离职日期，格式 YYYY-MM-DD，默认当天",
+                    "type": "string"
+                }
+            }
+        },
+        "models.HREventDeadLetter": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "description": "处理失败的次数",
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "eventId": {
+                    "type": "string"
+                },
+                "eventType": {
+                    "$ref": "#/definitions/models.HREventType"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lastError": {
+                    "description": "最近一次处理失败的原因",
+                    "type": "string"
+                },
+                "payload": {
+                    "description": "原始事件 JSON",
+                    "type": "string"
+                },
+                "replayedAt": {
+                    "description": "最近一次手动重放的时间",
+                    "type": "string"
+                },
+                "resolvedAt": {
+                    "description": "处理成功的时间，为空表示尚未解决",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.HREventPayload": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/models.HREventData"
+                },
+                "id": {
+                    "description": "事件ID，最长100个字符，同一事件重复推送时只处理一次",
                     "type": "string"
                 },
-                "id": {
+                "occurredAt": {
+                    "description": "事件在 HR 系统中发生的时间",
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/models.HREventType"
+                }
+            }
+        },
+        "models.HREventResult": {
+            "type": "object",
+            "properties": {
+                "deadLetterId": {
+                    "description": "处理失败时对应的死信ID，可用于重放",
                     "type": "integer"
                 },
-                "terminationDate": {
+                "employeeId": {
+                    "description": "事件对应的员工业务工号",
                     "type": "string"
                 },
-                "updatedAt": {
+                "error": {
+                    "description": "处理失败的原因",
                     "type": "string"
                 },
-                "usingMobileNumbers": {
-                    "description": "作为当前使用人的号码列表",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.MobileNumberBasicInfo"
-                    }
+                "eventId": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.HREventStatus"
                 }
             }
         },
+        "models.HREventStatus": {
+            "type": "string",
+            "enum": [
+                "processed",
+                "duplicate",
+                "processing",
+                "failed"
+            ],
+            "x-enum-comments": {
+                "HREventDuplicate": "事件此前已处理成功，本次未做任何变更",
+                "HREventFailed": "处理失败，已转入死信",
+                "HREventInProgress": "事件正在由其他请求处理，本次未做任何变更",
+                "HREventProcessed": "处理成功"
+            },
+            "x-enum-varnames": [
+                "HREventProcessed",
+                "HREventDuplicate",
+                "HREventInProgress",
+                "HREventFailed"
+            ]
+        },
+        "models.HREventType": {
+            "type": "string",
+            "enum": [
+                "employee.hired",
+                "employee.department_changed",
+                "employee.terminated"
+            ],
+            "x-enum-comments": {
+                "HREventDepartmentChanged": "调岗，变更员工部门",
+                "HREventHired": "入职，新建员工",
+                "HREventTerminated": "离职，离职日期在未来时登记为预约离职"
+            },
+            "x-enum-varnames": [
+                "HREventHired",
+                "HREventDepartmentChanged",
+                "HREventTerminated"
+            ]
+        },
         "models.HandleRiskNumberPayload": {
             "type": "object",
             "required": [
@@ -2003,6 +6784,40 @@ const docTemplate = `{
                 }
             }
         },
+        "models.InvalidStatusTransitionError": {
+            "type": "object",
+            "properties": {
+                "allowedNextStatuses": {
+                    "description": "当前状态允许进入的下一状态",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.NumberStatus"
+                    }
+                },
+                "from": {
+                    "$ref": "#/definitions/models.NumberStatus"
+                },
+                "to": {
+                    "$ref": "#/definitions/models.NumberStatus"
+                }
+            }
+        },
+        "models.MergeDepartmentsPayload": {
+            "type": "object",
+            "required": [
+                "sourceDepartmentIds"
+            ],
+            "properties": {
+                "sourceDepartmentIds": {
+                    "description": "被合并的部门ID，合并后删除，名称记为目标部门的别名",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
         "models.MobileNumber": {
             "type": "object",
             "required": [
@@ -2050,10 +6865,18 @@ const docTemplate = `{
                     "type": "string",
                     "maxLength": 255
                 },
+                "scheduledDeactivationDate": {
+                    "description": "ScheduledDeactivationDate 计划注销日期，仅待注销号码有值，到期后由定时任务自动注销",
+                    "type": "string"
+                },
                 "status": {
                     "description": "使用英文常量存储",
                     "type": "string"
                 },
+                "statusBeforeRisk": {
+                    "description": "进入风险待核实前的状态，办卡人复职时据此恢复号码",
+                    "type": "string"
+                },
                 "updatedAt": {
                     "type": "string"
                 },
@@ -2100,6 +6923,78 @@ const docTemplate = `{
                 }
             }
         },
+        "models.MobileNumberBulkFilter": {
+            "type": "object",
+            "properties": {
+                "applicantStatus": {
+                    "description": "办卡人在职状态 ('Active'或'Departed')",
+                    "type": "string"
+                },
+                "search": {
+                    "description": "匹配手机号、使用人、办卡人",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "号码状态",
+                    "type": "string"
+                }
+            }
+        },
+        "models.MobileNumberBulkPayload": {
+            "type": "object",
+            "required": [
+                "action"
+            ],
+            "properties": {
+                "action": {
+                    "description": "status_change, unassign, change_applicant, deactivate, set_vendor",
+                    "type": "string"
+                },
+                "atomic": {
+                    "description": "任一号码失败时整批回滚",
+                    "type": "boolean"
+                },
+                "dryRun": {
+                    "description": "仅预览，执行后回滚",
+                    "type": "boolean"
+                },
+                "filter": {
+                    "$ref": "#/definitions/models.MobileNumberBulkFilter"
+                },
+                "newApplicantEmployeeId": {
+                    "description": "change_applicant: 新办卡人员工业务工号",
+                    "type": "string"
+                },
+                "phoneNumbers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "reclaimDate": {
+                    "description": "unassign: 回收日期，默认当天",
+                    "type": "string"
+                },
+                "remarks": {
+                    "description": "change_applicant/deactivate: 备注",
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "scheduledDeactivationDate": {
+                    "description": "status_change: 变更为待注销时的计划注销日期",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "status_change: 目标状态",
+                    "type": "string"
+                },
+                "vendor": {
+                    "description": "set_vendor: 运营商",
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
         "models.MobileNumberResponse": {
             "type": "object",
             "properties": {
@@ -2145,6 +7040,10 @@ const docTemplate = `{
                 "remarks": {
                     "type": "string"
                 },
+                "scheduledDeactivationDate": {
+                    "description": "计划注销日期",
+                    "type": "string"
+                },
                 "status": {
                     "description": "英文状态值",
                     "type": "string"
@@ -2164,6 +7063,28 @@ const docTemplate = `{
                 }
             }
         },
+        "models.MobileNumberTransferPayload": {
+            "type": "object",
+            "required": [
+                "effectiveDate",
+                "employeeId",
+                "reason"
+            ],
+            "properties": {
+                "effectiveDate": {
+                    "type": "string"
+                },
+                "employeeId": {
+                    "description": "新使用人员工业务工号",
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "转移原因，记录在新使用人的使用历史中",
+                    "type": "string",
+                    "maxLength": 500
+                }
+            }
+        },
         "models.MobileNumberUnassignPayload": {
             "type": "object",
             "properties": {
@@ -2183,6 +7104,10 @@ const docTemplate = `{
                     "type": "string",
                     "maxLength": 255
                 },
+                "scheduledDeactivationDate": {
+                    "description": "ScheduledDeactivationDate 计划注销日期 (YYYY-MM-DD)，仅适用于待注销号码；变更为待注销时不提供则按宽限期自动计算",
+                    "type": "string"
+                },
                 "status": {
                     "type": "string"
                 },
@@ -2192,6 +7117,136 @@ const docTemplate = `{
                 }
             }
         },
+        "models.NumberDisposition": {
+            "type": "object",
+            "required": [
+                "action",
+                "phoneNumber"
+            ],
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "enum": [
+                        "reclaim",
+                        "transfer",
+                        "schedule_deactivation"
+                    ]
+                },
+                "phoneNumber": {
+                    "type": "string"
+                },
+                "remarks": {
+                    "description": "transfer: 转移原因",
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "scheduledDeactivationDate": {
+                    "description": "schedule_deactivation: 计划注销日期，默认按宽限期计算",
+                    "type": "string"
+                },
+                "transferToEmployeeId": {
+                    "description": "transfer: 接收号码的员工业务工号",
+                    "type": "string"
+                }
+            }
+        },
+        "models.NumberStatus": {
+            "type": "string",
+            "enum": [
+                "idle",
+                "in_use",
+                "pending_deactivation",
+                "deactivated",
+                "risk_pending",
+                "user_reported"
+            ],
+            "x-enum-comments": {
+                "StatusDeactivated": "已注销",
+                "StatusIdle": "闲置",
+                "StatusInUse": "使用中",
+                "StatusPendingDeactivation": "待注销",
+                "StatusRiskPending": "待核实-办卡人离职",
+                "StatusUserReport": "待核实-用户报告"
+            },
+            "x-enum-varnames": [
+                "StatusIdle",
+                "StatusInUse",
+                "StatusPendingDeactivation",
+                "StatusDeactivated",
+                "StatusRiskPending",
+                "StatusUserReport"
+            ]
+        },
+        "models.NumberTimelineEvent": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "引起状态变更的操作，如 mobile_number.assign",
+                    "type": "string"
+                },
+                "employeeId": {
+                    "description": "使用人或提交确认的员工工号",
+                    "type": "string"
+                },
+                "fromStatus": {
+                    "description": "变更前状态，号码创建时为空",
+                    "type": "string"
+                },
+                "newApplicantId": {
+                    "description": "新办卡人员工工号",
+                    "type": "string"
+                },
+                "occurredAt": {
+                    "description": "发生时间",
+                    "type": "string"
+                },
+                "operator": {
+                    "description": "操作者用户名",
+                    "type": "string"
+                },
+                "phoneNumber": {
+                    "description": "手机号码",
+                    "type": "string"
+                },
+                "previousApplicantId": {
+                    "description": "原办卡人员工工号",
+                    "type": "string"
+                },
+                "remarks": {
+                    "description": "备注、用途或员工留言",
+                    "type": "string"
+                },
+                "toStatus": {
+                    "description": "变更后状态",
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/models.TimelineEventType"
+                },
+                "verificationAction": {
+                    "description": "确认操作类型",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.VerificationActionType"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.NumberTimelineResponse": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.NumberTimelineEvent"
+                    }
+                },
+                "phoneNumber": {
+                    "type": "string"
+                }
+            }
+        },
         "models.NumberUsageHistory": {
             "type": "object",
             "properties": {
@@ -2217,6 +7272,10 @@ const docTemplate = `{
                     "description": "手机号码记录的数据库 ID",
                     "type": "integer"
                 },
+                "remarks": {
+                    "description": "备注，如号码转移原因",
+                    "type": "string"
+                },
                 "startDate": {
                     "description": "使用开始日期时间",
                     "type": "string"
@@ -2226,6 +7285,101 @@ const docTemplate = `{
                 }
             }
         },
+        "models.OutboundMessage": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "description": "已尝试发送的次数",
+                    "type": "integer"
+                },
+                "batchId": {
+                    "description": "所属的确认批次",
+                    "type": "string"
+                },
+                "channel": {
+                    "description": "员工偏好的通知渠道，为空时使用邮件",
+                    "type": "string"
+                },
+                "claimedAt": {
+                    "description": "最近一次被工作协程领取或开始发送的时间，用于识别发送中断的消息和丢弃过期的发送结果",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "employeeId": {
+                    "description": "收件员工的业务工号",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "description": "通知类型，如 verification",
+                    "type": "string"
+                },
+                "lastError": {
+                    "type": "string"
+                },
+                "maxAttempts": {
+                    "description": "最多尝试发送的次数",
+                    "type": "integer"
+                },
+                "nextAttemptAt": {
+                    "type": "string"
+                },
+                "recipientEmail": {
+                    "type": "string"
+                },
+                "recipientName": {
+                    "type": "string"
+                },
+                "recipientPhone": {
+                    "type": "string"
+                },
+                "sentAt": {
+                    "type": "string"
+                },
+                "sentChannel": {
+                    "description": "最近一次发送实际使用的渠道",
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.OutboundMessageStatus"
+                },
+                "subject": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "verificationTokenId": {
+                    "description": "号码确认通知对应的确认令牌",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.OutboundMessageStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "sending",
+                "sent",
+                "failed"
+            ],
+            "x-enum-comments": {
+                "OutboundMessageFailed": "达到最多尝试次数或无法发送，可通过重试接口重新排队",
+                "OutboundMessagePending": "等待发送，NextAttemptAt 到期后由工作协程领取",
+                "OutboundMessageSending": "工作协程正在发送",
+                "OutboundMessageSent": "发送成功"
+            },
+            "x-enum-varnames": [
+                "OutboundMessagePending",
+                "OutboundMessageSending",
+                "OutboundMessageSent",
+                "OutboundMessageFailed"
+            ]
+        },
         "models.PendingUserDetail": {
             "type": "object",
             "properties": {
@@ -2241,6 +7395,14 @@ const docTemplate = `{
                 "fullName": {
                     "type": "string"
                 },
+                "lastRemindedAt": {
+                    "description": "最近一次催办时间，未催办过时为空",
+                    "type": "string"
+                },
+                "reminderCount": {
+                    "description": "已催办次数",
+                    "type": "integer"
+                },
                 "tokenId": {
                     "type": "integer"
                 }
@@ -2301,6 +7463,93 @@ const docTemplate = `{
                 }
             }
         },
+        "models.PreviewEmailTemplatePayload": {
+            "type": "object",
+            "required": [
+                "locale",
+                "name"
+            ],
+            "properties": {
+                "htmlBody": {
+                    "type": "string"
+                },
+                "locale": {
+                    "type": "string",
+                    "enum": [
+                        "zh-CN",
+                        "en"
+                    ]
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "subject": {
+                    "type": "string"
+                },
+                "textBody": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RehireNumberOption": {
+            "type": "object",
+            "properties": {
+                "currentEmployeeId": {
+                    "description": "当前使用人员工业务工号",
+                    "type": "string"
+                },
+                "phoneNumber": {
+                    "type": "string"
+                },
+                "restoreStatus": {
+                    "description": "恢复后的状态，即号码进入风险待核实前的状态",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.NumberStatus"
+                        }
+                    ]
+                },
+                "restored": {
+                    "description": "是否已在本次复职中恢复",
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.RehirePayload": {
+            "type": "object",
+            "properties": {
+                "rehireDate": {
+                    "description": "复职日期，默认当天，作为新的入职日期",
+                    "type": "string"
+                },
+                "restoreAllNumbers": {
+                    "description": "恢复该员工作为办卡人的全部风险待核实号码",
+                    "type": "boolean"
+                },
+                "restorePhoneNumbers": {
+                    "description": "需要恢复的风险号码，必须是该员工作为办卡人的风险待核实号码",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.RehireResponse": {
+            "type": "object",
+            "properties": {
+                "employee": {
+                    "$ref": "#/definitions/models.Employee"
+                },
+                "numbers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RehireNumberOption"
+                    }
+                }
+            }
+        },
         "models.ReportedIssueDetail": {
             "type": "object",
             "properties": {
@@ -2347,6 +7596,19 @@ const docTemplate = `{
                 }
             }
         },
+        "models.ResetPasswordPayload": {
+            "type": "object",
+            "required": [
+                "newPassword"
+            ],
+            "properties": {
+                "newPassword": {
+                    "type": "string",
+                    "maxLength": 72,
+                    "minLength": 8
+                }
+            }
+        },
         "models.RiskNumberResponse": {
             "type": "object",
             "properties": {
@@ -2400,6 +7662,10 @@ const docTemplate = `{
                 "remarks": {
                     "type": "string"
                 },
+                "scheduledDeactivationDate": {
+                    "description": "计划注销日期",
+                    "type": "string"
+                },
                 "status": {
                     "description": "英文状态值",
                     "type": "string"
@@ -2419,6 +7685,105 @@ const docTemplate = `{
                 }
             }
         },
+        "models.ScheduledDepartureResponse": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "employeeId": {
+                    "description": "员工业务工号",
+                    "type": "string"
+                },
+                "executedAt": {
+                    "description": "定时任务办理离职（或取消）的时间",
+                    "type": "string"
+                },
+                "fullName": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lastError": {
+                    "description": "最近一次办理失败的原因，失败后每次检查时重试",
+                    "type": "string"
+                },
+                "numberDispositions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.NumberDisposition"
+                    }
+                },
+                "reminderSentAt": {
+                    "description": "提醒 IT 的时间",
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.ScheduledDepartureStatus"
+                },
+                "terminationDate": {
+                    "description": "离职日期",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ScheduledDepartureStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "completed",
+                "cancelled"
+            ],
+            "x-enum-comments": {
+                "ScheduledDepartureCancelled": "已取消或被新的预约替代",
+                "ScheduledDepartureCompleted": "已在离职日期办理离职",
+                "ScheduledDeparturePending": "等待离职日期到达"
+            },
+            "x-enum-varnames": [
+                "ScheduledDeparturePending",
+                "ScheduledDepartureCompleted",
+                "ScheduledDepartureCancelled"
+            ]
+        },
+        "models.TOTPCodePayload": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TimelineEventType": {
+            "type": "string",
+            "enum": [
+                "usage_started",
+                "usage_ended",
+                "applicant_changed",
+                "status_changed",
+                "verification_submission"
+            ],
+            "x-enum-comments": {
+                "TimelineApplicantChanged": "办卡人变更",
+                "TimelineStatusChanged": "状态变更",
+                "TimelineUsageEnded": "结束使用",
+                "TimelineUsageStarted": "开始使用",
+                "TimelineVerificationSubmission": "员工提交号码确认"
+            },
+            "x-enum-varnames": [
+                "TimelineUsageStarted",
+                "TimelineUsageEnded",
+                "TimelineApplicantChanged",
+                "TimelineStatusChanged",
+                "TimelineVerificationSubmission"
+            ]
+        },
         "models.UnlistedNumber": {
             "type": "object",
             "required": [
@@ -2438,6 +7803,43 @@ const docTemplate = `{
                 }
             }
         },
+        "models.UpdateDepartmentPayload": {
+            "type": "object",
+            "properties": {
+                "costCenter": {
+                    "description": "成本中心编码，空字符串表示清除",
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "managerEmployeeId": {
+                    "description": "部门负责人员工业务工号，空字符串表示清除",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "新名称，原名称记为别名",
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "parentId": {
+                    "description": "新的上级部门ID，0 表示改为顶级部门",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.UpdateEmailTemplatePayload": {
+            "type": "object",
+            "properties": {
+                "htmlBody": {
+                    "type": "string"
+                },
+                "subject": {
+                    "type": "string"
+                },
+                "textBody": {
+                    "type": "string"
+                }
+            }
+        },
         "models.UpdateEmployeePayload": {
             "type": "object",
             "properties": {
@@ -2445,6 +7847,11 @@ const docTemplate = `{
                     "type": "string",
                     "maxLength": 255
                 },
+                "email": {
+                    "description": "空字符串表示清除",
+                    "type": "string",
+                    "maxLength": 255
+                },
                 "employmentStatus": {
                     "description": "校验允许的值",
                     "type": "string",
@@ -2453,16 +7860,135 @@ const docTemplate = `{
                         "Departed"
                     ]
                 },
+                "fullName": {
+                    "type": "string",
+                    "maxLength": 255
+                },
                 "hireDate": {
                     "description": "入职日期，格式 YYYY-MM-DD",
                     "type": "string"
                 },
+                "locale": {
+                    "description": "接收通知的语言",
+                    "type": "string",
+                    "enum": [
+                        "zh-CN",
+                        "en"
+                    ]
+                },
+                "notificationChannel": {
+                    "description": "接收通知的渠道",
+                    "type": "string",
+                    "enum": [
+                        "email",
+                        "im",
+                        "sms"
+                    ]
+                },
+                "numberDispositions": {
+                    "description": "办理离职时员工使用中号码的处置方案，每个使用中的号码都必须提供处置方式",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.NumberDisposition"
+                    }
+                },
+                "phoneNumber": {
+                    "description": "11位手机号码，空字符串表示清除",
+                    "type": "string",
+                    "maxLength": 11
+                },
                 "terminationDate": {
                     "description": "日期格式 YYYY-MM-DD",
                     "type": "string"
                 }
             }
         },
+        "models.UpdateUserPayload": {
+            "type": "object",
+            "properties": {
+                "departmentIds": {
+                    "description": "替换所负责的部门，仅适用于 department_manager 角色；角色改为其他角色时自动清除",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "role": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "active",
+                        "disabled"
+                    ]
+                }
+            }
+        },
+        "models.User": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "departmentIds": {
+                    "description": "部门负责人所负责的部门ID，保存在 user_departments 表中",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "failedLoginCount": {
+                    "description": "连续登录失败次数，登录成功后清零",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lastFailedLoginAt": {
+                    "description": "最近一次登录失败时间",
+                    "type": "string"
+                },
+                "lockedUntil": {
+                    "description": "锁定截止时间，为空或已过去表示未锁定",
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "账号状态 ('active', 'disabled')",
+                    "type": "string"
+                },
+                "totpEnabled": {
+                    "description": "是否已启用 TOTP 两步验证",
+                    "type": "boolean"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.VerificationActionType": {
+            "type": "string",
+            "enum": [
+                "confirm_usage",
+                "report_issue",
+                "report_unlisted"
+            ],
+            "x-enum-varnames": [
+                "ActionConfirmUsage",
+                "ActionReportIssue",
+                "ActionReportUnlisted"
+            ]
+        },
         "models.VerificationBatchTask": {
             "type": "object",
             "properties": {
@@ -2479,6 +8005,10 @@ const docTemplate = `{
                 "emailsFailedCount": {
                     "type": "integer"
                 },
+                "emailsPendingCount": {
+                    "description": "尚在发送队列中等待发送或重试的通知数",
+                    "type": "integer"
+                },
                 "emailsSucceededCount": {
                     "type": "integer"
                 },
@@ -2488,6 +8018,14 @@ const docTemplate = `{
                 "id": {
                     "type": "string"
                 },
+                "includeSubDepartments": {
+                    "description": "按部门发起时是否包含下级部门",
+                    "type": "boolean"
+                },
+                "reminderSchedule": {
+                    "description": "自动催办计划 (ReminderSchedule 的 JSON)，为空时不自动催办",
+                    "type": "string"
+                },
                 "requestedDurationDays": {
                     "type": "integer"
                 },
@@ -2632,6 +8170,19 @@ const docTemplate = `{
                 }
             }
         },
+        "services.TOTPSetupResult": {
+            "type": "object",
+            "properties": {
+                "otpauthUri": {
+                    "description": "用于生成二维码的 otpauth:// URI",
+                    "type": "string"
+                },
+                "secret": {
+                    "description": "Base32 密钥，无法扫码时手动输入",
+                    "type": "string"
+                }
+            }
+        },
         "utils.APIErrorResponse": {
             "type": "object",
             "properties": {
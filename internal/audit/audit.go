@@ -0,0 +1,100 @@
+// Package audit 记录操作审计事件。
+// 操作者信息通过 context 传递：JWT 中间件为每个请求写入当前用户，
+// 仓库层在执行变更的同一事务中调用 Record 写入审计事件。
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/phone_management/internal/models"
+	"gorm.io/gorm"
+)
+
+// 审计事件来源
+const (
+	SourceAPI          = "api"          // 管理端接口
+	SourceImport       = "import"       // 批量导入
+	SourceBulk         = "bulk"         // 号码批量操作
+	SourceVerification = "verification" // 员工通过确认链接提交
+	SourceSystem       = "system"       // 系统任务
+	SourceDirectory    = "directory"    // 员工目录同步
+	SourceHR           = "hr"           // HR 系统推送的员工事件
+)
+
+// systemActor 是 context 中没有操作者信息时使用的默认操作者
+var systemActor = Actor{Username: "system", Source: SourceSystem}
+
+// Actor 描述一次变更的操作者
+type Actor struct {
+	UserID   *int64 // 系统用户ID，非系统用户操作时为空
+	Username string // 用户名或员工工号
+	Source   string // 来源
+	ClientIP string // 请求来源IP
+}
+
+type actorKey struct{}
+
+// WithActor 返回携带操作者信息的 context
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	if actor.Source == "" {
+		actor.Source = SourceAPI
+	}
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// WithSource 保留 context 中的操作者，只替换来源，例如批量导入
+func WithSource(ctx context.Context, source string) context.Context {
+	actor := ActorFromContext(ctx)
+	actor.Source = source
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext 读取 context 中的操作者，没有时视为系统操作
+func ActorFromContext(ctx context.Context) Actor {
+	if ctx != nil {
+		if actor, ok := ctx.Value(actorKey{}).(Actor); ok {
+			return actor
+		}
+	}
+	return systemActor
+}
+
+// Record 在 tx 所在的事务中写入一条审计事件。
+// 操作者从 tx 的 context 中读取，因此 tx 应由 db.WithContext(ctx) 开启。
+// before/after 为变更前后的实体，为 nil 时对应字段留空。
+func Record(tx *gorm.DB, action, entityType string, entityID interface{}, before, after interface{}) error {
+	actor := ActorFromContext(tx.Statement.Context)
+	event := models.AuditEvent{
+		ActorUserID:   actor.UserID,
+		ActorUsername: actor.Username,
+		Source:        actor.Source,
+		ClientIP:      actor.ClientIP,
+		Action:        action,
+		EntityType:    entityType,
+		EntityID:      fmt.Sprint(entityID),
+	}
+
+	var err error
+	if event.Before, err = snapshot(before); err != nil {
+		return err
+	}
+	if event.After, err = snapshot(after); err != nil {
+		return err
+	}
+	return tx.Create(&event).Error
+}
+
+// snapshot 将实体序列化为 JSON 字符串
+func snapshot(v interface{}) (*string, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("序列化审计快照失败: %w", err)
+	}
+	s := string(data)
+	return &s, nil
+}
@@ -0,0 +1,89 @@
+package auth
+
+import (
+	"sync"
+	"time"
+)
+
+// TokenDenylist 定义了已登出 Token (按 JTI) 的存储接口。
+// 多实例部署时应使用共享存储的实现（如 repositories.NewGormRevokedTokenRepository）。
+type TokenDenylist interface {
+	// Add 将 JTI 加入拒绝列表，expiresAt 为 Token 的原始过期时间
+	Add(jti string, expiresAt time.Time) error
+	// Contains 检查 JTI 是否在拒绝列表中且尚未过期
+	Contains(jti string) (bool, error)
+	// PurgeExpired 清理已过期的条目，返回清理的条数
+	PurgeExpired() (int64, error)
+}
+
+var (
+	denylistMu sync.RWMutex
+	// denylist 为当前使用的拒绝列表实现，未调用 SetTokenDenylist 时使用内存实现
+	denylist TokenDenylist = NewMemoryTokenDenylist()
+)
+
+// SetTokenDenylist 设置全局使用的拒绝列表实现，应在启动时调用
+func SetTokenDenylist(d TokenDenylist) {
+	denylistMu.Lock()
+	defer denylistMu.Unlock()
+	denylist = d
+}
+
+// GetTokenDenylist 返回当前使用的拒绝列表实现
+func GetTokenDenylist() TokenDenylist {
+	denylistMu.RLock()
+	defer denylistMu.RUnlock()
+	return denylist
+}
+
+// AddToDenylist 将JTI添加到拒绝列表
+func AddToDenylist(jti string, expiresAt time.Time) error {
+	return GetTokenDenylist().Add(jti, expiresAt)
+}
+
+// IsTokenDenylisted 检查JTI是否在拒绝列表中且尚未过期
+func IsTokenDenylisted(jti string) (bool, error) {
+	return GetTokenDenylist().Contains(jti)
+}
+
+// memoryTokenDenylist 是 TokenDenylist 的内存实现，服务重启会丢失，仅适用于单实例或测试
+type memoryTokenDenylist struct {
+	mu      sync.RWMutex
+	entries map[string]time.Time // key: JTI, value: 该JTI的原始过期时间点
+}
+
+// NewMemoryTokenDenylist 创建一个内存拒绝列表
+func NewMemoryTokenDenylist() TokenDenylist {
+	return &memoryTokenDenylist{entries: make(map[string]time.Time)}
+}
+
+// Add 将JTI加入内存拒绝列表
+func (m *memoryTokenDenylist) Add(jti string, expiresAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[jti] = expiresAt
+	return nil
+}
+
+// Contains 检查JTI是否在内存拒绝列表中且尚未过期
+func (m *memoryTokenDenylist) Contains(jti string) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	expTime, found := m.entries[jti]
+	return found && time.Now().Before(expTime), nil
+}
+
+// PurgeExpired 清理内存拒绝列表中已过期的JTI
+func (m *memoryTokenDenylist) PurgeExpired() (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var purged int64
+	now := time.Now()
+	for id, exp := range m.entries {
+		if now.After(exp) {
+			delete(m.entries, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
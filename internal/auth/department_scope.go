@@ -0,0 +1,33 @@
+package auth
+
+import (
+	"context"
+	"sync"
+)
+
+// DepartmentScopeResolver 查询部门负责人可访问的部门，
+// 实现为 repositories.NewGormUserRepository
+type DepartmentScopeResolver interface {
+	// GetManagedDepartmentIDs 返回用户所负责的部门及其全部下级部门的ID
+	GetManagedDepartmentIDs(ctx context.Context, userID int64) ([]uint, error)
+}
+
+var (
+	departmentScopeMu sync.RWMutex
+	// departmentScopeResolver 为当前使用的解析器，未设置时部门负责人的请求一律被拒绝
+	departmentScopeResolver DepartmentScopeResolver
+)
+
+// SetDepartmentScopeResolver 设置全局使用的部门范围解析器，应在启动时调用
+func SetDepartmentScopeResolver(r DepartmentScopeResolver) {
+	departmentScopeMu.Lock()
+	defer departmentScopeMu.Unlock()
+	departmentScopeResolver = r
+}
+
+// GetDepartmentScopeResolver 返回当前使用的部门范围解析器，未设置时返回 nil
+func GetDepartmentScopeResolver() DepartmentScopeResolver {
+	departmentScopeMu.RLock()
+	defer departmentScopeMu.RUnlock()
+	return departmentScopeResolver
+}
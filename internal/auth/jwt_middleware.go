@@ -2,15 +2,14 @@ package auth
 
 import (
 	"errors"
-	"fmt"
 	"net/http"
 	"strings"
-	"sync"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
-	"github.com/phone_management/configs"
+	"github.com/phone_management/internal/audit"
+	"github.com/phone_management/internal/models"
+	"github.com/phone_management/internal/scope"
 	"github.com/phone_management/pkg/utils"
 )
 
@@ -23,44 +22,6 @@ type Claims struct {
 	jwt.RegisteredClaims
 }
 
-var (
-	// tokenDenylist 存储已登出Token的JTI及其原始过期时间。
-	// key: JTI (JWT ID), value: 该JTI的原始过期时间点。
-	// 注意: 这是一个内存列表，服务重启会丢失。生产环境应使用Redis等持久化存储。
-	tokenDenylist = make(map[string]time.Time)
-	denylistMutex = &sync.RWMutex{}
-)
-
-// AddToDenylist 将JTI添加到拒绝列表，并清理已过期的条目。
-func AddToDenylist(jti string, expiresAt time.Time) {
-	denylistMutex.Lock()
-	defer denylistMutex.Unlock()
-
-	tokenDenylist[jti] = expiresAt
-
-	// 清理拒绝列表中其他已完全过期的JTI
-	now := time.Now()
-	for id, exp := range tokenDenylist {
-		if now.After(exp) {
-			delete(tokenDenylist, id)
-		}
-	}
-}
-
-// IsTokenDenylisted 检查JTI是否在拒绝列表中且尚未过期。
-func IsTokenDenylisted(jti string) bool {
-	denylistMutex.RLock()
-	defer denylistMutex.RUnlock()
-
-	expTime, found := tokenDenylist[jti]
-	if !found {
-		return false // 不在拒绝列表
-	}
-
-	// 如果JTI在拒绝列表中，且其记录的过期时间点仍在未来，则认为是 فعال (denylisted)
-	return time.Now().Before(expTime)
-}
-
 // JWTMiddleware 是一个Gin中间件，用于验证JWT。
 // 它从 Authorization 请求头中提取 Bearer Token，
 // 并使用 `golang-jwt/jwt/v5` 库进行验证。
@@ -81,15 +42,10 @@ func JWTMiddleware() gin.HandlerFunc {
 		}
 
 		tokenString := parts[1]
-		claims := &Claims{}
 
-		token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-			// 确保token的签名方法是我们期望的 HMAC
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-			}
-			return []byte(configs.AppConfig.JWTSecret), nil // 使用配置中的密钥
-		})
+		// 只接受访问令牌，两步验证的临时令牌受众不同，会在此被拒绝。
+		// 签名密钥根据令牌头部的 kid 从密钥环中选择。
+		token, claims, err := parseClaims(tokenString, accessTokenAudience)
 
 		if err != nil {
 			// 使用 errors.Is 来判断特定的JWT错误类型
@@ -99,6 +55,8 @@ func JWTMiddleware() gin.HandlerFunc {
 				utils.RespondUnauthorizedError(c, "Token is expired or not valid yet")
 			} else if errors.Is(err, jwt.ErrSignatureInvalid) {
 				utils.RespondUnauthorizedError(c, "Invalid token signature")
+			} else if errors.Is(err, jwt.ErrTokenInvalidAudience) {
+				utils.RespondUnauthorizedError(c, "Token audience is not allowed")
 			} else {
 				// 对于其他未明确分类的token错误，使用更通用的 RespondAPIError
 				utils.RespondAPIError(c, http.StatusUnauthorized, "Invalid token", err.Error())
@@ -121,7 +79,13 @@ func JWTMiddleware() gin.HandlerFunc {
 		}
 
 		// 检查Token是否已在拒绝列表
-		if IsTokenDenylisted(claims.ID) {
+		denylisted, err := IsTokenDenylisted(claims.ID)
+		if err != nil {
+			utils.RespondInternalServerError(c, "无法校验Token状态", err.Error())
+			c.Abort()
+			return
+		}
+		if denylisted {
 			utils.RespondUnauthorizedError(c, "Token has been invalidated (logged out)")
 			c.Abort()
 			return
@@ -136,6 +100,33 @@ func JWTMiddleware() gin.HandlerFunc {
 			c.Set("exp", claims.ExpiresAt.Time) // 存储过期时间
 		}
 
+		// 将操作者写入请求 context，供仓库层记录审计事件
+		userID := int64(claims.UserID)
+		c.Request = c.Request.WithContext(audit.WithActor(c.Request.Context(), audit.Actor{
+			UserID:   &userID,
+			Username: claims.Username,
+			Source:   audit.SourceAPI,
+			ClientIP: c.ClientIP(),
+		}))
+
+		// 部门负责人只能访问所负责部门的数据，将可访问的部门写入请求 context，由仓库层在查询时限定范围。
+		// 无法确定范围时拒绝请求，避免退化为不受限制的访问
+		if claims.Role == models.RoleDepartmentManager {
+			resolver := GetDepartmentScopeResolver()
+			if resolver == nil {
+				utils.RespondInternalServerError(c, "无法确定数据访问范围", "department scope resolver is not configured")
+				c.Abort()
+				return
+			}
+			departmentIDs, err := resolver.GetManagedDepartmentIDs(c.Request.Context(), userID)
+			if err != nil {
+				utils.RespondInternalServerError(c, "无法确定数据访问范围", err.Error())
+				c.Abort()
+				return
+			}
+			c.Request = c.Request.WithContext(scope.WithDepartments(c.Request.Context(), departmentIDs))
+		}
+
 		c.Next()
 	}
 }
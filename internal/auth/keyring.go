@@ -0,0 +1,182 @@
+package auth
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/phone_management/configs"
+)
+
+// SigningKey 是密钥环中的一个密钥
+type SigningKey struct {
+	ID        string            // kid，写入令牌头部
+	Method    jwt.SigningMethod // 签名算法
+	signKey   interface{}       // 签名用的密钥，仅当前密钥需要
+	verifyKey interface{}       // 验证用的密钥
+}
+
+// Keyring 保存当前签名密钥和仍被接受的旧密钥。
+// 轮换密钥时，将原密钥配置为“上一个密钥”，新签发的令牌使用新密钥，
+// 旧令牌在过期前仍可通过验证。
+type Keyring struct {
+	current *SigningKey
+	keys    map[string]*SigningKey
+}
+
+var (
+	keyringMu     sync.RWMutex
+	activeKeyring *Keyring
+)
+
+// InitKeyring 根据配置加载密钥环，应在 configs.LoadConfig 之后、启动服务之前调用
+func InitKeyring(cfg configs.Configuration) error {
+	keyring, err := NewKeyringFromConfig(cfg)
+	if err != nil {
+		return err
+	}
+	keyringMu.Lock()
+	defer keyringMu.Unlock()
+	activeKeyring = keyring
+	return nil
+}
+
+// getKeyring 返回当前密钥环，未初始化时按当前配置加载
+func getKeyring() (*Keyring, error) {
+	keyringMu.RLock()
+	keyring := activeKeyring
+	keyringMu.RUnlock()
+	if keyring != nil {
+		return keyring, nil
+	}
+	if err := InitKeyring(configs.AppConfig); err != nil {
+		return nil, err
+	}
+	return getKeyring()
+}
+
+// NewKeyringFromConfig 根据配置构建密钥环
+func NewKeyringFromConfig(cfg configs.Configuration) (*Keyring, error) {
+	current, err := loadCurrentKey(cfg)
+	if err != nil {
+		return nil, err
+	}
+	keyring := &Keyring{
+		current: current,
+		keys:    map[string]*SigningKey{current.ID: current},
+	}
+
+	if cfg.JWTPreviousKeyID == "" {
+		return keyring, nil
+	}
+	if cfg.JWTPreviousKeyID == current.ID {
+		return nil, fmt.Errorf("上一个JWT密钥的 kid 不能与当前密钥相同: %s", current.ID)
+	}
+	previous, err := loadPreviousKey(cfg)
+	if err != nil {
+		return nil, err
+	}
+	keyring.keys[previous.ID] = previous
+	return keyring, nil
+}
+
+// loadCurrentKey 加载当前签名密钥
+func loadCurrentKey(cfg configs.Configuration) (*SigningKey, error) {
+	key := &SigningKey{ID: cfg.JWTKeyID}
+	switch cfg.JWTSigningAlgorithm {
+	case jwt.SigningMethodHS256.Alg():
+		if cfg.JWTSecret == "" {
+			return nil, errors.New("HS256 签名需要设置 JWT 密钥")
+		}
+		key.Method = jwt.SigningMethodHS256
+		key.signKey = []byte(cfg.JWTSecret)
+		key.verifyKey = key.signKey
+	case jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg():
+		if cfg.JWTPrivateKeyFile == "" {
+			return nil, fmt.Errorf("%s 签名需要设置私钥文件", cfg.JWTSigningAlgorithm)
+		}
+		pemBytes, err := os.ReadFile(cfg.JWTPrivateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("读取JWT私钥文件失败: %w", err)
+		}
+		if cfg.JWTSigningAlgorithm == jwt.SigningMethodRS256.Alg() {
+			privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(pemBytes)
+			if err != nil {
+				return nil, fmt.Errorf("解析RSA私钥失败: %w", err)
+			}
+			key.Method = jwt.SigningMethodRS256
+			key.signKey = privateKey
+			key.verifyKey = &privateKey.PublicKey
+		} else {
+			privateKey, err := jwt.ParseEdPrivateKeyFromPEM(pemBytes)
+			if err != nil {
+				return nil, fmt.Errorf("解析Ed25519私钥失败: %w", err)
+			}
+			key.Method = jwt.SigningMethodEdDSA
+			key.signKey = privateKey
+			key.verifyKey = privateKey.(crypto.Signer).Public()
+		}
+	default:
+		return nil, fmt.Errorf("不支持的JWT签名算法: %s", cfg.JWTSigningAlgorithm)
+	}
+	return key, nil
+}
+
+// loadPreviousKey 加载上一个密钥，只用于验证。算法由配置的密钥类型决定。
+func loadPreviousKey(cfg configs.Configuration) (*SigningKey, error) {
+	key := &SigningKey{ID: cfg.JWTPreviousKeyID}
+	switch {
+	case cfg.JWTPreviousSecret != "":
+		key.Method = jwt.SigningMethodHS256
+		key.verifyKey = []byte(cfg.JWTPreviousSecret)
+	case cfg.JWTPreviousPublicKeyFile != "":
+		pemBytes, err := os.ReadFile(cfg.JWTPreviousPublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("读取上一个JWT公钥文件失败: %w", err)
+		}
+		if publicKey, err := jwt.ParseRSAPublicKeyFromPEM(pemBytes); err == nil {
+			key.Method = jwt.SigningMethodRS256
+			key.verifyKey = publicKey
+		} else if publicKey, err := jwt.ParseEdPublicKeyFromPEM(pemBytes); err == nil {
+			key.Method = jwt.SigningMethodEdDSA
+			key.verifyKey = publicKey
+		} else {
+			return nil, errors.New("上一个JWT公钥既不是RSA也不是Ed25519公钥")
+		}
+	default:
+		return nil, errors.New("设置了上一个JWT密钥的 kid，但未提供对应的密钥或公钥文件")
+	}
+	return key, nil
+}
+
+// Sign 使用当前密钥签名，并在头部写入 kid
+func (k *Keyring) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.current.Method, claims)
+	token.Header["kid"] = k.current.ID
+	return token.SignedString(k.current.signKey)
+}
+
+// KeyFunc 根据令牌头部的 kid 选择验证密钥，并确保签名算法与该密钥一致。
+// 没有 kid 的令牌（密钥环启用前签发）只有在当前密钥为 HMAC 时才会用当前密钥验证。
+func (k *Keyring) KeyFunc(token *jwt.Token) (interface{}, error) {
+	var key *SigningKey
+	if kid, ok := token.Header["kid"].(string); ok && kid != "" {
+		key = k.keys[kid]
+		if key == nil {
+			return nil, fmt.Errorf("unknown signing key: %s", kid)
+		}
+	} else {
+		if _, ok := k.current.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("token missing kid header")
+		}
+		key = k.current
+	}
+
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	return key.verifyKey, nil
+}
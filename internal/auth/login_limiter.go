@@ -0,0 +1,171 @@
+package auth
+
+import (
+	"sync"
+	"time"
+)
+
+// BackoffDelay 计算指数退避的等待时间：失败次数未达到 threshold 时不等待，
+// 之后每多失败一次等待时间翻倍，最长不超过 max。
+func BackoffDelay(failures, threshold int, base, max time.Duration) time.Duration {
+	if failures < threshold || base <= 0 {
+		return 0
+	}
+	delay := base
+	for i := threshold; i < failures; i++ {
+		delay *= 2
+		if delay >= max {
+			return max
+		}
+	}
+	if delay > max {
+		return max
+	}
+	return delay
+}
+
+// LoginLimiter 按来源（如客户端IP）统计登录失败次数，并在超过阈值后要求指数退避。
+// 数据保存在内存中，每个实例独立计数。
+type LoginLimiter struct {
+	mu        sync.Mutex
+	entries   map[string]*loginAttempts
+	threshold int
+	window    time.Duration
+	base      time.Duration
+	max       time.Duration
+}
+
+// loginAttempts 记录单个来源的失败情况
+type loginAttempts struct {
+	failures    int
+	lastFailure time.Time
+}
+
+// NewLoginLimiter 创建登录限流器。
+// threshold 为允许的失败次数，window 内无新的失败时计数清零，base/max 为退避参数。
+func NewLoginLimiter(threshold int, window, base, max time.Duration) *LoginLimiter {
+	return &LoginLimiter{
+		entries:   make(map[string]*loginAttempts),
+		threshold: threshold,
+		window:    window,
+		base:      base,
+		max:       max,
+	}
+}
+
+// RetryAt 返回该来源下一次允许尝试的时间，零值表示可以立即尝试
+func (l *LoginLimiter) RetryAt(key string, now time.Time) time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry, ok := l.entries[key]
+	if !ok {
+		return time.Time{}
+	}
+	if now.Sub(entry.lastFailure) > l.window {
+		delete(l.entries, key)
+		return time.Time{}
+	}
+	delay := BackoffDelay(entry.failures, l.threshold, l.base, l.max)
+	if delay == 0 || !now.Before(entry.lastFailure.Add(delay)) {
+		return time.Time{}
+	}
+	return entry.lastFailure.Add(delay)
+}
+
+// RecordFailure 记录一次失败，并顺带清理已超出时间窗口的条目
+func (l *LoginLimiter) RecordFailure(key string, now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for k, e := range l.entries {
+		if now.Sub(e.lastFailure) > l.window {
+			delete(l.entries, k)
+		}
+	}
+
+	entry, ok := l.entries[key]
+	if !ok {
+		entry = &loginAttempts{}
+		l.entries[key] = entry
+	}
+	entry.failures++
+	entry.lastFailure = now
+}
+
+// UnknownUsernameLimiter 为不存在的用户名模拟账号的失败计数、退避和锁定：
+// 每次失败后按指数退避等待，连续失败达到 maxFailures 次时锁定 lockout 并清零计数，与 users 表中记录的规则相同，
+// 使登录接口对存在和不存在的用户名返回相同的错误，无法据此枚举用户名。
+// 数据保存在内存中，锁定已结束且 lockout 内没有新的失败的条目会被清理
+type UnknownUsernameLimiter struct {
+	mu          sync.Mutex
+	entries     map[string]*usernameAttempts
+	maxFailures int
+	lockout     time.Duration
+	base        time.Duration
+	max         time.Duration
+}
+
+// usernameAttempts 记录单个用户名的失败情况
+type usernameAttempts struct {
+	failures    int
+	lastFailure time.Time
+	lockedUntil time.Time
+}
+
+// NewUnknownUsernameLimiter 创建不存在的用户名的限流器，参数含义与账号锁定和退避配置相同
+func NewUnknownUsernameLimiter(maxFailures int, lockout, base, max time.Duration) *UnknownUsernameLimiter {
+	return &UnknownUsernameLimiter{
+		entries:     make(map[string]*usernameAttempts),
+		maxFailures: maxFailures,
+		lockout:     lockout,
+		base:        base,
+		max:         max,
+	}
+}
+
+// RetryAt 返回该用户名下一次允许尝试的时间，零值表示可以立即尝试；locked 表示处于锁定中而不是退避中
+func (l *UnknownUsernameLimiter) RetryAt(username string, now time.Time) (retryAt time.Time, locked bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry, ok := l.entries[username]
+	if !ok {
+		return time.Time{}, false
+	}
+	if now.Before(entry.lockedUntil) {
+		return entry.lockedUntil, true
+	}
+	if entry.failures > 0 {
+		if retryAt := entry.lastFailure.Add(BackoffDelay(entry.failures, 1, l.base, l.max)); now.Before(retryAt) {
+			return retryAt, false
+		}
+	}
+	return time.Time{}, false
+}
+
+// RecordFailure 记录一次失败，达到 maxFailures 次时锁定该用户名并返回锁定截止时间，否则返回零值
+func (l *UnknownUsernameLimiter) RecordFailure(username string, now time.Time) time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for k, e := range l.entries {
+		if !now.Before(e.lockedUntil) && now.Sub(e.lastFailure) > l.lockout {
+			delete(l.entries, k)
+		}
+	}
+
+	entry, ok := l.entries[username]
+	if !ok {
+		entry = &usernameAttempts{}
+		l.entries[username] = entry
+	}
+	entry.failures++
+	entry.lastFailure = now
+	if entry.failures < l.maxFailures {
+		return time.Time{}
+	}
+	entry.failures = 0
+	entry.lockedUntil = now.Add(l.lockout)
+	return entry.lockedUntil
+}
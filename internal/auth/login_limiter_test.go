@@ -0,0 +1,112 @@
+package auth
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoffDelay(t *testing.T) {
+	base, max := time.Second, 10*time.Second
+	cases := []struct {
+		failures, threshold int
+		want                time.Duration
+	}{
+		{0, 3, 0},
+		{2, 3, 0},
+		{3, 3, time.Second},
+		{4, 3, 2 * time.Second},
+		{5, 3, 4 * time.Second},
+		{6, 3, 8 * time.Second},
+		{7, 3, max},
+		{100, 3, max},
+		{1, 1, time.Second},
+	}
+	for _, tc := range cases {
+		if got := BackoffDelay(tc.failures, tc.threshold, base, max); got != tc.want {
+			t.Errorf("BackoffDelay(%d, %d) = %v, want %v", tc.failures, tc.threshold, got, tc.want)
+		}
+	}
+	if got := BackoffDelay(5, 1, 0, max); got != 0 {
+		t.Errorf("BackoffDelay with zero base = %v, want 0", got)
+	}
+}
+
+func TestLoginLimiter(t *testing.T) {
+	now := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	l := NewLoginLimiter(3, 15*time.Minute, time.Second, time.Minute)
+
+	// 未达到阈值前不限制
+	for i := 0; i < 2; i++ {
+		l.RecordFailure("10.0.0.1", now)
+		if retryAt := l.RetryAt("10.0.0.1", now); !retryAt.IsZero() {
+			t.Fatalf("after %d failures RetryAt = %v, want zero", i+1, retryAt)
+		}
+	}
+
+	// 达到阈值后需要等待，等待时间随失败次数翻倍
+	l.RecordFailure("10.0.0.1", now)
+	if got, want := l.RetryAt("10.0.0.1", now), now.Add(time.Second); !got.Equal(want) {
+		t.Errorf("after 3 failures RetryAt = %v, want %v", got, want)
+	}
+	l.RecordFailure("10.0.0.1", now)
+	if got, want := l.RetryAt("10.0.0.1", now), now.Add(2*time.Second); !got.Equal(want) {
+		t.Errorf("after 4 failures RetryAt = %v, want %v", got, want)
+	}
+	if retryAt := l.RetryAt("10.0.0.1", now.Add(2*time.Second)); !retryAt.IsZero() {
+		t.Errorf("after backoff RetryAt = %v, want zero", retryAt)
+	}
+
+	// 其他来源不受影响
+	if retryAt := l.RetryAt("10.0.0.2", now); !retryAt.IsZero() {
+		t.Errorf("other key RetryAt = %v, want zero", retryAt)
+	}
+
+	// 时间窗口内没有新的失败后计数清零
+	later := now.Add(16 * time.Minute)
+	l.RecordFailure("10.0.0.1", later)
+	if retryAt := l.RetryAt("10.0.0.1", later); !retryAt.IsZero() {
+		t.Errorf("after window RetryAt = %v, want zero", retryAt)
+	}
+}
+
+func TestUnknownUsernameLimiter(t *testing.T) {
+	now := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	l := NewUnknownUsernameLimiter(3, 15*time.Minute, time.Second, time.Minute)
+
+	// 每次失败后都需要退避，与已存在的账号相同
+	if lockedUntil := l.RecordFailure("ghost", now); !lockedUntil.IsZero() {
+		t.Fatalf("after 1 failure lockedUntil = %v, want zero", lockedUntil)
+	}
+	if retryAt, locked := l.RetryAt("ghost", now); !retryAt.Equal(now.Add(time.Second)) || locked {
+		t.Errorf("after 1 failure RetryAt = %v, %v, want %v and not locked", retryAt, locked, now.Add(time.Second))
+	}
+	now = now.Add(time.Second)
+	if retryAt, _ := l.RetryAt("ghost", now); !retryAt.IsZero() {
+		t.Errorf("after backoff RetryAt = %v, want zero", retryAt)
+	}
+	l.RecordFailure("ghost", now)
+	if retryAt, _ := l.RetryAt("ghost", now); !retryAt.Equal(now.Add(2 * time.Second)) {
+		t.Errorf("after 2 failures RetryAt = %v, want %v", retryAt, now.Add(2*time.Second))
+	}
+
+	// 达到阈值后锁定，锁定结束后计数已清零
+	now = now.Add(2 * time.Second)
+	lockedUntil := l.RecordFailure("ghost", now)
+	if want := now.Add(15 * time.Minute); !lockedUntil.Equal(want) {
+		t.Fatalf("after 3 failures lockedUntil = %v, want %v", lockedUntil, want)
+	}
+	if retryAt, locked := l.RetryAt("ghost", now.Add(time.Minute)); !retryAt.Equal(lockedUntil) || !locked {
+		t.Errorf("while locked RetryAt = %v, %v, want %v and locked", retryAt, locked, lockedUntil)
+	}
+	if retryAt, _ := l.RetryAt("ghost", lockedUntil); !retryAt.IsZero() {
+		t.Errorf("after lockout RetryAt = %v, want zero", retryAt)
+	}
+	if lockedUntil := l.RecordFailure("ghost", lockedUntil); !lockedUntil.IsZero() {
+		t.Errorf("first failure after lockout locked the username until %v", lockedUntil)
+	}
+
+	// 其他用户名不受影响
+	if retryAt, _ := l.RetryAt("other", now); !retryAt.IsZero() {
+		t.Errorf("other username RetryAt = %v, want zero", retryAt)
+	}
+}
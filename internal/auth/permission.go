@@ -0,0 +1,99 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/phone_management/internal/models"
+	"github.com/phone_management/pkg/utils"
+)
+
+// Permission 表示一项可授权的操作
+type Permission string
+
+// 系统中的权限定义
+const (
+	PermissionMobileNumberRead   Permission = "mobilenumber:read"   // 查看号码
+	PermissionMobileNumberWrite  Permission = "mobilenumber:write"  // 新增、修改、导入号码及处理风险号码
+	PermissionMobileNumberAssign Permission = "mobilenumber:assign" // 分配、回收号码
+	PermissionEmployeeRead       Permission = "employee:read"       // 查看员工
+	PermissionEmployeeWrite      Permission = "employee:write"      // 新增、修改、导入员工
+	PermissionVerificationRead   Permission = "verification:read"   // 查看号码确认进度
+	PermissionVerificationManage Permission = "verification:manage" // 发起号码确认
+	PermissionVerificationRemind Permission = "verification:remind" // 催办未确认的员工
+	PermissionUserManage         Permission = "user:manage"         // 管理系统用户
+	PermissionAuditRead          Permission = "audit:read"          // 查看操作审计日志
+	PermissionNotificationManage Permission = "notification:manage" // 管理通知模板
+)
+
+// rolePermissions 声明每个角色拥有的权限，管理员拥有全部权限
+var rolePermissions = map[string][]Permission{
+	models.RoleAdmin: {
+		PermissionMobileNumberRead,
+		PermissionMobileNumberWrite,
+		PermissionMobileNumberAssign,
+		PermissionEmployeeRead,
+		PermissionEmployeeWrite,
+		PermissionVerificationRead,
+		PermissionVerificationManage,
+		PermissionVerificationRemind,
+		PermissionUserManage,
+		PermissionAuditRead,
+		PermissionNotificationManage,
+	},
+	models.RoleOperator: {
+		PermissionMobileNumberRead,
+		PermissionMobileNumberAssign,
+		PermissionEmployeeRead,
+		PermissionVerificationRead,
+	},
+	models.RoleViewer: {
+		PermissionMobileNumberRead,
+		PermissionEmployeeRead,
+		PermissionVerificationRead,
+	},
+	// 部门负责人的数据范围由 JWTMiddleware 写入请求 context，仓库层据此只返回所负责部门的数据
+	models.RoleDepartmentManager: {
+		PermissionMobileNumberRead,
+		PermissionVerificationRead,
+		PermissionVerificationRemind,
+	},
+}
+
+// PermissionDeniedDetails 是权限不足时返回的错误详情
+type PermissionDeniedDetails struct {
+	Role               string     `json:"role"`
+	RequiredPermission Permission `json:"requiredPermission"`
+}
+
+// HasPermission 检查角色是否拥有指定权限
+func HasPermission(role string, permission Permission) bool {
+	for _, p := range rolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// RequirePermission 返回一个中间件，要求当前用户的角色拥有指定权限。
+// 必须在 JWTMiddleware 之后使用。
+func RequirePermission(permission Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, ok := GetCurrentUserRole(c)
+		if !ok {
+			utils.RespondUnauthorizedError(c)
+			return
+		}
+
+		if !HasPermission(role, permission) {
+			utils.RespondAPIError(c, http.StatusForbidden, "权限不足", PermissionDeniedDetails{
+				Role:               role,
+				RequiredPermission: permission,
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,89 @@
+package auth
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/phone_management/configs"
+	"github.com/phone_management/internal/models"
+)
+
+const (
+	tokenIssuer         = "phone_system" // 签发者
+	accessTokenAudience = "admin"        // 访问令牌的受众
+	mfaTokenAudience    = "mfa"          // 两步验证临时令牌的受众，不能用于访问业务接口
+	mfaTokenTTL         = 5 * time.Minute
+)
+
+// GenerateAccessToken 为用户签发短期访问令牌，返回令牌及其过期时间
+func GenerateAccessToken(user *models.User) (string, time.Time, error) {
+	expirationTime := time.Now().Add(configs.AppConfig.AccessTokenTTL)
+	claims := &Claims{
+		UserID:   uint(user.ID),
+		Username: user.Username,
+		Role:     user.Role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Subject:   user.Username,
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(expirationTime),
+			Issuer:    tokenIssuer,
+			Audience:  jwt.ClaimStrings{accessTokenAudience},
+		},
+	}
+
+	return signClaims(claims, expirationTime)
+}
+
+// GenerateMFAToken 签发密码校验通过后、两步验证完成前使用的临时令牌
+func GenerateMFAToken(user *models.User) (string, time.Time, error) {
+	expirationTime := time.Now().Add(mfaTokenTTL)
+	claims := &Claims{
+		UserID:   uint(user.ID),
+		Username: user.Username,
+		Role:     user.Role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Subject:   user.Username,
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(expirationTime),
+			Issuer:    tokenIssuer,
+			Audience:  jwt.ClaimStrings{mfaTokenAudience},
+		},
+	}
+	return signClaims(claims, expirationTime)
+}
+
+// ParseMFAToken 校验两步验证临时令牌，返回其声明
+func ParseMFAToken(tokenString string) (*Claims, error) {
+	_, claims, err := parseClaims(tokenString, mfaTokenAudience)
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// signClaims 使用密钥环中的当前密钥签名
+func signClaims(claims *Claims, expirationTime time.Time) (string, time.Time, error) {
+	keyring, err := getKeyring()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	tokenString, err := keyring.Sign(claims)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return tokenString, expirationTime, nil
+}
+
+// parseClaims 使用密钥环验证令牌并解析声明，audience 为要求的受众
+func parseClaims(tokenString, audience string) (*jwt.Token, *Claims, error) {
+	keyring, err := getKeyring()
+	if err != nil {
+		return nil, nil, err
+	}
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, keyring.KeyFunc, jwt.WithAudience(audience))
+	return token, claims, err
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/phone_management/internal/models"
+	"github.com/phone_management/internal/services"
+	"github.com/phone_management/pkg/utils"
+)
+
+// AuditHandler 封装了操作审计日志相关的 HTTP 处理逻辑
+type AuditHandler struct {
+	service services.AuditService
+}
+
+// NewAuditHandler 创建一个新的 AuditHandler 实例
+func NewAuditHandler(service services.AuditService) *AuditHandler {
+	return &AuditHandler{service: service}
+}
+
+// PagedAuditEventsData 定义了审计事件列表的分页响应结构
+type PagedAuditEventsData struct {
+	Items      []models.AuditEvent `json:"items"`
+	Pagination PaginationInfo      `json:"pagination"`
+}
+
+// GetAuditEvents godoc
+// @Summary 查询操作审计日志
+// @Description 分页查询操作审计事件，按发生时间倒序。可按操作者、操作类型、实体类型和标识、来源以及时间范围筛选。
+// @Description 实体标识：号码为手机号，员工为工号，用户为用户ID，确认批次为批次ID。
+// @Description from/to 支持 RFC3339 时间或 YYYY-MM-DD 日期；to 为日期时包含当天。
+// @Tags AuditEvents
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量" default(20)
+// @Param actor query string false "操作者用户名"
+// @Param action query string false "操作类型，如 mobile_number.update"
+// @Param entityType query string false "实体类型 (mobile_number, employee, user, verification_batch)"
+// @Param entityId query string false "实体标识"
+// @Param source query string false "来源 (api, import, bulk, verification, system, directory, hr)"
+// @Param from query string false "起始时间"
+// @Param to query string false "结束时间"
+// @Success 200 {object} utils.SuccessResponse{data=PagedAuditEventsData} "成功响应，包含审计事件列表和分页信息"
+// @Failure 400 {object} utils.APIErrorResponse "请求参数错误"
+// @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
+// @Failure 403 {object} utils.APIErrorResponse "权限不足"
+// @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
+// @Router /audit-events [get]
+// @Security BearerAuth
+func (h *AuditHandler) GetAuditEvents(c *gin.Context) {
+	type GetAuditEventsQuery struct {
+		Page       int    `form:"page,default=1"`
+		Limit      int    `form:"limit,default=20"`
+		Actor      string `form:"actor"`
+		Action     string `form:"action"`
+		EntityType string `form:"entityType"`
+		EntityID   string `form:"entityId"`
+		Source     string `form:"source"`
+		From       string `form:"from"`
+		To         string `form:"to"`
+	}
+
+	var queryParams GetAuditEventsQuery
+	if err := c.ShouldBindQuery(&queryParams); err != nil {
+		utils.RespondValidationError(c, err.Error())
+		return
+	}
+	if queryParams.Limit <= 0 {
+		queryParams.Limit = 20
+	}
+	if queryParams.Limit > 100 {
+		queryParams.Limit = 100
+	}
+	if queryParams.Page <= 0 {
+		queryParams.Page = 1
+	}
+
+	filter := models.AuditEventFilter{
+		Actor:      queryParams.Actor,
+		Action:     queryParams.Action,
+		EntityType: queryParams.EntityType,
+		EntityID:   queryParams.EntityID,
+		Source:     queryParams.Source,
+	}
+	var err error
+	if filter.From, err = parseAuditTimeParam(queryParams.From, false); err != nil {
+		utils.RespondValidationError(c, "无效的起始时间: "+queryParams.From)
+		return
+	}
+	if filter.To, err = parseAuditTimeParam(queryParams.To, true); err != nil {
+		utils.RespondValidationError(c, "无效的结束时间: "+queryParams.To)
+		return
+	}
+
+	events, totalItems, err := h.service.ListAuditEvents(filter, queryParams.Page, queryParams.Limit)
+	if err != nil {
+		utils.RespondInternalServerError(c, "获取审计日志失败", err.Error())
+		return
+	}
+
+	totalPages := (totalItems + int64(queryParams.Limit) - 1) / int64(queryParams.Limit)
+	pagedData := PagedAuditEventsData{
+		Items: events,
+		Pagination: PaginationInfo{
+			TotalItems:  totalItems,
+			TotalPages:  totalPages,
+			CurrentPage: queryParams.Page,
+			PageSize:    queryParams.Limit,
+		},
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, pagedData, "审计日志获取成功")
+}
+
+// parseAuditTimeParam 解析 RFC3339 时间或 YYYY-MM-DD 日期。
+// 作为结束时间的日期表示包含当天，因此返回次日零点。
+func parseAuditTimeParam(value string, endOfRange bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return nil, err
+	}
+	if endOfRange {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
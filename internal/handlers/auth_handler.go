@@ -1,29 +1,46 @@
 package handlers
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
-	"github.com/google/uuid"
-	"golang.org/x/crypto/bcrypt"
 
-	"github.com/phone_management/configs"
 	"github.com/phone_management/internal/auth"
 	"github.com/phone_management/internal/models"
-	"github.com/phone_management/pkg/db"
+	"github.com/phone_management/internal/services"
 	"github.com/phone_management/pkg/utils"
 )
 
+// AuthHandler 封装了登录认证相关的 HTTP 处理逻辑
+type AuthHandler struct {
+	service services.AuthService
+}
+
+// NewAuthHandler 创建一个新的 AuthHandler 实例
+func NewAuthHandler(service services.AuthService) *AuthHandler {
+	return &AuthHandler{service: service}
+}
+
 type LoginRequest struct {
 	Username string `json:"username" binding:"required"`
 	Password string `json:"password" binding:"required"`
 }
 
 type LoginResponse struct {
-	Token string   `json:"token"`
-	User  UserInfo `json:"user"`
+	Token        string     `json:"token,omitempty"`        // 短期访问令牌
+	ExpiresAt    *time.Time `json:"expiresAt,omitempty"`    // 访问令牌过期时间
+	RefreshToken string     `json:"refreshToken,omitempty"` // 刷新令牌，每次刷新后轮换
+
+	// 用户启用了两步验证时，密码校验通过后只返回以下字段，需携带 MFAToken 调用 /auth/login/2fa
+	MFARequired       bool       `json:"mfaRequired"`
+	MFAToken          string     `json:"mfaToken,omitempty"`
+	MFATokenExpiresAt *time.Time `json:"mfaTokenExpiresAt,omitempty"`
+
+	User UserInfo `json:"user"`
 }
 
 type UserInfo struct {
@@ -31,9 +48,25 @@ type UserInfo struct {
 	Role     string `json:"role"`
 }
 
+// RefreshTokenRequest 定义了刷新令牌和登出时的请求体
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
+
+// MFALoginRequest 定义了两步验证登录第二步的请求体
+type MFALoginRequest struct {
+	MFAToken string `json:"mfaToken" binding:"required"` // 第一步登录返回的临时令牌
+	Code     string `json:"code" binding:"required"`     // TOTP 验证码或恢复码
+}
+
+// LogoutRequest 定义了登出请求体，提供刷新令牌时会一并吊销
+type LogoutRequest struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
 // Login godoc
 // @Summary 管理员登录
-// @Description 验证管理员凭证并返回 JWT
+// @Description 验证管理员凭证，返回短期访问令牌和刷新令牌。启用了两步验证的账号只返回 mfaRequired=true 和临时令牌 mfaToken，需继续调用 /auth/login/2fa。
 // @Tags auth
 // @Accept  json
 // @Produce  json
@@ -41,68 +74,162 @@ type UserInfo struct {
 // @Success 200 {object} utils.SuccessResponse{data=LoginResponse} "登录成功，返回 Token 和用户信息"
 // @Failure 400 {object} utils.APIErrorResponse "请求参数错误"
 // @Failure 401 {object} utils.APIErrorResponse "无效的用户名或密码"
+// @Failure 403 {object} utils.APIErrorResponse "账号已被禁用"
+// @Failure 423 {object} utils.APIErrorResponse{details=LoginBlockedDetails} "登录失败次数过多，账号已被临时锁定"
+// @Failure 429 {object} utils.APIErrorResponse{details=LoginBlockedDetails} "登录尝试过于频繁"
 // @Failure 500 {object} utils.APIErrorResponse "无法生成Token"
 // @Router /auth/login [post]
-func Login(c *gin.Context) {
+func (h *AuthHandler) Login(c *gin.Context) {
 	var req LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		utils.RespondValidationError(c, err.Error())
 		return
 	}
 
-	var user models.User
-	if err := db.GetDB().Where("username = ?", req.Username).First(&user).Error; err != nil {
-		utils.RespondUnauthorizedError(c, "无效的用户名或密码")
+	result, err := h.service.Login(req.Username, req.Password, c.ClientIP())
+	if err != nil {
+		respondAuthServiceError(c, err, "无法生成Token")
 		return
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
-		utils.RespondUnauthorizedError(c, "无效的用户名或密码")
+	if result.Tokens == nil {
+		utils.RespondSuccess(c, http.StatusOK, LoginResponse{
+			MFARequired:       true,
+			MFAToken:          result.MFAToken,
+			MFATokenExpiresAt: &result.MFATokenExpiresAt,
+			User: UserInfo{
+				Username: result.User.Username,
+				Role:     result.User.Role,
+			},
+		}, "请输入两步验证码")
 		return
 	}
 
-	expirationTime := time.Now().Add(24 * time.Hour)
-	claims := &auth.Claims{
-		UserID:   uint(user.ID),
-		Username: user.Username,
-		Role:     user.Role,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ID:        uuid.NewString(),
-			Subject:   user.Username,
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
-			Issuer:    "phone_system",            // 可选，签发者
-			Audience:  jwt.ClaimStrings{"admin"}, // 可选，受众
-		},
+	utils.RespondSuccess(c, http.StatusOK, newLoginResponse(result.Tokens, result.User), "登录成功")
+}
+
+// LoginWithTOTP godoc
+// @Summary 两步验证登录
+// @Description 使用第一步登录返回的临时令牌和 TOTP 验证码（或恢复码）完成登录，返回访问令牌和刷新令牌。临时令牌只能成功使用一次。
+// @Tags auth
+// @Accept  json
+// @Produce  json
+// @Param body body MFALoginRequest true "临时令牌和验证码"
+// @Success 200 {object} utils.SuccessResponse{data=LoginResponse} "登录成功，返回 Token 和用户信息"
+// @Failure 400 {object} utils.APIErrorResponse "请求参数错误"
+// @Failure 401 {object} utils.APIErrorResponse "临时令牌无效或验证码错误"
+// @Failure 403 {object} utils.APIErrorResponse "账号已被禁用"
+// @Failure 423 {object} utils.APIErrorResponse{details=LoginBlockedDetails} "登录失败次数过多，账号已被临时锁定"
+// @Failure 429 {object} utils.APIErrorResponse{details=LoginBlockedDetails} "登录尝试过于频繁"
+// @Failure 500 {object} utils.APIErrorResponse "无法生成Token"
+// @Router /auth/login/2fa [post]
+func (h *AuthHandler) LoginWithTOTP(c *gin.Context) {
+	var req MFALoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondValidationError(c, err.Error())
+		return
+	}
+
+	pair, user, err := h.service.CompleteMFALogin(req.MFAToken, req.Code, c.ClientIP())
+	if err != nil {
+		respondAuthServiceError(c, err, "无法生成Token")
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, newLoginResponse(pair, user), "登录成功")
+}
+
+// Refresh godoc
+// @Summary 刷新访问令牌
+// @Description 使用刷新令牌换取新的访问令牌和刷新令牌，旧刷新令牌随即失效。已失效的刷新令牌被再次使用时，该登录会话的所有刷新令牌都会被吊销。
+// @Tags auth
+// @Accept  json
+// @Produce  json
+// @Param body body RefreshTokenRequest true "刷新令牌"
+// @Success 200 {object} utils.SuccessResponse{data=LoginResponse} "刷新成功，返回新的令牌"
+// @Failure 400 {object} utils.APIErrorResponse "请求参数错误"
+// @Failure 401 {object} utils.APIErrorResponse "刷新令牌无效、已过期或被重复使用"
+// @Failure 403 {object} utils.APIErrorResponse "账号已被禁用"
+// @Failure 500 {object} utils.APIErrorResponse "无法生成Token"
+// @Router /auth/refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondValidationError(c, err.Error())
+		return
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(configs.AppConfig.JWTSecret))
+	pair, user, err := h.service.Refresh(req.RefreshToken)
 	if err != nil {
-		utils.RespondInternalServerError(c, "无法生成Token", err.Error())
+		respondAuthServiceError(c, err, "无法生成Token")
 		return
 	}
 
-	loginResp := LoginResponse{
-		Token: tokenString,
+	utils.RespondSuccess(c, http.StatusOK, newLoginResponse(pair, user), "令牌刷新成功")
+}
+
+// newLoginResponse 组装登录和刷新接口的响应
+func newLoginResponse(pair *services.TokenPair, user *models.User) LoginResponse {
+	return LoginResponse{
+		Token:        pair.AccessToken,
+		ExpiresAt:    &pair.AccessTokenExpiresAt,
+		RefreshToken: pair.RefreshToken,
 		User: UserInfo{
 			Username: user.Username,
 			Role:     user.Role,
 		},
 	}
-	utils.RespondSuccess(c, http.StatusOK, loginResp, "登录成功")
 }
 
-// LogoutHandler godoc
+// LoginBlockedDetails 是登录被锁定或限流时返回的错误详情
+type LoginBlockedDetails struct {
+	RetryAt time.Time `json:"retryAt"` // 允许再次尝试的时间
+}
+
+// respondAuthServiceError 将认证服务层错误映射为 HTTP 响应
+func respondAuthServiceError(c *gin.Context, err error, defaultMessage string) {
+	var blockedErr *services.LoginBlockedError
+	if errors.As(err, &blockedErr) {
+		status := http.StatusTooManyRequests
+		if errors.Is(err, services.ErrAccountLocked) {
+			status = http.StatusLocked
+		}
+		retryAfter := int(math.Ceil(time.Until(blockedErr.RetryAt).Seconds()))
+		if retryAfter < 1 {
+			retryAfter = 1
+		}
+		c.Header("Retry-After", strconv.Itoa(retryAfter))
+		utils.RespondAPIError(c, status, err.Error(), LoginBlockedDetails{RetryAt: blockedErr.RetryAt})
+		return
+	}
+
+	switch {
+	case errors.Is(err, services.ErrInvalidCredentials),
+		errors.Is(err, services.ErrInvalidRefreshToken),
+		errors.Is(err, services.ErrRefreshTokenReused),
+		errors.Is(err, services.ErrInvalidMFAToken),
+		errors.Is(err, services.ErrInvalidTOTPCode):
+		utils.RespondUnauthorizedError(c, err.Error())
+	case errors.Is(err, services.ErrUserDisabled):
+		utils.RespondAPIError(c, http.StatusForbidden, err.Error(), nil)
+	default:
+		utils.RespondInternalServerError(c, defaultMessage, err.Error())
+	}
+}
+
+// Logout godoc
 // @Summary User logout
-// @Description Logs out the current user by invalidating their token.
+// @Description Logs out the current user by invalidating their token. If a refresh token is provided, its whole session is revoked as well.
 // @Tags auth
 // @Security BearerAuth
 // @Accept  json
 // @Produce  json
+// @Param body body LogoutRequest false "要一并吊销的刷新令牌"
 // @Success 200 {object} utils.SuccessResponse "成功登出"
 // @Failure 400 {object} utils.APIErrorResponse "错误的请求 (例如，上下文中缺少JTI或EXP)"
+// @Failure 500 {object} utils.APIErrorResponse "登出失败"
 // @Router /auth/logout [post]
-func LogoutHandler(c *gin.Context) {
+func (h *AuthHandler) Logout(c *gin.Context) {
 	jtiVal, jtiExists := c.Get("jti")
 	expVal, expExists := c.Get("exp")
 
@@ -123,6 +250,24 @@ func LogoutHandler(c *gin.Context) {
 		return
 	}
 
-	auth.AddToDenylist(jti, exp)
+	if err := auth.AddToDenylist(jti, exp); err != nil {
+		utils.RespondInternalServerError(c, "登出失败", err.Error())
+		return
+	}
+
+	// 请求体可选，提供刷新令牌时吊销其所在的登录会话
+	var req LogoutRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.RespondValidationError(c, err.Error())
+			return
+		}
+	}
+	if req.RefreshToken != "" {
+		if err := h.service.RevokeRefreshToken(req.RefreshToken); err != nil {
+			utils.RespondInternalServerError(c, "登出失败", err.Error())
+			return
+		}
+	}
 	utils.RespondSuccess(c, http.StatusOK, nil, "成功登出")
 }
@@ -0,0 +1,259 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/phone_management/internal/models"
+	"github.com/phone_management/internal/services"
+	"github.com/phone_management/pkg/utils"
+)
+
+// DepartmentHandler 封装了部门相关的 HTTP 处理逻辑
+type DepartmentHandler struct {
+	service services.DepartmentService
+}
+
+// NewDepartmentHandler 创建一个新的 DepartmentHandler 实例
+func NewDepartmentHandler(service services.DepartmentService) *DepartmentHandler {
+	return &DepartmentHandler{service: service}
+}
+
+// GetDepartments godoc
+// @Summary 获取部门列表
+// @Description 返回全部部门（平铺），按名称升序，包含上级部门名称、负责人姓名、别名和直属员工数量。
+// @Tags Departments
+// @Produce json
+// @Success 200 {object} utils.SuccessResponse{data=[]models.DepartmentResponse} "成功响应，包含部门列表"
+// @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
+// @Failure 403 {object} utils.APIErrorResponse "权限不足"
+// @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
+// @Router /departments [get]
+// @Security BearerAuth
+func (h *DepartmentHandler) GetDepartments(c *gin.Context) {
+	departments, err := h.service.GetDepartments(c.Request.Context())
+	if err != nil {
+		utils.RespondInternalServerError(c, "获取部门列表失败", err.Error())
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, departments, "部门列表获取成功")
+}
+
+// GetDepartmentTree godoc
+// @Summary 获取部门树
+// @Description 按上级部门关系返回部门树，顶级部门及各级下级部门均按名称升序。
+// @Tags Departments
+// @Produce json
+// @Success 200 {object} utils.SuccessResponse{data=[]models.DepartmentResponse} "成功响应，包含部门树"
+// @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
+// @Failure 403 {object} utils.APIErrorResponse "权限不足"
+// @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
+// @Router /departments/tree [get]
+// @Security BearerAuth
+func (h *DepartmentHandler) GetDepartmentTree(c *gin.Context) {
+	tree, err := h.service.GetDepartmentTree(c.Request.Context())
+	if err != nil {
+		utils.RespondInternalServerError(c, "获取部门树失败", err.Error())
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, tree, "部门树获取成功")
+}
+
+// CreateDepartment godoc
+// @Summary 新增部门
+// @Description 创建部门，可指定上级部门、部门负责人和成本中心。部门名称会去除多余空白，且不能与已有部门名称或别名重复。
+// @Tags Departments
+// @Accept json
+// @Produce json
+// @Param department body models.CreateDepartmentPayload true "部门信息"
+// @Success 201 {object} utils.SuccessResponse{data=models.Department} "创建成功的部门"
+// @Failure 400 {object} utils.APIErrorResponse "请求参数错误、上级部门或负责人不存在"
+// @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
+// @Failure 403 {object} utils.APIErrorResponse "权限不足"
+// @Failure 409 {object} utils.APIErrorResponse "部门名称已存在"
+// @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
+// @Router /departments [post]
+// @Security BearerAuth
+func (h *DepartmentHandler) CreateDepartment(c *gin.Context) {
+	var payload models.CreateDepartmentPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.RespondValidationError(c, err.Error())
+		return
+	}
+
+	department, err := h.service.CreateDepartment(c.Request.Context(), payload)
+	if err != nil {
+		respondDepartmentServiceError(c, err, "创建部门失败")
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusCreated, department, "部门创建成功")
+}
+
+// UpdateDepartment godoc
+// @Summary 更新部门
+// @Description 更新部门名称、上级部门、负责人或成本中心，所有字段均可选。修改名称时原名称记为别名，并同步更新该部门员工的部门名称。
+// @Description parentId 为 0 表示改为顶级部门；不能将部门移到自身或其下级部门之下。managerEmployeeId、costCenter 为空字符串表示清除。
+// @Tags Departments
+// @Accept json
+// @Produce json
+// @Param id path int true "部门ID"
+// @Param department body models.UpdateDepartmentPayload true "要更新的部门字段"
+// @Success 200 {object} utils.SuccessResponse{data=models.Department} "更新后的部门"
+// @Failure 400 {object} utils.APIErrorResponse "请求参数错误、上级部门无效或负责人不存在"
+// @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
+// @Failure 403 {object} utils.APIErrorResponse "权限不足"
+// @Failure 404 {object} utils.APIErrorResponse "部门未找到"
+// @Failure 409 {object} utils.APIErrorResponse "部门名称已存在"
+// @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
+// @Router /departments/{id}/update [post]
+// @Security BearerAuth
+func (h *DepartmentHandler) UpdateDepartment(c *gin.Context) {
+	id, ok := parseDepartmentIDParam(c)
+	if !ok {
+		return
+	}
+
+	var payload models.UpdateDepartmentPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.RespondValidationError(c, err.Error())
+		return
+	}
+
+	department, err := h.service.UpdateDepartment(c.Request.Context(), id, payload)
+	if err != nil {
+		respondDepartmentServiceError(c, err, "更新部门失败")
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, department, "部门更新成功")
+}
+
+// DeleteDepartment godoc
+// @Summary 删除部门
+// @Description 删除没有下级部门和员工的部门，部门的别名一并删除。
+// @Tags Departments
+// @Produce json
+// @Param id path int true "部门ID"
+// @Success 200 {object} utils.SuccessResponse "删除成功"
+// @Failure 400 {object} utils.APIErrorResponse "无效的部门ID"
+// @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
+// @Failure 403 {object} utils.APIErrorResponse "权限不足"
+// @Failure 404 {object} utils.APIErrorResponse "部门未找到"
+// @Failure 409 {object} utils.APIErrorResponse "部门仍有下级部门或员工"
+// @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
+// @Router /departments/{id} [delete]
+// @Security BearerAuth
+func (h *DepartmentHandler) DeleteDepartment(c *gin.Context) {
+	id, ok := parseDepartmentIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteDepartment(c.Request.Context(), id); err != nil {
+		respondDepartmentServiceError(c, err, "删除部门失败")
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, nil, "部门删除成功")
+}
+
+// MergeDepartments godoc
+// @Summary 合并部门
+// @Description 将同一部门的不同写法合并到目标部门：被合并部门的员工和下级部门转到目标部门，其名称和别名记为目标部门的别名，随后删除被合并部门。
+// @Tags Departments
+// @Accept json
+// @Produce json
+// @Param id path int true "目标部门ID"
+// @Param merge body models.MergeDepartmentsPayload true "被合并的部门ID列表"
+// @Success 200 {object} utils.SuccessResponse{data=models.Department} "合并后的目标部门"
+// @Failure 400 {object} utils.APIErrorResponse "请求参数错误，或被合并部门不存在、是目标部门本身或其上级部门"
+// @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
+// @Failure 403 {object} utils.APIErrorResponse "权限不足"
+// @Failure 404 {object} utils.APIErrorResponse "目标部门未找到"
+// @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
+// @Router /departments/{id}/merge [post]
+// @Security BearerAuth
+func (h *DepartmentHandler) MergeDepartments(c *gin.Context) {
+	id, ok := parseDepartmentIDParam(c)
+	if !ok {
+		return
+	}
+
+	var payload models.MergeDepartmentsPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.RespondValidationError(c, err.Error())
+		return
+	}
+
+	department, err := h.service.MergeDepartments(c.Request.Context(), id, payload)
+	if err != nil {
+		respondDepartmentServiceError(c, err, "合并部门失败")
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, department, "部门合并成功")
+}
+
+// parseDepartmentIDParam 解析路径参数中的部门ID，失败时直接返回 400
+func parseDepartmentIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		utils.RespondAPIError(c, http.StatusBadRequest, "无效的部门ID", c.Param("id"))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// bindDepartmentFilter 从查询参数 departmentId、department（或 departmentName）和 includeSubDepartments 解析部门筛选条件，
+// 失败时直接返回 400
+func bindDepartmentFilter(c *gin.Context) (models.DepartmentFilter, bool) {
+	var filter models.DepartmentFilter
+
+	if value := c.Query("departmentId"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 64)
+		if err != nil || id == 0 {
+			utils.RespondAPIError(c, http.StatusBadRequest, "无效的部门ID", value)
+			return filter, false
+		}
+		departmentID := uint(id)
+		filter.DepartmentID = &departmentID
+	}
+
+	filter.DepartmentName = c.Query("department")
+	if filter.DepartmentName == "" {
+		filter.DepartmentName = c.Query("departmentName")
+	}
+
+	if value := c.Query("includeSubDepartments"); value != "" {
+		include, err := strconv.ParseBool(value)
+		if err != nil {
+			utils.RespondAPIError(c, http.StatusBadRequest, "includeSubDepartments 必须是 true 或 false", value)
+			return filter, false
+		}
+		filter.IncludeSubDepartments = include
+	}
+	return filter, true
+}
+
+// respondDepartmentServiceError 将部门服务层错误映射为 HTTP 响应
+func respondDepartmentServiceError(c *gin.Context, err error, defaultMessage string) {
+	switch {
+	case errors.Is(err, services.ErrDepartmentNotFound):
+		utils.RespondNotFoundError(c, "部门")
+	case errors.Is(err, services.ErrDepartmentNameExists), errors.Is(err, services.ErrDepartmentInUse):
+		utils.RespondConflictError(c, err.Error())
+	case errors.Is(err, services.ErrInvalidDepartmentName),
+		errors.Is(err, services.ErrInvalidDepartmentParent),
+		errors.Is(err, services.ErrDepartmentManagerNotFound),
+		errors.Is(err, services.ErrInvalidDepartmentMerge),
+		errors.Is(err, services.ErrNoUpdateFields):
+		utils.RespondAPIError(c, http.StatusBadRequest, err.Error(), nil)
+	default:
+		utils.RespondInternalServerError(c, defaultMessage, err.Error())
+	}
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/phone_management/internal/models"
+	"github.com/phone_management/internal/services"
+	"github.com/phone_management/pkg/utils"
+)
+
+// DirectorySyncHandler 封装了员工目录同步相关的 HTTP 处理逻辑
+type DirectorySyncHandler struct {
+	service services.DirectorySyncService
+}
+
+// NewDirectorySyncHandler 创建一个新的 DirectorySyncHandler 实例
+func NewDirectorySyncHandler(service services.DirectorySyncService) *DirectorySyncHandler {
+	return &DirectorySyncHandler{service: service}
+}
+
+// SyncDirectory godoc
+// @Summary 从员工目录同步员工
+// @Description 从 LDAP/Active Directory 读取用户，按配置的属性（默认邮箱）与员工匹配：目录中新增的用户新建为员工，姓名、手机号码、邮箱或部门有变化的员工更新信息，目录账号已禁用或已从目录删除的员工办理离职。
+// @Description 员工的新建、更新和离职与手动操作的校验一致；仍在使用号码的员工不会自动离职，在报告中记为失败，需要手动指定号码处置方式。目录中不存在的部门会自动创建。
+// @Description 需办理的离职数超过上限时不办理任何离职。dryRun 为 true 时只生成报告，不修改员工数据。同一时间只能进行一次同步。
+// @Tags Employees
+// @Produce json
+// @Param dryRun query bool false "是否只生成报告" default(false)
+// @Success 200 {object} utils.SuccessResponse{data=models.DirectorySyncRun} "同步报告"
+// @Failure 400 {object} utils.APIErrorResponse "请求参数错误或未配置员工目录服务器"
+// @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
+// @Failure 403 {object} utils.APIErrorResponse "权限不足"
+// @Failure 409 {object} utils.APIErrorResponse "已有同步正在进行"
+// @Failure 502 {object} utils.APIErrorResponse "读取员工目录失败"
+// @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
+// @Router /employees/directory-sync [post]
+// @Security BearerAuth
+func (h *DirectorySyncHandler) SyncDirectory(c *gin.Context) {
+	type SyncDirectoryQuery struct {
+		DryRun bool `form:"dryRun,default=false"`
+	}
+
+	var queryParams SyncDirectoryQuery
+	if err := c.ShouldBindQuery(&queryParams); err != nil {
+		utils.RespondValidationError(c, err.Error())
+		return
+	}
+
+	run, err := h.service.Sync(c.Request.Context(), models.DirectorySyncManual, queryParams.DryRun)
+	if err != nil {
+		if errors.Is(err, services.ErrDirectoryNotConfigured) {
+			utils.RespondAPIError(c, http.StatusBadRequest, err.Error(), nil)
+		} else if errors.Is(err, services.ErrDirectorySyncInProgress) {
+			utils.RespondConflictError(c, err.Error())
+		} else if errors.Is(err, services.ErrDirectoryUnavailable) {
+			utils.RespondAPIError(c, http.StatusBadGateway, err.Error(), run)
+		} else {
+			utils.RespondInternalServerError(c, "员工目录同步失败", err.Error())
+		}
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, run, "员工目录同步完成")
+}
+
+// GetDirectorySyncRuns godoc
+// @Summary 获取员工目录同步记录
+// @Description 按开始时间倒序返回最近的目录同步记录及各类计数，不含报告条目。
+// @Tags Employees
+// @Produce json
+// @Param limit query int false "返回的记录数 (1-100)" default(20)
+// @Success 200 {object} utils.SuccessResponse{data=[]models.DirectorySyncRun} "成功响应，包含同步记录列表"
+// @Failure 400 {object} utils.APIErrorResponse "请求参数错误"
+// @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
+// @Failure 403 {object} utils.APIErrorResponse "权限不足"
+// @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
+// @Router /employees/directory-sync/runs [get]
+// @Security BearerAuth
+func (h *DirectorySyncHandler) GetDirectorySyncRuns(c *gin.Context) {
+	type GetDirectorySyncRunsQuery struct {
+		Limit int `form:"limit,default=20" binding:"min=1,max=100"`
+	}
+
+	var queryParams GetDirectorySyncRunsQuery
+	if err := c.ShouldBindQuery(&queryParams); err != nil {
+		utils.RespondValidationError(c, err.Error())
+		return
+	}
+
+	runs, err := h.service.GetRuns(c.Request.Context(), queryParams.Limit)
+	if err != nil {
+		utils.RespondInternalServerError(c, "获取目录同步记录失败", err.Error())
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, runs, "目录同步记录获取成功")
+}
+
+// GetDirectorySyncRun godoc
+// @Summary 获取一次员工目录同步的报告
+// @Description 返回同步记录及报告条目。报告列出新建、更新、离职、跳过和失败的员工，未变更的员工只计数。
+// @Tags Employees
+// @Produce json
+// @Param runId path int true "同步记录ID"
+// @Success 200 {object} utils.SuccessResponse{data=models.DirectorySyncRun} "同步报告"
+// @Failure 400 {object} utils.APIErrorResponse "无效的同步记录ID"
+// @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
+// @Failure 403 {object} utils.APIErrorResponse "权限不足"
+// @Failure 404 {object} utils.APIErrorResponse "同步记录未找到"
+// @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
+// @Router /employees/directory-sync/runs/{runId} [get]
+// @Security BearerAuth
+func (h *DirectorySyncHandler) GetDirectorySyncRun(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("runId"), 10, 64)
+	if err != nil || id == 0 {
+		utils.RespondAPIError(c, http.StatusBadRequest, "无效的同步记录ID", c.Param("runId"))
+		return
+	}
+
+	run, err := h.service.GetRun(c.Request.Context(), uint(id))
+	if err != nil {
+		if errors.Is(err, services.ErrDirectorySyncRunNotFound) {
+			utils.RespondNotFoundError(c, "目录同步记录")
+		} else {
+			utils.RespondInternalServerError(c, "获取目录同步报告失败", err.Error())
+		}
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, run, "目录同步报告获取成功")
+}
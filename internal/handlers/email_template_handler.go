@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/phone_management/internal/models"
+	"github.com/phone_management/internal/services"
+	"github.com/phone_management/pkg/utils"
+)
+
+// EmailTemplateHandler 封装了通知模板相关的 HTTP 处理逻辑
+type EmailTemplateHandler struct {
+	service services.EmailTemplateService
+}
+
+// NewEmailTemplateHandler 创建一个新的 EmailTemplateHandler 实例
+func NewEmailTemplateHandler(service services.EmailTemplateService) *EmailTemplateHandler {
+	return &EmailTemplateHandler{service: service}
+}
+
+// GetEmailTemplates godoc
+// @Summary 获取通知模板列表
+// @Description 返回数据库中保存的全部通知模板，按名称、语言升序。未保存到数据库的模板使用 EMAIL_TEMPLATE_DIR 中的模板文件或内置模板，可通过预览接口查看。
+// @Tags EmailTemplates
+// @Produce json
+// @Success 200 {object} utils.SuccessResponse{data=[]models.EmailTemplate} "成功响应，包含模板列表"
+// @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
+// @Failure 403 {object} utils.APIErrorResponse "权限不足"
+// @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
+// @Router /email-templates [get]
+// @Security BearerAuth
+func (h *EmailTemplateHandler) GetEmailTemplates(c *gin.Context) {
+	templates, err := h.service.GetTemplates(c.Request.Context())
+	if err != nil {
+		utils.RespondInternalServerError(c, "获取通知模板列表失败", err.Error())
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, templates, "通知模板列表获取成功")
+}
+
+// GetEmailTemplate godoc
+// @Summary 获取通知模板详情
+// @Tags EmailTemplates
+// @Produce json
+// @Param id path int true "模板ID"
+// @Success 200 {object} utils.SuccessResponse{data=models.EmailTemplate} "通知模板"
+// @Failure 400 {object} utils.APIErrorResponse "无效的模板ID"
+// @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
+// @Failure 403 {object} utils.APIErrorResponse "权限不足"
+// @Failure 404 {object} utils.APIErrorResponse "通知模板未找到"
+// @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
+// @Router /email-templates/{id} [get]
+// @Security BearerAuth
+func (h *EmailTemplateHandler) GetEmailTemplate(c *gin.Context) {
+	id, ok := parseEmailTemplateIDParam(c)
+	if !ok {
+		return
+	}
+
+	tmpl, err := h.service.GetTemplate(c.Request.Context(), id)
+	if err != nil {
+		respondEmailTemplateServiceError(c, err, "获取通知模板失败")
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, tmpl, "通知模板获取成功")
+}
+
+// CreateEmailTemplate godoc
+// @Summary 新增通知模板
+// @Description 保存通知模板，覆盖同名称、同语言的模板文件和内置模板。subject 和 textBody 为 Go text/template 模板，htmlBody 为 html/template 模板。textBody 必须提供，短信使用纯文本正文；htmlBody 可选。IM 只发送标题，subject 中不能引用 .Link 和 .Numbers。
+// @Description verification 模板可使用的变量：.EmployeeName、.EmployeeID、.Link、.Numbers（每项含 .PhoneNumber、.Status）、.ExpiresAt、.ValidDays、.Contact、.Reminder（自动或手动催办时为 true）。保存前会以示例数据渲染，引用不存在的变量时返回 400。
+// @Tags EmailTemplates
+// @Accept json
+// @Produce json
+// @Param template body models.CreateEmailTemplatePayload true "模板内容"
+// @Success 201 {object} utils.SuccessResponse{data=models.EmailTemplate} "创建成功的模板"
+// @Failure 400 {object} utils.APIErrorResponse "请求参数错误、未知的模板名称或模板无效"
+// @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
+// @Failure 403 {object} utils.APIErrorResponse "权限不足"
+// @Failure 409 {object} utils.APIErrorResponse "该名称和语言的模板已存在"
+// @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
+// @Router /email-templates [post]
+// @Security BearerAuth
+func (h *EmailTemplateHandler) CreateEmailTemplate(c *gin.Context) {
+	var payload models.CreateEmailTemplatePayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.RespondValidationError(c, err.Error())
+		return
+	}
+
+	tmpl, err := h.service.CreateTemplate(c.Request.Context(), payload)
+	if err != nil {
+		respondEmailTemplateServiceError(c, err, "创建通知模板失败")
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusCreated, tmpl, "通知模板创建成功")
+}
+
+// UpdateEmailTemplate godoc
+// @Summary 更新通知模板
+// @Description 更新模板的标题或正文，所有字段均可选，正文为空字符串表示清除。更新后的模板会先以示例数据渲染校验。
+// @Tags EmailTemplates
+// @Accept json
+// @Produce json
+// @Param id path int true "模板ID"
+// @Param template body models.UpdateEmailTemplatePayload true "要更新的模板字段"
+// @Success 200 {object} utils.SuccessResponse{data=models.EmailTemplate} "更新后的模板"
+// @Failure 400 {object} utils.APIErrorResponse "请求参数错误或模板无效"
+// @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
+// @Failure 403 {object} utils.APIErrorResponse "权限不足"
+// @Failure 404 {object} utils.APIErrorResponse "通知模板未找到"
+// @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
+// @Router /email-templates/{id}/update [post]
+// @Security BearerAuth
+func (h *EmailTemplateHandler) UpdateEmailTemplate(c *gin.Context) {
+	id, ok := parseEmailTemplateIDParam(c)
+	if !ok {
+		return
+	}
+
+	var payload models.UpdateEmailTemplatePayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.RespondValidationError(c, err.Error())
+		return
+	}
+
+	tmpl, err := h.service.UpdateTemplate(c.Request.Context(), id, payload)
+	if err != nil {
+		respondEmailTemplateServiceError(c, err, "更新通知模板失败")
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, tmpl, "通知模板更新成功")
+}
+
+// DeleteEmailTemplate godoc
+// @Summary 删除通知模板
+// @Description 删除数据库中的模板，之后恢复使用模板文件或内置模板。
+// @Tags EmailTemplates
+// @Produce json
+// @Param id path int true "模板ID"
+// @Success 200 {object} utils.SuccessResponse "删除成功"
+// @Failure 400 {object} utils.APIErrorResponse "无效的模板ID"
+// @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
+// @Failure 403 {object} utils.APIErrorResponse "权限不足"
+// @Failure 404 {object} utils.APIErrorResponse "通知模板未找到"
+// @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
+// @Router /email-templates/{id} [delete]
+// @Security BearerAuth
+func (h *EmailTemplateHandler) DeleteEmailTemplate(c *gin.Context) {
+	id, ok := parseEmailTemplateIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteTemplate(c.Request.Context(), id); err != nil {
+		respondEmailTemplateServiceError(c, err, "删除通知模板失败")
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, nil, "通知模板删除成功")
+}
+
+// PreviewEmailTemplate godoc
+// @Summary 预览通知模板
+// @Description 以示例数据渲染通知模板，返回标题、HTML 正文和纯文本正文。subject、htmlBody、textBody 均为空时预览当前生效的模板（该语言没有模板时为 zh-CN 模板），否则预览提交的模板，不会保存。
+// @Tags EmailTemplates
+// @Accept json
+// @Produce json
+// @Param preview body models.PreviewEmailTemplatePayload true "模板名称、语言及可选的待预览模板"
+// @Success 200 {object} utils.SuccessResponse{data=models.EmailTemplatePreview} "渲染结果"
+// @Failure 400 {object} utils.APIErrorResponse "请求参数错误、未知的模板名称或模板无效"
+// @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
+// @Failure 403 {object} utils.APIErrorResponse "权限不足"
+// @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
+// @Router /email-templates/preview [post]
+// @Security BearerAuth
+func (h *EmailTemplateHandler) PreviewEmailTemplate(c *gin.Context) {
+	var payload models.PreviewEmailTemplatePayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.RespondValidationError(c, err.Error())
+		return
+	}
+
+	preview, err := h.service.PreviewTemplate(c.Request.Context(), payload)
+	if err != nil {
+		respondEmailTemplateServiceError(c, err, "预览通知模板失败")
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, preview, "通知模板预览成功")
+}
+
+// parseEmailTemplateIDParam 解析路径参数中的模板ID，失败时直接返回 400
+func parseEmailTemplateIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		utils.RespondAPIError(c, http.StatusBadRequest, "无效的模板ID", c.Param("id"))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// respondEmailTemplateServiceError 将通知模板服务的错误映射为 HTTP 响应
+func respondEmailTemplateServiceError(c *gin.Context, err error, defaultMessage string) {
+	switch {
+	case errors.Is(err, services.ErrEmailTemplateNotFound):
+		utils.RespondNotFoundError(c, "通知模板")
+	case errors.Is(err, services.ErrEmailTemplateExists):
+		utils.RespondConflictError(c, err.Error())
+	case errors.Is(err, services.ErrUnknownEmailTemplate),
+		errors.Is(err, services.ErrInvalidEmailTemplate),
+		errors.Is(err, services.ErrInvalidLocale):
+		utils.RespondAPIError(c, http.StatusBadRequest, err.Error(), nil)
+	default:
+		utils.RespondInternalServerError(c, defaultMessage, err.Error())
+	}
+}
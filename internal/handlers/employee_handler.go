@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/phone_management/internal/audit"
 	"github.com/phone_management/internal/models"
 	"github.com/phone_management/internal/repositories"
 	"github.com/phone_management/internal/services"
@@ -32,10 +33,14 @@ func NewEmployeeHandler(service services.EmployeeService) *EmployeeHandler {
 // 注意：EmployeeID 由系统自动生成，EmploymentStatus 默认为 "Active"
 type CreateEmployeePayload struct {
 	FullName    string  `json:"fullName" binding:"required,max=255"`
-	PhoneNumber *string `json:"phoneNumber,omitempty" binding:"omitempty,len=11,numeric"` // 手机号：可选，但如果提供，必须是11位数字
-	Email       *string `json:"email,omitempty" binding:"omitempty,email,max=255"`        // 可选，需要是合法的email格式，最大长度255
-	Department  *string `json:"department,omitempty" binding:"omitempty,max=255"`
+	PhoneNumber *string `json:"phoneNumber,omitempty" binding:"omitempty,len=11,numeric"`   // 手机号：可选，但如果提供，必须是11位数字
+	Email       *string `json:"email,omitempty" binding:"omitempty,email,max=255"`          // 可选，需要是合法的email格式，最大长度255
+	Department  *string `json:"department,omitempty" binding:"omitempty,max=255"`           // 部门名称或别名，必须是已有部门
 	HireDate    *string `json:"hireDate,omitempty" binding:"omitempty,datetime=2006-01-02"` // 入职日期，可选，格式 YYYY-MM-DD
+	// 接收通知的渠道，可选 email（默认）、im、sms；im 和 sms 需要登记手机号码，未登记时改用邮件。im 只在群内发送不含链接的提醒，完整通知仍发送到邮箱
+	NotificationChannel string `json:"notificationChannel,omitempty" binding:"omitempty,oneof=email im sms"`
+	// 接收通知的语言，可选 zh-CN（默认）、en
+	Locale string `json:"locale,omitempty" binding:"omitempty,oneof=zh-CN en"`
 	// EmploymentStatus 默认为 "Active"，在模型或服务层处理，此处不需传递
 }
 
@@ -63,12 +68,13 @@ type BatchImportResponse struct {
 // CreateEmployee godoc
 // @Summary 新增一个员工
 // @Description 从请求体绑定数据并验证，数据保存到数据库。员工工号由系统自动生成。支持设置可选的入职日期（格式：YYYY-MM-DD）。
+// @Description 部门按名称或别名匹配已有部门，员工保存规范的部门名称并关联部门ID。
 // @Tags Employees
 // @Accept json
 // @Produce json
 // @Param employee body CreateEmployeePayload true "员工信息。包含必填的姓名，可选的手机号、邮箱、部门和入职日期"
 // @Success 201 {object} utils.SuccessResponse{data=models.Employee} "创建成功的员工对象"
-// @Failure 400 {object} utils.APIErrorResponse "请求参数错误、数据校验失败或入职日期格式无效"
+// @Failure 400 {object} utils.APIErrorResponse "请求参数错误、数据校验失败、入职日期格式无效或部门不存在"
 // @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
 // @Failure 409 {object} utils.APIErrorResponse "手机号或邮箱已存在"
 // @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
@@ -82,10 +88,12 @@ func (h *EmployeeHandler) CreateEmployee(c *gin.Context) {
 	}
 
 	employeeToCreate := &models.Employee{
-		FullName:    payload.FullName,
-		PhoneNumber: payload.PhoneNumber,
-		Email:       payload.Email,
-		Department:  payload.Department,
+		FullName:            payload.FullName,
+		PhoneNumber:         payload.PhoneNumber,
+		Email:               payload.Email,
+		Department:          payload.Department,
+		NotificationChannel: payload.NotificationChannel,
+		Locale:              payload.Locale,
 	}
 
 	// 处理入职日期
@@ -98,13 +106,14 @@ func (h *EmployeeHandler) CreateEmployee(c *gin.Context) {
 		employeeToCreate.HireDate = &hireDate
 	}
 
-	createdEmployee, err := h.service.CreateEmployee(employeeToCreate)
+	createdEmployee, err := h.service.CreateEmployee(c.Request.Context(), employeeToCreate)
 	if err != nil {
 		// 处理来自服务层的唯一性冲突错误
 		if errors.Is(err, services.ErrPhoneNumberExists) || errors.Is(err, services.ErrEmailExists) || errors.Is(err, repositories.ErrEmployeeIDExists) {
 			utils.RespondConflictError(c, err.Error())
 			// 处理来自服务层（通过 utils 包传递）的格式错误
-		} else if errors.Is(err, utils.ErrInvalidPhoneNumberFormat) || errors.Is(err, utils.ErrInvalidPhoneNumberPrefix) {
+		} else if errors.Is(err, utils.ErrInvalidPhoneNumberFormat) || errors.Is(err, utils.ErrInvalidPhoneNumberPrefix) || errors.Is(err, services.ErrDepartmentNotFound) ||
+			errors.Is(err, services.ErrInvalidNotificationChannel) || errors.Is(err, services.ErrInvalidLocale) {
 			utils.RespondAPIError(c, http.StatusBadRequest, err.Error(), nil)
 			// 可选：如果 service 层也可能返回 utils.ErrInvalidEmailFormat (目前仅在handler的批量导入中校验)
 			// } else if errors.Is(err, utils.ErrInvalidEmailFormat) {
@@ -130,6 +139,9 @@ func (h *EmployeeHandler) CreateEmployee(c *gin.Context) {
 // @Param sortOrder query string false "排序顺序 ('asc'或'desc')" default("desc")
 // @Param search query string false "搜索关键词 (匹配姓名、工号)"
 // @Param employmentStatus query string false "在职状态筛选 ('Active'或'Departed')"
+// @Param departmentId query int false "部门ID筛选，优先于部门名称"
+// @Param department query string false "部门名称或别名筛选"
+// @Param includeSubDepartments query bool false "按部门筛选时是否包含下级部门" default(false)
 // @Success 200 {object} utils.SuccessResponse{data=PagedEmployeesData} "成功响应，包含员工列表和分页信息"
 // @Failure 400 {object} utils.APIErrorResponse "请求参数错误"
 // @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
@@ -162,13 +174,20 @@ func (h *EmployeeHandler) GetEmployees(c *gin.Context) {
 		queryParams.Page = 1
 	}
 
+	department, ok := bindDepartmentFilter(c)
+	if !ok {
+		return
+	}
+
 	employees, totalItems, err := h.service.GetEmployees(
+		c.Request.Context(),
 		queryParams.Page,
 		queryParams.Limit,
 		queryParams.SortBy,
 		queryParams.SortOrder,
 		queryParams.Search,
 		queryParams.EmploymentStatus,
+		department,
 	)
 
 	if err != nil {
@@ -230,16 +249,20 @@ func (h *EmployeeHandler) GetEmployeeByID(c *gin.Context) {
 
 // UpdateEmployee godoc
 // @Summary 更新指定业务工号的员工信息
-// @Description 根据员工业务工号更新员工的部门、入职日期、在职状态或离职日期。所有字段都是可选的，至少需要提供一个字段进行更新。入职日期和离职日期格式为 YYYY-MM-DD。在职状态允许值为 'Active' 或 'Departed'。
+// @Description 根据员工业务工号更新员工的姓名、手机号码、邮箱、部门、入职日期、在职状态或离职日期。所有字段都是可选的，至少需要提供一个字段进行更新。手机号码和邮箱为空字符串时清除，不能与其他员工重复。入职日期和离职日期格式为 YYYY-MM-DD。在职状态允许值为 'Active' 或 'Departed'。
+// @Description 办理离职时，员工每个使用中的号码都必须在 numberDispositions 中提供处置方式: reclaim（回收）、transfer（转移给 transferToEmployeeId）或 schedule_deactivation（回收并计划注销）。号码处置、办卡人号码转为风险号码与状态变更在同一事务中完成，任一号码处置失败则全部回滚。
+// @Description 离职日期在今天之后时只登记预约离职（员工保持在职，返回的员工带有离职日期），由定时任务在离职日期按处置方案办理，登记时按当前号码校验处置方案，使用中的号码缺少或有无效的处置方式时返回 400；将员工更新为在职即取消预约。
+// @Description 已离职的员工不能通过本接口更新为在职，须通过 POST /employees/{employeeId}/rehire 办理复职，否则返回 409。
 // @Tags Employees
 // @Accept json
 // @Produce json
 // @Param employeeId path string true "员工业务工号"
-// @Param employeeUpdate body models.UpdateEmployeePayload true "要更新的员工字段。可包含部门、入职日期、在职状态、离职日期"
+// @Param employeeUpdate body models.UpdateEmployeePayload true "要更新的员工字段。可包含姓名、手机号码、邮箱、部门、入职日期、在职状态、离职日期"
 // @Success 200 {object} utils.SuccessResponse{data=models.Employee} "更新后的员工对象"
-// @Failure 400 {object} utils.APIErrorResponse "请求参数错误、数据校验失败、日期格式无效或业务逻辑错误"
+// @Failure 400 {object} utils.APIErrorResponse "请求参数错误、数据校验失败、日期格式无效、部门不存在或业务逻辑错误"
 // @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
 // @Failure 404 {object} utils.APIErrorResponse "员工未找到"
+// @Failure 409 {object} utils.APIErrorResponse "手机号码或邮箱已被其他员工使用，号码无法按处置方案处置，或离职员工须办理复职"
 // @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
 // @Router /employees/{employeeId}/update [post]
 // @Security BearerAuth
@@ -253,7 +276,8 @@ func (h *EmployeeHandler) UpdateEmployee(c *gin.Context) {
 	}
 
 	// 基本校验：确保至少提供了一个字段进行更新
-	if payload.Department == nil && payload.EmploymentStatus == nil && payload.HireDate == nil && payload.TerminationDate == nil {
+	if payload.FullName == nil && payload.PhoneNumber == nil && payload.Email == nil &&
+		payload.Department == nil && payload.EmploymentStatus == nil && payload.HireDate == nil && payload.TerminationDate == nil {
 		utils.RespondAPIError(c, http.StatusBadRequest, "至少需要提供一个更新字段", nil)
 		return
 	}
@@ -282,13 +306,21 @@ func (h *EmployeeHandler) UpdateEmployee(c *gin.Context) {
 		return
 	}
 
-	updatedEmployee, err := h.service.UpdateEmployee(employeeIdStr, payload) // 服务层接收 models.UpdateEmployeePayload
+	updatedEmployee, err := h.service.UpdateEmployee(c.Request.Context(), employeeIdStr, payload) // 服务层接收 models.UpdateEmployeePayload
 	if err != nil {
 		if errors.Is(err, services.ErrEmployeeNotFound) {
 			utils.RespondNotFoundError(c, "员工")
 		} else if errors.Is(err, services.ErrEmployeeHasActiveNumbers) {
-			utils.RespondAPIError(c, http.StatusBadRequest, "员工当前正在使用手机号码，请先回收号码后再办理离职", nil)
-		} else if err.Error() == "没有提供任何有效的更新字段" || strings.Contains(err.Error(), "无效的离职日期格式") {
+			utils.RespondAPIError(c, http.StatusBadRequest, "员工当前正在使用手机号码，请为每个使用中的号码提供处置方式", err.Error())
+		} else if errors.Is(err, services.ErrInvalidDeparturePlan) || errors.Is(err, services.ErrDepartmentNotFound) ||
+			errors.Is(err, utils.ErrInvalidPhoneNumberFormat) || errors.Is(err, utils.ErrInvalidPhoneNumberPrefix) || errors.Is(err, utils.ErrInvalidEmailFormat) ||
+			errors.Is(err, services.ErrInvalidNotificationChannel) || errors.Is(err, services.ErrInvalidLocale) {
+			utils.RespondAPIError(c, http.StatusBadRequest, err.Error(), nil)
+		} else if errors.Is(err, services.ErrPhoneNumberExists) || errors.Is(err, services.ErrEmailExists) {
+			utils.RespondConflictError(c, err.Error())
+		} else if errors.Is(err, services.ErrDepartureDispositionFailed) || errors.Is(err, services.ErrRehireRequired) {
+			utils.RespondAPIError(c, http.StatusConflict, err.Error(), nil)
+		} else if err.Error() == "没有提供任何有效的更新字段" || err.Error() == "姓名不能为空" || strings.Contains(err.Error(), "无效的离职日期格式") {
 			utils.RespondAPIError(c, http.StatusBadRequest, err.Error(), nil)
 		} else {
 			utils.RespondInternalServerError(c, "更新员工信息失败", err.Error())
@@ -299,6 +331,139 @@ func (h *EmployeeHandler) UpdateEmployee(c *gin.Context) {
 	utils.RespondSuccess(c, http.StatusOK, updatedEmployee, "员工信息更新成功")
 }
 
+// GetScheduledDepartures godoc
+// @Summary 获取预约离职列表
+// @Description 获取所有尚未办理的预约离职，按离职日期升序。lastError 为定时任务最近一次办理失败的原因，失败后每次检查时会重试。
+// @Tags Employees
+// @Produce json
+// @Success 200 {object} utils.SuccessResponse{data=[]models.ScheduledDepartureResponse} "预约离职列表"
+// @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
+// @Failure 403 {object} utils.APIErrorResponse "权限不足"
+// @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
+// @Router /employees/scheduled-departures [get]
+// @Security BearerAuth
+func (h *EmployeeHandler) GetScheduledDepartures(c *gin.Context) {
+	departures, err := h.service.GetScheduledDepartures(c.Request.Context())
+	if err != nil {
+		utils.RespondInternalServerError(c, "获取预约离职列表失败", err.Error())
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, departures, "预约离职列表获取成功")
+}
+
+// PreviewDeparture godoc
+// @Summary 预览员工离职的号码处置结果
+// @Description 按号码处置方案模拟办理离职，返回员工每个使用中号码的处置结果以及将变为风险待核实的办卡人号码，不修改任何数据。
+// @Description 未提供处置方式或无法处置的号码在结果中带有 error，canDepart 表示按当前方案能否办理离职。
+// @Tags Employees
+// @Accept json
+// @Produce json
+// @Param employeeId path string true "员工业务工号"
+// @Param departure body models.DeparturePreviewPayload true "离职日期及号码处置方案"
+// @Success 200 {object} utils.SuccessResponse{data=models.DeparturePreviewResponse} "离职预览结果"
+// @Failure 400 {object} utils.APIErrorResponse "请求参数错误、日期格式无效或员工已离职"
+// @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
+// @Failure 403 {object} utils.APIErrorResponse "权限不足"
+// @Failure 404 {object} utils.APIErrorResponse "员工未找到"
+// @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
+// @Router /employees/{employeeId}/departure-preview [post]
+// @Security BearerAuth
+func (h *EmployeeHandler) PreviewDeparture(c *gin.Context) {
+	var payload models.DeparturePreviewPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.RespondValidationError(c, err.Error())
+		return
+	}
+
+	preview, err := h.service.PreviewDeparture(c.Request.Context(), c.Param("employeeId"), payload)
+	if err != nil {
+		if errors.Is(err, services.ErrEmployeeNotFound) {
+			utils.RespondNotFoundError(c, "员工")
+		} else if errors.Is(err, services.ErrEmployeeAlreadyDeparted) || strings.Contains(err.Error(), "无效的离职日期格式") {
+			utils.RespondAPIError(c, http.StatusBadRequest, err.Error(), nil)
+		} else {
+			utils.RespondInternalServerError(c, "离职预览失败", err.Error())
+		}
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, preview, "离职预览生成成功")
+}
+
+// PreviewRehire godoc
+// @Summary 预览离职员工复职可恢复的号码
+// @Description 列出离职员工作为办卡人的风险待核实号码，以及复职时恢复各号码将回到的状态（即号码进入风险待核实前的状态），不修改任何数据。
+// @Tags Employees
+// @Produce json
+// @Param employeeId path string true "员工业务工号"
+// @Success 200 {object} utils.SuccessResponse{data=models.RehireResponse} "员工当前信息及可恢复的号码"
+// @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
+// @Failure 403 {object} utils.APIErrorResponse "权限不足"
+// @Failure 404 {object} utils.APIErrorResponse "员工未找到"
+// @Failure 409 {object} utils.APIErrorResponse "员工不是离职状态"
+// @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
+// @Router /employees/{employeeId}/rehire-preview [get]
+// @Security BearerAuth
+func (h *EmployeeHandler) PreviewRehire(c *gin.Context) {
+	preview, err := h.service.PreviewRehire(c.Request.Context(), c.Param("employeeId"))
+	if err != nil {
+		if errors.Is(err, services.ErrEmployeeNotFound) {
+			utils.RespondNotFoundError(c, "员工")
+		} else if errors.Is(err, services.ErrEmployeeNotDeparted) {
+			utils.RespondAPIError(c, http.StatusConflict, err.Error(), nil)
+		} else {
+			utils.RespondInternalServerError(c, "复职预览失败", err.Error())
+		}
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, preview, "复职预览生成成功")
+}
+
+// RehireEmployee godoc
+// @Summary 办理离职员工复职
+// @Description 将离职员工恢复为在职，员工业务工号不变。原入职、离职日期记入任职历史（见员工详情的 employmentPeriods），复职日期作为新的入职日期，离职日期清空。
+// @Description 可同时恢复其作为办卡人的风险待核实号码：restoreAllNumbers 恢复全部，restorePhoneNumbers 恢复指定号码。号码恢复为进入风险待核实前的状态，原为待注销的号码按宽限期重新计算计划注销日期。
+// @Description 未恢复的号码保持风险待核实，仍可通过风险号码处理。
+// @Tags Employees
+// @Accept json
+// @Produce json
+// @Param employeeId path string true "员工业务工号"
+// @Param rehire body models.RehirePayload true "复职日期及需要恢复的号码"
+// @Success 200 {object} utils.SuccessResponse{data=models.RehireResponse} "复职后的员工信息及各号码的恢复情况"
+// @Failure 400 {object} utils.APIErrorResponse "请求参数错误、复职日期早于离职日期或指定的号码不可恢复"
+// @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
+// @Failure 403 {object} utils.APIErrorResponse "权限不足"
+// @Failure 404 {object} utils.APIErrorResponse "员工未找到"
+// @Failure 409 {object} utils.APIErrorResponse "员工不是离职状态"
+// @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
+// @Router /employees/{employeeId}/rehire [post]
+// @Security BearerAuth
+func (h *EmployeeHandler) RehireEmployee(c *gin.Context) {
+	var payload models.RehirePayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.RespondValidationError(c, err.Error())
+		return
+	}
+
+	result, err := h.service.Rehire(c.Request.Context(), c.Param("employeeId"), payload)
+	if err != nil {
+		if errors.Is(err, services.ErrEmployeeNotFound) {
+			utils.RespondNotFoundError(c, "员工")
+		} else if errors.Is(err, services.ErrEmployeeNotDeparted) {
+			utils.RespondAPIError(c, http.StatusConflict, err.Error(), nil)
+		} else if errors.Is(err, services.ErrInvalidRehireRequest) {
+			utils.RespondAPIError(c, http.StatusBadRequest, err.Error(), nil)
+		} else {
+			utils.RespondInternalServerError(c, "办理复职失败", err.Error())
+		}
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, result, "员工复职成功")
+}
+
 // BatchImportEmployees godoc
 // @Summary 批量导入员工数据 (CSV)
 // @Description 通过上传 CSV 文件批量导入员工。CSV文件必须包含表头：fullName,phoneNumber,email,department,hireDate。列顺序必须一致。fullName为必填，其他字段可为空。hireDate格式为YYYY-MM-DD。支持GBK和UTF-8编码。
@@ -351,6 +516,8 @@ func (h *EmployeeHandler) BatchImportEmployees(c *gin.Context) {
 		return
 	}
 
+	// 导入产生的审计事件标记来源为批量导入
+	ctx := audit.WithSource(c.Request.Context(), audit.SourceImport)
 	rowNum := 1
 	for {
 		rowNum++
@@ -426,7 +593,7 @@ func (h *EmployeeHandler) BatchImportEmployees(c *gin.Context) {
 			employeeToCreate.HireDate = nil
 		}
 
-		_, err = h.service.CreateEmployee(employeeToCreate)
+		_, err = h.service.CreateEmployee(ctx, employeeToCreate)
 		if err != nil {
 			reason := err.Error()
 			importErrors = append(importErrors, BatchImportErrorDetail{RowNumber: rowNum, RowData: record, Reason: reason})
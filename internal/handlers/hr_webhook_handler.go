@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/phone_management/internal/audit"
+	"github.com/phone_management/internal/models"
+	"github.com/phone_management/internal/services"
+	"github.com/phone_management/pkg/utils"
+)
+
+// hrEventMaxBodyBytes 是 HR 事件请求体的大小上限
+const hrEventMaxBodyBytes = 1 << 20
+
+// HRWebhookHandler 封装了 HR 系统事件推送相关的 HTTP 处理逻辑
+type HRWebhookHandler struct {
+	service services.HRWebhookService
+}
+
+// NewHRWebhookHandler 创建一个新的 HRWebhookHandler 实例
+func NewHRWebhookHandler(service services.HRWebhookService) *HRWebhookHandler {
+	return &HRWebhookHandler{service: service}
+}
+
+// ReceiveHREvent godoc
+// @Summary 接收 HR 系统推送的员工事件
+// @Description 接收 HR 系统推送的入职（employee.hired）、调岗（employee.department_changed）和离职（employee.terminated）事件，经由员工服务新建或更新员工，校验规则与手动操作一致。
+// @Description 请求须携带 X-HR-Timestamp（Unix 秒）和 X-HR-Signature（sha256= 加上以共享密钥对 "时间戳.请求体" 计算的 HMAC-SHA256 十六进制值），时间戳与服务器时间的偏差不能超过 HR_WEBHOOK_MAX_CLOCK_SKEW。
+// @Description 同一事件ID只处理一次，重复推送返回 duplicate；多个实例并发收到同一事件时只有一个处理，其余返回 409，HR 系统稍后重试即可。处理失败的事件保存到死信并返回 202，排除问题后可通过死信重放接口重新处理。
+// @Description 处理在完成前中断（如服务崩溃）的事件再次推送时不会自动重新处理，而是转入死信，由管理员核对员工数据后重放，避免重复新建员工。
+// @Tags Integrations
+// @Accept json
+// @Produce json
+// @Param X-HR-Timestamp header string true "签名时间戳（Unix 秒）"
+// @Param X-HR-Signature header string true "请求签名，格式 sha256=<hex>"
+// @Param event body models.HREventPayload true "HR 事件"
+// @Success 200 {object} utils.SuccessResponse{data=models.HREventResult} "事件已处理或此前已处理"
+// @Success 202 {object} utils.SuccessResponse{data=models.HREventResult} "事件处理失败，已转入死信"
+// @Failure 400 {object} utils.APIErrorResponse "事件格式无效"
+// @Failure 401 {object} utils.APIErrorResponse "签名无效或时间戳超出允许范围"
+// @Failure 409 {object} utils.APIErrorResponse "事件正在处理，details 为处理结果"
+// @Failure 413 {object} utils.APIErrorResponse "请求体过大"
+// @Failure 503 {object} utils.APIErrorResponse "未配置签名密钥"
+// @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
+// @Router /integrations/hr/events [post]
+func (h *HRWebhookHandler) ReceiveHREvent(c *gin.Context) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, hrEventMaxBodyBytes))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			utils.RespondAPIError(c, http.StatusRequestEntityTooLarge, "请求体过大", nil)
+		} else {
+			utils.RespondAPIError(c, http.StatusBadRequest, "读取请求体失败", err.Error())
+		}
+		return
+	}
+
+	if err := h.service.VerifySignature(c.GetHeader("X-HR-Timestamp"), c.GetHeader("X-HR-Signature"), body, time.Now()); err != nil {
+		if errors.Is(err, services.ErrHRWebhookNotConfigured) {
+			utils.RespondAPIError(c, http.StatusServiceUnavailable, err.Error(), nil)
+		} else {
+			utils.RespondUnauthorizedError(c, err.Error())
+		}
+		return
+	}
+
+	// 推送的请求没有经过 JWT 中间件，以 HR 系统作为审计事件的操作者
+	ctx := audit.WithActor(c.Request.Context(), audit.Actor{Username: "hr-system", Source: audit.SourceHR, ClientIP: c.ClientIP()})
+	result, err := h.service.HandleEvent(ctx, body)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidHREvent) {
+			utils.RespondAPIError(c, http.StatusBadRequest, err.Error(), nil)
+		} else {
+			utils.RespondInternalServerError(c, "处理 HR 事件失败", err.Error())
+		}
+		return
+	}
+
+	switch result.Status {
+	case models.HREventFailed:
+		utils.RespondSuccess(c, http.StatusAccepted, result, "HR 事件处理失败，已转入死信")
+	case models.HREventDuplicate:
+		utils.RespondSuccess(c, http.StatusOK, result, "HR 事件此前已处理")
+	case models.HREventInProgress:
+		utils.RespondAPIError(c, http.StatusConflict, "HR 事件正在处理，请稍后重试", result)
+	default:
+		utils.RespondSuccess(c, http.StatusOK, result, "HR 事件处理成功")
+	}
+}
+
+// GetHRDeadLetters godoc
+// @Summary 获取处理失败的 HR 事件
+// @Description 按创建时间倒序返回处理失败的 HR 事件（死信），包含原始事件、失败原因和失败次数。默认只返回尚未解决的死信。
+// @Tags Integrations
+// @Produce json
+// @Param includeResolved query bool false "是否包含已解决的死信" default(false)
+// @Success 200 {object} utils.SuccessResponse{data=[]models.HREventDeadLetter} "成功响应，包含死信列表"
+// @Failure 400 {object} utils.APIErrorResponse "请求参数错误"
+// @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
+// @Failure 403 {object} utils.APIErrorResponse "权限不足"
+// @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
+// @Router /integrations/hr/dead-letters [get]
+// @Security BearerAuth
+func (h *HRWebhookHandler) GetHRDeadLetters(c *gin.Context) {
+	type GetHRDeadLettersQuery struct {
+		IncludeResolved bool `form:"includeResolved,default=false"`
+	}
+
+	var queryParams GetHRDeadLettersQuery
+	if err := c.ShouldBindQuery(&queryParams); err != nil {
+		utils.RespondValidationError(c, err.Error())
+		return
+	}
+
+	deadLetters, err := h.service.GetDeadLetters(c.Request.Context(), queryParams.IncludeResolved)
+	if err != nil {
+		utils.RespondInternalServerError(c, "获取 HR 事件死信失败", err.Error())
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, deadLetters, "HR 事件死信获取成功")
+}
+
+// ReplayHRDeadLetter godoc
+// @Summary 重放处理失败的 HR 事件
+// @Description 重新处理死信中保存的原始事件，用于排除问题（如补建部门、先处置离职员工的号码）之后。处理成功后死信标记为已解决；再次失败时更新死信的失败原因和次数，返回 422。
+// @Tags Integrations
+// @Produce json
+// @Param id path int true "死信ID"
+// @Success 200 {object} utils.SuccessResponse{data=models.HREventResult} "事件处理成功，或该事件此前已处理"
+// @Failure 400 {object} utils.APIErrorResponse "无效的死信ID"
+// @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
+// @Failure 403 {object} utils.APIErrorResponse "权限不足"
+// @Failure 404 {object} utils.APIErrorResponse "死信未找到"
+// @Failure 409 {object} utils.APIErrorResponse "死信已解决，或事件正在处理"
+// @Failure 422 {object} utils.APIErrorResponse "事件再次处理失败，details 为处理结果"
+// @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
+// @Router /integrations/hr/dead-letters/{id}/replay [post]
+// @Security BearerAuth
+func (h *HRWebhookHandler) ReplayHRDeadLetter(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		utils.RespondAPIError(c, http.StatusBadRequest, "无效的死信ID", c.Param("id"))
+		return
+	}
+
+	result, err := h.service.ReplayDeadLetter(c.Request.Context(), uint(id))
+	if err != nil {
+		if errors.Is(err, services.ErrHRDeadLetterNotFound) {
+			utils.RespondNotFoundError(c, "HR 事件死信")
+		} else if errors.Is(err, services.ErrHRDeadLetterResolved) {
+			utils.RespondConflictError(c, err.Error())
+		} else if errors.Is(err, services.ErrInvalidHREvent) {
+			utils.RespondAPIError(c, http.StatusBadRequest, err.Error(), nil)
+		} else {
+			utils.RespondInternalServerError(c, "重放 HR 事件失败", err.Error())
+		}
+		return
+	}
+
+	if result.Status == models.HREventFailed {
+		utils.RespondAPIError(c, http.StatusUnprocessableEntity, "HR 事件再次处理失败", result)
+		return
+	}
+	if result.Status == models.HREventInProgress {
+		utils.RespondAPIError(c, http.StatusConflict, "HR 事件正在处理，请稍后重试", result)
+		return
+	}
+	utils.RespondSuccess(c, http.StatusOK, result, "HR 事件重放成功")
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/phone_management/internal/audit"
 	"github.com/phone_management/internal/auth"
 	"github.com/phone_management/internal/models"
 	"github.com/phone_management/internal/repositories" // 用于判断 ErrPhoneNumberExists
@@ -102,7 +103,7 @@ func (h *MobileNumberHandler) CreateMobileNumber(c *gin.Context) {
 		Remarks:             payload.Remarks,
 	}
 
-	createdMobileNumber, err := h.service.CreateMobileNumber(mobileNumberToCreate)
+	createdMobileNumber, err := h.service.CreateMobileNumber(c.Request.Context(), mobileNumberToCreate)
 	if err != nil {
 		if errors.Is(err, repositories.ErrMobileNumberStringConflict) {
 			utils.RespondConflictError(c, repositories.ErrMobileNumberStringConflict.Error())
@@ -165,6 +166,7 @@ func (h *MobileNumberHandler) GetMobileNumbers(c *gin.Context) {
 	}
 
 	mobileNumbers, totalItems, err := h.service.GetMobileNumbers(
+		c.Request.Context(),
 		queryParams.Page,
 		queryParams.Limit,
 		queryParams.SortBy,
@@ -223,7 +225,7 @@ func (h *MobileNumberHandler) GetMobileNumberByID(c *gin.Context) {
 	// }
 
 	// 假设服务层有 GetMobileNumberByPhoneNumberDetail 方法
-	mobileNumber, err := h.service.GetMobileNumberByPhoneNumberDetail(phoneNumberStr)
+	mobileNumber, err := h.service.GetMobileNumberByPhoneNumberDetail(c.Request.Context(), phoneNumberStr)
 	if err != nil {
 		if errors.Is(err, services.ErrMobileNumberNotFound) {
 			utils.RespondNotFoundError(c, "手机号码")
@@ -238,7 +240,9 @@ func (h *MobileNumberHandler) GetMobileNumberByID(c *gin.Context) {
 
 // UpdateMobileNumber godoc
 // @Summary 更新指定手机号码的信息
-// @Description 更新指定手机号码的信息 (主要用于更新状态、用途、供应商、备注)。当号码状态变更为"已注销"时，自动记录注销时间。注意：风险号码(risk_pending)不允许通过此接口更新，请使用专门的风险处理接口。
+// @Description 更新指定手机号码的信息 (主要用于更新状态、用途、供应商、备注)。状态变更须符合号码生命周期流转规则，不允许时返回 409 及允许的下一状态；变更为闲置、待注销或已注销时会结束当前使用人的使用历史，变更为"已注销"时自动记录注销时间。
+// @Description 变更为"待注销"时可指定计划注销日期(scheduledDeactivationDate)，不指定则按宽限期自动计算；待注销号码也可单独修改计划注销日期。到期后由定时任务自动注销。
+// @Description 注意：风险号码(risk_pending)不允许通过此接口更新，请使用专门的风险处理接口。
 // @Tags MobileNumbers
 // @Accept json
 // @Produce json
@@ -249,6 +253,7 @@ func (h *MobileNumberHandler) GetMobileNumberByID(c *gin.Context) {
 // @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
 // @Failure 403 {object} utils.APIErrorResponse "风险号码不允许通过常规接口更新"
 // @Failure 404 {object} utils.APIErrorResponse "号码未找到"
+// @Failure 409 {object} utils.APIErrorResponse{details=models.InvalidStatusTransitionError} "状态流转不被允许"
 // @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
 // @Router /mobilenumbers/{phoneNumber}/update [post]
 // @Security BearerAuth
@@ -268,24 +273,27 @@ func (h *MobileNumberHandler) UpdateMobileNumber(c *gin.Context) {
 	}
 
 	// 校验至少有一个字段被提供用于更新
-	if payload.Status == nil && payload.Purpose == nil && payload.Vendor == nil && payload.Remarks == nil {
+	if payload.Status == nil && payload.Purpose == nil && payload.Vendor == nil && payload.Remarks == nil && payload.ScheduledDeactivationDate == nil {
 		utils.RespondAPIError(c, http.StatusBadRequest, "没有提供任何有效的更新字段", nil)
 		return
 	}
 
 	// 假设服务层有 UpdateMobileNumberByPhoneNumber 方法
-	updatedMobileNumber, err := h.service.UpdateMobileNumberByPhoneNumber(phoneNumberStr, payload)
+	updatedMobileNumber, err := h.service.UpdateMobileNumberByPhoneNumber(c.Request.Context(), phoneNumberStr, payload)
 	if err != nil {
+		if respondStatusTransitionError(c, err) {
+			return
+		}
 		if errors.Is(err, services.ErrMobileNumberNotFound) {
 			utils.RespondNotFoundError(c, "手机号码")
 		} else if err.Error() == "没有提供任何更新字段" {
 			utils.RespondAPIError(c, http.StatusBadRequest, err.Error(), nil)
 		} else if err.Error() == "无效的状态值" {
 			utils.RespondAPIError(c, http.StatusBadRequest, err.Error(), nil)
-		} else if strings.Contains(err.Error(), "不能直接将状态更新为'使用中'") {
-			utils.RespondAPIError(c, http.StatusBadRequest, err.Error(), "请使用分配接口 /assign 来将号码分配给用户")
-		} else if strings.Contains(err.Error(), "号码当前为'使用中'状态") {
-			utils.RespondAPIError(c, http.StatusConflict, err.Error(), "请使用回收接口 /unassign 来回收号码后再进行状态更新")
+		} else if errors.Is(err, services.ErrDeactivationDateNotApplicable) || errors.Is(err, services.ErrDeactivationDateInPast) {
+			utils.RespondAPIError(c, http.StatusBadRequest, err.Error(), nil)
+		} else if errors.Is(err, repositories.ErrNoActiveUsageHistoryFound) {
+			utils.RespondAPIError(c, http.StatusConflict, "未找到该号码当前有效的分配记录", err.Error())
 		} else if strings.Contains(err.Error(), "风险号码不允许通过常规更新接口修改") {
 			utils.RespondAPIError(c, http.StatusForbidden, "风险号码不允许通过常规更新接口修改", "请使用专门的风险处理接口 /handle-risk")
 		} else {
@@ -335,8 +343,11 @@ func (h *MobileNumberHandler) AssignMobileNumber(c *gin.Context) {
 	}
 
 	// 调用服务层，传递 phoneNumberStr 而不是 numberID，并添加用途字段
-	assignedMobileNumber, err := h.service.AssignMobileNumber(phoneNumberStr, payload.EmployeeID, assignmentDate, payload.Purpose)
+	assignedMobileNumber, err := h.service.AssignMobileNumber(c.Request.Context(), phoneNumberStr, payload.EmployeeID, assignmentDate, payload.Purpose)
 	if err != nil {
+		if respondStatusTransitionError(c, err) {
+			return
+		}
 		switch {
 		case errors.Is(err, services.ErrMobileNumberNotFound):
 			utils.RespondNotFoundError(c, "手机号码")
@@ -355,9 +366,89 @@ func (h *MobileNumberHandler) AssignMobileNumber(c *gin.Context) {
 	utils.RespondSuccess(c, http.StatusOK, assignedMobileNumber, "手机号码分配成功")
 }
 
+// TransferMobileNumber godoc
+// @Summary 将使用中的号码转移给另一名员工
+// @Description 在一个事务中结束当前使用人的使用历史，并从生效日期开始为目标员工创建新的使用历史，号码保持"使用中"状态，避免先回收再分配造成的使用历史中断。
+// @Description 号码必须为"使用中"状态，目标员工必须在职且不是当前使用人；生效日期不能早于当前使用人的开始使用日期。转移原因记录在新的使用历史中。
+// @Tags MobileNumbers
+// @Accept json
+// @Produce json
+// @Param phoneNumber path string true "手机号码字符串"
+// @Param transferPayload body models.MobileNumberTransferPayload true "转移信息 (目标员工业务工号、生效日期 YYYY-MM-DD 和转移原因)"
+// @Success 200 {object} utils.SuccessResponse{data=models.MobileNumber} "转移后的号码对象"
+// @Failure 400 {object} utils.APIErrorResponse "请求参数错误 / 无效的日期格式 / 无效的手机号码格式 / 生效日期早于当前使用开始日期"
+// @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
+// @Failure 404 {object} utils.APIErrorResponse "手机号码或目标员工工号未找到"
+// @Failure 409 {object} utils.APIErrorResponse "操作冲突 (例如：号码非使用中，员工非在职，目标员工已是当前使用人)"
+// @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
+// @Router /mobilenumbers/{phoneNumber}/transfer [post]
+// @Security BearerAuth
+func (h *MobileNumberHandler) TransferMobileNumber(c *gin.Context) {
+	phoneNumberStr := c.Param("phoneNumber")
+
+	if err := utils.ValidatePhoneNumber(phoneNumberStr); err != nil {
+		utils.RespondAPIError(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	var payload models.MobileNumberTransferPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.RespondValidationError(c, err.Error())
+		return
+	}
+
+	effectiveDate, err := utils.ParseDate(payload.EffectiveDate)
+	if err != nil {
+		utils.RespondAPIError(c, http.StatusBadRequest, "生效日期(effectiveDate)格式无效: "+err.Error(), nil)
+		return
+	}
+
+	transferredMobileNumber, err := h.service.TransferMobileNumber(c.Request.Context(), phoneNumberStr, payload.EmployeeID, effectiveDate, payload.Reason)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrMobileNumberNotFound):
+			utils.RespondNotFoundError(c, "手机号码")
+		case errors.Is(err, services.ErrEmployeeNotFound), errors.Is(err, repositories.ErrEmployeeNotFound):
+			utils.RespondAPIError(c, http.StatusNotFound, "目标员工未找到 (基于提供的工号)", "employeeId: "+payload.EmployeeID)
+		case errors.Is(err, repositories.ErrMobileNumberNotInUse):
+			utils.RespondAPIError(c, http.StatusConflict, "手机号码不是使用中状态，无法转移", err.Error())
+		case errors.Is(err, repositories.ErrEmployeeNotActive):
+			utils.RespondAPIError(c, http.StatusConflict, "目标员工不是在职状态，无法转移", err.Error())
+		case errors.Is(err, repositories.ErrTransferToSameEmployee):
+			utils.RespondAPIError(c, http.StatusConflict, err.Error(), "employeeId: "+payload.EmployeeID)
+		case errors.Is(err, repositories.ErrNoActiveUsageHistoryFound):
+			utils.RespondAPIError(c, http.StatusConflict, err.Error(), nil)
+		case errors.Is(err, repositories.ErrTransferDateBeforeUsageStart):
+			utils.RespondAPIError(c, http.StatusBadRequest, err.Error(), "effectiveDate: "+payload.EffectiveDate)
+		default:
+			utils.RespondInternalServerError(c, "转移手机号码失败", err.Error())
+		}
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, transferredMobileNumber, "手机号码转移成功")
+}
+
+// respondStatusTransitionError 处理号码生命周期相关的错误，已处理时返回 true。
+// 不允许的状态流转返回 409，详情中列出当前状态允许进入的下一状态。
+func respondStatusTransitionError(c *gin.Context, err error) bool {
+	var transitionErr *models.InvalidStatusTransitionError
+	switch {
+	case errors.As(err, &transitionErr):
+		utils.RespondAPIError(c, http.StatusConflict, transitionErr.Error(), transitionErr)
+	case errors.Is(err, models.ErrInUseRequiresCurrentUser):
+		utils.RespondAPIError(c, http.StatusBadRequest, err.Error(), "请使用分配接口 /assign 来将号码分配给用户")
+	case errors.Is(err, models.ErrDeactivationDateRequired):
+		utils.RespondAPIError(c, http.StatusBadRequest, err.Error(), "请通过更新接口将号码变更为待注销并指定计划注销日期")
+	default:
+		return false
+	}
+	return true
+}
+
 // UnassignMobileNumber godoc
 // @Summary 从当前使用人处回收指定手机号码
-// @Description 回收手机号码，支持在用(in_use)、风险待核实(risk_pending)、用户报告(user_reported)状态的号码。对于有当前使用人的号码，会更新使用历史记录的结束时间；对于没有当前使用人的号码，直接设为闲置状态。
+// @Description 回收手机号码，即将号码流转为闲置(idle)状态，支持生命周期规则允许进入闲置状态的号码（在用、待注销、风险待核实、用户报告）。对于有当前使用人的号码，会更新使用历史记录的结束时间；对于没有当前使用人的号码，直接设为闲置状态。
 // @Tags MobileNumbers
 // @Accept json
 // @Produce json
@@ -406,13 +497,14 @@ func (h *MobileNumberHandler) UnassignMobileNumber(c *gin.Context) {
 	}
 
 	// 假设服务层有 UnassignMobileNumberByPhoneNumber 方法
-	unassignedMobileNumber, err := h.service.UnassignMobileNumberByPhoneNumber(phoneNumberStr, reclaimDate)
+	unassignedMobileNumber, err := h.service.UnassignMobileNumberByPhoneNumber(c.Request.Context(), phoneNumberStr, reclaimDate)
 	if err != nil {
+		if respondStatusTransitionError(c, err) {
+			return
+		}
 		switch {
 		case errors.Is(err, services.ErrMobileNumberNotFound):
 			utils.RespondNotFoundError(c, "手机号码")
-		case errors.Is(err, repositories.ErrMobileNumberNotRecoverable):
-			utils.RespondAPIError(c, http.StatusConflict, "手机号码状态不允许回收", "仅支持在用、风险待核实、用户报告状态的号码回收")
 		case errors.Is(err, repositories.ErrNoActiveUsageHistoryFound):
 			utils.RespondAPIError(c, http.StatusConflict, "未找到该号码当前有效的分配记录", err.Error())
 		case strings.Contains(err.Error(), "数据不一致：使用号码没有关联当前用户"): // 这个错误可能来自 service 层更深处
@@ -426,6 +518,85 @@ func (h *MobileNumberHandler) UnassignMobileNumber(c *gin.Context) {
 	utils.RespondSuccess(c, http.StatusOK, unassignedMobileNumber, "手机号码回收成功")
 }
 
+// CancelDeactivation godoc
+// @Summary 取消待注销号码的注销计划
+// @Description 在计划注销日期到达前取消注销，号码恢复为闲置(idle)状态并清空计划注销日期。只适用于待注销(pending_deactivation)状态的号码。
+// @Tags MobileNumbers
+// @Produce json
+// @Param phoneNumber path string true "手机号码字符串"
+// @Success 200 {object} utils.SuccessResponse{data=models.MobileNumber} "取消注销后的号码对象"
+// @Failure 400 {object} utils.APIErrorResponse "无效的手机号码格式"
+// @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
+// @Failure 403 {object} utils.APIErrorResponse "权限不足"
+// @Failure 404 {object} utils.APIErrorResponse "手机号码未找到"
+// @Failure 409 {object} utils.APIErrorResponse "号码不是待注销状态"
+// @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
+// @Router /mobilenumbers/{phoneNumber}/cancel-deactivation [post]
+// @Security BearerAuth
+func (h *MobileNumberHandler) CancelDeactivation(c *gin.Context) {
+	phoneNumberStr := c.Param("phoneNumber")
+	if err := utils.ValidatePhoneNumber(phoneNumberStr); err != nil {
+		utils.RespondAPIError(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	mobileNumber, err := h.service.CancelDeactivation(c.Request.Context(), phoneNumberStr)
+	if err != nil {
+		if respondStatusTransitionError(c, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, services.ErrMobileNumberNotFound):
+			utils.RespondNotFoundError(c, "手机号码")
+		case errors.Is(err, repositories.ErrMobileNumberNotPendingDeactivation):
+			utils.RespondAPIError(c, http.StatusConflict, err.Error(), nil)
+		default:
+			utils.RespondInternalServerError(c, "取消注销失败", err.Error())
+		}
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, mobileNumber, "已取消号码注销计划")
+}
+
+// UpcomingDeactivationsData 定义了即将注销号码列表的响应结构
+type UpcomingDeactivationsData struct {
+	Until time.Time                     `json:"until"` // 统计截止日期（含）
+	Items []models.MobileNumberResponse `json:"items"`
+}
+
+// GetUpcomingDeactivations godoc
+// @Summary 获取即将注销的号码列表
+// @Description 列出计划在未来 N 天内（含今天）注销的待注销号码，按计划注销日期升序，便于财务提前准备运营商注销手续。已到期但尚未被定时任务处理的号码也会列出。
+// @Tags MobileNumbers
+// @Produce json
+// @Param days query int false "未来天数 (0-366)" default(30)
+// @Success 200 {object} utils.SuccessResponse{data=UpcomingDeactivationsData} "成功响应，包含即将注销的号码列表"
+// @Failure 400 {object} utils.APIErrorResponse "请求参数错误"
+// @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
+// @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
+// @Router /mobilenumbers/pending-deactivation [get]
+// @Security BearerAuth
+func (h *MobileNumberHandler) GetUpcomingDeactivations(c *gin.Context) {
+	type GetUpcomingDeactivationsQuery struct {
+		Days int `form:"days,default=30" binding:"min=0,max=366"`
+	}
+
+	var queryParams GetUpcomingDeactivationsQuery
+	if err := c.ShouldBindQuery(&queryParams); err != nil {
+		utils.RespondValidationError(c, err.Error())
+		return
+	}
+
+	numbers, until, err := h.service.GetUpcomingDeactivations(c.Request.Context(), queryParams.Days)
+	if err != nil {
+		utils.RespondInternalServerError(c, "获取即将注销的号码失败", err.Error())
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, UpcomingDeactivationsData{Until: until, Items: numbers}, "即将注销的号码获取成功")
+}
+
 // BatchImportMobileNumberErrorDetail 描述了批量导入手机号码中单行数据的错误信息
 // (与员工导入的 BatchImportErrorDetail 结构相同，可以考虑提取到公共 utils 或 handlers/common_types.go)
 type BatchImportMobileNumberErrorDetail struct {
@@ -494,6 +665,8 @@ func (h *MobileNumberHandler) BatchImportMobileNumbers(c *gin.Context) {
 		return
 	}
 
+	// 导入产生的审计事件标记来源为批量导入
+	ctx := audit.WithSource(c.Request.Context(), audit.SourceImport)
 	rowNum := 1
 	for {
 		rowNum++
@@ -565,7 +738,7 @@ func (h *MobileNumberHandler) BatchImportMobileNumbers(c *gin.Context) {
 			Remarks:             "",
 		}
 
-		_, createErr := h.service.CreateMobileNumber(mobileToCreate)
+		_, createErr := h.service.CreateMobileNumber(ctx, mobileToCreate)
 		if createErr != nil {
 			importErrors = append(importErrors, BatchImportMobileNumberErrorDetail{RowNumber: rowNum, RowData: record, Reason: createErr.Error()})
 			errorCount++
@@ -584,6 +757,82 @@ func (h *MobileNumberHandler) BatchImportMobileNumbers(c *gin.Context) {
 	utils.RespondSuccess(c, http.StatusOK, response, response.Message)
 }
 
+// BulkMobileNumbersResponse 定义了号码批量操作的响应结构，错误明细与批量导入相同：
+// rowNumber 为号码在本次操作中的序号（从1开始），rowData 为对应的手机号码
+type BulkMobileNumbersResponse struct {
+	Message      string                               `json:"message"`
+	DryRun       bool                                 `json:"dryRun"`     // 是否为预览
+	Atomic       bool                                 `json:"atomic"`     // 是否整批原子执行
+	RolledBack   bool                                 `json:"rolledBack"` // 操作是否已回滚（预览或原子执行失败）
+	TotalCount   int                                  `json:"totalCount"`
+	SuccessCount int                                  `json:"successCount"`
+	ErrorCount   int                                  `json:"errorCount"`
+	Errors       []BatchImportMobileNumberErrorDetail `json:"errors,omitempty"`
+}
+
+// BulkMobileNumbers godoc
+// @Summary 批量操作手机号码
+// @Description 对一组手机号码（phoneNumbers）或按筛选条件（filter，含义同列表接口，包括风险号码）匹配的号码执行同一操作，单次最多 500 个。
+// @Description 支持的操作: status_change（需 status）, unassign（可选 reclaimDate）, change_applicant（需 newApplicantEmployeeId）, deactivate, set_vendor（需 vendor）。风险号码的变更办卡人和注销按风险号码处理流程执行。
+// @Description 每个号码单独执行、单独成败，错误明细与批量导入格式相同。dryRun=true 时执行全部校验后回滚，用于预览；atomic=true 时任一号码失败则整批回滚。
+// @Tags MobileNumbers
+// @Accept json
+// @Produce json
+// @Param bulk body models.MobileNumberBulkPayload true "批量操作请求体"
+// @Success 200 {object} utils.SuccessResponse{data=BulkMobileNumbersResponse} "批量操作结果摘要"
+// @Failure 400 {object} utils.APIErrorResponse "请求参数错误、操作类型无效或没有匹配的号码"
+// @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
+// @Failure 403 {object} utils.APIErrorResponse "权限不足"
+// @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
+// @Router /mobilenumbers/bulk [post]
+// @Security BearerAuth
+func (h *MobileNumberHandler) BulkMobileNumbers(c *gin.Context) {
+	var payload models.MobileNumberBulkPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.RespondValidationError(c, err.Error())
+		return
+	}
+
+	operatorUsername, ok := auth.GetCurrentUsername(c)
+	if !ok {
+		utils.RespondInternalServerError(c, "无法获取当前操作员信息", "用户上下文信息缺失")
+		return
+	}
+
+	ctx := audit.WithSource(c.Request.Context(), audit.SourceBulk)
+	result, err := h.service.BulkOperate(ctx, payload, operatorUsername)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidBulkRequest) || errors.Is(err, services.ErrBulkNoMatchingNumbers) {
+			utils.RespondAPIError(c, http.StatusBadRequest, err.Error(), nil)
+		} else {
+			utils.RespondInternalServerError(c, "批量操作手机号码失败", err.Error())
+		}
+		return
+	}
+
+	response := BulkMobileNumbersResponse{
+		DryRun:       payload.DryRun,
+		Atomic:       payload.Atomic,
+		RolledBack:   result.RolledBack,
+		TotalCount:   result.TotalCount,
+		SuccessCount: result.SuccessCount,
+		ErrorCount:   len(result.Failures),
+	}
+	for _, f := range result.Failures {
+		response.Errors = append(response.Errors, BatchImportMobileNumberErrorDetail{RowNumber: f.Index, RowData: []string{f.PhoneNumber}, Reason: f.Reason})
+	}
+	switch {
+	case payload.DryRun:
+		response.Message = fmt.Sprintf("批量操作预览完成，未做任何修改。可执行: %d, 失败: %d", response.SuccessCount, response.ErrorCount)
+	case result.RolledBack:
+		response.Message = fmt.Sprintf("批量操作存在失败的号码，已整批回滚。可执行: %d, 失败: %d", response.SuccessCount, response.ErrorCount)
+	default:
+		response.Message = fmt.Sprintf("批量操作处理完成。成功: %d, 失败: %d", response.SuccessCount, response.ErrorCount)
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, response, response.Message)
+}
+
 // GetRiskPendingNumbers godoc
 // @Summary 获取风险号码列表
 // @Description 获取状态为risk_pending的手机号码列表，支持分页、搜索和筛选
@@ -629,6 +878,7 @@ func (h *MobileNumberHandler) GetRiskPendingNumbers(c *gin.Context) {
 	}
 
 	riskNumbers, totalItems, err := h.service.GetRiskPendingNumbers(
+		c.Request.Context(),
 		queryParams.Page,
 		queryParams.Limit,
 		queryParams.SortBy,
@@ -708,8 +958,11 @@ func (h *MobileNumberHandler) HandleRiskNumber(c *gin.Context) {
 		return
 	}
 
-	handledNumber, err := h.service.HandleRiskNumber(phoneNumberStr, payload, operatorUsername)
+	handledNumber, err := h.service.HandleRiskNumber(c.Request.Context(), phoneNumberStr, payload, operatorUsername)
 	if err != nil {
+		if respondStatusTransitionError(c, err) {
+			return
+		}
 		if errors.Is(err, services.ErrMobileNumberNotFound) {
 			utils.RespondNotFoundError(c, "手机号码")
 		} else if errors.Is(err, services.ErrEmployeeNotFound) {
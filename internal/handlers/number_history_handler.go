@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/phone_management/internal/services"
+	"github.com/phone_management/pkg/utils"
+)
+
+// NumberHistoryHandler 封装了号码历史查询相关的 HTTP 处理逻辑
+type NumberHistoryHandler struct {
+	service services.NumberHistoryService
+}
+
+// NewNumberHistoryHandler 创建一个新的 NumberHistoryHandler 实例
+func NewNumberHistoryHandler(service services.NumberHistoryService) *NumberHistoryHandler {
+	return &NumberHistoryHandler{service: service}
+}
+
+// GetNumberTimeline godoc
+// @Summary 获取手机号码的时间线
+// @Description 将号码的使用记录、办卡人变更、状态变更和员工确认提交汇总为一个按时间升序排列的事件列表。
+// @Description 事件类型: usage_started, usage_ended, applicant_changed, status_changed, verification_submission。状态变更来自操作审计日志。
+// @Tags MobileNumbers
+// @Produce json
+// @Param phoneNumber path string true "手机号码字符串"
+// @Success 200 {object} utils.SuccessResponse{data=models.NumberTimelineResponse} "成功响应，包含号码时间线"
+// @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
+// @Failure 403 {object} utils.APIErrorResponse "权限不足"
+// @Failure 404 {object} utils.APIErrorResponse "号码未找到"
+// @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
+// @Router /mobilenumbers/{phoneNumber}/timeline [get]
+// @Security BearerAuth
+func (h *NumberHistoryHandler) GetNumberTimeline(c *gin.Context) {
+	timeline, err := h.service.GetNumberTimeline(c.Request.Context(), c.Param("phoneNumber"))
+	if err != nil {
+		if errors.Is(err, services.ErrMobileNumberNotFound) {
+			utils.RespondNotFoundError(c, "手机号码")
+		} else {
+			utils.RespondInternalServerError(c, "获取号码时间线失败", err.Error())
+		}
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, timeline, "号码时间线获取成功")
+}
+
+// GetEmployeeNumberHistory godoc
+// @Summary 获取员工的号码历史
+// @Description 汇总员工使用过的号码、作为原办卡人或新办卡人的变更记录以及其提交的号码确认，按时间升序排列。
+// @Tags Employees
+// @Produce json
+// @Param employeeId path string true "员工业务工号"
+// @Success 200 {object} utils.SuccessResponse{data=models.EmployeeNumberHistoryResponse} "成功响应，包含员工号码历史"
+// @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
+// @Failure 403 {object} utils.APIErrorResponse "权限不足"
+// @Failure 404 {object} utils.APIErrorResponse "员工未找到"
+// @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
+// @Router /employees/{employeeId}/number-history [get]
+// @Security BearerAuth
+func (h *NumberHistoryHandler) GetEmployeeNumberHistory(c *gin.Context) {
+	history, err := h.service.GetEmployeeNumberHistory(c.Request.Context(), c.Param("employeeId"))
+	if err != nil {
+		if errors.Is(err, services.ErrEmployeeNotFound) {
+			utils.RespondNotFoundError(c, "员工")
+		} else {
+			utils.RespondInternalServerError(c, "获取员工号码历史失败", err.Error())
+		}
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, history, "员工号码历史获取成功")
+}
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/phone_management/internal/models"
+	"github.com/phone_management/internal/services"
+	"github.com/phone_management/pkg/utils"
+)
+
+// OutboundMessageHandler 封装了通知发送队列相关的 HTTP 处理逻辑
+type OutboundMessageHandler struct {
+	service services.OutboundMessageService
+}
+
+// NewOutboundMessageHandler 创建一个新的 OutboundMessageHandler 实例
+func NewOutboundMessageHandler(service services.OutboundMessageService) *OutboundMessageHandler {
+	return &OutboundMessageHandler{service: service}
+}
+
+// PagedOutboundMessagesData 定义了通知列表的分页响应结构
+type PagedOutboundMessagesData struct {
+	Items      []models.OutboundMessage `json:"items"`
+	Pagination PaginationInfo           `json:"pagination"`
+}
+
+// GetOutboundMessages godoc
+// @Summary 查询通知发送队列
+// @Description 分页查询待发送和已发送的通知，按创建时间倒序。可按状态、确认批次和员工工号筛选。
+// @Description 状态：pending 等待发送或等待重试，sending 正在发送，sent 发送成功，failed 达到最多尝试次数或员工缺少联系方式，可重试。
+// @Tags OutboundMessages
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量" default(20)
+// @Param status query string false "状态 (pending, sending, sent, failed)"
+// @Param batchId query string false "确认批次ID"
+// @Param employeeId query string false "员工业务工号"
+// @Success 200 {object} utils.SuccessResponse{data=PagedOutboundMessagesData} "成功响应，包含通知列表和分页信息"
+// @Failure 400 {object} utils.APIErrorResponse "请求参数错误"
+// @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
+// @Failure 403 {object} utils.APIErrorResponse "权限不足"
+// @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
+// @Router /outbound-messages [get]
+// @Security BearerAuth
+func (h *OutboundMessageHandler) GetOutboundMessages(c *gin.Context) {
+	type GetOutboundMessagesQuery struct {
+		Page       int    `form:"page,default=1"`
+		Limit      int    `form:"limit,default=20"`
+		Status     string `form:"status"`
+		BatchID    string `form:"batchId"`
+		EmployeeID string `form:"employeeId"`
+	}
+
+	var queryParams GetOutboundMessagesQuery
+	if err := c.ShouldBindQuery(&queryParams); err != nil {
+		utils.RespondValidationError(c, err.Error())
+		return
+	}
+	if queryParams.Status != "" && !models.IsValidOutboundMessageStatus(queryParams.Status) {
+		utils.RespondValidationError(c, "无效的状态: "+queryParams.Status)
+		return
+	}
+	if queryParams.Limit <= 0 {
+		queryParams.Limit = 20
+	}
+	if queryParams.Limit > 100 {
+		queryParams.Limit = 100
+	}
+	if queryParams.Page <= 0 {
+		queryParams.Page = 1
+	}
+
+	filter := models.OutboundMessageFilter{
+		Status:     queryParams.Status,
+		BatchID:    queryParams.BatchID,
+		EmployeeID: queryParams.EmployeeID,
+	}
+	messages, totalItems, err := h.service.GetMessages(c.Request.Context(), filter, queryParams.Page, queryParams.Limit)
+	if err != nil {
+		utils.RespondInternalServerError(c, "获取通知列表失败", err.Error())
+		return
+	}
+
+	totalPages := (totalItems + int64(queryParams.Limit) - 1) / int64(queryParams.Limit)
+	pagedData := PagedOutboundMessagesData{
+		Items: messages,
+		Pagination: PaginationInfo{
+			TotalItems:  totalItems,
+			TotalPages:  totalPages,
+			CurrentPage: queryParams.Page,
+			PageSize:    queryParams.Limit,
+		},
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, pagedData, "通知列表获取成功")
+}
+
+// GetOutboundMessage godoc
+// @Summary 获取通知详情
+// @Description 返回通知的收件人、状态、尝试次数、下次尝试时间和最近一次失败原因，不包含正文。
+// @Tags OutboundMessages
+// @Produce json
+// @Param id path int true "通知ID"
+// @Success 200 {object} utils.SuccessResponse{data=models.OutboundMessage} "通知详情"
+// @Failure 400 {object} utils.APIErrorResponse "无效的通知ID"
+// @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
+// @Failure 403 {object} utils.APIErrorResponse "权限不足"
+// @Failure 404 {object} utils.APIErrorResponse "通知未找到"
+// @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
+// @Router /outbound-messages/{id} [get]
+// @Security BearerAuth
+func (h *OutboundMessageHandler) GetOutboundMessage(c *gin.Context) {
+	id, ok := parseOutboundMessageIDParam(c)
+	if !ok {
+		return
+	}
+
+	msg, err := h.service.GetMessage(c.Request.Context(), id)
+	if err != nil {
+		respondOutboundMessageServiceError(c, err, "获取通知失败")
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, msg, "通知获取成功")
+}
+
+// RetryOutboundMessage godoc
+// @Summary 重试发送失败的通知
+// @Description 将发送失败的通知重新排队，尝试次数从零开始计算，由发送队列尽快发送。所属确认批次会重新变为 InProgress，直到通知发送完成。
+// @Description 生成时就失败（如通知模板无效）的通知没有可发送的内容，不能重试。
+// @Tags OutboundMessages
+// @Produce json
+// @Param id path int true "通知ID"
+// @Success 200 {object} utils.SuccessResponse{data=models.OutboundMessage} "重新排队后的通知"
+// @Failure 400 {object} utils.APIErrorResponse "无效的通知ID或通知没有可发送的内容"
+// @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
+// @Failure 403 {object} utils.APIErrorResponse "权限不足"
+// @Failure 404 {object} utils.APIErrorResponse "通知未找到"
+// @Failure 409 {object} utils.APIErrorResponse "通知不是发送失败状态"
+// @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
+// @Router /outbound-messages/{id}/retry [post]
+// @Security BearerAuth
+func (h *OutboundMessageHandler) RetryOutboundMessage(c *gin.Context) {
+	id, ok := parseOutboundMessageIDParam(c)
+	if !ok {
+		return
+	}
+
+	msg, err := h.service.RetryMessage(c.Request.Context(), id)
+	if err != nil {
+		respondOutboundMessageServiceError(c, err, "重试发送通知失败")
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, msg, "通知已重新排队")
+}
+
+// parseOutboundMessageIDParam 解析路径参数中的通知ID，失败时直接返回 400
+func parseOutboundMessageIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		utils.RespondAPIError(c, http.StatusBadRequest, "无效的通知ID", c.Param("id"))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// respondOutboundMessageServiceError 将通知发送队列服务的错误映射为 HTTP 响应
+func respondOutboundMessageServiceError(c *gin.Context, err error, defaultMessage string) {
+	switch {
+	case errors.Is(err, services.ErrOutboundMessageNotFound):
+		utils.RespondNotFoundError(c, "通知")
+	case errors.Is(err, services.ErrOutboundMessageNotRetryable):
+		utils.RespondConflictError(c, err.Error())
+	case errors.Is(err, services.ErrOutboundMessageNoContent):
+		utils.RespondAPIError(c, http.StatusBadRequest, err.Error(), nil)
+	default:
+		utils.RespondInternalServerError(c, defaultMessage, err.Error())
+	}
+}
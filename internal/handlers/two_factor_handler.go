@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/phone_management/internal/auth"
+	"github.com/phone_management/internal/models"
+	"github.com/phone_management/internal/services"
+	"github.com/phone_management/pkg/utils"
+)
+
+// TwoFactorHandler 封装了 TOTP 两步验证相关的 HTTP 处理逻辑
+type TwoFactorHandler struct {
+	service services.TwoFactorService
+}
+
+// NewTwoFactorHandler 创建一个新的 TwoFactorHandler 实例
+func NewTwoFactorHandler(service services.TwoFactorService) *TwoFactorHandler {
+	return &TwoFactorHandler{service: service}
+}
+
+// RecoveryCodesResponse 定义了返回恢复码的响应结构，恢复码明文只返回这一次
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+// SetupTOTP godoc
+// @Summary 生成两步验证密钥
+// @Description 为当前用户生成新的 TOTP 密钥和 otpauth URI（用于生成二维码）。需调用 /auth/2fa/enable 提交验证码后才会生效。
+// @Tags auth
+// @Produce json
+// @Success 200 {object} utils.SuccessResponse{data=services.TOTPSetupResult} "密钥和 otpauth URI"
+// @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
+// @Failure 409 {object} utils.APIErrorResponse "两步验证已启用"
+// @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
+// @Router /auth/2fa/setup [post]
+// @Security BearerAuth
+func (h *TwoFactorHandler) SetupTOTP(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.Setup(userID)
+	if err != nil {
+		respondTwoFactorServiceError(c, err, "生成两步验证密钥失败")
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, result, "请使用验证器 App 扫描二维码")
+}
+
+// EnableTOTP godoc
+// @Summary 启用两步验证
+// @Description 提交验证器 App 生成的验证码以确认密钥并启用两步验证，返回一组一次性恢复码。
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param body body models.TOTPCodePayload true "验证码"
+// @Success 200 {object} utils.SuccessResponse{data=RecoveryCodesResponse} "启用成功，返回恢复码"
+// @Failure 400 {object} utils.APIErrorResponse "请求参数错误、验证码无效或尚未生成密钥"
+// @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
+// @Failure 409 {object} utils.APIErrorResponse "两步验证已启用"
+// @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
+// @Router /auth/2fa/enable [post]
+// @Security BearerAuth
+func (h *TwoFactorHandler) EnableTOTP(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var payload models.TOTPCodePayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.RespondValidationError(c, err.Error())
+		return
+	}
+
+	codes, err := h.service.Enable(c.Request.Context(), userID, payload.Code)
+	if err != nil {
+		respondTwoFactorServiceError(c, err, "启用两步验证失败")
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, RecoveryCodesResponse{RecoveryCodes: codes}, "两步验证已启用，请妥善保存恢复码")
+}
+
+// DisableTOTP godoc
+// @Summary 关闭两步验证
+// @Description 校验当前密码和验证码（或恢复码）后关闭两步验证，恢复码全部作废。
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param body body models.DisableTOTPPayload true "密码和验证码"
+// @Success 200 {object} utils.SuccessResponse "两步验证已关闭"
+// @Failure 400 {object} utils.APIErrorResponse "请求参数错误、密码或验证码不正确、两步验证未启用"
+// @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
+// @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
+// @Router /auth/2fa/disable [post]
+// @Security BearerAuth
+func (h *TwoFactorHandler) DisableTOTP(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var payload models.DisableTOTPPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.RespondValidationError(c, err.Error())
+		return
+	}
+
+	if err := h.service.Disable(c.Request.Context(), userID, payload.Password, payload.Code); err != nil {
+		respondTwoFactorServiceError(c, err, "关闭两步验证失败")
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, nil, "两步验证已关闭")
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary 重新生成恢复码
+// @Description 校验验证码后生成一组新的恢复码，旧恢复码全部作废。
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param body body models.TOTPCodePayload true "验证码"
+// @Success 200 {object} utils.SuccessResponse{data=RecoveryCodesResponse} "新的恢复码"
+// @Failure 400 {object} utils.APIErrorResponse "请求参数错误、验证码无效或两步验证未启用"
+// @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
+// @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
+// @Router /auth/2fa/recovery-codes [post]
+// @Security BearerAuth
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var payload models.TOTPCodePayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.RespondValidationError(c, err.Error())
+		return
+	}
+
+	codes, err := h.service.RegenerateRecoveryCodes(userID, payload.Code)
+	if err != nil {
+		respondTwoFactorServiceError(c, err, "生成恢复码失败")
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, RecoveryCodesResponse{RecoveryCodes: codes}, "恢复码已重新生成，请妥善保存")
+}
+
+// ResetUserTOTP godoc
+// @Summary 重置系统用户的两步验证
+// @Description 管理员清除指定用户的两步验证设置，用于用户丢失验证设备的情况。
+// @Tags Users
+// @Produce json
+// @Param id path int true "用户ID"
+// @Success 200 {object} utils.SuccessResponse "两步验证已重置"
+// @Failure 400 {object} utils.APIErrorResponse "无效的用户ID"
+// @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
+// @Failure 404 {object} utils.APIErrorResponse "用户未找到"
+// @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
+// @Router /users/{id}/reset-2fa [post]
+// @Security BearerAuth
+func (h *TwoFactorHandler) ResetUserTOTP(c *gin.Context) {
+	id, ok := parseUserIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Reset(c.Request.Context(), id); err != nil {
+		respondTwoFactorServiceError(c, err, "重置两步验证失败")
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, nil, "两步验证已重置")
+}
+
+// currentUserID 获取当前登录用户的ID，失败时直接返回错误响应
+func currentUserID(c *gin.Context) (int64, bool) {
+	userID, ok := auth.GetCurrentUserID(c)
+	if !ok {
+		utils.RespondInternalServerError(c, "无法获取当前操作员信息", "用户上下文信息缺失")
+		return 0, false
+	}
+	return int64(userID), true
+}
+
+// respondTwoFactorServiceError 将两步验证服务层错误映射为 HTTP 响应
+func respondTwoFactorServiceError(c *gin.Context, err error, defaultMessage string) {
+	switch {
+	case errors.Is(err, services.ErrUserNotFound):
+		utils.RespondNotFoundError(c, "用户")
+	case errors.Is(err, services.ErrTOTPAlreadyEnabled):
+		utils.RespondConflictError(c, err.Error())
+	case errors.Is(err, services.ErrTOTPNotEnabled),
+		errors.Is(err, services.ErrTOTPNotSetup),
+		errors.Is(err, services.ErrInvalidTOTPCode),
+		errors.Is(err, services.ErrIncorrectPassword):
+		utils.RespondAPIError(c, http.StatusBadRequest, err.Error(), nil)
+	default:
+		utils.RespondInternalServerError(c, defaultMessage, err.Error())
+	}
+}
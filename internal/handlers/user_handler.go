@@ -0,0 +1,353 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/phone_management/internal/auth"
+	"github.com/phone_management/internal/models"
+	"github.com/phone_management/internal/repositories"
+	"github.com/phone_management/internal/services"
+	"github.com/phone_management/pkg/utils"
+)
+
+// UserHandler 封装了系统用户管理相关的 HTTP 处理逻辑
+type UserHandler struct {
+	service services.UserService
+}
+
+// NewUserHandler 创建一个新的 UserHandler 实例
+func NewUserHandler(service services.UserService) *UserHandler {
+	return &UserHandler{service: service}
+}
+
+// PagedUsersData 定义了系统用户列表的分页响应结构
+type PagedUsersData struct {
+	Items      []models.User  `json:"items"`
+	Pagination PaginationInfo `json:"pagination"`
+}
+
+// CreateUser godoc
+// @Summary 新增系统用户
+// @Description 创建一个新的后台管理账号，密码使用 bcrypt 哈希存储。角色为 department_manager 时须通过 departmentIds 指定所负责的部门。
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param user body models.CreateUserPayload true "用户信息"
+// @Success 201 {object} utils.SuccessResponse{data=models.User} "创建成功的用户对象"
+// @Failure 400 {object} utils.APIErrorResponse "请求参数错误、角色无效或所负责的部门无效"
+// @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
+// @Failure 409 {object} utils.APIErrorResponse "用户名已存在"
+// @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
+// @Router /users [post]
+// @Security BearerAuth
+func (h *UserHandler) CreateUser(c *gin.Context) {
+	var payload models.CreateUserPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.RespondValidationError(c, err.Error())
+		return
+	}
+
+	user, err := h.service.CreateUser(c.Request.Context(), payload)
+	if err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrUsernameExists):
+			utils.RespondConflictError(c, err.Error())
+		case errors.Is(err, services.ErrInvalidRole):
+			utils.RespondAPIError(c, http.StatusBadRequest, err.Error(), models.GetAllRoles())
+		case errors.Is(err, services.ErrInvalidUserDepartments):
+			utils.RespondAPIError(c, http.StatusBadRequest, err.Error(), nil)
+		default:
+			utils.RespondInternalServerError(c, "创建用户失败", err.Error())
+		}
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusCreated, user, "用户创建成功")
+}
+
+// GetUsers godoc
+// @Summary 获取系统用户列表
+// @Description 获取后台管理账号列表，支持分页、按用户名搜索以及按角色和状态筛选。
+// @Tags Users
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量" default(10)
+// @Param search query string false "搜索关键词 (匹配用户名)"
+// @Param role query string false "角色筛选"
+// @Param status query string false "状态筛选 ('active'或'disabled')"
+// @Success 200 {object} utils.SuccessResponse{data=PagedUsersData} "成功响应，包含用户列表和分页信息"
+// @Failure 400 {object} utils.APIErrorResponse "请求参数错误"
+// @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
+// @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
+// @Router /users [get]
+// @Security BearerAuth
+func (h *UserHandler) GetUsers(c *gin.Context) {
+	type GetUsersQuery struct {
+		Page   int    `form:"page,default=1"`
+		Limit  int    `form:"limit,default=10"`
+		Search string `form:"search"`
+		Role   string `form:"role"`
+		Status string `form:"status" binding:"omitempty,oneof=active disabled"`
+	}
+
+	var queryParams GetUsersQuery
+	if err := c.ShouldBindQuery(&queryParams); err != nil {
+		utils.RespondValidationError(c, err.Error())
+		return
+	}
+	if queryParams.Limit <= 0 {
+		queryParams.Limit = 10
+	}
+	if queryParams.Page <= 0 {
+		queryParams.Page = 1
+	}
+
+	users, totalItems, err := h.service.GetUsers(queryParams.Page, queryParams.Limit, queryParams.Search, queryParams.Role, queryParams.Status)
+	if err != nil {
+		utils.RespondInternalServerError(c, "获取用户列表失败", err.Error())
+		return
+	}
+
+	totalPages := (totalItems + int64(queryParams.Limit) - 1) / int64(queryParams.Limit)
+	pagedData := PagedUsersData{
+		Items: users,
+		Pagination: PaginationInfo{
+			TotalItems:  totalItems,
+			TotalPages:  totalPages,
+			CurrentPage: queryParams.Page,
+			PageSize:    queryParams.Limit,
+		},
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, pagedData, "用户列表获取成功")
+}
+
+// GetUserByID godoc
+// @Summary 获取系统用户详情
+// @Tags Users
+// @Produce json
+// @Param id path int true "用户ID"
+// @Success 200 {object} utils.SuccessResponse{data=models.User} "用户详情"
+// @Failure 400 {object} utils.APIErrorResponse "无效的用户ID"
+// @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
+// @Failure 404 {object} utils.APIErrorResponse "用户未找到"
+// @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
+// @Router /users/{id} [get]
+// @Security BearerAuth
+func (h *UserHandler) GetUserByID(c *gin.Context) {
+	id, ok := parseUserIDParam(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.service.GetUserByID(id)
+	if err != nil {
+		respondUserServiceError(c, err, "获取用户详情失败")
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, user, "用户详情获取成功")
+}
+
+// UpdateUser godoc
+// @Summary 更新系统用户
+// @Description 更新用户的角色、状态（启用/禁用）或所负责的部门。不允许降级或禁用最后一个启用状态的管理员。
+// @Description 角色为 department_manager 时至少负责一个部门；角色改为其他角色时自动清除所负责的部门。
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param id path int true "用户ID"
+// @Param userUpdate body models.UpdateUserPayload true "要更新的字段"
+// @Success 200 {object} utils.SuccessResponse{data=models.User} "更新后的用户对象"
+// @Failure 400 {object} utils.APIErrorResponse "请求参数错误、角色无效或所负责的部门无效"
+// @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
+// @Failure 404 {object} utils.APIErrorResponse "用户未找到"
+// @Failure 409 {object} utils.APIErrorResponse "不能降级或禁用最后一个管理员"
+// @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
+// @Router /users/{id}/update [post]
+// @Security BearerAuth
+func (h *UserHandler) UpdateUser(c *gin.Context) {
+	id, ok := parseUserIDParam(c)
+	if !ok {
+		return
+	}
+
+	var payload models.UpdateUserPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.RespondValidationError(c, err.Error())
+		return
+	}
+	if payload.Role == nil && payload.Status == nil {
+		utils.RespondAPIError(c, http.StatusBadRequest, "至少需要提供一个更新字段", nil)
+		return
+	}
+
+	user, err := h.service.UpdateUser(c.Request.Context(), id, payload)
+	if err != nil {
+		respondUserServiceError(c, err, "更新用户失败")
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, user, "用户更新成功")
+}
+
+// DeleteUser godoc
+// @Summary 删除系统用户
+// @Description 删除后台管理账号。不允许删除当前登录的账号或最后一个启用状态的管理员。
+// @Tags Users
+// @Produce json
+// @Param id path int true "用户ID"
+// @Success 200 {object} utils.SuccessResponse "删除成功"
+// @Failure 400 {object} utils.APIErrorResponse "无效的用户ID或不能删除自己"
+// @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
+// @Failure 404 {object} utils.APIErrorResponse "用户未找到"
+// @Failure 409 {object} utils.APIErrorResponse "不能删除最后一个管理员"
+// @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
+// @Router /users/{id} [delete]
+// @Security BearerAuth
+func (h *UserHandler) DeleteUser(c *gin.Context) {
+	id, ok := parseUserIDParam(c)
+	if !ok {
+		return
+	}
+
+	currentUserID, ok := auth.GetCurrentUserID(c)
+	if !ok {
+		utils.RespondInternalServerError(c, "无法获取当前操作员信息", "用户上下文信息缺失")
+		return
+	}
+
+	if err := h.service.DeleteUser(c.Request.Context(), id, int64(currentUserID)); err != nil {
+		respondUserServiceError(c, err, "删除用户失败")
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, nil, "用户删除成功")
+}
+
+// ResetPassword godoc
+// @Summary 重置系统用户密码
+// @Description 管理员为指定用户设置新密码。
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param id path int true "用户ID"
+// @Param body body models.ResetPasswordPayload true "新密码"
+// @Success 200 {object} utils.SuccessResponse "密码重置成功"
+// @Failure 400 {object} utils.APIErrorResponse "请求参数错误"
+// @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
+// @Failure 404 {object} utils.APIErrorResponse "用户未找到"
+// @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
+// @Router /users/{id}/reset-password [post]
+// @Security BearerAuth
+func (h *UserHandler) ResetPassword(c *gin.Context) {
+	id, ok := parseUserIDParam(c)
+	if !ok {
+		return
+	}
+
+	var payload models.ResetPasswordPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.RespondValidationError(c, err.Error())
+		return
+	}
+
+	if err := h.service.ResetPassword(c.Request.Context(), id, payload.NewPassword); err != nil {
+		respondUserServiceError(c, err, "重置密码失败")
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, nil, "密码重置成功")
+}
+
+// UnlockUser godoc
+// @Summary 解除系统用户登录锁定
+// @Description 清除账号因连续登录失败产生的锁定状态和失败计数。
+// @Tags Users
+// @Produce json
+// @Param id path int true "用户ID"
+// @Success 200 {object} utils.SuccessResponse{data=models.User} "解锁后的用户对象"
+// @Failure 400 {object} utils.APIErrorResponse "无效的用户ID"
+// @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
+// @Failure 404 {object} utils.APIErrorResponse "用户未找到"
+// @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
+// @Router /users/{id}/unlock [post]
+// @Security BearerAuth
+func (h *UserHandler) UnlockUser(c *gin.Context) {
+	id, ok := parseUserIDParam(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.service.UnlockUser(c.Request.Context(), id)
+	if err != nil {
+		respondUserServiceError(c, err, "解除锁定失败")
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, user, "账号已解锁")
+}
+
+// ChangePassword godoc
+// @Summary 修改当前用户密码
+// @Description 当前登录用户校验原密码后修改自己的密码。
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param body body models.ChangePasswordPayload true "原密码和新密码"
+// @Success 200 {object} utils.SuccessResponse "密码修改成功"
+// @Failure 400 {object} utils.APIErrorResponse "请求参数错误或原密码不正确"
+// @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
+// @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
+// @Router /auth/change-password [post]
+// @Security BearerAuth
+func (h *UserHandler) ChangePassword(c *gin.Context) {
+	var payload models.ChangePasswordPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.RespondValidationError(c, err.Error())
+		return
+	}
+
+	currentUserID, ok := auth.GetCurrentUserID(c)
+	if !ok {
+		utils.RespondInternalServerError(c, "无法获取当前操作员信息", "用户上下文信息缺失")
+		return
+	}
+
+	if err := h.service.ChangePassword(c.Request.Context(), int64(currentUserID), payload.OldPassword, payload.NewPassword); err != nil {
+		respondUserServiceError(c, err, "修改密码失败")
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, nil, "密码修改成功")
+}
+
+// parseUserIDParam 解析路径参数中的用户ID，失败时直接返回 400
+func parseUserIDParam(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		utils.RespondAPIError(c, http.StatusBadRequest, "无效的用户ID", c.Param("id"))
+		return 0, false
+	}
+	return id, true
+}
+
+// respondUserServiceError 将用户服务层错误映射为 HTTP 响应
+func respondUserServiceError(c *gin.Context, err error, defaultMessage string) {
+	switch {
+	case errors.Is(err, services.ErrUserNotFound):
+		utils.RespondNotFoundError(c, "用户")
+	case errors.Is(err, services.ErrInvalidRole):
+		utils.RespondAPIError(c, http.StatusBadRequest, err.Error(), models.GetAllRoles())
+	case errors.Is(err, services.ErrLastAdmin):
+		utils.RespondConflictError(c, err.Error())
+	case errors.Is(err, services.ErrCannotDeleteSelf), errors.Is(err, services.ErrIncorrectPassword),
+		errors.Is(err, services.ErrInvalidUserDepartments), errors.Is(err, services.ErrNoUpdateFields):
+		utils.RespondAPIError(c, http.StatusBadRequest, err.Error(), nil)
+	default:
+		utils.RespondInternalServerError(c, defaultMessage, err.Error())
+	}
+}
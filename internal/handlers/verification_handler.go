@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -26,6 +27,14 @@ type InitiateVerificationRequest struct {
 	Scope        string   `json:"scope" binding:"required,oneof=all_users department employee_ids"`
 	ScopeValues  []string `json:"scopeValues,omitempty"`
 	DurationDays int      `json:"durationDays" binding:"required,min=1,max=30"`
+	// IncludeSubDepartments 仅在 scope 为 department 时有效，为 true 时同时向下级部门的员工发起确认
+	IncludeSubDepartments bool `json:"includeSubDepartments,omitempty"`
+	// RemindAfterDays 发出确认通知后第几天自动催办尚未提交确认结果的员工，未提供时使用服务端配置的默认值，传空数组表示不按此方式催办
+	RemindAfterDays []int `json:"remindAfterDays,omitempty" binding:"omitempty,max=10,dive,min=1,max=30"`
+	// RemindBeforeExpiryDays 链接过期前几天自动催办尚未提交确认结果的员工，未提供时使用服务端配置的默认值，传空数组表示不按此方式催办
+	RemindBeforeExpiryDays []int `json:"remindBeforeExpiryDays,omitempty" binding:"omitempty,max=10,dive,min=1,max=30"`
+	// MaxReminders 每位员工最多催办次数（包括手动催办），未提供时使用服务端配置的默认值，0 表示该批次不自动催办
+	MaxReminders *int `json:"maxReminders,omitempty" binding:"omitempty,min=0,max=10"`
 }
 
 // InitiateVerificationResponse 定义了发起确认流程API成功时的响应体
@@ -67,6 +76,7 @@ func (h *VerificationHandler) GetVerificationInfo(c *gin.Context) {
 // InitiateVerification godoc
 // @Summary 发起号码使用确认流程 (异步)
 // @Description 管理员调用此接口后，系统创建一个批处理任务来为目标员工生成 VerificationTokens 并发送邮件。接口立即返回批处理ID。
+// @Description 批次按 remindAfterDays、remindBeforeExpiryDays 和 maxReminders 自动催办尚未提交确认结果的员工，催办通知的标题带有催办标记；未提供时使用服务端配置的默认值。
 // @Tags Verification
 // @Accept json
 // @Produce json
@@ -101,8 +111,16 @@ func (h *VerificationHandler) InitiateVerification(c *gin.Context) {
 		return
 	}
 
-	batchID, err := h.verificationService.InitiateVerificationProcess(c.Request.Context(), scopeType, req.ScopeValues, req.DurationDays)
+	batchID, err := h.verificationService.InitiateVerificationProcess(c.Request.Context(), scopeType, req.ScopeValues, req.IncludeSubDepartments, req.DurationDays, services.ReminderOptions{
+		AfterSendDays:    req.RemindAfterDays,
+		BeforeExpiryDays: req.RemindBeforeExpiryDays,
+		MaxReminders:     req.MaxReminders,
+	})
 	if err != nil {
+		if errors.Is(err, services.ErrDepartmentNotFound) {
+			utils.RespondAPIError(c, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
 		// 根据错误类型，可能返回不同的状态码，例如，如果是预检员工查找失败，可能是400或500
 		// 暂时统一处理为500
 		utils.RespondInternalServerError(c, "发起确认流程失败", err.Error())
@@ -118,6 +136,8 @@ func (h *VerificationHandler) InitiateVerification(c *gin.Context) {
 // GetVerificationBatchStatus godoc
 // @Summary 获取号码确认批处理任务的状态
 // @Description 获取指定号码确认批处理任务的当前状态、整体进度（包括已处理员工数、令牌生成情况、邮件发送统计：尝试数、成功数、失败数）以及详细的错误报告（例如邮件发送失败的原因）。
+// @Description 通知由发送队列发送，失败时自动重试；令牌数和发送统计（尝试数、成功数、失败数、待发送数）由发送队列中该批次的通知计算得出，服务重启后不会丢失。
+// @Description 状态：Pending 正在生成通知，InProgress 通知已全部进入发送队列、正在发送，Completed/CompletedWithErrors 队列中已没有待发送的通知。失败的通知可通过 /outbound-messages/{id}/retry 重试。
 // @Tags Verification
 // @Produce json
 // @Param batchId path string true "批处理任务ID"
@@ -262,8 +282,10 @@ func (h *VerificationHandler) SubmitVerificationResult(c *gin.Context) {
 // @Produce json
 // @Param employee_id query string false "员工业务工号，用于筛选"
 // @Param employeeId query string false "员工业务工号，用于筛选（兼容旧版本）"
-// @Param department query string false "部门名称，用于筛选"
-// @Param departmentName query string false "部门名称，用于筛选（兼容旧版本）"
+// @Param departmentId query int false "部门ID，用于筛选，优先于部门名称"
+// @Param department query string false "部门名称或别名，用于筛选"
+// @Param departmentName query string false "部门名称或别名，用于筛选（兼容旧版本）"
+// @Param includeSubDepartments query bool false "按部门筛选时是否包含下级部门" default(false)
 // @Success 200 {object} utils.SuccessResponse{data=models.AdminVerificationStatusResponse} "成功响应"
 // @Failure 400 {object} utils.APIErrorResponse "请求参数错误"
 // @Failure 401 {object} utils.APIErrorResponse "未授权"
@@ -296,14 +318,16 @@ func (h *VerificationHandler) GetVerificationAdminStatus(c *gin.Context) {
 
 // GetPhoneVerificationStatus godoc
 // @Summary 获取基于手机号码维度的确认流程状态
-// @Description 获取基于手机号码维度的确认流程状态，包括统计摘要和详细信息
+// @Description 获取基于手机号码维度的确认流程状态，包括统计摘要和详细信息。部门负责人只能看到所负责部门（含下级部门）员工的数据
 // @Tags Verification
 // @Accept json
 // @Produce json
 // @Param employee_id query string false "员工业务工号，用于筛选"
 // @Param employeeId query string false "员工业务工号，用于筛选（兼容旧版本）"
-// @Param department query string false "部门名称，用于筛选"
-// @Param departmentName query string false "部门名称，用于筛选（兼容旧版本）"
+// @Param departmentId query int false "部门ID，用于筛选，优先于部门名称"
+// @Param department query string false "部门名称或别名，用于筛选"
+// @Param departmentName query string false "部门名称或别名，用于筛选（兼容旧版本）"
+// @Param includeSubDepartments query bool false "按部门筛选时是否包含下级部门" default(false)
 // @Success 200 {object} utils.SuccessResponse{data=models.PhoneVerificationStatusResponse} "成功响应"
 // @Failure 400 {object} utils.APIErrorResponse "请求参数错误"
 // @Failure 401 {object} utils.APIErrorResponse "未授权"
@@ -318,13 +342,13 @@ func (h *VerificationHandler) GetPhoneVerificationStatus(c *gin.Context) {
 		employeeID = c.Query("employeeId")
 	}
 
-	departmentName := c.Query("department")
-	if departmentName == "" {
-		departmentName = c.Query("departmentName")
+	department, ok := bindDepartmentFilter(c)
+	if !ok {
+		return
 	}
 
 	// 调用服务层获取基于手机号码维度的确认流程状态
-	phoneStatus, err := h.verificationService.GetPhoneVerificationStatus(c.Request.Context(), employeeID, departmentName)
+	phoneStatus, err := h.verificationService.GetPhoneVerificationStatus(c.Request.Context(), employeeID, department)
 	if err != nil {
 		utils.RespondInternalServerError(c, "获取基于手机号码维度的确认流程状态失败", err.Error())
 		return
@@ -332,3 +356,36 @@ func (h *VerificationHandler) GetPhoneVerificationStatus(c *gin.Context) {
 
 	utils.RespondSuccess(c, http.StatusOK, phoneStatus, "获取基于手机号码维度的确认流程状态成功")
 }
+
+// RemindPendingEmployee godoc
+// @Summary 催办未确认的员工
+// @Description 使用员工最近一次未过期的待确认令牌生成催办通知，与催办时间和次数一起加入通知发送队列后返回。通知与自动催办一样由队列按员工偏好的渠道发送（渠道不可用时改用邮件），失败时重试，可通过 GET /outbound-messages 查看发送结果。部门负责人只能催办所负责部门（含下级部门）的员工
+// @Tags Verification
+// @Produce json
+// @Param employeeId path string true "员工业务工号"
+// @Success 200 {object} utils.SuccessResponse{data=models.PendingUserDetail} "催办后的待确认信息"
+// @Failure 400 {object} utils.APIErrorResponse "员工未登记可用的邮箱或手机号码"
+// @Failure 401 {object} utils.APIErrorResponse "未认证或 Token 无效/过期"
+// @Failure 403 {object} utils.APIErrorResponse "权限不足"
+// @Failure 404 {object} utils.APIErrorResponse "该员工没有待确认的号码确认请求"
+// @Failure 500 {object} utils.APIErrorResponse "服务器内部错误"
+// @Router /verification/pending/{employeeId}/remind [post]
+// @Security BearerAuth
+func (h *VerificationHandler) RemindPendingEmployee(c *gin.Context) {
+	employeeID := c.Param("employeeId")
+
+	detail, err := h.verificationService.RemindPendingEmployee(c.Request.Context(), employeeID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrNoPendingVerification):
+			utils.RespondAPIError(c, http.StatusNotFound, err.Error(), nil)
+		case errors.Is(err, services.ErrEmployeeEmailMissing):
+			utils.RespondAPIError(c, http.StatusBadRequest, err.Error(), nil)
+		default:
+			utils.RespondInternalServerError(c, "催办失败", err.Error())
+		}
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, detail, "催办通知已加入发送队列")
+}
@@ -0,0 +1,84 @@
+package models
+
+import "time"
+
+// 审计事件的实体类型
+const (
+	AuditEntityMobileNumber      = "mobile_number"
+	AuditEntityEmployee          = "employee"
+	AuditEntityUser              = "user"
+	AuditEntityVerificationBatch = "verification_batch"
+	AuditEntityVerificationToken = "verification_token"
+	AuditEntityDepartment        = "department"
+	AuditEntityEmailTemplate     = "email_template"
+	AuditEntityOutboundMessage   = "outbound_message"
+)
+
+// 审计事件的操作类型，格式为 <实体>.<动作>
+const (
+	AuditActionMobileNumberCreate              = "mobile_number.create"
+	AuditActionMobileNumberUpdate              = "mobile_number.update"
+	AuditActionMobileNumberAssign              = "mobile_number.assign"
+	AuditActionMobileNumberUnassign            = "mobile_number.unassign"
+	AuditActionMobileNumberTransfer            = "mobile_number.transfer"
+	AuditActionMobileNumberChangeApplicant     = "mobile_number.change_applicant"
+	AuditActionMobileNumberHandleRisk          = "mobile_number.handle_risk"
+	AuditActionMobileNumberStatusChange        = "mobile_number.status_change"
+	AuditActionMobileNumberUserReport          = "mobile_number.user_report"
+	AuditActionMobileNumberCancelDeactivation  = "mobile_number.cancel_deactivation"
+	AuditActionMobileNumberScheduledDeactivate = "mobile_number.scheduled_deactivate"
+	AuditActionMobileNumberRestoreRisk         = "mobile_number.restore_risk"
+	AuditActionEmployeeCreate                  = "employee.create"
+	AuditActionEmployeeUpdate                  = "employee.update"
+	AuditActionEmployeeRehire                  = "employee.rehire"
+	AuditActionDepartmentCreate                = "department.create"
+	AuditActionDepartmentUpdate                = "department.update"
+	AuditActionDepartmentDelete                = "department.delete"
+	AuditActionDepartmentMerge                 = "department.merge"
+	AuditActionEmailTemplateCreate             = "email_template.create"
+	AuditActionEmailTemplateUpdate             = "email_template.update"
+	AuditActionEmailTemplateDelete             = "email_template.delete"
+	AuditActionOutboundMessageRetry            = "outbound_message.retry"
+	AuditActionVerificationInitiate            = "verification.initiate"
+	AuditActionVerificationRemind              = "verification.remind"
+	AuditActionUserCreate                      = "user.create"
+	AuditActionUserUpdate                      = "user.update"
+	AuditActionUserDelete                      = "user.delete"
+	AuditActionUserResetPassword               = "user.reset_password"
+	AuditActionUserChangePassword              = "user.change_password"
+	AuditActionUserUnlock                      = "user.unlock"
+	AuditActionUserTOTPEnable                  = "user.totp_enable"
+	AuditActionUserTOTPReset                   = "user.totp_reset"
+)
+
+// AuditEvent 操作审计事件，与被审计的变更在同一事务中写入。
+// Before/After 保存变更前后实体的 JSON 快照，创建时 Before 为空，删除时 After 为空。
+type AuditEvent struct {
+	ID            int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	ActorUserID   *int64    `json:"actorUserId,omitempty" gorm:"column:actor_user_id"`                  // 操作者系统用户ID，系统任务或员工自助操作时为空
+	ActorUsername string    `json:"actorUsername" gorm:"column:actor_username;size:100;not null;index"` // 操作者用户名（员工自助操作时为员工工号）
+	Source        string    `json:"source" gorm:"column:source;size:20;not null;index"`                 // 来源: api, import, bulk, verification, system, directory, hr
+	ClientIP      string    `json:"clientIp,omitempty" gorm:"column:client_ip;size:64"`                 // 请求来源IP
+	Action        string    `json:"action" gorm:"column:action;size:50;not null;index"`                 // 操作类型，如 mobile_number.assign
+	EntityType    string    `json:"entityType" gorm:"column:entity_type;size:50;not null;index:idx_audit_entity"`
+	EntityID      string    `json:"entityId" gorm:"column:entity_id;size:100;not null;index:idx_audit_entity"` // 实体标识：号码为手机号，员工为工号，用户、部门、通知模板和待发送通知为ID，确认批次为批次ID
+	Before        *string   `json:"before,omitempty" gorm:"column:before_data;type:text"`                      // 变更前的实体快照 (JSON)
+	After         *string   `json:"after,omitempty" gorm:"column:after_data;type:text"`                        // 变更后的实体快照 (JSON)
+	CreatedAt     time.Time `json:"createdAt" gorm:"column:created_at;autoCreateTime;index"`                   // 发生时间
+}
+
+// TableName 设置表名
+func (AuditEvent) TableName() string {
+	return "audit_events"
+}
+
+// AuditEventFilter 审计事件查询条件
+type AuditEventFilter struct {
+	Actor      string     // 操作者用户名
+	Action     string     // 操作类型
+	EntityType string     // 实体类型
+	EntityID   string     // 实体ID
+	Source     string     // 来源
+	From       *time.Time // 起始时间（含）
+	To         *time.Time // 结束时间（不含）
+}
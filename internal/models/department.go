@@ -0,0 +1,86 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Department 对应于数据库中的 departments 表。部门通过 ParentID 组成树形层级，
+// 员工通过 Employee.DepartmentID 关联部门，Employee.Department 保存部门名称的副本用于展示和兼容旧接口
+type Department struct {
+	ID                uint      `json:"id" gorm:"primaryKey"`
+	Name              string    `json:"name" gorm:"column:name;not null;uniqueIndex;size:255"`                 // 部门名称，全局唯一
+	ParentID          *uint     `json:"parentId,omitempty" gorm:"column:parent_id;index"`                      // 上级部门ID，顶级部门为空
+	ManagerEmployeeID *string   `json:"managerEmployeeId,omitempty" gorm:"column:manager_employee_id;size:10"` // 部门负责人员工业务工号
+	CostCenter        *string   `json:"costCenter,omitempty" gorm:"column:cost_center;size:50"`                // 成本中心编码
+	CreatedAt         time.Time `json:"createdAt" gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt         time.Time `json:"updatedAt" gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// TableName 设置表名
+func (Department) TableName() string {
+	return "departments"
+}
+
+// DepartmentAlias 部门的别名。合并部门或修改部门名称后，旧名称记为别名，
+// 按名称指定部门（如导入员工、按部门发起确认）时别名同样可以匹配到该部门
+type DepartmentAlias struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	Name         string    `json:"name" gorm:"column:name;not null;uniqueIndex;size:255"`
+	DepartmentID uint      `json:"departmentId" gorm:"column:department_id;not null;index"`
+	CreatedAt    time.Time `json:"createdAt" gorm:"column:created_at;autoCreateTime"`
+}
+
+// TableName 设置表名
+func (DepartmentAlias) TableName() string {
+	return "department_aliases"
+}
+
+// NormalizeDepartmentName 规范化部门名称：去除首尾空白，并将中间连续的空白（包括全角空格）合并为一个半角空格
+func NormalizeDepartmentName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// DepartmentResponse 部门信息及其负责人、上级部门、别名和直属员工数量
+type DepartmentResponse struct {
+	Department
+	ParentName    string               `json:"parentName,omitempty"`  // 上级部门名称
+	ManagerName   string               `json:"managerName,omitempty"` // 部门负责人姓名
+	Aliases       []string             `json:"aliases"`               // 部门别名
+	EmployeeCount int64                `json:"employeeCount"`         // 直属员工数量（不含下级部门）
+	Children      []DepartmentResponse `json:"children,omitempty"`    // 下级部门，仅部门树中返回
+}
+
+// CreateDepartmentPayload 定义了创建部门的请求体
+type CreateDepartmentPayload struct {
+	Name              string  `json:"name" binding:"required,max=255"`
+	ParentID          *uint   `json:"parentId,omitempty"`                              // 上级部门ID，不提供则为顶级部门
+	ManagerEmployeeID *string `json:"managerEmployeeId,omitempty"`                     // 部门负责人员工业务工号
+	CostCenter        *string `json:"costCenter,omitempty" binding:"omitempty,max=50"` // 成本中心编码
+}
+
+// UpdateDepartmentPayload 定义了更新部门的请求体，所有字段都是可选的
+type UpdateDepartmentPayload struct {
+	Name              *string `json:"name,omitempty" binding:"omitempty,max=255"`      // 新名称，原名称记为别名
+	ParentID          *uint   `json:"parentId,omitempty"`                              // 新的上级部门ID，0 表示改为顶级部门
+	ManagerEmployeeID *string `json:"managerEmployeeId,omitempty"`                     // 部门负责人员工业务工号，空字符串表示清除
+	CostCenter        *string `json:"costCenter,omitempty" binding:"omitempty,max=50"` // 成本中心编码，空字符串表示清除
+}
+
+// MergeDepartmentsPayload 定义了合并部门的请求体
+type MergeDepartmentsPayload struct {
+	SourceDepartmentIDs []uint `json:"sourceDepartmentIds" binding:"required,min=1"` // 被合并的部门ID，合并后删除，名称记为目标部门的别名
+}
+
+// DepartmentFilter 按部门筛选的条件。DepartmentID 优先于 DepartmentName，名称可以是部门名称或别名；
+// 两者都为空时不筛选
+type DepartmentFilter struct {
+	DepartmentID          *uint
+	DepartmentName        string
+	IncludeSubDepartments bool // 是否包含下级部门
+}
+
+// IsEmpty 判断是否未指定部门
+func (f DepartmentFilter) IsEmpty() bool {
+	return f.DepartmentID == nil && NormalizeDepartmentName(f.DepartmentName) == ""
+}
@@ -0,0 +1,69 @@
+package models
+
+import "time"
+
+// DirectorySyncTrigger 定义了目录同步的触发方式
+type DirectorySyncTrigger string
+
+const (
+	DirectorySyncManual    DirectorySyncTrigger = "manual"    // 管理员手动触发
+	DirectorySyncScheduled DirectorySyncTrigger = "scheduled" // 定时任务触发
+)
+
+// DirectorySyncStatus 定义了目录同步的状态
+type DirectorySyncStatus string
+
+const (
+	DirectorySyncRunning   DirectorySyncStatus = "running"   // 同步中
+	DirectorySyncSucceeded DirectorySyncStatus = "succeeded" // 全部条目处理成功
+	DirectorySyncPartial   DirectorySyncStatus = "partial"   // 部分条目处理失败，或因超过离职上限未办理离职
+	DirectorySyncFailed    DirectorySyncStatus = "failed"    // 读取目录失败，未处理任何条目
+)
+
+// DirectorySyncAction 定义了同步报告中单个条目的处理结果
+type DirectorySyncAction string
+
+const (
+	DirectorySyncCreate DirectorySyncAction = "create" // 新建员工
+	DirectorySyncUpdate DirectorySyncAction = "update" // 更新员工信息
+	DirectorySyncDepart DirectorySyncAction = "depart" // 办理离职
+	DirectorySyncSkip   DirectorySyncAction = "skip"   // 需要人工处理，未做变更
+	DirectorySyncFail   DirectorySyncAction = "fail"   // 处理失败
+)
+
+// DirectorySyncItem 同步报告中的一个条目，未变更的员工不出现在报告中
+type DirectorySyncItem struct {
+	Action      DirectorySyncAction `json:"action"`
+	EmployeeID  string              `json:"employeeId,omitempty"`  // 员工业务工号，新建失败时为空
+	DirectoryDN string              `json:"directoryDn,omitempty"` // 目录用户 DN，员工已不在目录中时为空
+	FullName    string              `json:"fullName"`              // 姓名
+	Changes     []string            `json:"changes,omitempty"`     // 变更的字段
+	Reason      string              `json:"reason,omitempty"`      // 跳过或失败的原因
+}
+
+// DirectorySyncRun 一次员工目录同步的记录及报告。试运行时只生成报告，不修改员工数据
+type DirectorySyncRun struct {
+	ID             uint                 `json:"id" gorm:"primaryKey"`
+	Trigger        DirectorySyncTrigger `json:"trigger" gorm:"column:trigger_type;type:varchar(20);not null"`
+	DryRun         bool                 `json:"dryRun" gorm:"column:dry_run;not null;default:false"`
+	Status         DirectorySyncStatus  `json:"status" gorm:"column:status;type:varchar(20);not null;index"`
+	TriggeredBy    string               `json:"triggeredBy" gorm:"column:triggered_by;size:100"` // 触发同步的用户名，定时任务为 system
+	StartedAt      time.Time            `json:"startedAt" gorm:"column:started_at;not null"`
+	FinishedAt     *time.Time           `json:"finishedAt,omitempty" gorm:"column:finished_at"`
+	ScannedCount   int                  `json:"scannedCount" gorm:"column:scanned_count;not null;default:0"` // 从目录读取的用户数
+	CreatedCount   int                  `json:"createdCount" gorm:"column:created_count;not null;default:0"`
+	UpdatedCount   int                  `json:"updatedCount" gorm:"column:updated_count;not null;default:0"`
+	DepartedCount  int                  `json:"departedCount" gorm:"column:departed_count;not null;default:0"`
+	UnchangedCount int                  `json:"unchangedCount" gorm:"column:unchanged_count;not null;default:0"`
+	SkippedCount   int                  `json:"skippedCount" gorm:"column:skipped_count;not null;default:0"`
+	FailedCount    int                  `json:"failedCount" gorm:"column:failed_count;not null;default:0"`
+	ErrorMessage   *string              `json:"errorMessage,omitempty" gorm:"column:error_message;type:text"` // 同步整体失败或未办理离职的原因
+	ItemsJSON      *string              `json:"-" gorm:"column:items;type:text"`                              // 报告条目 (JSON)
+	Items          []DirectorySyncItem  `json:"items,omitempty" gorm:"-"`                                     // 报告条目，仅在查询单次同步详情时返回
+	CreatedAt      time.Time            `json:"createdAt" gorm:"column:created_at;autoCreateTime"`
+}
+
+// TableName 设置表名
+func (DirectorySyncRun) TableName() string {
+	return "directory_sync_runs"
+}
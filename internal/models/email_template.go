@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+// EmailTemplate 保存在数据库中的通知模板，覆盖同名称、同语言的模板文件和内置模板。
+// Subject 和 TextBody 为 text/template 模板，HTMLBody 为 html/template 模板（变量会按 HTML 转义）
+type EmailTemplate struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Name      string    `json:"name" gorm:"column:name;size:50;not null;uniqueIndex:idx_email_template_name_locale"`     // 模板名称，如 verification
+	Locale    string    `json:"locale" gorm:"column:locale;size:10;not null;uniqueIndex:idx_email_template_name_locale"` // 语言 ('zh-CN', 'en')
+	Subject   string    `json:"subject" gorm:"column:subject;type:text;not null"`                                        // 邮件标题模板
+	HTMLBody  string    `json:"htmlBody" gorm:"column:html_body;type:text;not null"`                                     // HTML 正文模板
+	TextBody  string    `json:"textBody" gorm:"column:text_body;type:text;not null"`                                     // 纯文本正文模板，作为邮件的纯文本版本，也用于 IM 和短信
+	CreatedAt time.Time `json:"createdAt" gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt time.Time `json:"updatedAt" gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// TableName 设置表名
+func (EmailTemplate) TableName() string {
+	return "email_templates"
+}
+
+// 通知模板的来源，按此顺序查找
+const (
+	EmailTemplateSourceDatabase = "database" // 数据库中保存的模板
+	EmailTemplateSourceFile     = "file"     // EMAIL_TEMPLATE_DIR 中的模板文件
+	EmailTemplateSourceBuiltin  = "builtin"  // 内置模板
+	EmailTemplateSourceDraft    = "draft"    // 预览请求中提交的未保存模板
+)
+
+// CreateEmailTemplatePayload 定义了新增通知模板的请求体，TextBody 必须提供，HTMLBody 可选
+type CreateEmailTemplatePayload struct {
+	Name     string `json:"name" binding:"required,max=50"`           // 模板名称，目前支持 verification
+	Locale   string `json:"locale" binding:"required,oneof=zh-CN en"` // 语言
+	Subject  string `json:"subject" binding:"required"`               // 邮件标题模板
+	HTMLBody string `json:"htmlBody,omitempty"`                       // HTML 正文模板
+	TextBody string `json:"textBody,omitempty"`                       // 纯文本正文模板
+}
+
+// UpdateEmailTemplatePayload 定义了更新通知模板的请求体，所有字段都是可选的，空字符串表示清除正文
+type UpdateEmailTemplatePayload struct {
+	Subject  *string `json:"subject,omitempty"`
+	HTMLBody *string `json:"htmlBody,omitempty"`
+	TextBody *string `json:"textBody,omitempty"`
+}
+
+// PreviewEmailTemplatePayload 定义了预览通知模板的请求体。
+// Subject、HTMLBody、TextBody 均为空时预览当前生效的模板，否则预览提交的模板（不保存）
+type PreviewEmailTemplatePayload struct {
+	Name     string `json:"name" binding:"required,max=50"`
+	Locale   string `json:"locale" binding:"required,oneof=zh-CN en"`
+	Subject  string `json:"subject,omitempty"`
+	HTMLBody string `json:"htmlBody,omitempty"`
+	TextBody string `json:"textBody,omitempty"`
+}
+
+// EmailTemplatePreview 通知模板以示例数据渲染的结果
+type EmailTemplatePreview struct {
+	Name    string `json:"name"`
+	Locale  string `json:"locale"`
+	Source  string `json:"source"` // 模板来源: database, file, builtin, draft
+	Subject string `json:"subject"`
+	HTML    string `json:"html"`
+	Text    string `json:"text"`
+}
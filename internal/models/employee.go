@@ -11,18 +11,49 @@ import (
 
 // Employee 对应于数据库中的 employees 表
 type Employee struct {
-	ID               int64          `json:"id" gorm:"primaryKey;autoIncrement"`
-	EmployeeID       string         `json:"employeeId" gorm:"column:employee_id;unique;not null;size:10"`                                      // 员工业务工号, 例如 EMP0000001
-	FullName         string         `json:"fullName" gorm:"column:full_name;not null;size:255"`                                                // 姓名
-	PhoneNumber      *string        `json:"phoneNumber,omitempty" gorm:"column:phone_number;size:11;uniqueIndex:idx_phone_number_not_deleted"` // 员工手机号码, 11位, 可选, 唯一 (NULLS NOT DISTINCT)
-	Email            *string        `json:"email,omitempty" gorm:"column:email;size:255;uniqueIndex:idx_email_not_deleted"`                    // 员工邮箱, 可选, 唯一 (NULLS NOT DISTINCT)
-	Department       *string        `json:"department,omitempty" gorm:"column:department;size:255"`                                            // 部门
-	EmploymentStatus string         `json:"employmentStatus" gorm:"column:employment_status;not null;default:'Active';size:50"`                // 在职状态 ('Active', 'Departed')
-	HireDate         *time.Time     `json:"hireDate,omitempty" gorm:"column:hire_date;type:date"`                                              // 入职日期
-	TerminationDate  *time.Time     `json:"terminationDate,omitempty" gorm:"column:termination_date;type:date"`                                // 离职日期
-	CreatedAt        time.Time      `json:"createdAt" gorm:"column:created_at;not null;autoCreateTime"`
-	UpdatedAt        time.Time      `json:"updatedAt" gorm:"column:updated_at;not null;autoUpdateTime"`
-	DeletedAt        gorm.DeletedAt `json:"deletedAt,omitempty" gorm:"index" swaggertype:"string" format:"date-time"`
+	ID                  int64          `json:"id" gorm:"primaryKey;autoIncrement"`
+	EmployeeID          string         `json:"employeeId" gorm:"column:employee_id;unique;not null;size:10"`                                      // 员工业务工号, 例如 EMP0000001
+	FullName            string         `json:"fullName" gorm:"column:full_name;not null;size:255"`                                                // 姓名
+	PhoneNumber         *string        `json:"phoneNumber,omitempty" gorm:"column:phone_number;size:11;uniqueIndex:idx_phone_number_not_deleted"` // 员工手机号码, 11位, 可选, 唯一 (NULLS NOT DISTINCT)
+	Email               *string        `json:"email,omitempty" gorm:"column:email;size:255;uniqueIndex:idx_email_not_deleted"`                    // 员工邮箱, 可选, 唯一 (NULLS NOT DISTINCT)
+	Department          *string        `json:"department,omitempty" gorm:"column:department;size:255"`                                            // 部门名称，与 DepartmentID 对应的部门保持一致
+	DepartmentID        *uint          `json:"departmentId,omitempty" gorm:"column:department_id;index"`                                          // 所属部门ID
+	EmploymentStatus    string         `json:"employmentStatus" gorm:"column:employment_status;not null;default:'Active';size:50"`                // 在职状态 ('Active', 'Departed')
+	HireDate            *time.Time     `json:"hireDate,omitempty" gorm:"column:hire_date;type:date"`                                              // 入职日期
+	TerminationDate     *time.Time     `json:"terminationDate,omitempty" gorm:"column:termination_date;type:date"`                                // 离职日期
+	DirectoryDN         *string        `json:"directoryDn,omitempty" gorm:"column:directory_dn;size:512;index"`                                   // 关联的目录用户 DN，由目录同步写入
+	NotificationChannel string         `json:"notificationChannel" gorm:"column:notification_channel;not null;default:'email';size:20"`           // 接收通知的渠道 ('email', 'im', 'sms')
+	Locale              string         `json:"locale" gorm:"column:locale;not null;default:'zh-CN';size:10"`                                      // 接收通知的语言 ('zh-CN', 'en')
+	CreatedAt           time.Time      `json:"createdAt" gorm:"column:created_at;not null;autoCreateTime"`
+	UpdatedAt           time.Time      `json:"updatedAt" gorm:"column:updated_at;not null;autoUpdateTime"`
+	DeletedAt           gorm.DeletedAt `json:"deletedAt,omitempty" gorm:"index" swaggertype:"string" format:"date-time"`
+}
+
+// 员工接收通知的渠道
+const (
+	NotificationChannelEmail = "email" // 邮件
+	NotificationChannelIM    = "im"    // 企业 IM 群机器人按员工手机号码提醒，只发送不含链接的通知标题，完整通知仍发送到邮箱
+	NotificationChannelSMS   = "sms"   // 短信，发送到员工手机号码
+)
+
+// IsValidNotificationChannel 判断通知渠道是否有效
+func IsValidNotificationChannel(channel string) bool {
+	switch channel {
+	case NotificationChannelEmail, NotificationChannelIM, NotificationChannelSMS:
+		return true
+	}
+	return false
+}
+
+// 员工接收通知的语言，对应通知模板的语言
+const (
+	LocaleZhCN = "zh-CN" // 简体中文
+	LocaleEN   = "en"    // 英文
+)
+
+// IsValidLocale 判断通知语言是否有效
+func IsValidLocale(locale string) bool {
+	return locale == LocaleZhCN || locale == LocaleEN
 }
 
 // TableName 指定 Employee 结构体对应的数据库表名
@@ -72,6 +103,7 @@ type EmployeeDetailResponse struct {
 	EmployeeID           string                  `json:"employeeId"`
 	FullName             string                  `json:"fullName"`
 	Department           *string                 `json:"department,omitempty"`
+	DepartmentID         *uint                   `json:"departmentId,omitempty"`
 	EmploymentStatus     string                  `json:"employmentStatus"`
 	HireDate             *time.Time              `json:"hireDate,omitempty"`
 	TerminationDate      *time.Time              `json:"terminationDate,omitempty"`
@@ -79,14 +111,22 @@ type EmployeeDetailResponse struct {
 	UpdatedAt            time.Time               `json:"updatedAt"`
 	HandledMobileNumbers []MobileNumberBasicInfo `json:"handledMobileNumbers,omitempty"` // 作为办卡人的号码列表
 	UsingMobileNumbers   []MobileNumberBasicInfo `json:"usingMobileNumbers,omitempty"`   // 作为当前使用人的号码列表
+	EmploymentPeriods    []EmploymentPeriod      `json:"employmentPeriods,omitempty"`    // 复职前已结束的任职期间，按离职日期升序
 }
 
 // UpdateEmployeePayload 定义了更新员工请求的 JSON 结构体
 // 所有字段都是可选的，因此使用指针类型
 // 这个结构体用于API层的数据绑定和校验，并传递给服务层。
 type UpdateEmployeePayload struct {
-	Department       *string `json:"department,omitempty" binding:"omitempty,max=255"`
-	EmploymentStatus *string `json:"employmentStatus,omitempty" binding:"omitempty,oneof=Active Departed"` // 校验允许的值
-	HireDate         *string `json:"hireDate,omitempty" binding:"omitempty,datetime=2006-01-02"`           // 入职日期，格式 YYYY-MM-DD
-	TerminationDate  *string `json:"terminationDate,omitempty" binding:"omitempty,datetime=2006-01-02"`    // 日期格式 YYYY-MM-DD
+	FullName            *string `json:"fullName,omitempty" binding:"omitempty,max=255"`
+	PhoneNumber         *string `json:"phoneNumber,omitempty" binding:"omitempty,max=11"` // 11位手机号码，空字符串表示清除
+	Email               *string `json:"email,omitempty" binding:"omitempty,max=255"`      // 空字符串表示清除
+	Department          *string `json:"department,omitempty" binding:"omitempty,max=255"`
+	EmploymentStatus    *string `json:"employmentStatus,omitempty" binding:"omitempty,oneof=Active Departed"` // 校验允许的值
+	HireDate            *string `json:"hireDate,omitempty" binding:"omitempty,datetime=2006-01-02"`           // 入职日期，格式 YYYY-MM-DD
+	TerminationDate     *string `json:"terminationDate,omitempty" binding:"omitempty,datetime=2006-01-02"`    // 日期格式 YYYY-MM-DD
+	NotificationChannel *string `json:"notificationChannel,omitempty" binding:"omitempty,oneof=email im sms"` // 接收通知的渠道
+	Locale              *string `json:"locale,omitempty" binding:"omitempty,oneof=zh-CN en"`                  // 接收通知的语言
+	// 办理离职时员工使用中号码的处置方案，每个使用中的号码都必须提供处置方式
+	NumberDispositions []NumberDisposition `json:"numberDispositions,omitempty" binding:"omitempty,dive"`
 }
@@ -0,0 +1,82 @@
+package models
+
+import "time"
+
+// DepartureNumberAction 定义了员工离职时对其使用中号码的处置方式
+type DepartureNumberAction string
+
+const (
+	DepartureReclaim              DepartureNumberAction = "reclaim"               // 回收为闲置
+	DepartureTransfer             DepartureNumberAction = "transfer"              // 转移给其他在职员工
+	DepartureScheduleDeactivation DepartureNumberAction = "schedule_deactivation" // 回收并计划注销
+)
+
+// NumberDisposition 离职号码处置方案中单个号码的处置方式
+type NumberDisposition struct {
+	PhoneNumber               string  `json:"phoneNumber" binding:"required"`
+	Action                    string  `json:"action" binding:"required,oneof=reclaim transfer schedule_deactivation"`
+	TransferToEmployeeID      *string `json:"transferToEmployeeId,omitempty"`                                              // transfer: 接收号码的员工业务工号
+	ScheduledDeactivationDate *string `json:"scheduledDeactivationDate,omitempty" binding:"omitempty,datetime=2006-01-02"` // schedule_deactivation: 计划注销日期，默认按宽限期计算
+	Remarks                   string  `json:"remarks,omitempty" binding:"omitempty,max=500"`                               // transfer: 转移原因
+}
+
+// DeparturePreviewPayload 定义了离职预览请求的 JSON 结构体，字段含义与更新员工时相同
+type DeparturePreviewPayload struct {
+	TerminationDate    *string             `json:"terminationDate,omitempty" binding:"omitempty,datetime=2006-01-02"` // 离职日期，默认当天
+	NumberDispositions []NumberDisposition `json:"numberDispositions,omitempty" binding:"omitempty,dive"`
+}
+
+// DepartureNumberOutcome 离职时单个号码的处置结果
+type DepartureNumberOutcome struct {
+	PhoneNumber          string                `json:"phoneNumber"`
+	Action               DepartureNumberAction `json:"action,omitempty"`               // 方案中的处置方式，未提供时为空
+	TransferToEmployeeID string                `json:"transferToEmployeeId,omitempty"` // 接收号码的员工业务工号
+	ResultStatus         string                `json:"resultStatus,omitempty"`         // 处置后的号码状态
+	Error                string                `json:"error,omitempty"`                // 无法按方案处置的原因
+}
+
+// DeparturePreviewResponse 离职预览响应结构，展示按方案办理离职时各号码的处置结果
+type DeparturePreviewResponse struct {
+	EmployeeID         string                   `json:"employeeId"`
+	FullName           string                   `json:"fullName"`
+	TerminationDate    time.Time                `json:"terminationDate"`    // 离职日期，也是号码回收、转移的生效日期
+	CanDepart          bool                     `json:"canDepart"`          // 按当前方案能否办理离职
+	Numbers            []DepartureNumberOutcome `json:"numbers"`            // 员工使用中的号码及方案中的其他号码
+	RiskPendingNumbers []string                 `json:"riskPendingNumbers"` // 因办卡人离职将变为风险待核实的号码
+}
+
+// ScheduledDepartureStatus 定义了预约离职的状态
+type ScheduledDepartureStatus string
+
+const (
+	ScheduledDeparturePending   ScheduledDepartureStatus = "pending"   // 等待离职日期到达
+	ScheduledDepartureCompleted ScheduledDepartureStatus = "completed" // 已在离职日期办理离职
+	ScheduledDepartureCancelled ScheduledDepartureStatus = "cancelled" // 已取消或被新的预约替代
+)
+
+// ScheduledDeparture 预约离职记录。离职日期在未来时，更新员工为离职只登记离职日期和号码处置方案，
+// 由定时任务在离职日期到达时办理离职，并提前提醒 IT 处理该员工的号码
+type ScheduledDeparture struct {
+	ID                 uint                     `json:"id" gorm:"primaryKey"`
+	EmployeeID         string                   `json:"employeeId" gorm:"column:employee_id;not null;index;size:10"`       // 员工业务工号
+	TerminationDate    time.Time                `json:"terminationDate" gorm:"column:termination_date;type:date;not null"` // 离职日期
+	NumberDispositions *string                  `json:"-" gorm:"column:number_dispositions;type:text"`                     // 号码处置方案 (JSON)
+	Status             ScheduledDepartureStatus `json:"status" gorm:"column:status;type:varchar(20);not null;index"`
+	ReminderSentAt     *time.Time               `json:"reminderSentAt,omitempty" gorm:"column:reminder_sent_at"` // 提醒 IT 的时间
+	ExecutedAt         *time.Time               `json:"executedAt,omitempty" gorm:"column:executed_at"`          // 定时任务办理离职（或取消）的时间
+	LastError          *string                  `json:"lastError,omitempty" gorm:"column:last_error;type:text"`  // 最近一次办理失败的原因，失败后每次检查时重试
+	CreatedAt          time.Time                `json:"createdAt" gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt          time.Time                `json:"updatedAt" gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// TableName 设置表名
+func (ScheduledDeparture) TableName() string {
+	return "scheduled_departures"
+}
+
+// ScheduledDepartureResponse 预约离职列表中的一条记录
+type ScheduledDepartureResponse struct {
+	ScheduledDeparture
+	FullName           string              `json:"fullName"`
+	NumberDispositions []NumberDisposition `json:"numberDispositions"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// EmploymentPeriod 员工已结束的一段任职期间。员工复职时把复职前的入职、离职日期记入任职历史，
+// 员工记录本身只保留当前任职期间的入职日期
+type EmploymentPeriod struct {
+	ID              uint       `json:"id" gorm:"primaryKey"`
+	EmployeeID      string     `json:"employeeId" gorm:"column:employee_id;not null;index;size:10"` // 员工业务工号
+	HireDate        *time.Time `json:"hireDate,omitempty" gorm:"column:hire_date;type:date"`        // 该期间的入职日期，未登记时为空
+	TerminationDate *time.Time `json:"terminationDate,omitempty" gorm:"column:termination_date;type:date"`
+	CreatedAt       time.Time  `json:"createdAt" gorm:"column:created_at;autoCreateTime"`
+}
+
+// TableName 设置表名
+func (EmploymentPeriod) TableName() string {
+	return "employment_periods"
+}
+
+// RehirePayload 定义了员工复职请求的 JSON 结构体
+type RehirePayload struct {
+	RehireDate *string `json:"rehireDate,omitempty" binding:"omitempty,datetime=2006-01-02"` // 复职日期，默认当天，作为新的入职日期
+	// 需要恢复的风险号码，必须是该员工作为办卡人的风险待核实号码
+	RestorePhoneNumbers []string `json:"restorePhoneNumbers,omitempty"`
+	RestoreAllNumbers   bool     `json:"restoreAllNumbers,omitempty"` // 恢复该员工作为办卡人的全部风险待核实号码
+}
+
+// RehireNumberOption 员工复职时可恢复的一个风险号码
+type RehireNumberOption struct {
+	PhoneNumber       string       `json:"phoneNumber"`
+	CurrentEmployeeID *string      `json:"currentEmployeeId,omitempty"` // 当前使用人员工业务工号
+	RestoreStatus     NumberStatus `json:"restoreStatus"`               // 恢复后的状态，即号码进入风险待核实前的状态
+	Restored          bool         `json:"restored"`                    // 是否已在本次复职中恢复
+}
+
+// RehireResponse 员工复职的响应结构。预览时返回员工当前信息及所有可恢复的号码；
+// 办理复职后返回复职后的员工信息及各号码是否已恢复，未恢复的号码仍为风险待核实，可通过风险号码处理
+type RehireResponse struct {
+	Employee Employee             `json:"employee"`
+	Numbers  []RehireNumberOption `json:"numbers"`
+}
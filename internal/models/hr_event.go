@@ -0,0 +1,94 @@
+package models
+
+import "time"
+
+// HREventType 定义了 HR 系统推送的员工事件类型
+type HREventType string
+
+const (
+	HREventHired             HREventType = "employee.hired"              // 入职，新建员工
+	HREventDepartmentChanged HREventType = "employee.department_changed" // 调岗，变更员工部门
+	HREventTerminated        HREventType = "employee.terminated"         // 离职，离职日期在未来时登记为预约离职
+)
+
+// HREventPayload 定义了 HR 系统推送的事件 JSON 结构
+type HREventPayload struct {
+	ID         string      `json:"id"` // 事件ID，最长100个字符，同一事件重复推送时只处理一次
+	Type       HREventType `json:"type"`
+	OccurredAt *time.Time  `json:"occurredAt,omitempty"` // 事件在 HR 系统中发生的时间
+	Data       HREventData `json:"data"`
+}
+
+// HREventData 事件中的员工信息。入职事件需要姓名；调岗和离职事件按 employeeId 或 email 确定员工
+type HREventData struct {
+	EmployeeID      string `json:"employeeId,omitempty"`      // 员工业务工号
+	FullName        string `json:"fullName,omitempty"`        // 姓名，入职事件必填
+	Email           string `json:"email,omitempty"`           // 邮箱，未提供 employeeId 时用于确定员工
+	PhoneNumber     string `json:"phoneNumber,omitempty"`     // 手机号码，仅入职事件使用
+	Department      string `json:"department,omitempty"`      // 部门名称或别名，调岗事件必填
+	HireDate        string `json:"hireDate,omitempty"`        // 入职日期，格式 YYYY-MM-DD
+	TerminationDate string `json:"terminationDate,omitempty"` // 离职日期，格式 YYYY-MM-DD，默认当天
+}
+
+// HREventClaimStatus 定义了 HR 事件处理记录的状态
+type HREventClaimStatus string
+
+const (
+	HREventClaimProcessing HREventClaimStatus = "processing" // 已认领，正在处理
+	HREventClaimProcessed  HREventClaimStatus = "processed"  // 处理成功
+)
+
+// HREvent HR 事件的处理记录。处理前先以唯一的事件ID插入 processing 记录认领事件，
+// 多个实例或并发推送同一事件时只有一个请求能认领成功；处理成功后改为 processed，失败时删除记录并转入死信
+type HREvent struct {
+	ID          uint               `json:"id" gorm:"primaryKey"`
+	EventID     string             `json:"eventId" gorm:"column:event_id;size:100;not null;uniqueIndex"`
+	EventType   HREventType        `json:"eventType" gorm:"column:event_type;type:varchar(50);not null"`
+	Status      HREventClaimStatus `json:"status" gorm:"column:status;type:varchar(20);not null;default:'processed'"`
+	EmployeeID  string             `json:"employeeId" gorm:"column:employee_id;size:10;index"` // 事件对应的员工业务工号
+	ProcessedAt time.Time          `json:"processedAt" gorm:"column:processed_at;not null"`    // 处理成功的时间，处理中时为认领的时间
+}
+
+// TableName 设置表名
+func (HREvent) TableName() string {
+	return "hr_events"
+}
+
+// HREventDeadLetter 处理失败的 HR 事件，保存原始事件以便排除问题（如补建部门）后重放。
+// 同一事件再次推送或重放失败时更新失败原因和次数，处理成功后记录解决时间
+type HREventDeadLetter struct {
+	ID         uint        `json:"id" gorm:"primaryKey"`
+	EventID    string      `json:"eventId" gorm:"column:event_id;size:100;not null;uniqueIndex"`
+	EventType  HREventType `json:"eventType" gorm:"column:event_type;type:varchar(50);not null"`
+	Payload    string      `json:"payload" gorm:"column:payload;type:text;not null"`      // 原始事件 JSON
+	LastError  string      `json:"lastError" gorm:"column:last_error;type:text;not null"` // 最近一次处理失败的原因
+	Attempts   int         `json:"attempts" gorm:"column:attempts;not null;default:1"`    // 处理失败的次数
+	ReplayedAt *time.Time  `json:"replayedAt,omitempty" gorm:"column:replayed_at"`        // 最近一次手动重放的时间
+	ResolvedAt *time.Time  `json:"resolvedAt,omitempty" gorm:"column:resolved_at;index"`  // 处理成功的时间，为空表示尚未解决
+	CreatedAt  time.Time   `json:"createdAt" gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt  time.Time   `json:"updatedAt" gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// TableName 设置表名
+func (HREventDeadLetter) TableName() string {
+	return "hr_event_dead_letters"
+}
+
+// HREventStatus 定义了 HR 事件的处理结果
+type HREventStatus string
+
+const (
+	HREventProcessed  HREventStatus = "processed"  // 处理成功
+	HREventDuplicate  HREventStatus = "duplicate"  // 事件此前已处理成功，本次未做任何变更
+	HREventInProgress HREventStatus = "processing" // 事件正在由其他请求处理，本次未做任何变更
+	HREventFailed     HREventStatus = "failed"     // 处理失败，已转入死信
+)
+
+// HREventResult HR 事件的处理结果
+type HREventResult struct {
+	EventID      string        `json:"eventId"`
+	Status       HREventStatus `json:"status"`
+	EmployeeID   string        `json:"employeeId,omitempty"`   // 事件对应的员工业务工号
+	Error        string        `json:"error,omitempty"`        // 处理失败的原因
+	DeadLetterID *uint         `json:"deadLetterId,omitempty"` // 处理失败时对应的死信ID，可用于重放
+}
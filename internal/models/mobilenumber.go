@@ -70,20 +70,23 @@ func IsValidRiskHandleAction(action string) bool {
 
 // MobileNumber 对应于数据库中的 mobile_numbers 表
 type MobileNumber struct {
-	ID                   uint           `json:"id" gorm:"primaryKey"`
-	PhoneNumber          string         `json:"phoneNumber" gorm:"unique;not null;size:11" binding:"required,len=11,numeric"`
-	ApplicantEmployeeID  string         `json:"applicantEmployeeId" gorm:"column:applicant_employee_id;not null" binding:"required"` // 办卡人员工业务工号
-	ApplicationDate      time.Time      `json:"applicationDate" gorm:"not null" binding:"required,time_format=2006-01-02"`
-	CurrentEmployeeID    *string        `json:"currentEmployeeId,omitempty" gorm:"column:current_employee_id"` // 当前使用人员工业务工号
-	Status               string         `json:"status" gorm:"not null"`                                        // 使用英文常量存储
-	Purpose              *string        `json:"purpose,omitempty" gorm:"type:varchar(255);null"`               // 号码用途，例如"办公"、"客户联系"等
-	Vendor               string         `json:"vendor" binding:"max=100"`
-	Remarks              string         `json:"remarks" binding:"max=255"`
-	CancellationDate     *time.Time     `json:"cancellationDate" binding:"omitempty,time_format=2006-01-02"`
-	LastConfirmationDate *time.Time     `json:"lastConfirmationDate" gorm:"column:last_confirmation_date"` // 最后确认日期
-	CreatedAt            time.Time      `json:"createdAt" gorm:"autoCreateTime"`
-	UpdatedAt            time.Time      `json:"updatedAt" gorm:"autoUpdateTime"`
-	DeletedAt            gorm.DeletedAt `json:"deletedAt,omitempty" gorm:"index" swaggertype:"string" format:"date-time"`
+	ID                  uint       `json:"id" gorm:"primaryKey"`
+	PhoneNumber         string     `json:"phoneNumber" gorm:"unique;not null;size:11" binding:"required,len=11,numeric"`
+	ApplicantEmployeeID string     `json:"applicantEmployeeId" gorm:"column:applicant_employee_id;not null" binding:"required"` // 办卡人员工业务工号
+	ApplicationDate     time.Time  `json:"applicationDate" gorm:"not null" binding:"required,time_format=2006-01-02"`
+	CurrentEmployeeID   *string    `json:"currentEmployeeId,omitempty" gorm:"column:current_employee_id"` // 当前使用人员工业务工号
+	Status              string     `json:"status" gorm:"not null"`                                        // 使用英文常量存储
+	Purpose             *string    `json:"purpose,omitempty" gorm:"type:varchar(255);null"`               // 号码用途，例如"办公"、"客户联系"等
+	Vendor              string     `json:"vendor" binding:"max=100"`
+	Remarks             string     `json:"remarks" binding:"max=255"`
+	CancellationDate    *time.Time `json:"cancellationDate" binding:"omitempty,time_format=2006-01-02"`
+	// ScheduledDeactivationDate 计划注销日期，仅待注销号码有值，到期后由定时任务自动注销
+	ScheduledDeactivationDate *time.Time     `json:"scheduledDeactivationDate,omitempty" gorm:"column:scheduled_deactivation_date;index"`
+	LastConfirmationDate      *time.Time     `json:"lastConfirmationDate" gorm:"column:last_confirmation_date"`           // 最后确认日期
+	StatusBeforeRisk          *string        `json:"statusBeforeRisk,omitempty" gorm:"column:status_before_risk;size:30"` // 进入风险待核实前的状态，办卡人复职时据此恢复号码
+	CreatedAt                 time.Time      `json:"createdAt" gorm:"autoCreateTime"`
+	UpdatedAt                 time.Time      `json:"updatedAt" gorm:"autoUpdateTime"`
+	DeletedAt                 gorm.DeletedAt `json:"deletedAt,omitempty" gorm:"index" swaggertype:"string" format:"date-time"`
 }
 
 // TableName 指定 MobileNumber 结构体对应的数据库表名
@@ -93,22 +96,23 @@ func (MobileNumber) TableName() string {
 
 // MobileNumberResponse 是用于 API 响应的手机号码数据结构，包含关联信息
 type MobileNumberResponse struct {
-	ID                  uint                 `json:"id"`
-	PhoneNumber         string               `json:"phoneNumber"`
-	ApplicantEmployeeID string               `json:"applicantEmployeeId"`       // 办卡人员工业务工号
-	ApplicantName       string               `json:"applicantName,omitempty"`   // 办卡人姓名
-	ApplicantStatus     string               `json:"applicantStatus,omitempty"` // 办卡人当前在职状态
-	ApplicationDate     time.Time            `json:"applicationDate"`
-	CurrentEmployeeID   *string              `json:"currentEmployeeId,omitempty"` // 当前使用人员工业务工号
-	CurrentUserName     string               `json:"currentUserName,omitempty"`   // 当前使用人姓名
-	Status              string               `json:"status"`                      // 英文状态值
-	Purpose             *string              `json:"purpose,omitempty"`           // 号码用途
-	Vendor              string               `json:"vendor,omitempty"`
-	Remarks             string               `json:"remarks,omitempty"`
-	CancellationDate    *time.Time           `json:"cancellationDate,omitempty"`
-	CreatedAt           time.Time            `json:"createdAt"`
-	UpdatedAt           time.Time            `json:"updatedAt"`
-	UsageHistory        []NumberUsageHistory `json:"usageHistory,omitempty" gorm:"foreignKey:MobileNumberDbID"` // 号码使用历史
+	ID                        uint                 `json:"id"`
+	PhoneNumber               string               `json:"phoneNumber"`
+	ApplicantEmployeeID       string               `json:"applicantEmployeeId"`       // 办卡人员工业务工号
+	ApplicantName             string               `json:"applicantName,omitempty"`   // 办卡人姓名
+	ApplicantStatus           string               `json:"applicantStatus,omitempty"` // 办卡人当前在职状态
+	ApplicationDate           time.Time            `json:"applicationDate"`
+	CurrentEmployeeID         *string              `json:"currentEmployeeId,omitempty"` // 当前使用人员工业务工号
+	CurrentUserName           string               `json:"currentUserName,omitempty"`   // 当前使用人姓名
+	Status                    string               `json:"status"`                      // 英文状态值
+	Purpose                   *string              `json:"purpose,omitempty"`           // 号码用途
+	Vendor                    string               `json:"vendor,omitempty"`
+	Remarks                   string               `json:"remarks,omitempty"`
+	CancellationDate          *time.Time           `json:"cancellationDate,omitempty"`
+	ScheduledDeactivationDate *time.Time           `json:"scheduledDeactivationDate,omitempty"` // 计划注销日期
+	CreatedAt                 time.Time            `json:"createdAt"`
+	UpdatedAt                 time.Time            `json:"updatedAt"`
+	UsageHistory              []NumberUsageHistory `json:"usageHistory,omitempty" gorm:"foreignKey:MobileNumberDbID"` // 号码使用历史
 }
 
 // MobileNumberUpdatePayload 定义了更新手机号码信息的请求体结构
@@ -117,6 +121,8 @@ type MobileNumberUpdatePayload struct {
 	Purpose *string `json:"purpose,omitempty" binding:"omitempty,max=255"`
 	Vendor  *string `json:"vendor,omitempty" binding:"omitempty,max=100"`
 	Remarks *string `json:"remarks,omitempty" binding:"omitempty,max=255"`
+	// ScheduledDeactivationDate 计划注销日期 (YYYY-MM-DD)，仅适用于待注销号码；变更为待注销时不提供则按宽限期自动计算
+	ScheduledDeactivationDate *string `json:"scheduledDeactivationDate,omitempty" binding:"omitempty,datetime=2006-01-02"`
 }
 
 // MobileNumberAssignPayload 定义了分配号码的请求体
@@ -126,6 +132,13 @@ type MobileNumberAssignPayload struct {
 	Purpose        string `json:"purpose" binding:"required,max=255"` // 号码用途，必填
 }
 
+// MobileNumberTransferPayload 定义了将号码转移给另一名员工的请求体
+type MobileNumberTransferPayload struct {
+	EmployeeID    string `json:"employeeId" binding:"required"` // 新使用人员工业务工号
+	EffectiveDate string `json:"effectiveDate" binding:"required,datetime=2006-01-02"`
+	Reason        string `json:"reason" binding:"required,max=500"` // 转移原因，记录在新使用人的使用历史中
+}
+
 // MobileNumberUnassignPayload 定义了回收号码的请求体
 type MobileNumberUnassignPayload struct {
 	ReclaimDate string `json:"reclaimDate,omitempty" binding:"omitempty,datetime=2006-01-02"`
@@ -151,3 +164,72 @@ type RiskNumberResponse struct {
 	ApplicantDepartureDate *time.Time `json:"applicantDepartureDate,omitempty"` // 办卡人离职日期
 	DaysSinceDeparture     *int       `json:"daysSinceDeparture,omitempty"`     // 离职天数
 }
+
+// MobileNumberBulkAction 定义了号码批量操作的类型
+type MobileNumberBulkAction string
+
+const (
+	BulkActionStatusChange    MobileNumberBulkAction = "status_change"    // 变更状态
+	BulkActionUnassign        MobileNumberBulkAction = "unassign"         // 回收
+	BulkActionChangeApplicant MobileNumberBulkAction = "change_applicant" // 变更办卡人
+	BulkActionDeactivate      MobileNumberBulkAction = "deactivate"       // 注销
+	BulkActionSetVendor       MobileNumberBulkAction = "set_vendor"       // 设置运营商
+)
+
+// GetAllBulkActions 返回所有可用的批量操作类型
+func GetAllBulkActions() []MobileNumberBulkAction {
+	return []MobileNumberBulkAction{
+		BulkActionStatusChange,
+		BulkActionUnassign,
+		BulkActionChangeApplicant,
+		BulkActionDeactivate,
+		BulkActionSetVendor,
+	}
+}
+
+// IsValidBulkAction 检查批量操作类型是否有效
+func IsValidBulkAction(action string) bool {
+	for _, validAction := range GetAllBulkActions() {
+		if string(validAction) == action {
+			return true
+		}
+	}
+	return false
+}
+
+// MobileNumberBulkFilter 批量操作的号码筛选条件，与号码列表接口的筛选参数含义相同，但包括风险号码
+type MobileNumberBulkFilter struct {
+	Search          string `json:"search,omitempty"`          // 匹配手机号、使用人、办卡人
+	Status          string `json:"status,omitempty"`          // 号码状态
+	ApplicantStatus string `json:"applicantStatus,omitempty"` // 办卡人在职状态 ('Active'或'Departed')
+}
+
+// MobileNumberBulkPayload 定义了号码批量操作的请求体，phoneNumbers 与 filter 二选一，其余参数按操作类型使用
+type MobileNumberBulkPayload struct {
+	PhoneNumbers              []string                `json:"phoneNumbers,omitempty"`
+	Filter                    *MobileNumberBulkFilter `json:"filter,omitempty"`
+	Action                    string                  `json:"action" binding:"required"`                                                   // status_change, unassign, change_applicant, deactivate, set_vendor
+	Status                    *string                 `json:"status,omitempty"`                                                            // status_change: 目标状态
+	ScheduledDeactivationDate *string                 `json:"scheduledDeactivationDate,omitempty" binding:"omitempty,datetime=2006-01-02"` // status_change: 变更为待注销时的计划注销日期
+	ReclaimDate               string                  `json:"reclaimDate,omitempty" binding:"omitempty,datetime=2006-01-02"`               // unassign: 回收日期，默认当天
+	NewApplicantEmployeeID    *string                 `json:"newApplicantEmployeeId,omitempty"`                                            // change_applicant: 新办卡人员工业务工号
+	Vendor                    *string                 `json:"vendor,omitempty" binding:"omitempty,max=100"`                                // set_vendor: 运营商
+	Remarks                   string                  `json:"remarks" binding:"omitempty,max=500"`                                         // change_applicant/deactivate: 备注
+	DryRun                    bool                    `json:"dryRun"`                                                                      // 仅预览，执行后回滚
+	Atomic                    bool                    `json:"atomic"`                                                                      // 任一号码失败时整批回滚
+}
+
+// MobileNumberBulkFailure 批量操作中单个号码的失败信息
+type MobileNumberBulkFailure struct {
+	Index       int    // 号码在本次操作中的序号，从1开始
+	PhoneNumber string // 手机号码
+	Reason      string // 失败原因
+}
+
+// MobileNumberBulkResult 号码批量操作的执行结果
+type MobileNumberBulkResult struct {
+	TotalCount   int                       // 本次操作的号码总数
+	SuccessCount int                       // 执行成功（预览时为可执行）的号码数
+	Failures     []MobileNumberBulkFailure // 失败的号码
+	RolledBack   bool                      // 是否因预览或整批原子执行失败而回滚
+}
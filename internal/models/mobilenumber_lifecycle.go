@@ -0,0 +1,80 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInUseRequiresCurrentUser 表示号码没有当前使用人却要设为使用中
+var ErrInUseRequiresCurrentUser = errors.New("号码没有当前使用人，不能设为使用中，请使用分配操作")
+
+// ErrDeactivationDateRequired 表示号码变更为待注销时没有计划注销日期
+var ErrDeactivationDateRequired = errors.New("待注销号码必须设置计划注销日期")
+
+// numberStatusTransitions 是号码生命周期的状态流转表，记录每个状态允许进入的下一状态。
+// 已注销是终止状态。各状态流转的副作用（关闭使用历史、记录注销日期等）由仓库层统一处理。
+var numberStatusTransitions = map[NumberStatus][]NumberStatus{
+	StatusIdle:                {StatusInUse, StatusPendingDeactivation, StatusDeactivated, StatusRiskPending},
+	StatusInUse:               {StatusIdle, StatusUserReport, StatusRiskPending},
+	StatusPendingDeactivation: {StatusIdle, StatusDeactivated, StatusRiskPending},
+	StatusRiskPending:         {StatusIdle, StatusInUse, StatusPendingDeactivation, StatusDeactivated},
+	StatusUserReport:          {StatusIdle, StatusInUse, StatusDeactivated},
+	StatusDeactivated:         {},
+}
+
+// RiskRestoreStatus 返回风险号码在办卡人复职后应恢复的状态，即进入风险待核实前的状态。
+// 没有记录原状态时（早于该字段的数据），有当前使用人则恢复为使用中，否则为闲置
+func RiskRestoreStatus(number *MobileNumber) NumberStatus {
+	if number.StatusBeforeRisk != nil && CanTransition(StatusRiskPending, NumberStatus(*number.StatusBeforeRisk)) {
+		return NumberStatus(*number.StatusBeforeRisk)
+	}
+	if number.CurrentEmployeeID != nil && *number.CurrentEmployeeID != "" {
+		return StatusInUse
+	}
+	return StatusIdle
+}
+
+// InvalidStatusTransitionError 表示号码状态流转不被允许
+type InvalidStatusTransitionError struct {
+	From    NumberStatus   `json:"from"`
+	To      NumberStatus   `json:"to"`
+	Allowed []NumberStatus `json:"allowedNextStatuses"` // 当前状态允许进入的下一状态
+}
+
+func (e *InvalidStatusTransitionError) Error() string {
+	if len(e.Allowed) == 0 {
+		return fmt.Sprintf("号码状态不能从 %s 变更为 %s：%s 是终止状态", e.From, e.To, e.From)
+	}
+	allowed := make([]string, len(e.Allowed))
+	for i, s := range e.Allowed {
+		allowed[i] = string(s)
+	}
+	return fmt.Sprintf("号码状态不能从 %s 变更为 %s，允许的下一状态: %s", e.From, e.To, strings.Join(allowed, ", "))
+}
+
+// AllowedNextStatuses 返回指定状态允许进入的下一状态
+func AllowedNextStatuses(from NumberStatus) []NumberStatus {
+	next := numberStatusTransitions[from]
+	allowed := make([]NumberStatus, len(next))
+	copy(allowed, next)
+	return allowed
+}
+
+// CanTransition 检查号码状态能否从 from 变更为 to，状态不变不算流转
+func CanTransition(from, to NumberStatus) bool {
+	for _, s := range numberStatusTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckStatusTransition 校验状态流转，不允许时返回 *InvalidStatusTransitionError
+func CheckStatusTransition(from, to NumberStatus) error {
+	if CanTransition(from, to) {
+		return nil
+	}
+	return &InvalidStatusTransitionError{From: from, To: to, Allowed: AllowedNextStatuses(from)}
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckStatusTransition(t *testing.T) {
+	cases := []struct {
+		from, to NumberStatus
+		ok       bool
+	}{
+		{StatusIdle, StatusInUse, true},
+		{StatusInUse, StatusIdle, true},
+		{StatusInUse, StatusDeactivated, false},
+		{StatusRiskPending, StatusDeactivated, true},
+		{StatusRiskPending, StatusPendingDeactivation, true},
+		{StatusUserReport, StatusRiskPending, false},
+		{StatusDeactivated, StatusIdle, false},
+		{StatusIdle, StatusIdle, false},
+	}
+	for _, tc := range cases {
+		err := CheckStatusTransition(tc.from, tc.to)
+		if (err == nil) != tc.ok {
+			t.Errorf("%s -> %s: got err=%v, want ok=%v", tc.from, tc.to, err, tc.ok)
+		}
+	}
+}
+
+func TestInvalidStatusTransitionErrorListsAllowed(t *testing.T) {
+	err := CheckStatusTransition(StatusInUse, StatusDeactivated)
+	var transitionErr *InvalidStatusTransitionError
+	if !errors.As(err, &transitionErr) {
+		t.Fatalf("expected *InvalidStatusTransitionError, got %T", err)
+	}
+	want := AllowedNextStatuses(StatusInUse)
+	if len(transitionErr.Allowed) != len(want) {
+		t.Fatalf("allowed = %v, want %v", transitionErr.Allowed, want)
+	}
+	for i := range want {
+		if transitionErr.Allowed[i] != want[i] {
+			t.Fatalf("allowed = %v, want %v", transitionErr.Allowed, want)
+		}
+	}
+
+	if len(AllowedNextStatuses(StatusDeactivated)) != 0 {
+		t.Errorf("deactivated should be terminal")
+	}
+}
+
+func TestRiskRestoreStatus(t *testing.T) {
+	pending := string(StatusPendingDeactivation)
+	reported := string(StatusUserReport)
+	user := "EMP0000001"
+	cases := []struct {
+		name   string
+		number MobileNumber
+		want   NumberStatus
+	}{
+		{"recorded status", MobileNumber{StatusBeforeRisk: &pending}, StatusPendingDeactivation},
+		{"no record with current user", MobileNumber{CurrentEmployeeID: &user}, StatusInUse},
+		{"no record without current user", MobileNumber{}, StatusIdle},
+		{"recorded status not reachable", MobileNumber{StatusBeforeRisk: &reported}, StatusIdle},
+	}
+	for _, tc := range cases {
+		if got := RiskRestoreStatus(&tc.number); got != tc.want {
+			t.Errorf("%s: got %s, want %s", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// TimelineEventType 定义了号码时间线事件的类型
+type TimelineEventType string
+
+const (
+	TimelineUsageStarted           TimelineEventType = "usage_started"           // 开始使用
+	TimelineUsageEnded             TimelineEventType = "usage_ended"             // 结束使用
+	TimelineApplicantChanged       TimelineEventType = "applicant_changed"       // 办卡人变更
+	TimelineStatusChanged          TimelineEventType = "status_changed"          // 状态变更
+	TimelineVerificationSubmission TimelineEventType = "verification_submission" // 员工提交号码确认
+)
+
+// NumberTimelineEvent 号码时间线中的一条事件，按事件类型只填充相关字段
+type NumberTimelineEvent struct {
+	Type                TimelineEventType      `json:"type"`
+	OccurredAt          time.Time              `json:"occurredAt"`                    // 发生时间
+	PhoneNumber         string                 `json:"phoneNumber"`                   // 手机号码
+	EmployeeID          string                 `json:"employeeId,omitempty"`          // 使用人或提交确认的员工工号
+	PreviousApplicantID string                 `json:"previousApplicantId,omitempty"` // 原办卡人员工工号
+	NewApplicantID      string                 `json:"newApplicantId,omitempty"`      // 新办卡人员工工号
+	FromStatus          string                 `json:"fromStatus,omitempty"`          // 变更前状态，号码创建时为空
+	ToStatus            string                 `json:"toStatus,omitempty"`            // 变更后状态
+	Action              string                 `json:"action,omitempty"`              // 引起状态变更的操作，如 mobile_number.assign
+	VerificationAction  VerificationActionType `json:"verificationAction,omitempty"`  // 确认操作类型
+	Operator            string                 `json:"operator,omitempty"`            // 操作者用户名
+	Remarks             string                 `json:"remarks,omitempty"`             // 备注、用途或员工留言
+}
+
+// NumberTimelineResponse 号码时间线响应结构，事件按发生时间升序
+type NumberTimelineResponse struct {
+	PhoneNumber string                `json:"phoneNumber"`
+	Events      []NumberTimelineEvent `json:"events"`
+}
+
+// EmployeeNumberHistoryResponse 员工号码历史响应结构，包含其使用过的号码及作为办卡人的变更记录，按发生时间升序
+type EmployeeNumberHistoryResponse struct {
+	EmployeeID string                `json:"employeeId"`
+	FullName   string                `json:"fullName"`
+	Events     []NumberTimelineEvent `json:"events"`
+}
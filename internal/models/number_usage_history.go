@@ -13,6 +13,7 @@ type NumberUsageHistory struct {
 	EmployeeID       string         `json:"employeeId" gorm:"column:employee_id;not null"`               // 使用人员工业务工号
 	StartDate        time.Time      `json:"startDate" gorm:"column:start_date;not null"`                 // 使用开始日期时间
 	EndDate          *time.Time     `json:"endDate,omitempty" gorm:"column:end_date"`                    // 使用结束日期时间
+	Remarks          string         `json:"remarks,omitempty" gorm:"column:remarks;size:500"`            // 备注，如号码转移原因
 	CreatedAt        time.Time      `json:"createdAt" gorm:"column:created_at;not null;autoCreateTime"`
 	UpdatedAt        time.Time      `json:"updatedAt" gorm:"column:updated_at;not null;autoUpdateTime"`
 	DeletedAt        gorm.DeletedAt `json:"deletedAt,omitempty" gorm:"index" swaggertype:"string" format:"date-time"`
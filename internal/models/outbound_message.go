@@ -0,0 +1,83 @@
+package models
+
+import "time"
+
+// OutboundMessageStatus 定义了待发送通知的状态
+type OutboundMessageStatus string
+
+const (
+	OutboundMessagePending OutboundMessageStatus = "pending" // 等待发送，NextAttemptAt 到期后由工作协程领取
+	OutboundMessageSending OutboundMessageStatus = "sending" // 工作协程正在发送
+	OutboundMessageSent    OutboundMessageStatus = "sent"    // 发送成功
+	OutboundMessageFailed  OutboundMessageStatus = "failed"  // 达到最多尝试次数或无法发送，可通过重试接口重新排队
+)
+
+// IsValidOutboundMessageStatus 检查状态是否有效
+func IsValidOutboundMessageStatus(status string) bool {
+	switch OutboundMessageStatus(status) {
+	case OutboundMessagePending, OutboundMessageSending, OutboundMessageSent, OutboundMessageFailed:
+		return true
+	}
+	return false
+}
+
+// 通知的类型
+const (
+	OutboundMessageKindVerification         = "verification"          // 号码确认通知
+	OutboundMessageKindVerificationReminder = "verification_reminder" // 号码确认催办通知，包括按催办计划自动催办和手动催办
+	OutboundMessageKindDepartureReminder    = "departure_reminder"    // 提醒 IT 处理即将离职员工号码的邮件，每个 IT 邮箱一条
+)
+
+// OutboundMessage 通知发送队列中的一条消息。消息内容在入队时渲染完成，发送失败时按指数退避重试，
+// 同一确认批次的消息以 BatchID 关联，批次的发送统计由这些消息的状态计算得出
+type OutboundMessage struct {
+	ID                  uint                  `json:"id" gorm:"primaryKey"`
+	Kind                string                `json:"kind" gorm:"column:kind;size:50;not null"`                                // 通知类型，如 verification
+	BatchID             *string               `json:"batchId,omitempty" gorm:"column:batch_id;type:varchar(36);index"`         // 所属的确认批次
+	VerificationTokenID *uint                 `json:"verificationTokenId,omitempty" gorm:"column:verification_token_id;index"` // 号码确认通知对应的确认令牌
+	EmployeeID          string                `json:"employeeId" gorm:"column:employee_id;size:10;not null;index"`             // 收件员工的业务工号，离职提醒为离职员工的业务工号
+	RecipientName       string                `json:"recipientName" gorm:"column:recipient_name;size:255"`
+	RecipientEmail      string                `json:"recipientEmail,omitempty" gorm:"column:recipient_email;size:255"`
+	RecipientPhone      string                `json:"recipientPhone,omitempty" gorm:"column:recipient_phone;size:50"`
+	Channel             string                `json:"channel,omitempty" gorm:"column:channel;size:20"` // 员工偏好的通知渠道，为空时使用邮件
+	Subject             string                `json:"subject" gorm:"column:subject;type:text;not null"`
+	HTMLBody            string                `json:"-" gorm:"column:html_body;type:text;not null"`
+	TextBody            string                `json:"-" gorm:"column:text_body;type:text;not null"`
+	Status              OutboundMessageStatus `json:"status" gorm:"column:status;type:varchar(20);not null;index:idx_outbound_due"`
+	Attempts            int                   `json:"attempts" gorm:"column:attempts;not null;default:0"`        // 已尝试发送的次数
+	MaxAttempts         int                   `json:"maxAttempts" gorm:"column:max_attempts;not null;default:5"` // 最多尝试发送的次数
+	NextAttemptAt       time.Time             `json:"nextAttemptAt" gorm:"column:next_attempt_at;not null;index:idx_outbound_due"`
+	ClaimedAt           *time.Time            `json:"claimedAt,omitempty" gorm:"column:claimed_at"` // 最近一次被工作协程领取或开始发送的时间，用于识别发送中断的消息和丢弃过期的发送结果
+	LastError           string                `json:"lastError,omitempty" gorm:"column:last_error;type:text"`
+	SentChannel         string                `json:"sentChannel,omitempty" gorm:"column:sent_channel;size:20"` // 最近一次发送实际使用的渠道
+	SentAt              *time.Time            `json:"sentAt,omitempty" gorm:"column:sent_at"`
+	CreatedAt           time.Time             `json:"createdAt" gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt           time.Time             `json:"updatedAt" gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// TableName 设置表名
+func (OutboundMessage) TableName() string {
+	return "outbound_messages"
+}
+
+// HasContent 消息是否有可发送的内容；入队时渲染失败的消息没有内容，不能重试
+func (m *OutboundMessage) HasContent() bool {
+	return m.Subject != "" && (m.HTMLBody != "" || m.TextBody != "")
+}
+
+// OutboundMessageFilter 待发送通知的查询条件
+type OutboundMessageFilter struct {
+	Status     string // 状态
+	BatchID    string // 确认批次ID
+	EmployeeID string // 员工业务工号
+}
+
+// OutboundMessageCounts 一个确认批次中各状态的消息数
+type OutboundMessageCounts struct {
+	Total     int // 消息总数，每条消息对应一个生成的确认令牌
+	Pending   int
+	Sending   int
+	Sent      int
+	Failed    int
+	Attempted int // 至少尝试发送过一次的消息数
+}
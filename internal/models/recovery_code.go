@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// RecoveryCode 两步验证的一次性恢复码，只存储哈希
+type RecoveryCode struct {
+	ID        int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID    int64      `json:"userId" gorm:"column:user_id;not null;index"`       // 所属系统用户ID
+	CodeHash  string     `json:"-" gorm:"column:code_hash;not null;size:64"`        // 恢复码的 SHA-256 哈希 (hex)
+	UsedAt    *time.Time `json:"usedAt,omitempty" gorm:"column:used_at"`            // 使用时间，为空表示未使用
+	CreatedAt time.Time  `json:"createdAt" gorm:"column:created_at;autoCreateTime"` // 记录创建时间
+}
+
+// TableName 设置表名
+func (RecoveryCode) TableName() string {
+	return "recovery_codes"
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// RefreshToken 服务端保存的刷新令牌，只存储令牌的 SHA-256 哈希。
+// 每次刷新都会轮换令牌，同一次登录产生的令牌属于同一个 FamilyID。
+type RefreshToken struct {
+	ID        int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID    int64      `json:"userId" gorm:"column:user_id;not null;index"`             // 所属系统用户ID
+	TokenHash string     `json:"-" gorm:"column:token_hash;not null;uniqueIndex;size:64"` // 令牌的 SHA-256 哈希 (hex)
+	FamilyID  string     `json:"familyId" gorm:"column:family_id;not null;index;size:36"` // 令牌族ID，检测到重用时整族吊销
+	ExpiresAt time.Time  `json:"expiresAt" gorm:"column:expires_at;not null;index"`       // 过期时间
+	RevokedAt *time.Time `json:"revokedAt,omitempty" gorm:"column:revoked_at"`            // 吊销（或已被轮换）时间
+	CreatedAt time.Time  `json:"createdAt" gorm:"column:created_at;autoCreateTime"`       // 记录创建时间
+}
+
+// TableName 设置表名
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
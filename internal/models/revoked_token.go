@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// RevokedToken 已登出（吊销）的 JWT 记录，按 JTI 存储
+type RevokedToken struct {
+	JTI       string    `json:"jti" gorm:"column:jti;primaryKey;size:64"`          // JWT ID
+	ExpiresAt time.Time `json:"expiresAt" gorm:"column:expires_at;not null;index"` // Token 原始过期时间，过期后记录可被清理
+	CreatedAt time.Time `json:"createdAt" gorm:"column:created_at;autoCreateTime"` // 记录创建时间
+}
+
+// TableName 设置表名
+func (RevokedToken) TableName() string {
+	return "revoked_tokens"
+}
@@ -6,18 +6,113 @@ import (
 	"gorm.io/gorm"
 )
 
+// 系统用户角色
+const (
+	RoleAdmin             = "admin"              // 管理员，拥有全部权限
+	RoleOperator          = "operator"           // 操作员，可查看数据并办理号码分配/回收
+	RoleViewer            = "viewer"             // 只读用户
+	RoleDepartmentManager = "department_manager" // 部门负责人，只能查看所负责部门（含下级部门）的号码和确认进度，并可催办
+)
+
+// 系统用户状态
+const (
+	UserStatusActive   = "active"   // 启用
+	UserStatusDisabled = "disabled" // 禁用
+)
+
+// GetAllRoles 返回所有可用的系统用户角色
+func GetAllRoles() []string {
+	return []string{RoleAdmin, RoleOperator, RoleViewer, RoleDepartmentManager}
+}
+
+// IsValidRole 检查角色是否有效
+func IsValidRole(role string) bool {
+	for _, validRole := range GetAllRoles() {
+		if validRole == role {
+			return true
+		}
+	}
+	return false
+}
+
 // User 对应于数据库中的 users 表
 type User struct {
-	ID           int64          `json:"id" gorm:"primaryKey;autoIncrement"`
-	Username     string         `json:"username" gorm:"column:username;unique;not null;size:255"`
-	PasswordHash string         `json:"-" gorm:"column:password_hash;not null;size:255"` // 密码哈希不通过JSON暴露
-	Role         string         `json:"role" gorm:"column:role;not null;default:'admin';size:50"`
-	CreatedAt    time.Time      `json:"createdAt" gorm:"column:created_at;not null;autoCreateTime"`
-	UpdatedAt    time.Time      `json:"updatedAt" gorm:"column:updated_at;not null;autoUpdateTime"`
-	DeletedAt    gorm.DeletedAt `json:"deletedAt,omitempty" gorm:"index" swaggertype:"string" format:"date-time"`
+	ID           int64  `json:"id" gorm:"primaryKey;autoIncrement"`
+	Username     string `json:"username" gorm:"column:username;unique;not null;size:255"`
+	PasswordHash string `json:"-" gorm:"column:password_hash;not null;size:255"` // 密码哈希不通过JSON暴露
+	Role         string `json:"role" gorm:"column:role;not null;default:'admin';size:50"`
+	Status       string `json:"status" gorm:"column:status;not null;default:'active';size:50"` // 账号状态 ('active', 'disabled')
+
+	FailedLoginCount  int        `json:"failedLoginCount" gorm:"column:failed_login_count;not null;default:0"` // 连续登录失败次数，登录成功后清零
+	LastFailedLoginAt *time.Time `json:"lastFailedLoginAt,omitempty" gorm:"column:last_failed_login_at"`       // 最近一次登录失败时间
+	LockedUntil       *time.Time `json:"lockedUntil,omitempty" gorm:"column:locked_until"`                     // 锁定截止时间，为空或已过去表示未锁定
+
+	TOTPEnabled      bool   `json:"totpEnabled" gorm:"column:totp_enabled;not null;default:false"` // 是否已启用 TOTP 两步验证
+	TOTPSecret       string `json:"-" gorm:"column:totp_secret;size:64"`                           // TOTP 密钥 (Base32)，启用前为待确认的密钥
+	TOTPLastUsedStep int64  `json:"-" gorm:"column:totp_last_used_step;not null;default:0"`        // 最近一次成功使用的时间步，防止验证码重放
+
+	DepartmentIDs []uint `json:"departmentIds,omitempty" gorm:"-"` // 部门负责人所负责的部门ID，保存在 user_departments 表中
+
+	CreatedAt time.Time      `json:"createdAt" gorm:"column:created_at;not null;autoCreateTime"`
+	UpdatedAt time.Time      `json:"updatedAt" gorm:"column:updated_at;not null;autoUpdateTime"`
+	DeletedAt gorm.DeletedAt `json:"deletedAt,omitempty" gorm:"index" swaggertype:"string" format:"date-time"`
+}
+
+// IsLocked 检查账号在给定时间点是否处于锁定状态
+func (u *User) IsLocked(now time.Time) bool {
+	return u.LockedUntil != nil && now.Before(*u.LockedUntil)
 }
 
 // TableName 指定 User 结构体对应的数据库表名
 func (User) TableName() string {
 	return "users"
 }
+
+// UserDepartment 对应于数据库中的 user_departments 表，记录部门负责人角色的用户所负责的部门
+type UserDepartment struct {
+	UserID       int64     `gorm:"column:user_id;primaryKey"`
+	DepartmentID uint      `gorm:"column:department_id;primaryKey;index"`
+	CreatedAt    time.Time `gorm:"column:created_at;autoCreateTime"`
+}
+
+// TableName 设置表名
+func (UserDepartment) TableName() string {
+	return "user_departments"
+}
+
+// CreateUserPayload 定义了创建系统用户的请求体
+type CreateUserPayload struct {
+	Username      string `json:"username" binding:"required,max=255"`
+	Password      string `json:"password" binding:"required,min=8,max=72"` // bcrypt 最多处理72字节
+	Role          string `json:"role" binding:"required"`
+	DepartmentIDs []uint `json:"departmentIds,omitempty"` // 所负责的部门ID，角色为 department_manager 时必填，其他角色不可提供
+}
+
+// UpdateUserPayload 定义了更新系统用户的请求体，所有字段可选
+type UpdateUserPayload struct {
+	Role          *string `json:"role,omitempty"`
+	Status        *string `json:"status,omitempty" binding:"omitempty,oneof=active disabled"`
+	DepartmentIDs *[]uint `json:"departmentIds,omitempty"` // 替换所负责的部门，仅适用于 department_manager 角色；角色改为其他角色时自动清除
+}
+
+// ResetPasswordPayload 定义了管理员重置用户密码的请求体
+type ResetPasswordPayload struct {
+	NewPassword string `json:"newPassword" binding:"required,min=8,max=72"`
+}
+
+// ChangePasswordPayload 定义了当前用户修改自己密码的请求体
+type ChangePasswordPayload struct {
+	OldPassword string `json:"oldPassword" binding:"required"`
+	NewPassword string `json:"newPassword" binding:"required,min=8,max=72"`
+}
+
+// TOTPCodePayload 定义了提交 TOTP 验证码的请求体
+type TOTPCodePayload struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// DisableTOTPPayload 定义了关闭两步验证的请求体，需要密码和验证码（或恢复码）
+type DisableTOTPPayload struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"` // TOTP 验证码或恢复码
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
@@ -34,6 +35,10 @@ type VerificationToken struct {
 	CreatedAt  time.Time               `json:"createdAt" gorm:"column:created_at;not null;autoCreateTime"`
 	UpdatedAt  time.Time               `json:"updatedAt" gorm:"column:updated_at;not null;autoUpdateTime"`
 	DeletedAt  gorm.DeletedAt          `json:"deletedAt,omitempty" gorm:"index" swaggertype:"string" format:"date-time"`
+
+	LastRemindedAt *time.Time `gorm:"column:last_reminded_at"`                  // 最近一次催办时间
+	ReminderCount  int        `gorm:"column:reminder_count;not null;default:0"` // 已催办次数，包括手动催办和自动催办
+	BatchID        *string    `gorm:"column:batch_id;type:varchar(36);index"`   // 生成令牌的确认批次，按批次的催办计划自动催办
 }
 
 // TableName specifies the table name for the VerificationToken model
@@ -54,7 +59,9 @@ const (
 	BatchTaskStatusFailed              VerificationBatchTaskStatus = "Failed"
 )
 
-// VerificationBatchTask 代表一个号码验证的批处理任务
+// VerificationBatchTask 代表一个号码验证的批处理任务。
+// 批次为 Pending 时正在生成令牌和通知；通知全部进入发送队列后变为 InProgress，队列中没有待发送的通知后变为 Completed 或 CompletedWithErrors。
+// 令牌数和通知发送统计由发送队列中该批次的消息计算得出
 type VerificationBatchTask struct {
 	ID                      string                      `json:"id" gorm:"type:varchar(36);primaryKey"`
 	Status                  VerificationBatchTaskStatus `json:"status" gorm:"type:varchar(50);not null;index"`
@@ -63,10 +70,13 @@ type VerificationBatchTask struct {
 	EmailsAttemptedCount    int                         `json:"emailsAttemptedCount" gorm:"not null;default:0"`
 	EmailsSucceededCount    int                         `json:"emailsSucceededCount" gorm:"not null;default:0"`
 	EmailsFailedCount       int                         `json:"emailsFailedCount" gorm:"not null;default:0"`
+	EmailsPendingCount      int                         `json:"emailsPendingCount" gorm:"not null;default:0"` // 尚在发送队列中等待发送或重试的通知数
 	ErrorSummary            *string                     `json:"errorSummary,omitempty" gorm:"type:text"`
 	RequestedScopeType      VerificationScopeType       `json:"requestedScopeType" gorm:"type:varchar(50)"`
 	RequestedScopeValues    *string                     `json:"requestedScopeValues,omitempty" gorm:"type:text"`
 	RequestedDurationDays   int                         `json:"requestedDurationDays"`
+	IncludeSubDepartments   bool                        `json:"includeSubDepartments" gorm:"not null;default:false"` // 按部门发起时是否包含下级部门
+	ReminderSchedule        *string                     `json:"reminderSchedule,omitempty" gorm:"type:text"`         // 自动催办计划 (ReminderSchedule 的 JSON)，为空时不自动催办
 	CreatedAt               time.Time                   `json:"createdAt" gorm:"autoCreateTime"`
 	UpdatedAt               time.Time                   `json:"updatedAt" gorm:"autoUpdateTime"`
 	DeletedAt               gorm.DeletedAt              `json:"deletedAt,omitempty" swaggertype:"string" format:"date-time" gorm:"index"`
@@ -85,11 +95,62 @@ func (task *VerificationBatchTask) BeforeCreate(tx *gorm.DB) (err error) {
 	return nil
 }
 
+// ReminderSchedule 确认批次的自动催办计划：在发出确认通知后第 AfterSendDays 天、链接过期前 BeforeExpiryDays 天，
+// 向尚未提交确认结果的员工重新发送确认通知，每个令牌的催办次数（包括手动催办）不超过 MaxReminders
+type ReminderSchedule struct {
+	AfterSendDays    []int `json:"afterSendDays,omitempty"`
+	BeforeExpiryDays []int `json:"beforeExpiryDays,omitempty"`
+	MaxReminders     int   `json:"maxReminders"`
+}
+
+// DueTimes 返回令牌在 sentAt 发出、expiresAt 过期时的催办时间，按时间升序，不包含发出前和过期后的时间
+func (s ReminderSchedule) DueTimes(sentAt, expiresAt time.Time) []time.Time {
+	var times []time.Time
+	add := func(t time.Time) {
+		if !t.After(sentAt) || !t.Before(expiresAt) {
+			return
+		}
+		for _, existing := range times {
+			if existing.Equal(t) {
+				return
+			}
+		}
+		times = append(times, t)
+	}
+	for _, days := range s.AfterSendDays {
+		add(sentAt.AddDate(0, 0, days))
+	}
+	for _, days := range s.BeforeExpiryDays {
+		add(expiresAt.AddDate(0, 0, -days))
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	return times
+}
+
+// IsDue 判断令牌是否需要催办：催办次数未达到 MaxReminders，且自上次催办（从未催办时为发出时间）以来已经过了至少一个催办时间。
+// 服务停止期间错过的多个催办时间只补发一次
+func (s ReminderSchedule) IsDue(sentAt, expiresAt time.Time, lastRemindedAt *time.Time, reminderCount int, now time.Time) bool {
+	if reminderCount >= s.MaxReminders {
+		return false
+	}
+	last := sentAt
+	if lastRemindedAt != nil && lastRemindedAt.After(last) {
+		last = *lastRemindedAt
+	}
+	for _, t := range s.DueTimes(sentAt, expiresAt) {
+		if t.After(last) && !t.After(now) {
+			return true
+		}
+	}
+	return false
+}
+
 // EmailFailureDetail 用于在 ErrorSummary 中记录单个邮件发送失败的详情
 type EmailFailureDetail struct {
 	EmployeeID   string `json:"employeeId"`
 	EmployeeName string `json:"employeeName"`
-	EmailAddress string `json:"emailAddress"`
+	EmailAddress string `json:"emailAddress"`      // 发送的地址，通过 IM 或短信发送时为手机号码
+	Channel      string `json:"channel,omitempty"` // 发送使用的通知渠道
 	Reason       string `json:"reason"`
 }
 
@@ -149,11 +210,13 @@ type ReportedUnlistedNumberInfo struct {
 
 // PendingUserDetail 表示未响应确认的用户详情 (用于 PhoneVerificationStatusResponse)
 type PendingUserDetail struct {
-	EmployeeID string     `json:"employeeId"`
-	FullName   string     `json:"fullName"`
-	Email      *string    `json:"email,omitempty"`
-	TokenID    uint       `json:"tokenId,omitempty"`
-	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	EmployeeID     string     `json:"employeeId"`
+	FullName       string     `json:"fullName"`
+	Email          *string    `json:"email,omitempty"`
+	TokenID        uint       `json:"tokenId,omitempty"`
+	ExpiresAt      *time.Time `json:"expiresAt,omitempty"`
+	LastRemindedAt *time.Time `json:"lastRemindedAt,omitempty"` // 最近一次催办时间，未催办过时为空
+	ReminderCount  int        `json:"reminderCount"`            // 已催办次数
 }
 
 // ReportedIssueDetail 表示用户报告的号码问题详情 (用于 PhoneVerificationStatusResponse)
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReminderScheduleDueTimes(t *testing.T) {
+	sentAt := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	expiresAt := sentAt.AddDate(0, 0, 7)
+	s := ReminderSchedule{AfterSendDays: []int{6, 3, 8}, BeforeExpiryDays: []int{1, 4, 7}, MaxReminders: 2}
+
+	// 第 8 天在过期之后、过期前第 7 天即发出时间，均被忽略；第 3 天与过期前第 4 天重复
+	got := s.DueTimes(sentAt, expiresAt)
+	want := []time.Time{sentAt.AddDate(0, 0, 3), sentAt.AddDate(0, 0, 6)}
+	if len(got) != len(want) {
+		t.Fatalf("DueTimes() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if !got[i].Equal(want[i]) {
+			t.Errorf("DueTimes()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestReminderScheduleIsDue(t *testing.T) {
+	sentAt := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	expiresAt := sentAt.AddDate(0, 0, 7)
+	s := ReminderSchedule{AfterSendDays: []int{3}, BeforeExpiryDays: []int{1}, MaxReminders: 2}
+	day := func(d float64) time.Time { return sentAt.Add(time.Duration(d * 24 * float64(time.Hour))) }
+	at := func(d float64) *time.Time { t := day(d); return &t }
+
+	cases := []struct {
+		name           string
+		lastRemindedAt *time.Time
+		reminderCount  int
+		now            time.Time
+		want           bool
+	}{
+		{"before first", nil, 0, day(2), false},
+		{"first due", nil, 0, day(3), true},
+		{"already reminded", at(3.1), 1, day(5), false},
+		{"second due", at(3.1), 1, day(6.5), true},
+		{"missed times sent once", at(6.6), 1, day(6.9), false},
+		{"manual reminder after due time", at(4), 1, day(5), false},
+		{"max reached", at(3.1), 2, day(6.5), false},
+	}
+	for _, tc := range cases {
+		if got := s.IsDue(sentAt, expiresAt, tc.lastRemindedAt, tc.reminderCount, tc.now); got != tc.want {
+			t.Errorf("%s: IsDue() = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"github.com/phone_management/internal/models"
+	"gorm.io/gorm"
+)
+
+// AuditEventRepository 定义了审计事件数据仓库的接口。
+// 审计事件由各仓库在变更所在的事务中通过 audit.Record 写入，这里只负责查询。
+type AuditEventRepository interface {
+	// List 按条件分页查询审计事件，按发生时间倒序
+	List(filter models.AuditEventFilter, page, limit int) ([]models.AuditEvent, int64, error)
+	// ListByEntity 查询指定实体的全部审计事件，按发生时间升序
+	ListByEntity(entityType, entityID string) ([]models.AuditEvent, error)
+}
+
+// gormAuditEventRepository 是 AuditEventRepository 的 GORM 实现
+type gormAuditEventRepository struct {
+	db *gorm.DB
+}
+
+// NewGormAuditEventRepository 创建一个新的 gormAuditEventRepository 实例
+func NewGormAuditEventRepository(db *gorm.DB) AuditEventRepository {
+	return &gormAuditEventRepository{db: db}
+}
+
+// List 按条件分页查询审计事件
+func (r *gormAuditEventRepository) List(filter models.AuditEventFilter, page, limit int) ([]models.AuditEvent, int64, error) {
+	var events []models.AuditEvent
+	var totalItems int64
+
+	tx := r.db.Model(&models.AuditEvent{})
+	if filter.Actor != "" {
+		tx = tx.Where("actor_username = ?", filter.Actor)
+	}
+	if filter.Action != "" {
+		tx = tx.Where("action = ?", filter.Action)
+	}
+	if filter.EntityType != "" {
+		tx = tx.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		tx = tx.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.Source != "" {
+		tx = tx.Where("source = ?", filter.Source)
+	}
+	if filter.From != nil {
+		tx = tx.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		tx = tx.Where("created_at < ?", *filter.To)
+	}
+
+	if err := tx.Count(&totalItems).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := tx.Order("created_at desc, id desc").Offset(offset).Limit(limit).Find(&events).Error; err != nil {
+		return nil, 0, err
+	}
+	return events, totalItems, nil
+}
+
+// ListByEntity 查询指定实体的全部审计事件，按发生时间升序
+func (r *gormAuditEventRepository) ListByEntity(entityType, entityID string) ([]models.AuditEvent, error) {
+	var events []models.AuditEvent
+	err := r.db.Where("entity_type = ? AND entity_id = ?", entityType, entityID).
+		Order("created_at asc, id asc").
+		Find(&events).Error
+	return events, err
+}
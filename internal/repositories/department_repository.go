@@ -0,0 +1,462 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/phone_management/internal/audit"
+	"github.com/phone_management/internal/models"
+	"gorm.io/gorm"
+)
+
+// ErrDepartmentNameExists 表示部门名称已被其他部门或其别名使用
+var ErrDepartmentNameExists = errors.New("部门名称已存在")
+
+// ErrDepartmentInUse 表示部门仍有下级部门或员工，不能删除
+var ErrDepartmentInUse = errors.New("部门仍有下级部门或员工，不能删除")
+
+// DepartmentRepository 定义了部门数据仓库的接口
+type DepartmentRepository interface {
+	CreateDepartment(ctx context.Context, department *models.Department) (*models.Department, error)
+	// GetDepartments 返回全部部门（平铺），按名称升序
+	GetDepartments(ctx context.Context) ([]models.DepartmentResponse, error)
+	GetDepartmentByID(ctx context.Context, id uint) (*models.Department, error)
+	// UpdateDepartment 更新部门字段。updates 中包含 name 时，原名称记为别名，员工上的部门名称同步更新
+	UpdateDepartment(ctx context.Context, id uint, updates map[string]interface{}) (*models.Department, error)
+	// DeleteDepartment 删除没有下级部门和员工的部门及其别名
+	DeleteDepartment(ctx context.Context, id uint) error
+	// MergeDepartments 将 sourceIDs 对应的部门合并到目标部门：员工和下级部门转到目标部门，
+	// 被合并部门的名称及别名记为目标部门的别名，然后删除被合并的部门
+	MergeDepartments(ctx context.Context, targetID uint, sourceIDs []uint) (*models.Department, error)
+	// FindByName 按部门名称或别名查找部门，名称会先规范化
+	FindByName(ctx context.Context, name string) (*models.Department, error)
+	// DescendantIDs 返回 rootIDs 及其所有下级部门的ID
+	DescendantIDs(ctx context.Context, rootIDs []uint) ([]uint, error)
+	// ResolveDepartmentIDs 将部门筛选条件解析为部门ID列表。未指定部门时返回 nil（不筛选），
+	// 部门不存在时返回空列表（不匹配任何记录）
+	ResolveDepartmentIDs(ctx context.Context, filter models.DepartmentFilter) ([]uint, error)
+	// NormalizeEmployeeDepartments 为尚未关联部门的员工按其部门名称关联部门，名称规范化后仍找不到的部门会自动创建。
+	// 用于把旧版本中自由填写的部门名称迁移为部门实体，返回关联的员工数
+	NormalizeEmployeeDepartments(ctx context.Context) (int, error)
+}
+
+// gormDepartmentRepository 是 DepartmentRepository 的 GORM 实现
+type gormDepartmentRepository struct {
+	db *gorm.DB
+}
+
+// NewGormDepartmentRepository 创建一个新的 gormDepartmentRepository 实例
+func NewGormDepartmentRepository(db *gorm.DB) DepartmentRepository {
+	return &gormDepartmentRepository{db: db}
+}
+
+// departmentEntityID 返回部门在审计事件中的实体标识
+func departmentEntityID(id uint) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
+
+// departmentNameTaken 检查名称是否已被 exceptID 以外的部门或其别名使用
+func departmentNameTaken(tx *gorm.DB, name string, exceptID uint) (bool, error) {
+	var count int64
+	if err := tx.Model(&models.Department{}).Where("name = ? AND id <> ?", name, exceptID).Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return true, nil
+	}
+	if err := tx.Model(&models.DepartmentAlias{}).Where("name = ? AND department_id <> ?", name, exceptID).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// findDepartmentByName 在事务中按名称或别名查找部门，name 须已规范化
+func findDepartmentByName(tx *gorm.DB, name string) (*models.Department, error) {
+	var department models.Department
+	err := tx.Where("name = ?", name).First(&department).Error
+	if err == nil {
+		return &department, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	var alias models.DepartmentAlias
+	if err := tx.Where("name = ?", name).First(&alias).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, err
+	}
+	if err := tx.First(&department, alias.DepartmentID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &department, nil
+}
+
+// CreateDepartment 创建部门，名称与已有部门或别名重复时返回 ErrDepartmentNameExists
+func (r *gormDepartmentRepository) CreateDepartment(ctx context.Context, department *models.Department) (*models.Department, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		taken, err := departmentNameTaken(tx, department.Name, 0)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return ErrDepartmentNameExists
+		}
+		if err := tx.Create(department).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, models.AuditActionDepartmentCreate, models.AuditEntityDepartment, departmentEntityID(department.ID), nil, department)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return department, nil
+}
+
+// GetDepartments 查询全部部门，并补充上级部门名称、负责人姓名、别名和直属员工数量
+func (r *gormDepartmentRepository) GetDepartments(ctx context.Context) ([]models.DepartmentResponse, error) {
+	db := r.db.WithContext(ctx)
+
+	var departments []models.Department
+	if err := db.Order("name ASC").Find(&departments).Error; err != nil {
+		return nil, err
+	}
+
+	var aliases []models.DepartmentAlias
+	if err := db.Order("name ASC").Find(&aliases).Error; err != nil {
+		return nil, err
+	}
+	aliasesByDepartment := make(map[uint][]string)
+	for _, a := range aliases {
+		aliasesByDepartment[a.DepartmentID] = append(aliasesByDepartment[a.DepartmentID], a.Name)
+	}
+
+	var counts []struct {
+		DepartmentID uint
+		Count        int64
+	}
+	if err := db.Model(&models.Employee{}).
+		Select("department_id, COUNT(*) AS count").
+		Where("department_id IS NOT NULL").
+		Group("department_id").
+		Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+	employeeCounts := make(map[uint]int64, len(counts))
+	for _, c := range counts {
+		employeeCounts[c.DepartmentID] = c.Count
+	}
+
+	var managerIDs []string
+	names := make(map[uint]string, len(departments))
+	for _, d := range departments {
+		names[d.ID] = d.Name
+		if d.ManagerEmployeeID != nil {
+			managerIDs = append(managerIDs, *d.ManagerEmployeeID)
+		}
+	}
+	managerNames := make(map[string]string)
+	if len(managerIDs) > 0 {
+		var managers []models.Employee
+		if err := db.Select("employee_id, full_name").Where("employee_id IN ?", managerIDs).Find(&managers).Error; err != nil {
+			return nil, err
+		}
+		for _, m := range managers {
+			managerNames[m.EmployeeID] = m.FullName
+		}
+	}
+
+	responses := make([]models.DepartmentResponse, 0, len(departments))
+	for _, d := range departments {
+		resp := models.DepartmentResponse{
+			Department:    d,
+			Aliases:       aliasesByDepartment[d.ID],
+			EmployeeCount: employeeCounts[d.ID],
+		}
+		if resp.Aliases == nil {
+			resp.Aliases = []string{}
+		}
+		if d.ParentID != nil {
+			resp.ParentName = names[*d.ParentID]
+		}
+		if d.ManagerEmployeeID != nil {
+			resp.ManagerName = managerNames[*d.ManagerEmployeeID]
+		}
+		responses = append(responses, resp)
+	}
+	return responses, nil
+}
+
+// GetDepartmentByID 根据ID查询部门
+func (r *gormDepartmentRepository) GetDepartmentByID(ctx context.Context, id uint) (*models.Department, error) {
+	var department models.Department
+	if err := r.db.WithContext(ctx).First(&department, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &department, nil
+}
+
+// UpdateDepartment 更新部门。改名时新名称不能与其他部门或别名重复，原名称记为本部门的别名
+func (r *gormDepartmentRepository) UpdateDepartment(ctx context.Context, id uint, updates map[string]interface{}) (*models.Department, error) {
+	var department, updated models.Department
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&department, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrRecordNotFound
+			}
+			return err
+		}
+
+		newName, renaming := updates["name"].(string)
+		renaming = renaming && newName != department.Name
+		if renaming {
+			taken, err := departmentNameTaken(tx, newName, department.ID)
+			if err != nil {
+				return err
+			}
+			if taken {
+				return ErrDepartmentNameExists
+			}
+			// 新名称原是本部门的别名时不再保留该别名，原名称改为别名
+			if err := tx.Where("name = ?", newName).Delete(&models.DepartmentAlias{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Create(&models.DepartmentAlias{Name: department.Name, DepartmentID: department.ID}).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&models.Employee{}).Where("department_id = ?", department.ID).Update("department", newName).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&models.Department{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+			return err
+		}
+		if err := tx.First(&updated, id).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, models.AuditActionDepartmentUpdate, models.AuditEntityDepartment, departmentEntityID(id), department, updated)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteDepartment 删除部门，部门仍有下级部门或员工时返回 ErrDepartmentInUse
+func (r *gormDepartmentRepository) DeleteDepartment(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var department models.Department
+		if err := tx.First(&department, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrRecordNotFound
+			}
+			return err
+		}
+
+		var children, employees int64
+		if err := tx.Model(&models.Department{}).Where("parent_id = ?", id).Count(&children).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Employee{}).Where("department_id = ?", id).Count(&employees).Error; err != nil {
+			return err
+		}
+		if children > 0 || employees > 0 {
+			return ErrDepartmentInUse
+		}
+
+		if err := tx.Where("department_id = ?", id).Delete(&models.DepartmentAlias{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("department_id = ?", id).Delete(&models.UserDepartment{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&department).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, models.AuditActionDepartmentDelete, models.AuditEntityDepartment, departmentEntityID(id), department, nil)
+	})
+}
+
+// MergeDepartments 合并部门，每个被合并的部门记录一条 department.merge 审计事件（变更前为被合并部门，变更后为目标部门）
+func (r *gormDepartmentRepository) MergeDepartments(ctx context.Context, targetID uint, sourceIDs []uint) (*models.Department, error) {
+	var target models.Department
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&target, targetID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrRecordNotFound
+			}
+			return err
+		}
+
+		for _, sourceID := range sourceIDs {
+			var source models.Department
+			if err := tx.First(&source, sourceID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return ErrRecordNotFound
+				}
+				return err
+			}
+
+			if err := tx.Model(&models.Employee{}).Where("department_id = ?", source.ID).
+				Updates(map[string]interface{}{"department_id": target.ID, "department": target.Name}).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&models.Department{}).Where("parent_id = ?", source.ID).Update("parent_id", target.ID).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&models.DepartmentAlias{}).Where("department_id = ?", source.ID).Update("department_id", target.ID).Error; err != nil {
+				return err
+			}
+			if err := tx.Create(&models.DepartmentAlias{Name: source.Name, DepartmentID: target.ID}).Error; err != nil {
+				return err
+			}
+			// 负责被合并部门的部门负责人改为负责目标部门，已负责目标部门的直接移除原记录
+			alreadyManaging := tx.Model(&models.UserDepartment{}).Select("user_id").Where("department_id = ?", target.ID)
+			if err := tx.Where("department_id = ? AND user_id IN (?)", source.ID, alreadyManaging).Delete(&models.UserDepartment{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&models.UserDepartment{}).Where("department_id = ?", source.ID).Update("department_id", target.ID).Error; err != nil {
+				return err
+			}
+			if err := tx.Delete(&source).Error; err != nil {
+				return err
+			}
+			if err := audit.Record(tx, models.AuditActionDepartmentMerge, models.AuditEntityDepartment, departmentEntityID(source.ID), source, target); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &target, nil
+}
+
+// FindByName 按规范化后的名称查找部门，先匹配部门名称再匹配别名
+func (r *gormDepartmentRepository) FindByName(ctx context.Context, name string) (*models.Department, error) {
+	return findDepartmentByName(r.db.WithContext(ctx), models.NormalizeDepartmentName(name))
+}
+
+// DescendantIDs 按上级部门关系逐层展开，返回 rootIDs 及全部下级部门的ID
+func (r *gormDepartmentRepository) DescendantIDs(ctx context.Context, rootIDs []uint) ([]uint, error) {
+	return departmentDescendantIDs(r.db.WithContext(ctx), rootIDs)
+}
+
+// departmentDescendantIDs 按上级部门关系逐层展开，返回 rootIDs 及全部下级部门的ID
+func departmentDescendantIDs(db *gorm.DB, rootIDs []uint) ([]uint, error) {
+	var departments []models.Department
+	if err := db.Model(&models.Department{}).Select("id, parent_id").Find(&departments).Error; err != nil {
+		return nil, err
+	}
+	children := make(map[uint][]uint)
+	for _, d := range departments {
+		if d.ParentID != nil {
+			children[*d.ParentID] = append(children[*d.ParentID], d.ID)
+		}
+	}
+
+	seen := make(map[uint]bool)
+	ids := make([]uint, 0, len(rootIDs))
+	queue := append([]uint(nil), rootIDs...)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+		queue = append(queue, children[id]...)
+	}
+	return ids, nil
+}
+
+// ResolveDepartmentIDs 解析部门筛选条件
+func (r *gormDepartmentRepository) ResolveDepartmentIDs(ctx context.Context, filter models.DepartmentFilter) ([]uint, error) {
+	if filter.IsEmpty() {
+		return nil, nil
+	}
+
+	var department *models.Department
+	var err error
+	if filter.DepartmentID != nil {
+		department, err = r.GetDepartmentByID(ctx, *filter.DepartmentID)
+	} else {
+		department, err = r.FindByName(ctx, filter.DepartmentName)
+	}
+	if err != nil {
+		if errors.Is(err, ErrRecordNotFound) {
+			return []uint{}, nil
+		}
+		return nil, err
+	}
+
+	if !filter.IncludeSubDepartments {
+		return []uint{department.ID}, nil
+	}
+	return r.DescendantIDs(ctx, []uint{department.ID})
+}
+
+// NormalizeEmployeeDepartments 迁移旧数据：部门名称规范化后为空的清空，其余关联到同名（或别名）部门
+func (r *gormDepartmentRepository) NormalizeEmployeeDepartments(ctx context.Context) (int, error) {
+	linked := 0
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var employees []models.Employee
+		if err := tx.Unscoped().Select("id, department").
+			Where("department_id IS NULL AND department IS NOT NULL").
+			Find(&employees).Error; err != nil {
+			return err
+		}
+
+		departments := make(map[string]*models.Department)
+		for _, e := range employees {
+			name := models.NormalizeDepartmentName(*e.Department)
+			if name == "" {
+				if err := tx.Unscoped().Model(&models.Employee{}).Where("id = ?", e.ID).UpdateColumn("department", nil).Error; err != nil {
+					return err
+				}
+				continue
+			}
+
+			department, ok := departments[name]
+			if !ok {
+				found, err := findDepartmentByName(tx, name)
+				if errors.Is(err, ErrRecordNotFound) {
+					found = &models.Department{Name: name}
+					if err = tx.Create(found).Error; err == nil {
+						err = audit.Record(tx, models.AuditActionDepartmentCreate, models.AuditEntityDepartment, departmentEntityID(found.ID), nil, found)
+					}
+				}
+				if err != nil {
+					return err
+				}
+				department = found
+				departments[name] = department
+			}
+
+			if err := tx.Unscoped().Model(&models.Employee{}).Where("id = ?", e.ID).
+				UpdateColumns(map[string]interface{}{"department_id": department.ID, "department": department.Name}).Error; err != nil {
+				return err
+			}
+			linked++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return linked, nil
+}
@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/phone_management/internal/scope"
+	"gorm.io/gorm"
+)
+
+// 以下函数在 context 限定了部门范围时（见 scope.WithDepartments）为查询追加条件，
+// 未限定时原样返回查询。所有可被部门负责人访问的查询都必须经过这些函数。
+
+// scopedEmployeeIDs 返回限定部门内员工工号的子查询
+func scopedEmployeeIDs(query *gorm.DB, departmentIDs []uint) *gorm.DB {
+	return query.Session(&gorm.Session{NewDB: true}).
+		Table("employees").
+		Select("employee_id").
+		Where("department_id IN ?", departmentIDs)
+}
+
+// scopeMobileNumbers 只保留当前使用人或办卡人属于可访问部门的号码，table 为查询中号码表的名称或别名
+func scopeMobileNumbers(ctx context.Context, query *gorm.DB, table string) *gorm.DB {
+	departmentIDs, restricted := scope.Departments(ctx)
+	if !restricted {
+		return query
+	}
+	employees := scopedEmployeeIDs(query, departmentIDs)
+	return query.Where(fmt.Sprintf("(%[1]s.current_employee_id IN (?) OR %[1]s.applicant_employee_id IN (?))", table), employees, employees)
+}
+
+// scopePhoneNumbers 只保留 column 中的手机号码属于可访问号码（见 scopeMobileNumbers）的记录
+func scopePhoneNumbers(ctx context.Context, query *gorm.DB, column string) *gorm.DB {
+	departmentIDs, restricted := scope.Departments(ctx)
+	if !restricted {
+		return query
+	}
+	employees := scopedEmployeeIDs(query, departmentIDs)
+	numbers := query.Session(&gorm.Session{NewDB: true}).
+		Table("mobile_numbers").
+		Select("phone_number").
+		Where("current_employee_id IN (?) OR applicant_employee_id IN (?)", employees, employees)
+	return query.Where(column+" IN (?)", numbers)
+}
+
+// scopeEmployees 只保留 column 中的员工工号属于可访问部门员工的记录
+func scopeEmployees(ctx context.Context, query *gorm.DB, column string) *gorm.DB {
+	departmentIDs, restricted := scope.Departments(ctx)
+	if !restricted {
+		return query
+	}
+	return query.Where(column+" IN (?)", scopedEmployeeIDs(query, departmentIDs))
+}
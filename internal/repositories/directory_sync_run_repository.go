@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/phone_management/internal/models"
+	"gorm.io/gorm"
+)
+
+// DirectorySyncRunRepository 定义了员工目录同步记录数据仓库的接口
+type DirectorySyncRunRepository interface {
+	Create(ctx context.Context, run *models.DirectorySyncRun) error
+	// Save 保存同步结果（状态、计数、报告条目等）
+	Save(ctx context.Context, run *models.DirectorySyncRun) error
+	// FindRecent 按开始时间倒序查询最近的同步记录，不含报告条目
+	FindRecent(ctx context.Context, limit int) ([]models.DirectorySyncRun, error)
+	GetByID(ctx context.Context, id uint) (*models.DirectorySyncRun, error)
+}
+
+// gormDirectorySyncRunRepository 是 DirectorySyncRunRepository 的 GORM 实现
+type gormDirectorySyncRunRepository struct {
+	db *gorm.DB
+}
+
+// NewGormDirectorySyncRunRepository 创建一个新的 gormDirectorySyncRunRepository 实例
+func NewGormDirectorySyncRunRepository(db *gorm.DB) DirectorySyncRunRepository {
+	return &gormDirectorySyncRunRepository{db: db}
+}
+
+// Create 创建同步记录
+func (r *gormDirectorySyncRunRepository) Create(ctx context.Context, run *models.DirectorySyncRun) error {
+	return r.db.WithContext(ctx).Create(run).Error
+}
+
+// Save 保存同步记录的全部字段
+func (r *gormDirectorySyncRunRepository) Save(ctx context.Context, run *models.DirectorySyncRun) error {
+	return r.db.WithContext(ctx).Save(run).Error
+}
+
+// FindRecent 查询最近的同步记录
+func (r *gormDirectorySyncRunRepository) FindRecent(ctx context.Context, limit int) ([]models.DirectorySyncRun, error) {
+	var runs []models.DirectorySyncRun
+	err := r.db.WithContext(ctx).
+		Omit("items").
+		Order("started_at DESC, id DESC").
+		Limit(limit).
+		Find(&runs).Error
+	return runs, err
+}
+
+// GetByID 根据ID查询同步记录
+func (r *gormDirectorySyncRunRepository) GetByID(ctx context.Context, id uint) (*models.DirectorySyncRun, error) {
+	var run models.DirectorySyncRun
+	if err := r.db.WithContext(ctx).First(&run, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &run, nil
+}
@@ -0,0 +1,136 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/phone_management/internal/audit"
+	"github.com/phone_management/internal/models"
+	"gorm.io/gorm"
+)
+
+// ErrEmailTemplateExists 表示同名称、同语言的通知模板已存在
+var ErrEmailTemplateExists = errors.New("该名称和语言的通知模板已存在")
+
+// EmailTemplateRepository 定义了通知模板的数据仓库接口，新增、更新和删除均记录审计事件
+type EmailTemplateRepository interface {
+	// FindAll 按名称、语言升序查询全部模板
+	FindAll(ctx context.Context) ([]models.EmailTemplate, error)
+	GetByID(ctx context.Context, id uint) (*models.EmailTemplate, error)
+	// FindByNameAndLocale 按名称和语言查询模板，不存在时返回 ErrRecordNotFound
+	FindByNameAndLocale(ctx context.Context, name, locale string) (*models.EmailTemplate, error)
+	// Create 新增模板，同名称、同语言的模板已存在时返回 ErrEmailTemplateExists
+	Create(ctx context.Context, tmpl *models.EmailTemplate) (*models.EmailTemplate, error)
+	Update(ctx context.Context, id uint, updates map[string]interface{}) (*models.EmailTemplate, error)
+	Delete(ctx context.Context, id uint) error
+}
+
+// gormEmailTemplateRepository 是 EmailTemplateRepository 的 GORM 实现
+type gormEmailTemplateRepository struct {
+	db *gorm.DB
+}
+
+// NewGormEmailTemplateRepository 创建一个新的 gormEmailTemplateRepository 实例
+func NewGormEmailTemplateRepository(db *gorm.DB) EmailTemplateRepository {
+	return &gormEmailTemplateRepository{db: db}
+}
+
+func emailTemplateEntityID(id uint) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
+
+// FindAll 查询全部模板
+func (r *gormEmailTemplateRepository) FindAll(ctx context.Context) ([]models.EmailTemplate, error) {
+	var templates []models.EmailTemplate
+	if err := r.db.WithContext(ctx).Order("name ASC, locale ASC").Find(&templates).Error; err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// GetByID 根据ID查询模板
+func (r *gormEmailTemplateRepository) GetByID(ctx context.Context, id uint) (*models.EmailTemplate, error) {
+	var tmpl models.EmailTemplate
+	if err := r.db.WithContext(ctx).First(&tmpl, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &tmpl, nil
+}
+
+// FindByNameAndLocale 按名称和语言查询模板
+func (r *gormEmailTemplateRepository) FindByNameAndLocale(ctx context.Context, name, locale string) (*models.EmailTemplate, error) {
+	var tmpl models.EmailTemplate
+	if err := r.db.WithContext(ctx).Where("name = ? AND locale = ?", name, locale).First(&tmpl).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &tmpl, nil
+}
+
+// Create 新增模板
+func (r *gormEmailTemplateRepository) Create(ctx context.Context, tmpl *models.EmailTemplate) (*models.EmailTemplate, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.EmailTemplate{}).Where("name = ? AND locale = ?", tmpl.Name, tmpl.Locale).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrEmailTemplateExists
+		}
+		if err := tx.Create(tmpl).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, models.AuditActionEmailTemplateCreate, models.AuditEntityEmailTemplate, emailTemplateEntityID(tmpl.ID), nil, tmpl)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// Update 更新模板
+func (r *gormEmailTemplateRepository) Update(ctx context.Context, id uint, updates map[string]interface{}) (*models.EmailTemplate, error) {
+	var tmpl, updated models.EmailTemplate
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&tmpl, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrRecordNotFound
+			}
+			return err
+		}
+		if err := tx.Model(&models.EmailTemplate{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+			return err
+		}
+		if err := tx.First(&updated, id).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, models.AuditActionEmailTemplateUpdate, models.AuditEntityEmailTemplate, emailTemplateEntityID(id), tmpl, updated)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// Delete 删除模板，删除后恢复使用模板文件或内置模板
+func (r *gormEmailTemplateRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var tmpl models.EmailTemplate
+		if err := tx.First(&tmpl, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrRecordNotFound
+			}
+			return err
+		}
+		if err := tx.Delete(&tmpl).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, models.AuditActionEmailTemplateDelete, models.AuditEntityEmailTemplate, emailTemplateEntityID(id), tmpl, nil)
+	})
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"strings"
+	"time"
 
+	"github.com/phone_management/internal/audit"
 	"github.com/phone_management/internal/models"
 	"gorm.io/gorm"
 )
@@ -18,19 +20,30 @@ var ErrEmployeePhoneNumberConflict = errors.New("员工手机号码已存在")
 // ErrEmployeeEmailConflict 表示员工的邮箱已存在
 var ErrEmployeeEmailConflict = errors.New("员工邮箱已存在")
 
+// ErrEmployeeNotDeparted 表示员工不是离职状态
+var ErrEmployeeNotDeparted = errors.New("员工不是离职状态")
+
 // EmployeeRepository 定义了员工数据仓库的接口
 type EmployeeRepository interface {
-	CreateEmployee(employee *models.Employee) (*models.Employee, error)
-	GetEmployees(page, limit int, sortBy, sortOrder, search, employmentStatus string) ([]models.Employee, int64, error)
+	CreateEmployee(ctx context.Context, employee *models.Employee) (*models.Employee, error)
+	// GetEmployees 的 departmentIDs 为 nil 时不按部门筛选
+	GetEmployees(page, limit int, sortBy, sortOrder, search, employmentStatus string, departmentIDs []uint) ([]models.Employee, int64, error)
 	GetEmployeeDetailByEmployeeID(employeeID string) (*models.EmployeeDetailResponse, error)
 	GetEmployeeByEmployeeID(employeeID string) (*models.Employee, error)
 	GetEmployeeByPhoneNumber(phoneNumber string) (*models.Employee, error)
 	GetEmployeeByEmail(email string) (*models.Employee, error)
-	UpdateEmployee(employeeID string, updates map[string]interface{}) (*models.Employee, error)
+	UpdateEmployee(ctx context.Context, employeeID string, updates map[string]interface{}) (*models.Employee, error)
+	// Rehire 办理员工复职，记录上一段任职期间并恢复为在职；员工不是离职状态时返回 ErrEmployeeNotDeparted
+	Rehire(ctx context.Context, employeeID string, rehireDate time.Time) (*models.Employee, error)
 	GetEmployeesByFullName(fullName string) ([]*models.Employee, error)
 	FindAllActive(ctx context.Context) ([]models.Employee, error)
-	FindActiveByDepartmentNames(ctx context.Context, departmentNames []string) ([]models.Employee, error)
+	// FindAll 查询所有员工（含离职员工），用于与员工目录对账
+	FindAll(ctx context.Context) ([]models.Employee, error)
+	FindActiveByDepartmentIDs(ctx context.Context, departmentIDs []uint) ([]models.Employee, error)
 	FindActiveByEmployeeIDs(ctx context.Context, employeeIDs []string) ([]models.Employee, error)
+	// WithinTransaction 在一个事务中执行 fn，传入的员工仓库、号码仓库和预约离职仓库都绑定到该事务，
+	// 仓库方法自身的事务以保存点嵌套执行。fn 返回错误时整个事务回滚
+	WithinTransaction(ctx context.Context, fn func(employeeRepo EmployeeRepository, mobileNumberRepo MobileNumberRepository, departureRepo ScheduledDepartureRepository) error) error
 	// 未来可以扩展其他方法，如 GetEmployeeByID, UpdateEmployee, DeleteEmployee 等
 }
 
@@ -45,12 +58,18 @@ func NewGormEmployeeRepository(db *gorm.DB) EmployeeRepository {
 }
 
 // CreateEmployee 在数据库中创建一个新的员工记录
-func (r *gormEmployeeRepository) CreateEmployee(employee *models.Employee) (*models.Employee, error) {
+func (r *gormEmployeeRepository) CreateEmployee(ctx context.Context, employee *models.Employee) (*models.Employee, error) {
 	// EmployeeID 的生成由 model hooks (BeforeCreate, AfterCreate) 处理
 	// 在 AfterCreate hook 中，会执行一次 update 来设置最终的 EmployeeID
 	// 因此，这里的 Create 操作实际上是用一个临时的 EmployeeID (如果 BeforeCreate hook 被触发了)
 
-	if err := r.db.Create(employee).Error; err != nil {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(employee).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, models.AuditActionEmployeeCreate, models.AuditEntityEmployee, employee.EmployeeID, nil, employee)
+	})
+	if err != nil {
 		// 检查是否是已知的唯一约束错误
 		// 注意：错误字符串的判断可能因数据库类型而异，这里尝试覆盖常见情况
 		lowerErr := strings.ToLower(err.Error())
@@ -72,7 +91,7 @@ func (r *gormEmployeeRepository) CreateEmployee(employee *models.Employee) (*mod
 }
 
 // GetEmployees 从数据库中获取员工列表，支持分页、排序、搜索和筛选
-func (r *gormEmployeeRepository) GetEmployees(page, limit int, sortBy, sortOrder, search, employmentStatus string) ([]models.Employee, int64, error) {
+func (r *gormEmployeeRepository) GetEmployees(page, limit int, sortBy, sortOrder, search, employmentStatus string, departmentIDs []uint) ([]models.Employee, int64, error) {
 	var employees []models.Employee
 	var totalItems int64
 
@@ -89,6 +108,11 @@ func (r *gormEmployeeRepository) GetEmployees(page, limit int, sortBy, sortOrder
 		tx = tx.Where("employment_status = ?", employmentStatus)
 	}
 
+	// 处理部门筛选
+	if departmentIDs != nil {
+		tx = tx.Where("department_id IN ?", departmentIDs)
+	}
+
 	// 计算总数（在应用分页之前）
 	if err := tx.Count(&totalItems).Error; err != nil {
 		return nil, 0, err
@@ -144,6 +168,7 @@ func (r *gormEmployeeRepository) GetEmployeeDetailByEmployeeID(employeeID string
 		EmployeeID:       employee.EmployeeID,
 		FullName:         employee.FullName,
 		Department:       employee.Department,
+		DepartmentID:     employee.DepartmentID,
 		EmploymentStatus: employee.EmploymentStatus,
 		HireDate:         employee.HireDate,
 		TerminationDate:  employee.TerminationDate,
@@ -171,6 +196,15 @@ func (r *gormEmployeeRepository) GetEmployeeDetailByEmployeeID(employeeID string
 	}
 	empDetailResp.UsingMobileNumbers = usingNumbers
 
+	// 获取复职前已结束的任职期间
+	var periods []models.EmploymentPeriod
+	if err := r.db.Where("employee_id = ?", employee.EmployeeID).
+		Order("termination_date ASC, id ASC").
+		Find(&periods).Error; err != nil {
+		return nil, err
+	}
+	empDetailResp.EmploymentPeriods = periods
+
 	return empDetailResp, nil
 }
 
@@ -220,33 +254,92 @@ func (r *gormEmployeeRepository) GetEmployeesByFullName(fullName string) ([]*mod
 }
 
 // UpdateEmployee 更新指定业务工号的员工信息
-func (r *gormEmployeeRepository) UpdateEmployee(employeeID string, updates map[string]interface{}) (*models.Employee, error) {
-	// 首先，检查员工是否存在，确保我们操作的是一个有效的记录
-	// GetEmployeeByEmployeeID 方法内部已经处理了 ErrRecordNotFound
-	var employee models.Employee
-	if err := r.db.Where("employee_id = ?", employeeID).First(&employee).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, ErrRecordNotFound // 如果员工不存在，返回错误
+func (r *gormEmployeeRepository) UpdateEmployee(ctx context.Context, employeeID string, updates map[string]interface{}) (*models.Employee, error) {
+	var employee, updatedEmployee models.Employee
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 首先，检查员工是否存在，确保我们操作的是一个有效的记录
+		if err := tx.Where("employee_id = ?", employeeID).First(&employee).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrRecordNotFound // 如果员工不存在，返回错误
+			}
+			return err // 其他数据库查询错误
 		}
-		return nil, err // 其他数据库查询错误
-	}
 
-	// 更新记录
-	// 使用 Model(&models.Employee{}) 指定模型，并通过 Where 更新特定 employee_id 的记录
-	if err := r.db.Model(&models.Employee{}).Where("employee_id = ?", employeeID).Updates(updates).Error; err != nil {
+		// 更新记录
+		// 使用 Model(&models.Employee{}) 指定模型，并通过 Where 更新特定 employee_id 的记录
+		if err := tx.Model(&models.Employee{}).Where("employee_id = ?", employeeID).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		// 重新查询更新后的记录并返回
+		if err := tx.Where("employee_id = ?", employeeID).First(&updatedEmployee).Error; err != nil {
+			return err // 理论上此时应该能找到
+		}
+
+		return audit.Record(tx, models.AuditActionEmployeeUpdate, models.AuditEntityEmployee, employeeID, employee, updatedEmployee)
+	})
+
+	if err != nil {
 		return nil, err
 	}
+	return &updatedEmployee, nil
+}
+
+// Rehire 在一个事务中办理员工复职：把当前的入职、离职日期记为一段已结束的任职期间，
+// 然后将员工恢复为在职，以复职日期作为新的入职日期并清空离职日期。员工业务工号保持不变。
+// 恢复在职以离职状态为条件，并发的复职请求只有一个成功，不会重复记录任职期间
+func (r *gormEmployeeRepository) Rehire(ctx context.Context, employeeID string, rehireDate time.Time) (*models.Employee, error) {
+	var employee, updatedEmployee models.Employee
 
-	// 重新查询更新后的记录并返回
-	// 直接使用之前查询到的 employee 变量的指针，并让 GORM 通过其主键刷新
-	// 或者更可靠地，再次通过 employeeID 查询
-	var updatedEmployee models.Employee
-	if err := r.db.Where("employee_id = ?", employeeID).First(&updatedEmployee).Error; err != nil {
-		return nil, err // 理论上此时应该能找到
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("employee_id = ?", employeeID).First(&employee).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrRecordNotFound
+			}
+			return err
+		}
+
+		result := tx.Model(&models.Employee{}).Where("employee_id = ? AND employment_status = ?", employeeID, "Departed").Updates(map[string]interface{}{
+			"employment_status": "Active",
+			"hire_date":         &rehireDate,
+			"termination_date":  nil,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrEmployeeNotDeparted
+		}
+
+		period := &models.EmploymentPeriod{
+			EmployeeID:      employee.EmployeeID,
+			HireDate:        employee.HireDate,
+			TerminationDate: employee.TerminationDate,
+		}
+		if err := tx.Create(period).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("employee_id = ?", employeeID).First(&updatedEmployee).Error; err != nil {
+			return err
+		}
+
+		return audit.Record(tx, models.AuditActionEmployeeRehire, models.AuditEntityEmployee, employeeID, employee, updatedEmployee)
+	})
+
+	if err != nil {
+		return nil, err
 	}
 	return &updatedEmployee, nil
 }
 
+// WithinTransaction 在一个事务中执行 fn，传给 fn 的员工仓库、号码仓库和预约离职仓库绑定到该事务
+func (r *gormEmployeeRepository) WithinTransaction(ctx context.Context, fn func(employeeRepo EmployeeRepository, mobileNumberRepo MobileNumberRepository, departureRepo ScheduledDepartureRepository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(NewGormEmployeeRepository(tx), NewGormMobileNumberRepository(tx), NewGormScheduledDepartureRepository(tx))
+	})
+}
+
 // FindAllActive 查询所有在职员工
 func (r *gormEmployeeRepository) FindAllActive(ctx context.Context) ([]models.Employee, error) {
 	var employees []models.Employee
@@ -256,10 +349,19 @@ func (r *gormEmployeeRepository) FindAllActive(ctx context.Context) ([]models.Em
 	return employees, nil
 }
 
-// FindActiveByDepartmentNames 查询指定部门名称列表中的所有在职员工
-func (r *gormEmployeeRepository) FindActiveByDepartmentNames(ctx context.Context, departmentNames []string) ([]models.Employee, error) {
+// FindAll 查询所有员工
+func (r *gormEmployeeRepository) FindAll(ctx context.Context) ([]models.Employee, error) {
+	var employees []models.Employee
+	if err := r.db.WithContext(ctx).Order("id ASC").Find(&employees).Error; err != nil {
+		return nil, err
+	}
+	return employees, nil
+}
+
+// FindActiveByDepartmentIDs 查询属于指定部门ID列表的所有在职员工
+func (r *gormEmployeeRepository) FindActiveByDepartmentIDs(ctx context.Context, departmentIDs []uint) ([]models.Employee, error) {
 	var employees []models.Employee
-	if err := r.db.WithContext(ctx).Where("employment_status = ? AND department IN (?)", "Active", departmentNames).Find(&employees).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("employment_status = ? AND department_id IN (?)", "Active", departmentIDs).Find(&employees).Error; err != nil {
 		return nil, err
 	}
 	return employees, nil
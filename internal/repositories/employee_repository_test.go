@@ -0,0 +1,41 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phone_management/internal/models"
+)
+
+func TestRehireRecordsOnePeriod(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &models.Employee{}, &models.EmploymentPeriod{})
+	repo := NewGormEmployeeRepository(db)
+
+	terminationDate := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+	employee := &models.Employee{FullName: "张三", EmploymentStatus: "Departed", TerminationDate: &terminationDate}
+	if err := db.Create(employee).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	rehireDate := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	updated, err := repo.Rehire(ctx, employee.EmployeeID, rehireDate)
+	if err != nil {
+		t.Fatalf("Rehire: %v", err)
+	}
+	if updated.EmploymentStatus != "Active" || updated.TerminationDate != nil {
+		t.Errorf("status = %s, terminationDate = %v, want Active and nil", updated.EmploymentStatus, updated.TerminationDate)
+	}
+
+	// 重复的复职请求不能再记录一段任职期间
+	if _, err := repo.Rehire(ctx, employee.EmployeeID, rehireDate); !errors.Is(err, ErrEmployeeNotDeparted) {
+		t.Fatalf("second Rehire err = %v, want ErrEmployeeNotDeparted", err)
+	}
+	var periods int64
+	db.Model(&models.EmploymentPeriod{}).Where("employee_id = ?", employee.EmployeeID).Count(&periods)
+	if periods != 1 {
+		t.Errorf("employment periods = %d, want 1", periods)
+	}
+}
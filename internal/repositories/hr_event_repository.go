@@ -0,0 +1,138 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/phone_management/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// HREventRepository 定义了 HR 事件处理记录和死信的数据仓库接口
+type HREventRepository interface {
+	// Claim 以唯一的事件ID插入 processing 记录认领事件，返回 true 表示认领成功；
+	// 事件已有处理记录时返回该记录和 false
+	Claim(ctx context.Context, event *models.HREvent) (*models.HREvent, bool, error)
+	// RecordProcessed 在一个事务中将认领的事件标记为处理成功，并将该事件尚未解决的死信标记为已解决
+	RecordProcessed(ctx context.Context, event *models.HREvent) error
+	// RecordFailure 在一个事务中删除事件处理中的认领记录并保存处理失败的事件。
+	// 该事件已有死信时更新事件内容和失败原因、累加失败次数并重新标记为未解决
+	RecordFailure(ctx context.Context, deadLetter *models.HREventDeadLetter) (*models.HREventDeadLetter, error)
+	// FindDeadLetters 按创建时间倒序查询死信，includeResolved 为 false 时只返回尚未解决的死信
+	FindDeadLetters(ctx context.Context, includeResolved bool) ([]models.HREventDeadLetter, error)
+	GetDeadLetter(ctx context.Context, id uint) (*models.HREventDeadLetter, error)
+	// MarkReplayed 记录死信的重放时间
+	MarkReplayed(ctx context.Context, id uint, at time.Time) error
+}
+
+// gormHREventRepository 是 HREventRepository 的 GORM 实现
+type gormHREventRepository struct {
+	db *gorm.DB
+}
+
+// NewGormHREventRepository 创建一个新的 gormHREventRepository 实例
+func NewGormHREventRepository(db *gorm.DB) HREventRepository {
+	return &gormHREventRepository{db: db}
+}
+
+// Claim 认领事件，事件ID已存在时不插入并返回已有的记录
+func (r *gormHREventRepository) Claim(ctx context.Context, event *models.HREvent) (*models.HREvent, bool, error) {
+	event.Status = models.HREventClaimProcessing
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(event)
+	if result.Error != nil {
+		return nil, false, result.Error
+	}
+	if result.RowsAffected > 0 {
+		return event, true, nil
+	}
+
+	var existing models.HREvent
+	if err := r.db.WithContext(ctx).Where("event_id = ?", event.EventID).First(&existing).Error; err != nil {
+		return nil, false, err
+	}
+	return &existing, false, nil
+}
+
+// RecordProcessed 将认领的事件标记为处理成功并解决对应的死信
+func (r *gormHREventRepository) RecordProcessed(ctx context.Context, event *models.HREvent) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		event.Status = models.HREventClaimProcessed
+		if err := tx.Model(&models.HREvent{}).Where("event_id = ?", event.EventID).Updates(map[string]interface{}{
+			"status":       event.Status,
+			"employee_id":  event.EmployeeID,
+			"processed_at": event.ProcessedAt,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.HREventDeadLetter{}).
+			Where("event_id = ? AND resolved_at IS NULL", event.EventID).
+			Update("resolved_at", event.ProcessedAt).Error
+	})
+}
+
+// RecordFailure 新建或更新事件的死信
+func (r *gormHREventRepository) RecordFailure(ctx context.Context, deadLetter *models.HREventDeadLetter) (*models.HREventDeadLetter, error) {
+	var saved models.HREventDeadLetter
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 释放认领，事件排除问题后可以重放或由 HR 系统重新推送
+		if err := tx.Where("event_id = ? AND status = ?", deadLetter.EventID, models.HREventClaimProcessing).Delete(&models.HREvent{}).Error; err != nil {
+			return err
+		}
+		err := tx.Where("event_id = ?", deadLetter.EventID).First(&saved).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			deadLetter.Attempts = 1
+			if err := tx.Create(deadLetter).Error; err != nil {
+				return err
+			}
+			saved = *deadLetter
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&models.HREventDeadLetter{}).Where("id = ?", saved.ID).Updates(map[string]interface{}{
+			"event_type":  deadLetter.EventType,
+			"payload":     deadLetter.Payload,
+			"last_error":  deadLetter.LastError,
+			"attempts":    gorm.Expr("attempts + 1"),
+			"resolved_at": nil,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.First(&saved, saved.ID).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
+
+// FindDeadLetters 查询死信
+func (r *gormHREventRepository) FindDeadLetters(ctx context.Context, includeResolved bool) ([]models.HREventDeadLetter, error) {
+	var deadLetters []models.HREventDeadLetter
+	query := r.db.WithContext(ctx).Order("created_at DESC, id DESC")
+	if !includeResolved {
+		query = query.Where("resolved_at IS NULL")
+	}
+	err := query.Find(&deadLetters).Error
+	return deadLetters, err
+}
+
+// GetDeadLetter 根据ID查询死信
+func (r *gormHREventRepository) GetDeadLetter(ctx context.Context, id uint) (*models.HREventDeadLetter, error) {
+	var deadLetter models.HREventDeadLetter
+	if err := r.db.WithContext(ctx).First(&deadLetter, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &deadLetter, nil
+}
+
+// MarkReplayed 记录死信的重放时间
+func (r *gormHREventRepository) MarkReplayed(ctx context.Context, id uint, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.HREventDeadLetter{}).Where("id = ?", id).Update("replayed_at", at).Error
+}
@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"github.com/phone_management/internal/models"
+	"gorm.io/gorm"
+)
+
+// transitionStatus 在事务中按生命周期流转表将号码变更为目标状态，并执行流转的副作用：
+//   - 进入闲置、待注销、已注销状态时，结束当前使用人的使用历史并清空当前使用人
+//   - 进入待注销状态时要求号码已设置计划注销日期，离开待注销状态时清空计划注销日期
+//   - 进入已注销状态时记录注销日期
+//   - 进入使用中状态时要求号码已有当前使用人
+//   - 进入风险待核实状态时记录原状态，离开时清空，用于办卡人复职时恢复号码
+//
+// 只修改 number 本身，由调用方负责保存。at 为使用历史结束及注销的时间。
+func transitionStatus(tx *gorm.DB, number *models.MobileNumber, to models.NumberStatus, at time.Time) error {
+	if err := models.CheckStatusTransition(models.NumberStatus(number.Status), to); err != nil {
+		return err
+	}
+
+	switch to {
+	case models.StatusInUse:
+		if number.CurrentEmployeeID == nil || *number.CurrentEmployeeID == "" {
+			return models.ErrInUseRequiresCurrentUser
+		}
+	case models.StatusPendingDeactivation:
+		if number.ScheduledDeactivationDate == nil {
+			return models.ErrDeactivationDateRequired
+		}
+		if err := closeActiveUsage(tx, number, at); err != nil {
+			return err
+		}
+	case models.StatusIdle, models.StatusDeactivated:
+		if err := closeActiveUsage(tx, number, at); err != nil {
+			return err
+		}
+	}
+
+	if to != models.StatusPendingDeactivation {
+		number.ScheduledDeactivationDate = nil
+	}
+
+	if to == models.StatusRiskPending {
+		previous := number.Status
+		number.StatusBeforeRisk = &previous
+	} else {
+		number.StatusBeforeRisk = nil
+	}
+
+	if to == models.StatusDeactivated {
+		cancellationDate := at
+		number.CancellationDate = &cancellationDate
+	}
+	number.Status = string(to)
+	return nil
+}
+
+// closeActiveUsage 结束号码当前使用人的使用历史并清空当前使用人，没有使用人时不做处理
+func closeActiveUsage(tx *gorm.DB, number *models.MobileNumber, endDate time.Time) error {
+	if number.CurrentEmployeeID == nil || *number.CurrentEmployeeID == "" {
+		number.CurrentEmployeeID = nil
+		return nil
+	}
+
+	var usageHistory models.NumberUsageHistory
+	if err := tx.Where("mobile_number_db_id = ? AND employee_id = ? AND end_date IS NULL", number.ID, *number.CurrentEmployeeID).
+		Order("start_date DESC").
+		First(&usageHistory).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNoActiveUsageHistoryFound
+		}
+		return err
+	}
+	if err := tx.Model(&usageHistory).Update("end_date", endDate).Error; err != nil {
+		return err
+	}
+
+	number.CurrentEmployeeID = nil
+	return nil
+}
+
+// statusColumns 返回状态流转可能修改的字段，用于按 map 更新号码
+func statusColumns(number *models.MobileNumber) map[string]interface{} {
+	return map[string]interface{}{
+		"status":                      number.Status,
+		"current_employee_id":         number.CurrentEmployeeID,
+		"cancellation_date":           number.CancellationDate,
+		"scheduled_deactivation_date": number.ScheduledDeactivationDate,
+		"status_before_risk":          number.StatusBeforeRisk,
+	}
+}
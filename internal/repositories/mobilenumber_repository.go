@@ -3,9 +3,11 @@ package repositories
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 
+	"github.com/phone_management/internal/audit"
 	"github.com/phone_management/internal/models"
 	"gorm.io/gorm"
 )
@@ -20,21 +22,29 @@ var ErrRecordNotFound = gorm.ErrRecordNotFound
 var ErrMobileNumberNotInIdleStatus = errors.New("手机号码不是闲置状态")
 var ErrEmployeeNotFound = errors.New("员工未找到")
 var ErrEmployeeNotActive = errors.New("员工不是在职状态")
-var ErrMobileNumberNotRecoverable = errors.New("手机号码不允许回收，仅支持在用、风险待核实、用户报告状态的号码回收")
 var ErrNoActiveUsageHistoryFound = errors.New("未找到该号码当前有效的分配记录")
+var ErrMobileNumberNotPendingDeactivation = errors.New("手机号码不是待注销状态")
+var ErrMobileNumberNotInUse = errors.New("手机号码不是使用中状态")
+var ErrTransferToSameEmployee = errors.New("目标员工已是该号码的当前使用人")
+var ErrTransferDateBeforeUsageStart = errors.New("转移日期不能早于当前使用人的开始使用日期")
+var ErrApplicantUnchanged = errors.New("新办卡人与当前办卡人相同")
+var ErrMobileNumberNotRiskPending = errors.New("手机号码不是风险待核实状态")
 
 // MobileNumberRepository 定义了手机号码数据仓库的接口
 type MobileNumberRepository interface {
 	// CreateMobileNumber 的第二个参数 mobileNumber 中已包含 ApplicantEmployeeID (string)
-	CreateMobileNumber(mobileNumber *models.MobileNumber) (*models.MobileNumber, error)
-	GetMobileNumbers(page, limit int, sortBy, sortOrder, search, status, applicantStatus string) ([]models.MobileNumberResponse, int64, error)
-	GetMobileNumberResponseByPhoneNumber(phoneNumber string) (*models.MobileNumberResponse, error)
-	GetMobileNumberByPhoneNumber(phoneNumber string) (*models.MobileNumber, error)
+	CreateMobileNumber(ctx context.Context, mobileNumber *models.MobileNumber) (*models.MobileNumber, error)
+	// 以下查询方法遵循 context 中的部门范围，部门负责人只能查到当前使用人或办卡人属于其负责部门的号码
+	GetMobileNumbers(ctx context.Context, page, limit int, sortBy, sortOrder, search, status, applicantStatus string) ([]models.MobileNumberResponse, int64, error)
+	GetMobileNumberResponseByPhoneNumber(ctx context.Context, phoneNumber string) (*models.MobileNumberResponse, error)
+	GetMobileNumberByPhoneNumber(ctx context.Context, phoneNumber string) (*models.MobileNumber, error)
 	//未来可以扩展其他方法，如 GetByPhoneNumber, Update, Delete 等
-	UpdateMobileNumber(id uint, updates map[string]interface{}) (*models.MobileNumber, error)
+	UpdateMobileNumber(ctx context.Context, id uint, updates map[string]interface{}) (*models.MobileNumber, error)
 	// AssignMobileNumber 的第二个参数 employeeBusinessID 应该是 string (业务工号)
-	AssignMobileNumber(numberID uint, employeeBusinessID string, assignmentDate time.Time, purpose string) (*models.MobileNumber, error)
-	UnassignMobileNumber(numberID uint, reclaimDate time.Time) (*models.MobileNumber, error)
+	AssignMobileNumber(ctx context.Context, numberID uint, employeeBusinessID string, assignmentDate time.Time, purpose string) (*models.MobileNumber, error)
+	UnassignMobileNumber(ctx context.Context, numberID uint, reclaimDate time.Time) (*models.MobileNumber, error)
+	// TransferMobileNumber 在一个事务中将使用中的号码转移给另一名在职员工
+	TransferMobileNumber(ctx context.Context, numberID uint, employeeBusinessID string, effectiveDate time.Time, reason string) (*models.MobileNumber, error)
 	// FindAssignedToEmployee 查询分配给特定员工的手机号码
 	FindAssignedToEmployee(ctx context.Context, employeeID string) ([]models.MobileNumber, error)
 	// FindByApplicantEmployeeID 查询指定员工作为办卡人的手机号码
@@ -42,13 +52,33 @@ type MobileNumberRepository interface {
 	// BatchUpdateStatus 批量更新多个号码的状态
 	BatchUpdateStatus(ctx context.Context, numberIDs []uint, status string) error
 	// GetRiskPendingNumbers 获取风险号码列表
-	GetRiskPendingNumbers(page, limit int, sortBy, sortOrder, search, applicantStatus string) ([]models.RiskNumberResponse, int64, error)
+	GetRiskPendingNumbers(ctx context.Context, page, limit int, sortBy, sortOrder, search, applicantStatus string) ([]models.RiskNumberResponse, int64, error)
 	// HandleRiskNumber 处理风险号码（变更办卡人、回收、注销）
 	HandleRiskNumber(ctx context.Context, phoneNumber string, payload models.HandleRiskNumberPayload, operatorUsername string) (*models.MobileNumber, error)
+	// RestoreFromRisk 将风险号码恢复为进入风险待核实前的状态，用于办卡人复职
+	RestoreFromRisk(ctx context.Context, numberID uint, scheduledDeactivationDate time.Time) (*models.MobileNumber, error)
 	// UpdateLastConfirmationDate 更新号码的最后确认日期
 	UpdateLastConfirmationDate(ctx context.Context, numberID uint) error
-	// MarkAsReportedByUser 将号码标记为用户报告问题
+	// MarkAsReportedByUser 将号码标记为用户报告问题，号码当前状态不能变为用户报告时保持原状态
 	MarkAsReportedByUser(ctx context.Context, numberID uint) error
+	// CancelDeactivation 在宽限期内取消待注销号码的注销计划，号码恢复为闲置状态
+	CancelDeactivation(ctx context.Context, numberID uint) (*models.MobileNumber, error)
+	// GetPendingDeactivations 获取计划注销日期不晚于 until 的待注销号码，按计划注销日期升序
+	GetPendingDeactivations(ctx context.Context, until time.Time) ([]models.MobileNumberResponse, error)
+	// DeactivateDueNumbers 注销计划注销日期不晚于 asOf 的待注销号码，返回成功注销的数量
+	DeactivateDueNumbers(ctx context.Context, asOf time.Time) (int, error)
+	// BackfillDeactivationDates 为没有计划注销日期的待注销号码（早期版本变更为待注销的号码）补充计划注销日期：
+	// 最后更新时间加 graceDays 天，并记录审计事件，返回补充的数量
+	BackfillDeactivationDates(ctx context.Context, graceDays int) (int, error)
+	// FindByIDs 根据ID批量查询号码，包含已删除的号码，用于展示历史记录
+	FindByIDs(ctx context.Context, ids []uint) ([]models.MobileNumber, error)
+	// FindPhoneNumbersByFilter 按列表筛选条件查询全部匹配的手机号码（包括风险号码），按手机号升序，遵循 context 中的部门范围
+	FindPhoneNumbersByFilter(ctx context.Context, search, status, applicantStatus string) ([]string, error)
+	// ChangeApplicant 变更号码办卡人并记录变更历史，号码状态不变
+	ChangeApplicant(ctx context.Context, numberID uint, newApplicantEmployeeID, remarks, operatorUsername string) (*models.MobileNumber, error)
+	// WithinTransaction 在一个事务中执行 fn，fn 通过传入的仓库执行的操作都在该事务内，
+	// 仓库方法自身的事务以保存点嵌套执行。fn 返回错误时整个事务回滚
+	WithinTransaction(ctx context.Context, fn func(repo MobileNumberRepository) error) error
 	FindByVerificationBatchTaskId(ctx context.Context, batchTaskId string) ([]models.MobileNumber, error)
 	FindConfirmedNumberIdsByTokenId(ctx context.Context, tokenId uint) ([]uint, error)
 }
@@ -71,16 +101,22 @@ func NewGormMobileNumberRepository(db *gorm.DB) MobileNumberRepository {
 
 // CreateMobileNumber 在数据库中创建一个新的手机号码记录
 // mobileNumber.ApplicantEmployeeID (string) 已经在模型中设置
-func (r *gormMobileNumberRepository) CreateMobileNumber(mobileNumber *models.MobileNumber) (*models.MobileNumber, error) {
+func (r *gormMobileNumberRepository) CreateMobileNumber(ctx context.Context, mobileNumber *models.MobileNumber) (*models.MobileNumber, error) {
 	var existing models.MobileNumber
 	// 检查 phone_number 字符串是否已作为记录存在
-	if err := r.db.Where("phone_number = ?", mobileNumber.PhoneNumber).First(&existing).Error; err == nil {
+	if err := r.db.WithContext(ctx).Where("phone_number = ?", mobileNumber.PhoneNumber).First(&existing).Error; err == nil {
 		return nil, ErrMobileNumberStringConflict // 使用新的错误变量名
 	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, err
 	}
 
-	if err := r.db.Create(mobileNumber).Error; err != nil {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(mobileNumber).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, models.AuditActionMobileNumberCreate, models.AuditEntityMobileNumber, mobileNumber.PhoneNumber, nil, mobileNumber)
+	})
+	if err != nil {
 		if strings.Contains(strings.ToLower(err.Error()), "unique constraint") || strings.Contains(strings.ToLower(err.Error()), "duplicate key") {
 			if strings.Contains(err.Error(), models.MobileNumber{}.TableName()+".phone_number") || strings.Contains(err.Error(), "MobileNumbers.phone_number") {
 				return nil, ErrMobileNumberStringConflict // 使用新的错误变量名
@@ -92,15 +128,16 @@ func (r *gormMobileNumberRepository) CreateMobileNumber(mobileNumber *models.Mob
 }
 
 // GetMobileNumbers 从数据库中获取手机号码列表，支持分页、排序、搜索和筛选
-func (r *gormMobileNumberRepository) GetMobileNumbers(page, limit int, sortBy, sortOrder, search, status, applicantStatus string) ([]models.MobileNumberResponse, int64, error) {
+func (r *gormMobileNumberRepository) GetMobileNumbers(ctx context.Context, page, limit int, sortBy, sortOrder, search, status, applicantStatus string) ([]models.MobileNumberResponse, int64, error) {
 	var mobileNumbers []models.MobileNumberResponse
 	var totalItems int64
 
 	// 基础查询构建器 (不包含 SELECT specific to response, or ORDER/LIMIT/OFFSET yet)
-	queryBuilder := r.db.Model(&models.MobileNumber{}).
+	queryBuilder := r.db.WithContext(ctx).Model(&models.MobileNumber{}).
 		Joins("LEFT JOIN employees AS applicant ON applicant.employee_id = mobile_numbers.applicant_employee_id").
 		Joins("LEFT JOIN employees AS current_user ON current_user.employee_id = mobile_numbers.current_employee_id").
 		Where("mobile_numbers.status != ?", string(models.StatusRiskPending)) // 排除风险号码
+	queryBuilder = scopeMobileNumbers(ctx, queryBuilder, "mobile_numbers")
 
 	// 应用可选的过滤条件
 	if search != "" {
@@ -133,6 +170,7 @@ func (r *gormMobileNumberRepository) GetMobileNumbers(page, limit int, sortBy, s
 		"mobile_numbers.vendor AS vendor",
 		"mobile_numbers.remarks AS remarks",
 		"mobile_numbers.cancellation_date AS cancellation_date",
+		"mobile_numbers.scheduled_deactivation_date AS scheduled_deactivation_date",
 		"mobile_numbers.created_at AS created_at",
 		"mobile_numbers.updated_at AS updated_at",
 	}
@@ -179,10 +217,10 @@ func (r *gormMobileNumberRepository) GetMobileNumbers(page, limit int, sortBy, s
 }
 
 // GetMobileNumberResponseByPhoneNumber 根据手机号码字符串查询手机号码详细信息，包括关联数据
-func (r *gormMobileNumberRepository) GetMobileNumberResponseByPhoneNumber(phoneNumber string) (*models.MobileNumberResponse, error) {
+func (r *gormMobileNumberRepository) GetMobileNumberResponseByPhoneNumber(ctx context.Context, phoneNumber string) (*models.MobileNumberResponse, error) {
 	var mobileNumberDetail models.MobileNumberResponse
 
-	tx := r.db.Model(&models.MobileNumber{}).
+	tx := scopeMobileNumbers(ctx, r.db.WithContext(ctx), "mobile_numbers").Model(&models.MobileNumber{}).
 		Select(
 			"mobile_numbers.id AS id",
 			"mobile_numbers.phone_number AS phone_number",
@@ -196,6 +234,7 @@ func (r *gormMobileNumberRepository) GetMobileNumberResponseByPhoneNumber(phoneN
 			"mobile_numbers.vendor AS vendor",
 			"mobile_numbers.remarks AS remarks",
 			"mobile_numbers.cancellation_date AS cancellation_date",
+			"mobile_numbers.scheduled_deactivation_date AS scheduled_deactivation_date",
 			"mobile_numbers.created_at AS created_at",
 			"mobile_numbers.updated_at AS updated_at",
 		).
@@ -212,7 +251,7 @@ func (r *gormMobileNumberRepository) GetMobileNumberResponseByPhoneNumber(phoneN
 	}
 
 	// 加载使用历史
-	histories, err := r.usageHistoryRepo.GetByMobileNumberID(context.Background(), mobileNumberDetail.ID)
+	histories, err := r.usageHistoryRepo.GetByMobileNumberID(ctx, mobileNumberDetail.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -224,9 +263,10 @@ func (r *gormMobileNumberRepository) GetMobileNumberResponseByPhoneNumber(phoneN
 // GetMobileNumberByPhoneNumber 根据手机号码字符串查询手机号码信息
 // 注意：这里返回 *models.MobileNumber 而不是 *models.MobileNumberResponse
 // 因为服务层可能需要原始模型对象进行操作，例如获取其数据库ID
-func (r *gormMobileNumberRepository) GetMobileNumberByPhoneNumber(phoneNumber string) (*models.MobileNumber, error) {
+func (r *gormMobileNumberRepository) GetMobileNumberByPhoneNumber(ctx context.Context, phoneNumber string) (*models.MobileNumber, error) {
 	var mobileNumber models.MobileNumber
-	if err := r.db.Where("phone_number = ?", phoneNumber).First(&mobileNumber).Error; err != nil {
+	query := scopeMobileNumbers(ctx, r.db.WithContext(ctx), "mobile_numbers")
+	if err := query.Where("phone_number = ?", phoneNumber).First(&mobileNumber).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrRecordNotFound // 使用仓库已定义的错误
 		}
@@ -237,36 +277,62 @@ func (r *gormMobileNumberRepository) GetMobileNumberByPhoneNumber(phoneNumber st
 
 // UpdateMobileNumber 更新数据库中指定ID的手机号码信息
 // updates 是一个包含要更新字段及其新值的 map
-func (r *gormMobileNumberRepository) UpdateMobileNumber(id uint, updates map[string]interface{}) (*models.MobileNumber, error) {
-	var mobileNumber models.MobileNumber
-	// 首先，检查记录是否存在
-	if err := r.db.First(&mobileNumber, id).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, ErrRecordNotFound
+func (r *gormMobileNumberRepository) UpdateMobileNumber(ctx context.Context, id uint, updates map[string]interface{}) (*models.MobileNumber, error) {
+	var before, mobileNumber models.MobileNumber
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 首先，检查记录是否存在
+		if err := tx.First(&before, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrRecordNotFound
+			}
+			return err
 		}
-		return nil, err
-	}
 
-	// 更新记录
-	// 使用 Model(&models.MobileNumber{}) 指定模型，并通过 Where 更新特定ID的记录
-	if err := r.db.Model(&models.MobileNumber{}).Where("id = ?", id).Updates(updates).Error; err != nil {
-		return nil, err
-	}
+		// 状态变更按生命周期流转表校验，并一并写入流转的副作用
+		if status, ok := updates["status"].(string); ok && status != before.Status {
+			number := before
+			if date, ok := updates["scheduled_deactivation_date"].(time.Time); ok {
+				number.ScheduledDeactivationDate = &date
+			}
+			if err := transitionStatus(tx, &number, models.NumberStatus(status), time.Now()); err != nil {
+				return err
+			}
+			merged := statusColumns(&number)
+			for column, value := range updates {
+				if _, isStatusColumn := merged[column]; !isStatusColumn {
+					merged[column] = value
+				}
+			}
+			updates = merged
+		}
 
-	// 重新查询更新后的记录并返回
-	if err := r.db.First(&mobileNumber, id).Error; err != nil {
-		return nil, err // 理论上此时应该能找到
-	}
+		// 更新记录
+		// 使用 Model(&models.MobileNumber{}) 指定模型，并通过 Where 更新特定ID的记录
+		if err := tx.Model(&models.MobileNumber{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		// 重新查询更新后的记录并返回
+		if err := tx.First(&mobileNumber, id).Error; err != nil {
+			return err // 理论上此时应该能找到
+		}
 
+		return audit.Record(tx, models.AuditActionMobileNumberUpdate, models.AuditEntityMobileNumber, mobileNumber.PhoneNumber, before, mobileNumber)
+	})
+
+	if err != nil {
+		return nil, err
+	}
 	return &mobileNumber, nil
 }
 
 // AssignMobileNumber 将手机号码分配给员工 (employeeID 为业务工号)
-func (r *gormMobileNumberRepository) AssignMobileNumber(numberID uint, employeeBusinessID string, assignmentDate time.Time, purpose string) (*models.MobileNumber, error) {
+func (r *gormMobileNumberRepository) AssignMobileNumber(ctx context.Context, numberID uint, employeeBusinessID string, assignmentDate time.Time, purpose string) (*models.MobileNumber, error) {
 	var mobileNumber models.MobileNumber
 	var employee models.Employee // 用于校验员工状态
 
-	err := r.db.Transaction(func(tx *gorm.DB) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		if err := tx.Set("gorm:query_option", "FOR UPDATE").First(&mobileNumber, numberID).Error; err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
 				return ErrRecordNotFound
@@ -290,8 +356,11 @@ func (r *gormMobileNumberRepository) AssignMobileNumber(numberID uint, employeeB
 			return ErrEmployeeNotActive
 		}
 
+		before := mobileNumber
 		mobileNumber.CurrentEmployeeID = &employeeBusinessID // 直接存储业务工号
-		mobileNumber.Status = string(models.StatusInUse)
+		if err := transitionStatus(tx, &mobileNumber, models.StatusInUse, assignmentDate); err != nil {
+			return err
+		}
 		mobileNumber.Purpose = &purpose // 设置用途字段
 		if err := tx.Save(&mobileNumber).Error; err != nil {
 			return err
@@ -307,7 +376,7 @@ func (r *gormMobileNumberRepository) AssignMobileNumber(numberID uint, employeeB
 			return err
 		}
 
-		return nil
+		return audit.Record(tx, models.AuditActionMobileNumberAssign, models.AuditEntityMobileNumber, mobileNumber.PhoneNumber, before, mobileNumber)
 	})
 
 	if err != nil {
@@ -317,10 +386,10 @@ func (r *gormMobileNumberRepository) AssignMobileNumber(numberID uint, employeeB
 }
 
 // UnassignMobileNumber 从当前使用人处回收手机号码
-func (r *gormMobileNumberRepository) UnassignMobileNumber(numberID uint, reclaimDate time.Time) (*models.MobileNumber, error) {
+func (r *gormMobileNumberRepository) UnassignMobileNumber(ctx context.Context, numberID uint, reclaimDate time.Time) (*models.MobileNumber, error) {
 	var mobileNumber models.MobileNumber
 
-	err := r.db.Transaction(func(tx *gorm.DB) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		if err := tx.Set("gorm:query_option", "FOR UPDATE").First(&mobileNumber, numberID).Error; err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
 				return ErrRecordNotFound
@@ -328,51 +397,90 @@ func (r *gormMobileNumberRepository) UnassignMobileNumber(numberID uint, reclaim
 			return err
 		}
 
-		// 检查号码状态是否允许回收（支持：in_use, risk_pending, user_reported）
-		allowedStatuses := []string{
-			string(models.StatusInUse),
-			string(models.StatusRiskPending),
-			string(models.StatusUserReport),
+		// 回收即流转为闲置状态，由流转表决定哪些状态允许回收，并结束当前使用人的使用历史
+		before := mobileNumber
+		if err := transitionStatus(tx, &mobileNumber, models.StatusIdle, reclaimDate); err != nil {
+			return err
 		}
+		if err := tx.Save(&mobileNumber).Error; err != nil {
+			return err
+		}
+
+		return audit.Record(tx, models.AuditActionMobileNumberUnassign, models.AuditEntityMobileNumber, mobileNumber.PhoneNumber, before, mobileNumber)
+	})
 
-		statusAllowed := false
-		for _, allowedStatus := range allowedStatuses {
-			if mobileNumber.Status == allowedStatus {
-				statusAllowed = true
-				break
+	if err != nil {
+		return nil, err
+	}
+	return &mobileNumber, nil
+}
+
+// TransferMobileNumber 在一个事务中将使用中的号码从当前使用人转移给另一名在职员工：
+// 在 effectiveDate 结束原使用人的使用历史，并从同一时间开始新使用人的使用历史，号码保持使用中状态。
+// reason 记录在新使用人的使用历史中
+func (r *gormMobileNumberRepository) TransferMobileNumber(ctx context.Context, numberID uint, employeeBusinessID string, effectiveDate time.Time, reason string) (*models.MobileNumber, error) {
+	var mobileNumber models.MobileNumber
+	var employee models.Employee
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Set("gorm:query_option", "FOR UPDATE").First(&mobileNumber, numberID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrRecordNotFound
 			}
+			return err
 		}
 
-		if !statusAllowed {
-			return ErrMobileNumberNotRecoverable
+		if mobileNumber.Status != string(models.StatusInUse) || mobileNumber.CurrentEmployeeID == nil {
+			return ErrMobileNumberNotInUse
+		}
+		if *mobileNumber.CurrentEmployeeID == employeeBusinessID {
+			return ErrTransferToSameEmployee
 		}
 
-		// 对于有当前使用人的号码，需要更新使用历史
-		if mobileNumber.CurrentEmployeeID != nil && *mobileNumber.CurrentEmployeeID != "" {
-			// 查找当前有效的使用历史记录并更新结束时间
-			var usageHistory models.NumberUsageHistory
-			if err := tx.Where("mobile_number_db_id = ? AND employee_id = ? AND end_date IS NULL", numberID, *mobileNumber.CurrentEmployeeID).
-				Order("start_date DESC").
-				First(&usageHistory).Error; err != nil {
-				if errors.Is(err, gorm.ErrRecordNotFound) {
-					return ErrNoActiveUsageHistoryFound
-				}
-				return err
+		if err := tx.Where("employee_id = ?", employeeBusinessID).First(&employee).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrEmployeeNotFound
 			}
+			return err
+		}
+		if employee.EmploymentStatus != "Active" {
+			return ErrEmployeeNotActive
+		}
 
-			// 更新使用历史的结束时间
-			if err := tx.Model(&usageHistory).Update("end_date", reclaimDate).Error; err != nil {
-				return err
+		// 结束原使用人的使用历史，转移日期不能早于其开始使用日期
+		var currentUsage models.NumberUsageHistory
+		if err := tx.Where("mobile_number_db_id = ? AND employee_id = ? AND end_date IS NULL", mobileNumber.ID, *mobileNumber.CurrentEmployeeID).
+			Order("start_date DESC").
+			First(&currentUsage).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrNoActiveUsageHistoryFound
 			}
+			return err
+		}
+		if effectiveDate.Before(currentUsage.StartDate) {
+			return ErrTransferDateBeforeUsageStart
+		}
+		if err := tx.Model(&currentUsage).Update("end_date", effectiveDate).Error; err != nil {
+			return err
 		}
 
-		mobileNumber.CurrentEmployeeID = nil // 清空业务工号
-		mobileNumber.Status = string(models.StatusIdle)
+		before := mobileNumber
+		mobileNumber.CurrentEmployeeID = &employeeBusinessID
 		if err := tx.Save(&mobileNumber).Error; err != nil {
 			return err
 		}
 
-		return nil
+		usageHistory := models.NumberUsageHistory{
+			MobileNumberDbID: int64(numberID),
+			EmployeeID:       employeeBusinessID,
+			StartDate:        effectiveDate,
+			Remarks:          reason,
+		}
+		if err := tx.Create(&usageHistory).Error; err != nil {
+			return err
+		}
+
+		return audit.Record(tx, models.AuditActionMobileNumberTransfer, models.AuditEntityMobileNumber, mobileNumber.PhoneNumber, before, mobileNumber)
 	})
 
 	if err != nil {
@@ -401,21 +509,139 @@ func (r *gormMobileNumberRepository) FindByApplicantEmployeeID(ctx context.Conte
 	return numbers, nil
 }
 
+// FindByIDs 根据ID批量查询号码，包含已删除的号码，用于展示历史记录
+func (r *gormMobileNumberRepository) FindByIDs(ctx context.Context, ids []uint) ([]models.MobileNumber, error) {
+	var numbers []models.MobileNumber
+	if len(ids) == 0 {
+		return numbers, nil
+	}
+	err := r.db.WithContext(ctx).Unscoped().Where("id IN ?", ids).Find(&numbers).Error
+	return numbers, err
+}
+
+// FindPhoneNumbersByFilter 按列表筛选条件查询全部匹配的手机号码（包括风险号码），按手机号升序
+func (r *gormMobileNumberRepository) FindPhoneNumbersByFilter(ctx context.Context, search, status, applicantStatus string) ([]string, error) {
+	queryBuilder := scopeMobileNumbers(ctx, r.db.WithContext(ctx), "mobile_numbers").Model(&models.MobileNumber{}).
+		Joins("LEFT JOIN employees AS applicant ON applicant.employee_id = mobile_numbers.applicant_employee_id").
+		Joins("LEFT JOIN employees AS current_user ON current_user.employee_id = mobile_numbers.current_employee_id")
+
+	if search != "" {
+		searchTerm := "%" + search + "%"
+		queryBuilder = queryBuilder.Where("mobile_numbers.phone_number LIKE ? OR applicant.full_name LIKE ? OR current_user.full_name LIKE ?", searchTerm, searchTerm, searchTerm)
+	}
+	if status != "" {
+		queryBuilder = queryBuilder.Where("mobile_numbers.status = ?", status)
+	}
+	if applicantStatus != "" {
+		queryBuilder = queryBuilder.Where("applicant.employment_status = ?", applicantStatus)
+	}
+
+	var phoneNumbers []string
+	err := queryBuilder.Order("mobile_numbers.phone_number asc").Pluck("mobile_numbers.phone_number", &phoneNumbers).Error
+	return phoneNumbers, err
+}
+
+// ChangeApplicant 变更号码办卡人并记录办卡人变更历史，新办卡人必须在职。
+// 风险号码的办卡人变更应通过 HandleRiskNumber 处理，以便同时恢复号码状态
+func (r *gormMobileNumberRepository) ChangeApplicant(ctx context.Context, numberID uint, newApplicantEmployeeID, remarks, operatorUsername string) (*models.MobileNumber, error) {
+	var mobileNumber models.MobileNumber
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Set("gorm:query_option", "FOR UPDATE").First(&mobileNumber, numberID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrRecordNotFound
+			}
+			return err
+		}
+		if mobileNumber.ApplicantEmployeeID == newApplicantEmployeeID {
+			return ErrApplicantUnchanged
+		}
+
+		var newApplicant models.Employee
+		if err := tx.Where("employee_id = ?", newApplicantEmployeeID).First(&newApplicant).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrEmployeeNotFound
+			}
+			return err
+		}
+		if newApplicant.EmploymentStatus != "Active" {
+			return ErrEmployeeNotActive
+		}
+
+		history := &models.NumberApplicantHistory{
+			MobileNumberDbID:    mobileNumber.ID,
+			PreviousApplicantID: mobileNumber.ApplicantEmployeeID,
+			NewApplicantID:      newApplicantEmployeeID,
+			ChangeDate:          time.Now(),
+			OperatorUsername:    &operatorUsername,
+			Remarks:             remarks,
+		}
+		if err := tx.Create(history).Error; err != nil {
+			return err
+		}
+
+		before := mobileNumber
+		mobileNumber.ApplicantEmployeeID = newApplicantEmployeeID
+		if err := tx.Save(&mobileNumber).Error; err != nil {
+			return err
+		}
+
+		return audit.Record(tx, models.AuditActionMobileNumberChangeApplicant, models.AuditEntityMobileNumber, mobileNumber.PhoneNumber, before, mobileNumber)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return &mobileNumber, nil
+}
+
+// WithinTransaction 在一个事务中执行 fn，传给 fn 的仓库绑定到该事务
+func (r *gormMobileNumberRepository) WithinTransaction(ctx context.Context, fn func(repo MobileNumberRepository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&gormMobileNumberRepository{
+			db:                   tx,
+			applicantHistoryRepo: NewGormNumberApplicantHistoryRepository(tx),
+			usageHistoryRepo:     NewGormNumberUsageHistoryRepository(tx),
+		})
+	})
+}
+
 // BatchUpdateStatus 批量更新多个号码的状态
+// 每个号码都按生命周期流转表校验，任一号码不允许流转时整体回滚。每个号码各记录一条审计事件
 func (r *gormMobileNumberRepository) BatchUpdateStatus(ctx context.Context, numberIDs []uint, status string) error {
-	return r.db.WithContext(ctx).Model(&models.MobileNumber{}).Where("id IN ?", numberIDs).Update("status", status).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var numbers []models.MobileNumber
+		if err := tx.Where("id IN ?", numberIDs).Find(&numbers).Error; err != nil {
+			return err
+		}
+		now := time.Now()
+		for _, before := range numbers {
+			after := before
+			if err := transitionStatus(tx, &after, models.NumberStatus(status), now); err != nil {
+				return err
+			}
+			if err := tx.Save(&after).Error; err != nil {
+				return err
+			}
+			if err := audit.Record(tx, models.AuditActionMobileNumberStatusChange, models.AuditEntityMobileNumber, before.PhoneNumber, before, after); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
 // GetRiskPendingNumbers 获取风险号码列表
-func (r *gormMobileNumberRepository) GetRiskPendingNumbers(page, limit int, sortBy, sortOrder, search, applicantStatus string) ([]models.RiskNumberResponse, int64, error) {
+func (r *gormMobileNumberRepository) GetRiskPendingNumbers(ctx context.Context, page, limit int, sortBy, sortOrder, search, applicantStatus string) ([]models.RiskNumberResponse, int64, error) {
 	var riskNumbers []models.RiskNumberResponse
 	var totalItems int64
 
 	// 基础查询构建器，只查询 risk_pending 状态的号码
-	queryBuilder := r.db.Model(&models.MobileNumber{}).
+	queryBuilder := r.db.WithContext(ctx).Model(&models.MobileNumber{}).
 		Joins("LEFT JOIN employees AS applicant ON applicant.employee_id = mobile_numbers.applicant_employee_id").
 		Joins("LEFT JOIN employees AS current_user ON current_user.employee_id = mobile_numbers.current_employee_id").
 		Where("mobile_numbers.status = ?", string(models.StatusRiskPending))
+	queryBuilder = scopeMobileNumbers(ctx, queryBuilder, "mobile_numbers")
 
 	// 应用可选的过滤条件
 	if search != "" {
@@ -517,6 +743,7 @@ func (r *gormMobileNumberRepository) HandleRiskNumber(ctx context.Context, phone
 		if mobileNumber.Status != string(models.StatusRiskPending) {
 			return errors.New("只能处理状态为 risk_pending 的号码")
 		}
+		before := mobileNumber
 
 		switch payload.Action {
 		case string(models.ActionChangeApplicant):
@@ -552,25 +779,26 @@ func (r *gormMobileNumberRepository) HandleRiskNumber(ctx context.Context, phone
 
 			// 更新号码办卡人并恢复为正常状态
 			mobileNumber.ApplicantEmployeeID = *payload.NewApplicantEmployeeID
-			// 如果号码当前有使用人，保持 in_use 状态，否则设为 idle
+			// 如果号码当前有使用人，恢复为 in_use 状态，否则设为 idle
+			next := models.StatusIdle
 			if mobileNumber.CurrentEmployeeID != nil {
-				mobileNumber.Status = string(models.StatusInUse)
-			} else {
-				mobileNumber.Status = string(models.StatusIdle)
+				next = models.StatusInUse
+			}
+			if err := transitionStatus(tx, &mobileNumber, next, time.Now()); err != nil {
+				return err
 			}
 
 		case string(models.ActionReclaim):
-			// 回收号码，设为闲置状态
-			mobileNumber.Status = string(models.StatusIdle)
-			mobileNumber.CurrentEmployeeID = nil // 清空当前使用人
+			// 回收号码，设为闲置状态并结束当前使用人的使用历史
+			if err := transitionStatus(tx, &mobileNumber, models.StatusIdle, time.Now()); err != nil {
+				return err
+			}
 
 		case string(models.ActionDeactivate):
-			// 注销号码
-			mobileNumber.Status = string(models.StatusDeactivated)
-			mobileNumber.CancellationDate = &time.Time{}
-			now := time.Now()
-			mobileNumber.CancellationDate = &now
-			mobileNumber.CurrentEmployeeID = nil // 清空当前使用人
+			// 注销号码，记录注销日期并结束当前使用人的使用历史
+			if err := transitionStatus(tx, &mobileNumber, models.StatusDeactivated, time.Now()); err != nil {
+				return err
+			}
 
 		default:
 			return errors.New("无效的操作类型")
@@ -581,7 +809,44 @@ func (r *gormMobileNumberRepository) HandleRiskNumber(ctx context.Context, phone
 			return err
 		}
 
-		return nil
+		return audit.Record(tx, models.AuditActionMobileNumberHandleRisk, models.AuditEntityMobileNumber, mobileNumber.PhoneNumber, before, mobileNumber)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return &mobileNumber, nil
+}
+
+// RestoreFromRisk 将风险号码恢复为 models.RiskRestoreStatus 给出的状态，办卡人不变。
+// 原状态为待注销时，风险期间已清空的计划注销日期以 scheduledDeactivationDate 重新设置
+func (r *gormMobileNumberRepository) RestoreFromRisk(ctx context.Context, numberID uint, scheduledDeactivationDate time.Time) (*models.MobileNumber, error) {
+	var mobileNumber models.MobileNumber
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Set("gorm:query_option", "FOR UPDATE").First(&mobileNumber, numberID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrRecordNotFound
+			}
+			return err
+		}
+		if mobileNumber.Status != string(models.StatusRiskPending) {
+			return ErrMobileNumberNotRiskPending
+		}
+
+		before := mobileNumber
+		next := models.RiskRestoreStatus(&mobileNumber)
+		if next == models.StatusPendingDeactivation {
+			mobileNumber.ScheduledDeactivationDate = &scheduledDeactivationDate
+		}
+		if err := transitionStatus(tx, &mobileNumber, next, time.Now()); err != nil {
+			return err
+		}
+		if err := tx.Save(&mobileNumber).Error; err != nil {
+			return err
+		}
+
+		return audit.Record(tx, models.AuditActionMobileNumberRestoreRisk, models.AuditEntityMobileNumber, mobileNumber.PhoneNumber, before, mobileNumber)
 	})
 
 	if err != nil {
@@ -600,10 +865,172 @@ func (r *gormMobileNumberRepository) UpdateLastConfirmationDate(ctx context.Cont
 
 // MarkAsReportedByUser 将号码标记为用户报告问题
 func (r *gormMobileNumberRepository) MarkAsReportedByUser(ctx context.Context, numberID uint) error {
-	return r.db.WithContext(ctx).Model(&models.MobileNumber{}).
-		Where("id = ?", numberID).
-		Update("status", string(models.StatusUserReport)).
-		Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var before models.MobileNumber
+		if err := tx.First(&before, numberID).Error; err != nil {
+			return err
+		}
+		// 号码不能进入用户报告状态时（已是用户报告、正在按办卡人离职处理的风险号码，或确认通知发出后号码已闲置、待注销或注销），
+		// 保持原状态；问题报告本身由调用方另行记录，员工的提交不因号码状态变化而失败
+		if !models.CanTransition(models.NumberStatus(before.Status), models.StatusUserReport) {
+			return nil
+		}
+		after := before
+		if err := transitionStatus(tx, &after, models.StatusUserReport, time.Now()); err != nil {
+			return err
+		}
+		if err := tx.Model(&models.MobileNumber{}).
+			Where("id = ?", numberID).
+			Update("status", after.Status).
+			Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, models.AuditActionMobileNumberUserReport, models.AuditEntityMobileNumber, before.PhoneNumber, before, after)
+	})
+}
+
+// CancelDeactivation 在宽限期内取消待注销号码的注销计划，号码恢复为闲置状态并清空计划注销日期
+func (r *gormMobileNumberRepository) CancelDeactivation(ctx context.Context, numberID uint) (*models.MobileNumber, error) {
+	var mobileNumber models.MobileNumber
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Set("gorm:query_option", "FOR UPDATE").First(&mobileNumber, numberID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrRecordNotFound
+			}
+			return err
+		}
+		if mobileNumber.Status != string(models.StatusPendingDeactivation) {
+			return ErrMobileNumberNotPendingDeactivation
+		}
+
+		before := mobileNumber
+		if err := transitionStatus(tx, &mobileNumber, models.StatusIdle, time.Now()); err != nil {
+			return err
+		}
+		if err := tx.Save(&mobileNumber).Error; err != nil {
+			return err
+		}
+
+		return audit.Record(tx, models.AuditActionMobileNumberCancelDeactivation, models.AuditEntityMobileNumber, mobileNumber.PhoneNumber, before, mobileNumber)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return &mobileNumber, nil
+}
+
+// GetPendingDeactivations 获取计划注销日期不晚于 until 的待注销号码（包括已到期但尚未被定时任务处理的号码）
+func (r *gormMobileNumberRepository) GetPendingDeactivations(ctx context.Context, until time.Time) ([]models.MobileNumberResponse, error) {
+	var numbers []models.MobileNumberResponse
+
+	err := scopeMobileNumbers(ctx, r.db.WithContext(ctx), "mobile_numbers").Model(&models.MobileNumber{}).
+		Select(
+			"mobile_numbers.id AS id",
+			"mobile_numbers.phone_number AS phone_number",
+			"mobile_numbers.applicant_employee_id AS applicant_employee_id",
+			"applicant.full_name AS applicant_name",
+			"applicant.employment_status AS applicant_status",
+			"mobile_numbers.application_date AS application_date",
+			"mobile_numbers.status AS status",
+			"mobile_numbers.purpose AS purpose",
+			"mobile_numbers.vendor AS vendor",
+			"mobile_numbers.remarks AS remarks",
+			"mobile_numbers.scheduled_deactivation_date AS scheduled_deactivation_date",
+			"mobile_numbers.created_at AS created_at",
+			"mobile_numbers.updated_at AS updated_at",
+		).
+		Joins("LEFT JOIN employees AS applicant ON applicant.employee_id = mobile_numbers.applicant_employee_id").
+		Where("mobile_numbers.status = ?", models.StatusPendingDeactivation).
+		Where("mobile_numbers.scheduled_deactivation_date <= ?", until).
+		Order("mobile_numbers.scheduled_deactivation_date ASC, mobile_numbers.phone_number ASC").
+		Scan(&numbers).Error
+	if err != nil {
+		return nil, err
+	}
+	return numbers, nil
+}
+
+// DeactivateDueNumbers 注销计划注销日期不晚于 asOf 的待注销号码，注销日期记为计划注销日期。
+// 每个号码在独立事务中处理，单个号码失败不影响其他号码，所有失败汇总后一并返回。
+func (r *gormMobileNumberRepository) DeactivateDueNumbers(ctx context.Context, asOf time.Time) (int, error) {
+	var dueNumbers []models.MobileNumber
+	if err := r.db.WithContext(ctx).
+		Where("status = ? AND scheduled_deactivation_date <= ?", models.StatusPendingDeactivation, asOf).
+		Find(&dueNumbers).Error; err != nil {
+		return 0, err
+	}
+
+	deactivated := 0
+	var errs []error
+	for _, due := range dueNumbers {
+		skipped := false
+		err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			// 在事务中重新读取号码：查询之后已被取消注销或修改了计划注销日期的号码不再处理
+			var before models.MobileNumber
+			if err := tx.Set("gorm:query_option", "FOR UPDATE").
+				Where("id = ? AND status = ? AND scheduled_deactivation_date <= ?", due.ID, models.StatusPendingDeactivation, asOf).
+				First(&before).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					skipped = true
+					return nil
+				}
+				return err
+			}
+			after := before
+			if err := transitionStatus(tx, &after, models.StatusDeactivated, *before.ScheduledDeactivationDate); err != nil {
+				return err
+			}
+			if err := tx.Model(&models.MobileNumber{}).Where("id = ?", before.ID).Updates(statusColumns(&after)).Error; err != nil {
+				return err
+			}
+			return audit.Record(tx, models.AuditActionMobileNumberScheduledDeactivate, models.AuditEntityMobileNumber, after.PhoneNumber, before, after)
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("注销号码 %s 失败: %w", due.PhoneNumber, err))
+			continue
+		}
+		if !skipped {
+			deactivated++
+		}
+	}
+	return deactivated, errors.Join(errs...)
+}
+
+// BackfillDeactivationDates 为缺少计划注销日期的待注销号码补充日期，使其能被列出并按期自动注销
+func (r *gormMobileNumberRepository) BackfillDeactivationDates(ctx context.Context, graceDays int) (int, error) {
+	var numbers []models.MobileNumber
+	if err := r.db.WithContext(ctx).
+		Where("status = ? AND scheduled_deactivation_date IS NULL", models.StatusPendingDeactivation).
+		Find(&numbers).Error; err != nil {
+		return 0, err
+	}
+
+	backfilled := 0
+	for _, number := range numbers {
+		scheduledDate := number.UpdatedAt.UTC().Truncate(24*time.Hour).AddDate(0, 0, graceDays)
+		updated := false
+		err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			result := tx.Model(&models.MobileNumber{}).
+				Where("id = ? AND status = ? AND scheduled_deactivation_date IS NULL", number.ID, models.StatusPendingDeactivation).
+				UpdateColumn("scheduled_deactivation_date", scheduledDate)
+			if result.Error != nil || result.RowsAffected == 0 {
+				return result.Error
+			}
+			updated = true
+			after := number
+			after.ScheduledDeactivationDate = &scheduledDate
+			return audit.Record(tx, models.AuditActionMobileNumberUpdate, models.AuditEntityMobileNumber, number.PhoneNumber, number, after)
+		})
+		if err != nil {
+			return backfilled, fmt.Errorf("补充号码 %s 的计划注销日期失败: %w", number.PhoneNumber, err)
+		}
+		if updated {
+			backfilled++
+		}
+	}
+	return backfilled, nil
 }
 
 // FindByVerificationBatchTaskId 根据验证批处理任务ID查找手机号码
@@ -0,0 +1,341 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/phone_management/internal/audit"
+	"github.com/phone_management/internal/models"
+	"gorm.io/gorm"
+)
+
+// ErrOutboundMessageNotRetryable 表示消息不是失败状态，不能重试
+var ErrOutboundMessageNotRetryable = errors.New("只有发送失败的通知可以重试")
+
+// OutboundMessageRepository 定义了通知发送队列的数据仓库接口
+type OutboundMessageRepository interface {
+	// EnqueueWithToken 在同一事务中创建确认令牌和对应的通知，避免生成了令牌却没有通知
+	EnqueueWithToken(ctx context.Context, token *models.VerificationToken, msg *models.OutboundMessage) error
+	// EnqueueReminder 在同一事务中记录一次催办并将催办通知加入发送队列，同时记录审计事件。
+	// 令牌的催办次数已不是 token.ReminderCount（期间有其他催办）时不做任何修改，返回 false
+	EnqueueReminder(ctx context.Context, token *models.VerificationToken, remindedAt time.Time, msg *models.OutboundMessage) (bool, error)
+	// EnqueueDepartureReminder 在同一事务中记录预约离职已提醒 IT 并将提醒邮件加入发送队列。
+	// 预约已提醒过时不做任何修改，返回 false
+	EnqueueDepartureReminder(ctx context.Context, departureID uint, remindedAt time.Time, msgs []models.OutboundMessage) (bool, error)
+	GetByID(ctx context.Context, id uint) (*models.OutboundMessage, error)
+	// List 按条件分页查询消息，按创建时间倒序
+	List(ctx context.Context, filter models.OutboundMessageFilter, page, limit int) ([]models.OutboundMessage, int64, error)
+	// FindEmployeeIDsByBatch 返回确认批次中已有通知的员工工号
+	FindEmployeeIDsByBatch(ctx context.Context, batchID string) ([]string, error)
+	// CountByBatch 统计确认批次中各状态的消息数
+	CountByBatch(ctx context.Context, batchID string) (*models.OutboundMessageCounts, error)
+	// FindFailedByBatch 查询确认批次中发送失败的消息
+	FindFailedByBatch(ctx context.Context, batchID string) ([]models.OutboundMessage, error)
+	// ClaimDue 领取最多 limit 条到期的待发送消息：状态改为 sending、记录领取时间并将尝试次数加一
+	ClaimDue(ctx context.Context, now time.Time, limit int) ([]models.OutboundMessage, error)
+	// StartSending 在开始发送前将领取时间从 claimedAt 更新为 startedAt，使发送超时从开始发送时计算。
+	// 消息已不是 claimedAt 这次领取（超时后被重新排队或再次领取）时不做修改并返回 false，不应再发送
+	StartSending(ctx context.Context, id uint, claimedAt, startedAt time.Time) (bool, error)
+	// MarkSent 记录消息发送成功。消息已不是 claimedAt 这次领取时不做修改并返回 false
+	MarkSent(ctx context.Context, id uint, claimedAt time.Time, channel string, sentAt time.Time) (bool, error)
+	// RecordFailure 记录一次发送失败：retryAt 不为空时在该时间重新排队，否则标记为失败。
+	// 消息已不是 claimedAt 这次领取时不做修改并返回 false
+	RecordFailure(ctx context.Context, id uint, claimedAt time.Time, channel, lastError string, retryAt *time.Time) (bool, error)
+	// ResetSending 将在 claimedBefore 之前领取、仍处于 sending 状态的消息重新排队，用于恢复实例退出或崩溃时未完成的发送。
+	// 其他实例刚领取、仍在发送中的消息不受影响
+	ResetSending(ctx context.Context, now, claimedBefore time.Time) (int64, error)
+	// Retry 将失败的消息重新排队并重置尝试次数，并记录审计事件；消息不是失败状态时返回 ErrOutboundMessageNotRetryable
+	Retry(ctx context.Context, id uint, now time.Time) (*models.OutboundMessage, error)
+}
+
+// gormOutboundMessageRepository 是 OutboundMessageRepository 的 GORM 实现
+type gormOutboundMessageRepository struct {
+	db *gorm.DB
+}
+
+// NewGormOutboundMessageRepository 创建一个新的 gormOutboundMessageRepository 实例
+func NewGormOutboundMessageRepository(db *gorm.DB) OutboundMessageRepository {
+	return &gormOutboundMessageRepository{db: db}
+}
+
+// EnqueueWithToken 创建确认令牌和通知
+func (r *gormOutboundMessageRepository) EnqueueWithToken(ctx context.Context, token *models.VerificationToken, msg *models.OutboundMessage) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(token).Error; err != nil {
+			return err
+		}
+		msg.VerificationTokenID = &token.ID
+		return tx.Create(msg).Error
+	})
+}
+
+// EnqueueReminder 记录催办并将催办通知加入发送队列
+func (r *gormOutboundMessageRepository) EnqueueReminder(ctx context.Context, token *models.VerificationToken, remindedAt time.Time, msg *models.OutboundMessage) (bool, error) {
+	enqueued := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.VerificationToken{}).
+			Where("id = ? AND reminder_count = ?", token.ID, token.ReminderCount).
+			UpdateColumns(map[string]interface{}{
+				"last_reminded_at": remindedAt,
+				"reminder_count":   gorm.Expr("reminder_count + 1"),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		var updated models.VerificationToken
+		if err := tx.First(&updated, token.ID).Error; err != nil {
+			return err
+		}
+		msg.VerificationTokenID = &token.ID
+		if err := tx.Create(msg).Error; err != nil {
+			return err
+		}
+		enqueued = true
+		return audit.Record(tx, models.AuditActionVerificationRemind, models.AuditEntityVerificationToken, token.ID, reminderSnapshot(*token), reminderSnapshot(updated))
+	})
+	if err != nil {
+		return false, err
+	}
+	return enqueued, nil
+}
+
+// EnqueueDepartureReminder 记录预约离职已提醒并将提醒邮件加入发送队列
+func (r *gormOutboundMessageRepository) EnqueueDepartureReminder(ctx context.Context, departureID uint, remindedAt time.Time, msgs []models.OutboundMessage) (bool, error) {
+	enqueued := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.ScheduledDeparture{}).
+			Where("id = ? AND reminder_sent_at IS NULL", departureID).
+			Update("reminder_sent_at", remindedAt)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		if err := tx.Create(&msgs).Error; err != nil {
+			return err
+		}
+		enqueued = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return enqueued, nil
+}
+
+// GetByID 根据ID查询消息
+func (r *gormOutboundMessageRepository) GetByID(ctx context.Context, id uint) (*models.OutboundMessage, error) {
+	var msg models.OutboundMessage
+	if err := r.db.WithContext(ctx).First(&msg, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &msg, nil
+}
+
+// List 按条件分页查询消息
+func (r *gormOutboundMessageRepository) List(ctx context.Context, filter models.OutboundMessageFilter, page, limit int) ([]models.OutboundMessage, int64, error) {
+	var messages []models.OutboundMessage
+	var totalItems int64
+
+	tx := r.db.WithContext(ctx).Model(&models.OutboundMessage{})
+	if filter.Status != "" {
+		tx = tx.Where("status = ?", filter.Status)
+	}
+	if filter.BatchID != "" {
+		tx = tx.Where("batch_id = ?", filter.BatchID)
+	}
+	if filter.EmployeeID != "" {
+		tx = tx.Where("employee_id = ?", filter.EmployeeID)
+	}
+
+	if err := tx.Count(&totalItems).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := tx.Order("created_at desc, id desc").Offset(offset).Limit(limit).Find(&messages).Error; err != nil {
+		return nil, 0, err
+	}
+	return messages, totalItems, nil
+}
+
+// FindEmployeeIDsByBatch 返回确认批次中已有通知的员工工号
+func (r *gormOutboundMessageRepository) FindEmployeeIDsByBatch(ctx context.Context, batchID string) ([]string, error) {
+	var employeeIDs []string
+	err := r.db.WithContext(ctx).Model(&models.OutboundMessage{}).
+		Where("batch_id = ?", batchID).
+		Distinct().Pluck("employee_id", &employeeIDs).Error
+	return employeeIDs, err
+}
+
+// CountByBatch 统计确认批次中各状态的消息数
+func (r *gormOutboundMessageRepository) CountByBatch(ctx context.Context, batchID string) (*models.OutboundMessageCounts, error) {
+	var rows []struct {
+		Status    models.OutboundMessageStatus
+		Total     int
+		Attempted int
+	}
+	err := r.db.WithContext(ctx).Model(&models.OutboundMessage{}).
+		Select("status, COUNT(*) AS total, SUM(CASE WHEN attempts > 0 THEN 1 ELSE 0 END) AS attempted").
+		Where("batch_id = ?", batchID).
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := &models.OutboundMessageCounts{}
+	for _, row := range rows {
+		counts.Total += row.Total
+		counts.Attempted += row.Attempted
+		switch row.Status {
+		case models.OutboundMessagePending:
+			counts.Pending = row.Total
+		case models.OutboundMessageSending:
+			counts.Sending = row.Total
+		case models.OutboundMessageSent:
+			counts.Sent = row.Total
+		case models.OutboundMessageFailed:
+			counts.Failed = row.Total
+		}
+	}
+	return counts, nil
+}
+
+// FindFailedByBatch 查询确认批次中发送失败的消息
+func (r *gormOutboundMessageRepository) FindFailedByBatch(ctx context.Context, batchID string) ([]models.OutboundMessage, error) {
+	var messages []models.OutboundMessage
+	err := r.db.WithContext(ctx).
+		Where("batch_id = ? AND status = ?", batchID, models.OutboundMessageFailed).
+		Order("id ASC").
+		Find(&messages).Error
+	return messages, err
+}
+
+// ClaimDue 领取到期的待发送消息。逐条以状态为条件更新，多个实例同时领取时每条消息只会被一个实例领取
+func (r *gormOutboundMessageRepository) ClaimDue(ctx context.Context, now time.Time, limit int) ([]models.OutboundMessage, error) {
+	var due []models.OutboundMessage
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND next_attempt_at <= ?", models.OutboundMessagePending, now).
+		Order("next_attempt_at ASC, id ASC").
+		Limit(limit).
+		Find(&due).Error
+	if err != nil {
+		return nil, err
+	}
+
+	claimed := make([]models.OutboundMessage, 0, len(due))
+	for _, msg := range due {
+		result := r.db.WithContext(ctx).Model(&models.OutboundMessage{}).
+			Where("id = ? AND status = ?", msg.ID, models.OutboundMessagePending).
+			Updates(map[string]interface{}{
+				"status":     models.OutboundMessageSending,
+				"attempts":   gorm.Expr("attempts + 1"),
+				"claimed_at": now,
+			})
+		if result.Error != nil {
+			return claimed, result.Error
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+		msg.Status = models.OutboundMessageSending
+		msg.Attempts++
+		claimedAt := now
+		msg.ClaimedAt = &claimedAt
+		claimed = append(claimed, msg)
+	}
+	return claimed, nil
+}
+
+// StartSending 以领取时间为条件更新领取时间
+func (r *gormOutboundMessageRepository) StartSending(ctx context.Context, id uint, claimedAt, startedAt time.Time) (bool, error) {
+	return r.updateClaimed(ctx, id, claimedAt, map[string]interface{}{"claimed_at": startedAt})
+}
+
+// MarkSent 记录消息发送成功
+func (r *gormOutboundMessageRepository) MarkSent(ctx context.Context, id uint, claimedAt time.Time, channel string, sentAt time.Time) (bool, error) {
+	return r.updateClaimed(ctx, id, claimedAt, map[string]interface{}{
+		"status":       models.OutboundMessageSent,
+		"sent_channel": channel,
+		"sent_at":      sentAt,
+		"last_error":   "",
+	})
+}
+
+// RecordFailure 记录一次发送失败
+func (r *gormOutboundMessageRepository) RecordFailure(ctx context.Context, id uint, claimedAt time.Time, channel, lastError string, retryAt *time.Time) (bool, error) {
+	updates := map[string]interface{}{
+		"status":       models.OutboundMessageFailed,
+		"sent_channel": channel,
+		"last_error":   lastError,
+	}
+	if retryAt != nil {
+		updates["status"] = models.OutboundMessagePending
+		updates["next_attempt_at"] = *retryAt
+	}
+	return r.updateClaimed(ctx, id, claimedAt, updates)
+}
+
+// updateClaimed 只在消息仍处于 claimedAt 这次领取的 sending 状态时更新，返回是否已更新。
+// 领取超时后消息会被重新排队并可能由其他工作协程再次领取，此时原工作协程的结果已过期，不能覆盖新的结果
+func (r *gormOutboundMessageRepository) updateClaimed(ctx context.Context, id uint, claimedAt time.Time, updates map[string]interface{}) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.OutboundMessage{}).
+		Where("id = ? AND status = ? AND claimed_at = ?", id, models.OutboundMessageSending, claimedAt).
+		Updates(updates)
+	return result.RowsAffected > 0, result.Error
+}
+
+// ResetSending 将领取已久仍处于 sending 状态的消息重新排队。没有领取时间的消息是添加该字段之前领取的，同样重新排队。
+// 服务在发送过程中退出时，消息可能已经送达但未记录，因此重新排队后可能重复发送一次
+func (r *gormOutboundMessageRepository) ResetSending(ctx context.Context, now, claimedBefore time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.OutboundMessage{}).
+		Where("status = ? AND (claimed_at IS NULL OR claimed_at < ?)", models.OutboundMessageSending, claimedBefore).
+		Updates(map[string]interface{}{
+			"status":          models.OutboundMessagePending,
+			"next_attempt_at": now,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// Retry 将失败的消息重新排队
+func (r *gormOutboundMessageRepository) Retry(ctx context.Context, id uint, now time.Time) (*models.OutboundMessage, error) {
+	var msg, updated models.OutboundMessage
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&msg, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrRecordNotFound
+			}
+			return err
+		}
+		if msg.Status != models.OutboundMessageFailed {
+			return ErrOutboundMessageNotRetryable
+		}
+		err := tx.Model(&models.OutboundMessage{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"status":          models.OutboundMessagePending,
+			"attempts":        0,
+			"next_attempt_at": now,
+		}).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.First(&updated, id).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, models.AuditActionOutboundMessageRetry, models.AuditEntityOutboundMessage, strconv.FormatUint(uint64(id), 10), msg, updated)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
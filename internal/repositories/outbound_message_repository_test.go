@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/phone_management/internal/models"
+)
+
+func TestOutboundMessageStaleWorkerCannotOverwriteResult(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &models.OutboundMessage{})
+	repo := NewGormOutboundMessageRepository(db)
+
+	now := time.Now()
+	msg := &models.OutboundMessage{Kind: models.OutboundMessageKindVerification, EmployeeID: "EMP0000001", Subject: "s", TextBody: "t",
+		Status: models.OutboundMessagePending, NextAttemptAt: now.Add(-time.Minute)}
+	if err := db.Create(msg).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// 第一个工作协程领取并开始发送
+	first, err := repo.ClaimDue(ctx, now, 1)
+	if err != nil || len(first) != 1 {
+		t.Fatalf("ClaimDue = %v, %v", first, err)
+	}
+	firstStart := now.Add(time.Second)
+	if ok, err := repo.StartSending(ctx, msg.ID, *first[0].ClaimedAt, firstStart); err != nil || !ok {
+		t.Fatalf("StartSending = %v, %v", ok, err)
+	}
+
+	// 发送超时后被重新排队，由第二个工作协程再次领取并发送成功
+	later := now.Add(time.Hour)
+	if n, err := repo.ResetSending(ctx, later, later.Add(-10*time.Minute)); err != nil || n != 1 {
+		t.Fatalf("ResetSending = %d, %v", n, err)
+	}
+	second, err := repo.ClaimDue(ctx, later, 1)
+	if err != nil || len(second) != 1 {
+		t.Fatalf("ClaimDue = %v, %v", second, err)
+	}
+	secondStart := later.Add(time.Second)
+	if ok, err := repo.StartSending(ctx, msg.ID, *second[0].ClaimedAt, secondStart); err != nil || !ok {
+		t.Fatalf("StartSending = %v, %v", ok, err)
+	}
+	if ok, err := repo.MarkSent(ctx, msg.ID, secondStart, "email", later.Add(2*time.Second)); err != nil || !ok {
+		t.Fatalf("MarkSent = %v, %v", ok, err)
+	}
+
+	// 第一个工作协程此时才报告失败，结果已过期，不能覆盖
+	retryAt := later.Add(time.Minute)
+	if ok, err := repo.RecordFailure(ctx, msg.ID, firstStart, "email", "timeout", &retryAt); err != nil || ok {
+		t.Fatalf("RecordFailure = %v, %v, want false", ok, err)
+	}
+	if ok, err := repo.StartSending(ctx, msg.ID, *first[0].ClaimedAt, later); err != nil || ok {
+		t.Fatalf("StartSending with a stale claim = %v, %v, want false", ok, err)
+	}
+
+	got, err := repo.GetByID(ctx, msg.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != models.OutboundMessageSent || got.LastError != "" || got.Attempts != 2 {
+		t.Errorf("status = %s, lastError = %q, attempts = %d, want sent, empty and 2", got.Status, got.LastError, got.Attempts)
+	}
+}
+
+func TestEnqueueDepartureReminderOnlyOnce(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &models.OutboundMessage{}, &models.ScheduledDeparture{})
+	repo := NewGormOutboundMessageRepository(db)
+
+	now := time.Now()
+	departure := &models.ScheduledDeparture{EmployeeID: "EMP0000001", TerminationDate: now.AddDate(0, 0, 3), Status: models.ScheduledDeparturePending}
+	if err := db.Create(departure).Error; err != nil {
+		t.Fatal(err)
+	}
+	newMsgs := func() []models.OutboundMessage {
+		var msgs []models.OutboundMessage
+		for _, to := range []string{"it@example.com", "helpdesk@example.com"} {
+			msgs = append(msgs, models.OutboundMessage{Kind: models.OutboundMessageKindDepartureReminder, EmployeeID: "EMP0000001",
+				RecipientEmail: to, Channel: "email", Subject: "s", Status: models.OutboundMessagePending, NextAttemptAt: now})
+		}
+		return msgs
+	}
+
+	if ok, err := repo.EnqueueDepartureReminder(ctx, departure.ID, now, newMsgs()); err != nil || !ok {
+		t.Fatalf("EnqueueDepartureReminder = %v, %v", ok, err)
+	}
+	// 同一预约再次提醒时不重复入队
+	if ok, err := repo.EnqueueDepartureReminder(ctx, departure.ID, now.Add(time.Hour), newMsgs()); err != nil || ok {
+		t.Fatalf("second EnqueueDepartureReminder = %v, %v, want false", ok, err)
+	}
+
+	var count int64
+	if err := db.Model(&models.OutboundMessage{}).Where("kind = ?", models.OutboundMessageKindDepartureReminder).Count(&count).Error; err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("queued %d messages, want 2", count)
+	}
+	var got models.ScheduledDeparture
+	if err := db.First(&got, departure.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if got.ReminderSentAt == nil || !got.ReminderSentAt.Equal(now) {
+		t.Errorf("reminderSentAt = %v, want %v", got.ReminderSentAt, now)
+	}
+}
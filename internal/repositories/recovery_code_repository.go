@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/phone_management/internal/models"
+	"gorm.io/gorm"
+)
+
+// RecoveryCodeRepository 定义了两步验证恢复码数据仓库的接口
+type RecoveryCodeRepository interface {
+	// ReplaceForUser 删除用户已有的恢复码并保存新的一组
+	ReplaceForUser(userID int64, codeHashes []string) error
+	// UseCode 将用户一个未使用的恢复码标记为已使用，找不到可用的恢复码时返回 false
+	UseCode(userID int64, codeHash string) (bool, error)
+	// DeleteForUser 删除用户的所有恢复码
+	DeleteForUser(userID int64) error
+	// CountUnused 统计用户剩余可用的恢复码数量
+	CountUnused(userID int64) (int64, error)
+}
+
+// gormRecoveryCodeRepository 是 RecoveryCodeRepository 的 GORM 实现
+type gormRecoveryCodeRepository struct {
+	db *gorm.DB
+}
+
+// NewGormRecoveryCodeRepository 创建一个新的 gormRecoveryCodeRepository 实例
+func NewGormRecoveryCodeRepository(db *gorm.DB) RecoveryCodeRepository {
+	return &gormRecoveryCodeRepository{db: db}
+}
+
+// ReplaceForUser 删除用户已有的恢复码并保存新的一组
+func (r *gormRecoveryCodeRepository) ReplaceForUser(userID int64, codeHashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		codes := make([]models.RecoveryCode, 0, len(codeHashes))
+		for _, hash := range codeHashes {
+			codes = append(codes, models.RecoveryCode{UserID: userID, CodeHash: hash})
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+// UseCode 将用户一个未使用的恢复码标记为已使用
+func (r *gormRecoveryCodeRepository) UseCode(userID int64, codeHash string) (bool, error) {
+	result := r.db.Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// DeleteForUser 删除用户的所有恢复码
+func (r *gormRecoveryCodeRepository) DeleteForUser(userID int64) error {
+	return r.db.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error
+}
+
+// CountUnused 统计用户剩余可用的恢复码数量
+func (r *gormRecoveryCodeRepository) CountUnused(userID int64) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
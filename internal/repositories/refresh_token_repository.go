@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"github.com/phone_management/internal/models"
+	"gorm.io/gorm"
+)
+
+// RefreshTokenRepository 定义了刷新令牌数据仓库的接口
+type RefreshTokenRepository interface {
+	Create(token *models.RefreshToken) error
+	// GetByHash 根据令牌哈希查询，不存在时返回 ErrRecordNotFound
+	GetByHash(tokenHash string) (*models.RefreshToken, error)
+	// Rotate 在同一事务中吊销旧令牌并保存新令牌。
+	// 若旧令牌已被并发吊销，返回 false 且不保存新令牌。
+	Rotate(oldID int64, newToken *models.RefreshToken) (bool, error)
+	// RevokeFamily 吊销整个令牌族
+	RevokeFamily(familyID string) error
+	// RevokeAllForUser 吊销指定用户的所有令牌
+	RevokeAllForUser(userID int64) error
+	// PurgeExpired 删除所有已过期的令牌，返回删除的条数
+	PurgeExpired() (int64, error)
+}
+
+// gormRefreshTokenRepository 是 RefreshTokenRepository 的 GORM 实现
+type gormRefreshTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewGormRefreshTokenRepository 创建一个新的 gormRefreshTokenRepository 实例
+func NewGormRefreshTokenRepository(db *gorm.DB) RefreshTokenRepository {
+	return &gormRefreshTokenRepository{db: db}
+}
+
+// Create 保存刷新令牌
+func (r *gormRefreshTokenRepository) Create(token *models.RefreshToken) error {
+	return r.db.Create(token).Error
+}
+
+// GetByHash 根据令牌哈希查询
+func (r *gormRefreshTokenRepository) GetByHash(tokenHash string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	if err := r.db.Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Rotate 吊销旧令牌并保存新令牌
+func (r *gormRefreshTokenRepository) Rotate(oldID int64, newToken *models.RefreshToken) (bool, error) {
+	rotated := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// 只有仍未吊销的令牌才能被轮换，防止同一令牌被并发使用两次
+		result := tx.Model(&models.RefreshToken{}).
+			Where("id = ? AND revoked_at IS NULL", oldID).
+			Update("revoked_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		if err := tx.Create(newToken).Error; err != nil {
+			return err
+		}
+		rotated = true
+		return nil
+	})
+	return rotated, err
+}
+
+// RevokeFamily 吊销整个令牌族
+func (r *gormRefreshTokenRepository) RevokeFamily(familyID string) error {
+	return r.db.Model(&models.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// RevokeAllForUser 吊销指定用户的所有令牌
+func (r *gormRefreshTokenRepository) RevokeAllForUser(userID int64) error {
+	return r.db.Model(&models.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// PurgeExpired 删除所有已过期的令牌
+func (r *gormRefreshTokenRepository) PurgeExpired() (int64, error) {
+	result := r.db.Where("expires_at <= ?", time.Now()).Delete(&models.RefreshToken{})
+	return result.RowsAffected, result.Error
+}
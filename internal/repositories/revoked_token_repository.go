@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/phone_management/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RevokedTokenRepository 定义了已吊销 JWT 数据仓库的接口，实现了 auth.TokenDenylist
+type RevokedTokenRepository interface {
+	// Add 记录一个已吊销的 JTI，重复添加时更新其过期时间
+	Add(jti string, expiresAt time.Time) error
+	// Contains 检查 JTI 是否已吊销且尚未过期
+	Contains(jti string) (bool, error)
+	// PurgeExpired 删除所有已过期的记录，返回删除的条数
+	PurgeExpired() (int64, error)
+}
+
+// gormRevokedTokenRepository 是 RevokedTokenRepository 的 GORM 实现
+type gormRevokedTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewGormRevokedTokenRepository 创建一个新的 gormRevokedTokenRepository 实例
+func NewGormRevokedTokenRepository(db *gorm.DB) RevokedTokenRepository {
+	return &gormRevokedTokenRepository{db: db}
+}
+
+// Add 记录一个已吊销的 JTI
+func (r *gormRevokedTokenRepository) Add(jti string, expiresAt time.Time) error {
+	record := models.RevokedToken{JTI: jti, ExpiresAt: expiresAt}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "jti"}},
+		DoUpdates: clause.AssignmentColumns([]string{"expires_at"}),
+	}).Create(&record).Error
+}
+
+// Contains 检查 JTI 是否已吊销且尚未过期
+func (r *gormRevokedTokenRepository) Contains(jti string) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.RevokedToken{}).
+		Where("jti = ? AND expires_at > ?", jti, time.Now()).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// PurgeExpired 删除所有已过期的记录
+func (r *gormRevokedTokenRepository) PurgeExpired() (int64, error) {
+	result := r.db.Where("expires_at <= ?", time.Now()).Delete(&models.RevokedToken{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,131 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/phone_management/internal/audit"
+	"github.com/phone_management/internal/models"
+	"gorm.io/gorm"
+)
+
+// ScheduledDepartureRepository 定义了预约离职数据仓库的接口
+type ScheduledDepartureRepository interface {
+	// Schedule 在一个事务中取消员工已有的预约离职、保存新的预约并按 employeeUpdates 更新员工信息（含离职日期）
+	Schedule(ctx context.Context, departure *models.ScheduledDeparture, employeeUpdates map[string]interface{}) (*models.Employee, error)
+	// CancelPending 取消员工尚未办理的预约离职
+	CancelPending(ctx context.Context, employeeID string) error
+	// FindPending 查询所有尚未办理的预约离职，按离职日期升序
+	FindPending(ctx context.Context) ([]models.ScheduledDeparture, error)
+	// FindDue 查询离职日期不晚于 asOf 的待办理预约离职
+	FindDue(ctx context.Context, asOf time.Time) ([]models.ScheduledDeparture, error)
+	// FindReminderDue 查询离职日期不晚于 until 且尚未提醒的待办理预约离职
+	FindReminderDue(ctx context.Context, until time.Time) ([]models.ScheduledDeparture, error)
+	// MarkFinished 将预约离职标记为已办理或已取消
+	MarkFinished(ctx context.Context, id uint, status models.ScheduledDepartureStatus, at time.Time) error
+	// RecordFailure 记录办理失败的原因，预约保持待办理状态以便下次重试
+	RecordFailure(ctx context.Context, id uint, reason string) error
+}
+
+// gormScheduledDepartureRepository 是 ScheduledDepartureRepository 的 GORM 实现
+type gormScheduledDepartureRepository struct {
+	db *gorm.DB
+}
+
+// NewGormScheduledDepartureRepository 创建一个新的 gormScheduledDepartureRepository 实例
+func NewGormScheduledDepartureRepository(db *gorm.DB) ScheduledDepartureRepository {
+	return &gormScheduledDepartureRepository{db: db}
+}
+
+// Schedule 保存预约离职并更新员工信息，员工更新按 employee.update 记录审计事件
+func (r *gormScheduledDepartureRepository) Schedule(ctx context.Context, departure *models.ScheduledDeparture, employeeUpdates map[string]interface{}) (*models.Employee, error) {
+	var employee, updatedEmployee models.Employee
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("employee_id = ?", departure.EmployeeID).First(&employee).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrRecordNotFound
+			}
+			return err
+		}
+
+		// 同一员工只保留最新的一条待办理预约
+		if err := cancelPendingDepartures(tx, departure.EmployeeID); err != nil {
+			return err
+		}
+		departure.Status = models.ScheduledDeparturePending
+		if err := tx.Create(departure).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.Employee{}).Where("employee_id = ?", departure.EmployeeID).Updates(employeeUpdates).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("employee_id = ?", departure.EmployeeID).First(&updatedEmployee).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, models.AuditActionEmployeeUpdate, models.AuditEntityEmployee, departure.EmployeeID, employee, updatedEmployee)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return &updatedEmployee, nil
+}
+
+// CancelPending 取消员工尚未办理的预约离职
+func (r *gormScheduledDepartureRepository) CancelPending(ctx context.Context, employeeID string) error {
+	return cancelPendingDepartures(r.db.WithContext(ctx), employeeID)
+}
+
+// cancelPendingDepartures 将员工所有待办理的预约离职标记为已取消
+func cancelPendingDepartures(tx *gorm.DB, employeeID string) error {
+	return tx.Model(&models.ScheduledDeparture{}).
+		Where("employee_id = ? AND status = ?", employeeID, models.ScheduledDeparturePending).
+		Update("status", models.ScheduledDepartureCancelled).Error
+}
+
+// FindPending 查询所有尚未办理的预约离职
+func (r *gormScheduledDepartureRepository) FindPending(ctx context.Context) ([]models.ScheduledDeparture, error) {
+	var departures []models.ScheduledDeparture
+	err := r.db.WithContext(ctx).
+		Where("status = ?", models.ScheduledDeparturePending).
+		Order("termination_date ASC, id ASC").
+		Find(&departures).Error
+	return departures, err
+}
+
+// FindDue 查询到期的待办理预约离职
+func (r *gormScheduledDepartureRepository) FindDue(ctx context.Context, asOf time.Time) ([]models.ScheduledDeparture, error) {
+	var departures []models.ScheduledDeparture
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND termination_date <= ?", models.ScheduledDeparturePending, asOf).
+		Order("termination_date ASC, id ASC").
+		Find(&departures).Error
+	return departures, err
+}
+
+// FindReminderDue 查询需要提醒的待办理预约离职
+func (r *gormScheduledDepartureRepository) FindReminderDue(ctx context.Context, until time.Time) ([]models.ScheduledDeparture, error) {
+	var departures []models.ScheduledDeparture
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND reminder_sent_at IS NULL AND termination_date <= ?", models.ScheduledDeparturePending, until).
+		Order("termination_date ASC, id ASC").
+		Find(&departures).Error
+	return departures, err
+}
+
+// MarkFinished 将预约离职标记为已办理或已取消
+func (r *gormScheduledDepartureRepository) MarkFinished(ctx context.Context, id uint, status models.ScheduledDepartureStatus, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.ScheduledDeparture{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":      status,
+		"executed_at": at,
+		"last_error":  nil,
+	}).Error
+}
+
+// RecordFailure 记录办理失败的原因
+func (r *gormScheduledDepartureRepository) RecordFailure(ctx context.Context, id uint, reason string) error {
+	return r.db.WithContext(ctx).Model(&models.ScheduledDeparture{}).Where("id = ?", id).Update("last_error", reason).Error
+}
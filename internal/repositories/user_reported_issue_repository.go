@@ -22,8 +22,9 @@ type UserReportedIssueRepository interface {
 	FindUnlistedByTokenId(ctx context.Context, tokenId uint) ([]models.UserReportedIssue, error)
 	// 以下是管理员查看状态API所需的方法
 	// CountReportedIssues(ctx context.Context) (int, error) // 统计所有报告的问题总数
-	FindReportedIssuesWithDetails(ctx context.Context, employeeID, departmentName string) ([]models.ReportedIssueDetail, error)
-	FindUnlistedNumbersWithDetails(ctx context.Context, employeeID, departmentName string) ([]models.ReportedUnlistedNumberInfo, error)
+	// FindReportedIssuesWithDetails 和 FindUnlistedNumbersWithDetails 遵循 context 中的部门范围，按报告人所属部门限定
+	FindReportedIssuesWithDetails(ctx context.Context, employeeID string, departmentIDs []uint) ([]models.ReportedIssueDetail, error)
+	FindUnlistedNumbersWithDetails(ctx context.Context, employeeID string, departmentIDs []uint) ([]models.ReportedUnlistedNumberInfo, error)
 	// FindLatestByMobileNumberIdAndTokenId 查找特定手机号码ID和验证令牌ID对应的最新的用户报告问题记录
 	FindLatestByMobileNumberIdAndTokenId(ctx context.Context, mobileNumberId uint, tokenId uint) (*models.UserReportedIssue, error)
 	// FindPendingByMobileNumberDbIdAndEmployeeId 查找指定 MobileNumberDbId 和 EmployeeId 的待处理用户报告问题记录
@@ -88,7 +89,7 @@ func (r *gormUserReportedIssueRepository) FindUnlistedByTokenId(ctx context.Cont
 }
 
 // FindReportedIssuesWithDetails 查询用户报告的号码问题详情
-func (r *gormUserReportedIssueRepository) FindReportedIssuesWithDetails(ctx context.Context, employeeID, departmentName string) ([]models.ReportedIssueDetail, error) {
+func (r *gormUserReportedIssueRepository) FindReportedIssuesWithDetails(ctx context.Context, employeeID string, departmentIDs []uint) ([]models.ReportedIssueDetail, error) {
 	var results []struct {
 		IssueID           uint      `gorm:"column:id"`
 		PhoneNumber       string    `gorm:"column:phone_number"`
@@ -104,13 +105,14 @@ func (r *gormUserReportedIssueRepository) FindReportedIssuesWithDetails(ctx cont
 		Joins("JOIN employees e ON uri.reported_by_employee_id = e.employee_id").
 		Joins("JOIN mobile_numbers mn ON uri.mobile_number_db_id = mn.id").
 		Where("uri.issue_type = ?", "number_issue")
+	query = scopeEmployees(ctx, query, "uri.reported_by_employee_id")
 
 	// 应用过滤条件
 	if employeeID != "" {
 		query = query.Where("uri.reported_by_employee_id = ?", employeeID)
 	}
-	if departmentName != "" {
-		query = query.Where("e.department = ?", departmentName)
+	if departmentIDs != nil {
+		query = query.Where("e.department_id IN ?", departmentIDs)
 	}
 
 	err := query.Order("uri.created_at desc").Scan(&results).Error
@@ -163,7 +165,7 @@ func (r *gormUserReportedIssueRepository) FindReportedIssuesWithDetails(ctx cont
 }
 
 // FindUnlistedNumbersWithDetails 查询用户报告的未列出号码详情
-func (r *gormUserReportedIssueRepository) FindUnlistedNumbersWithDetails(ctx context.Context, employeeID, departmentName string) ([]models.ReportedUnlistedNumberInfo, error) {
+func (r *gormUserReportedIssueRepository) FindUnlistedNumbersWithDetails(ctx context.Context, employeeID string, departmentIDs []uint) ([]models.ReportedUnlistedNumberInfo, error) {
 	var results []struct {
 		PhoneNumber *string   `gorm:"column:reported_phone_number"`
 		ReportedBy  string    `gorm:"column:reported_by"`
@@ -175,13 +177,14 @@ func (r *gormUserReportedIssueRepository) FindUnlistedNumbersWithDetails(ctx con
 		Select("uri.reported_phone_number, e.full_name as reported_by, uri.user_comment, uri.created_at").
 		Joins("JOIN employees e ON uri.reported_by_employee_id = e.employee_id").
 		Where("uri.issue_type = ?", "unlisted_number")
+	query = scopeEmployees(ctx, query, "uri.reported_by_employee_id")
 
 	// 应用过滤条件
 	if employeeID != "" {
 		query = query.Where("uri.reported_by_employee_id = ?", employeeID)
 	}
-	if departmentName != "" {
-		query = query.Where("e.department = ?", departmentName)
+	if departmentIDs != nil {
+		query = query.Where("e.department_id IN ?", departmentIDs)
 	}
 
 	err := query.Order("uri.created_at desc").Scan(&results).Error
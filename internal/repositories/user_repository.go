@@ -0,0 +1,297 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/phone_management/internal/audit"
+	"github.com/phone_management/internal/models"
+	"gorm.io/gorm"
+)
+
+// ErrUsernameExists 表示用户名已存在
+var ErrUsernameExists = errors.New("用户名已存在")
+
+// ErrLastActiveAdmin 表示变更后将没有启用状态的管理员
+var ErrLastActiveAdmin = errors.New("不能删除、禁用或降级最后一个启用状态的管理员")
+
+// UserRepository 定义了系统用户数据仓库的接口
+type UserRepository interface {
+	CreateUser(ctx context.Context, user *models.User) (*models.User, error)
+	GetUsers(page, limit int, search, role, status string) ([]models.User, int64, error)
+	GetUserByID(id int64) (*models.User, error)
+	GetUserByUsername(username string) (*models.User, error)
+	// UpdateUser 更新用户并记录审计事件，action 为审计事件的操作类型。
+	// updates 中的 department_ids（[]uint）不是 users 表的列，表示替换用户所负责的部门。
+	// 更新后没有启用状态的管理员时回滚并返回 ErrLastActiveAdmin
+	UpdateUser(ctx context.Context, id int64, action string, updates map[string]interface{}) (*models.User, error)
+	// UpdateLoginState 更新登录过程维护的内部状态（失败计数、锁定、TOTP 密钥和时间步），不记录审计事件
+	UpdateLoginState(id int64, updates map[string]interface{}) (*models.User, error)
+	// DeleteUser 删除用户；删除后没有启用状态的管理员时回滚并返回 ErrLastActiveAdmin
+	DeleteUser(ctx context.Context, id int64) error
+	// IncrementFailedLogin 原子地将登录失败次数加一并记录失败时间，返回更新后的用户
+	IncrementFailedLogin(id int64) (*models.User, error)
+	// UseTOTPStep 原子地记录用户使用了 step 时间步的 TOTP 验证码，step 不大于已使用的时间步（重放）时返回 false
+	UseTOTPStep(id int64, step int64) (bool, error)
+	// GetManagedDepartmentIDs 返回用户所负责的部门及其全部下级部门的ID
+	GetManagedDepartmentIDs(ctx context.Context, userID int64) ([]uint, error)
+}
+
+// gormUserRepository 是 UserRepository 的 GORM 实现
+type gormUserRepository struct {
+	db *gorm.DB
+}
+
+// NewGormUserRepository 创建一个新的 gormUserRepository 实例
+func NewGormUserRepository(db *gorm.DB) UserRepository {
+	return &gormUserRepository{db: db}
+}
+
+// CreateUser 创建系统用户
+func (r *gormUserRepository) CreateUser(ctx context.Context, user *models.User) (*models.User, error) {
+	// 预先检查用户名以返回明确的错误
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.User{}).Where("username = ?", user.Username).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, ErrUsernameExists
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 早期版本软删除的用户仍占用唯一的用户名，创建同名用户前将其彻底删除
+		if err := tx.Unscoped().Where("username = ? AND deleted_at IS NOT NULL", user.Username).Delete(&models.User{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		if err := replaceUserDepartments(tx, user.ID, user.DepartmentIDs); err != nil {
+			return err
+		}
+		return audit.Record(tx, models.AuditActionUserCreate, models.AuditEntityUser, user.ID, nil, user)
+	})
+	if err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "unique constraint") {
+			return nil, ErrUsernameExists
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// GetUsers 获取系统用户列表，支持分页、搜索和筛选
+func (r *gormUserRepository) GetUsers(page, limit int, search, role, status string) ([]models.User, int64, error) {
+	var users []models.User
+	var totalItems int64
+
+	tx := r.db.Model(&models.User{})
+	if search != "" {
+		tx = tx.Where("username LIKE ?", "%"+search+"%")
+	}
+	if role != "" {
+		tx = tx.Where("role = ?", role)
+	}
+	if status != "" {
+		tx = tx.Where("status = ?", status)
+	}
+
+	if err := tx.Count(&totalItems).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := tx.Order("created_at desc").Offset(offset).Limit(limit).Find(&users).Error; err != nil {
+		return nil, 0, err
+	}
+	for i := range users {
+		if err := loadUserDepartments(r.db, &users[i]); err != nil {
+			return nil, 0, err
+		}
+	}
+	return users, totalItems, nil
+}
+
+// GetUserByID 根据ID查询系统用户
+func (r *gormUserRepository) GetUserByID(id int64) (*models.User, error) {
+	var user models.User
+	if err := r.db.First(&user, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, err
+	}
+	if err := loadUserDepartments(r.db, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetUserByUsername 根据用户名查询系统用户
+func (r *gormUserRepository) GetUserByUsername(username string) (*models.User, error) {
+	var user models.User
+	if err := r.db.Where("username = ?", username).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// UpdateUser 更新指定ID的系统用户，并在同一事务中记录审计事件
+func (r *gormUserRepository) UpdateUser(ctx context.Context, id int64, action string, updates map[string]interface{}) (*models.User, error) {
+	var before, user models.User
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&before, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrRecordNotFound
+			}
+			return err
+		}
+		if err := loadUserDepartments(tx, &before); err != nil {
+			return err
+		}
+
+		columns := make(map[string]interface{}, len(updates))
+		for key, value := range updates {
+			columns[key] = value
+		}
+		if departmentIDs, ok := columns["department_ids"]; ok {
+			delete(columns, "department_ids")
+			if err := replaceUserDepartments(tx, id, departmentIDs.([]uint)); err != nil {
+				return err
+			}
+		}
+		if len(columns) > 0 {
+			if err := tx.Model(&models.User{}).Where("id = ?", id).Updates(columns).Error; err != nil {
+				return err
+			}
+			if err := ensureActiveAdminRemains(tx, &before); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.First(&user, id).Error; err != nil {
+			return err
+		}
+		if err := loadUserDepartments(tx, &user); err != nil {
+			return err
+		}
+		return audit.Record(tx, action, models.AuditEntityUser, id, before, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// UpdateLoginState 更新登录过程维护的内部状态，不记录审计事件
+func (r *gormUserRepository) UpdateLoginState(id int64, updates map[string]interface{}) (*models.User, error) {
+	if err := r.db.Model(&models.User{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+	return r.GetUserByID(id)
+}
+
+// DeleteUser 彻底删除指定ID的系统用户及其负责部门、刷新令牌和恢复码，使用户名可以再次使用，并在同一事务中记录审计事件
+func (r *gormUserRepository) DeleteUser(ctx context.Context, id int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var before models.User
+		if err := tx.First(&before, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrRecordNotFound
+			}
+			return err
+		}
+		if err := tx.Unscoped().Delete(&models.User{}, id).Error; err != nil {
+			return err
+		}
+		if err := ensureActiveAdminRemains(tx, &before); err != nil {
+			return err
+		}
+		for _, dependent := range []interface{}{&models.UserDepartment{}, &models.RefreshToken{}, &models.RecoveryCode{}} {
+			if err := tx.Where("user_id = ?", id).Delete(dependent).Error; err != nil {
+				return err
+			}
+		}
+		return audit.Record(tx, models.AuditActionUserDelete, models.AuditEntityUser, id, before, nil)
+	})
+}
+
+// ensureActiveAdminRemains 在变更用户的事务中、写入之后检查：变更前是启用状态管理员的用户被降级、禁用或删除后，
+// 至少还有一个启用状态的管理员。在写入之后统计，并发降级最后两个管理员时后提交的事务会看到先提交的变更
+func ensureActiveAdminRemains(tx *gorm.DB, before *models.User) error {
+	if before.Role != models.RoleAdmin || before.Status != models.UserStatusActive {
+		return nil
+	}
+	var count int64
+	if err := tx.Model(&models.User{}).
+		Where("role = ? AND status = ?", models.RoleAdmin, models.UserStatusActive).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrLastActiveAdmin
+	}
+	return nil
+}
+
+// IncrementFailedLogin 原子地将登录失败次数加一并记录失败时间
+func (r *gormUserRepository) IncrementFailedLogin(id int64) (*models.User, error) {
+	err := r.db.Model(&models.User{}).Where("id = ?", id).UpdateColumns(map[string]interface{}{
+		"failed_login_count":   gorm.Expr("failed_login_count + 1"),
+		"last_failed_login_at": time.Now(),
+	}).Error
+	if err != nil {
+		return nil, err
+	}
+	return r.GetUserByID(id)
+}
+
+// UseTOTPStep 仅在 step 大于已使用的时间步时更新，并发提交同一验证码时只有一个请求成功
+func (r *gormUserRepository) UseTOTPStep(id int64, step int64) (bool, error) {
+	result := r.db.Model(&models.User{}).
+		Where("id = ? AND totp_last_used_step < ?", id, step).
+		UpdateColumn("totp_last_used_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetManagedDepartmentIDs 返回用户所负责的部门及其全部下级部门的ID，未负责任何部门时返回空列表
+func (r *gormUserRepository) GetManagedDepartmentIDs(ctx context.Context, userID int64) ([]uint, error) {
+	var user models.User
+	user.ID = userID
+	if err := loadUserDepartments(r.db.WithContext(ctx), &user); err != nil {
+		return nil, err
+	}
+	if len(user.DepartmentIDs) == 0 {
+		return []uint{}, nil
+	}
+	return departmentDescendantIDs(r.db.WithContext(ctx), user.DepartmentIDs)
+}
+
+// loadUserDepartments 加载用户所负责的部门ID
+func loadUserDepartments(tx *gorm.DB, user *models.User) error {
+	return tx.Model(&models.UserDepartment{}).
+		Where("user_id = ?", user.ID).
+		Order("department_id").
+		Pluck("department_id", &user.DepartmentIDs).Error
+}
+
+// replaceUserDepartments 在事务中将用户所负责的部门替换为 departmentIDs
+func replaceUserDepartments(tx *gorm.DB, userID int64, departmentIDs []uint) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&models.UserDepartment{}).Error; err != nil {
+		return err
+	}
+	for _, departmentID := range departmentIDs {
+		if err := tx.Create(&models.UserDepartment{UserID: userID, DepartmentID: departmentID}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
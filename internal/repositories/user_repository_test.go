@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/phone_management/internal/models"
+)
+
+func TestLastActiveAdminIsKept(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &models.User{}, &models.UserDepartment{}, &models.RefreshToken{}, &models.RecoveryCode{})
+	repo := NewGormUserRepository(db)
+
+	var ids []int64
+	for _, username := range []string{"admin1", "admin2"} {
+		user, err := repo.CreateUser(ctx, &models.User{Username: username, PasswordHash: "x", Role: models.RoleAdmin, Status: models.UserStatusActive})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, user.ID)
+	}
+
+	// 并发降级最后两个管理员，只有一个成功
+	var wg sync.WaitGroup
+	errs := make([]error, len(ids))
+	for i, id := range ids {
+		wg.Add(1)
+		go func(i int, id int64) {
+			defer wg.Done()
+			_, errs[i] = repo.UpdateUser(ctx, id, models.AuditActionUserUpdate, map[string]interface{}{"status": models.UserStatusDisabled})
+		}(i, id)
+	}
+	wg.Wait()
+	var active int64
+	db.Model(&models.User{}).Where("role = ? AND status = ?", models.RoleAdmin, models.UserStatusActive).Count(&active)
+	if active != 1 {
+		t.Fatalf("active admins = %d, want 1 (errors: %v)", active, errs)
+	}
+
+	var remaining int64
+	for i, id := range ids {
+		if errs[i] != nil {
+			remaining = id
+		}
+	}
+	if err := repo.DeleteUser(ctx, remaining); !errors.Is(err, ErrLastActiveAdmin) {
+		t.Fatalf("DeleteUser err = %v, want ErrLastActiveAdmin", err)
+	}
+	if _, err := repo.UpdateUser(ctx, remaining, models.AuditActionUserUpdate, map[string]interface{}{"role": models.RoleDepartmentManager}); !errors.Is(err, ErrLastActiveAdmin) {
+		t.Fatalf("UpdateUser err = %v, want ErrLastActiveAdmin", err)
+	}
+}
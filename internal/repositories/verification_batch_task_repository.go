@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"time"
 
+	"github.com/phone_management/internal/audit"
 	"github.com/phone_management/internal/models"
+	"github.com/phone_management/internal/scope"
 	"gorm.io/gorm"
 )
 
@@ -14,6 +16,8 @@ type VerificationBatchTaskRepository interface {
 	Create(ctx context.Context, task *models.VerificationBatchTask) error
 	GetByID(ctx context.Context, batchID string) (*models.VerificationBatchTask, error)
 	Update(ctx context.Context, task *models.VerificationBatchTask) error
+	// FindByStatus 查询指定状态的批处理任务，按创建时间升序
+	FindByStatus(ctx context.Context, status models.VerificationBatchTaskStatus) ([]models.VerificationBatchTask, error)
 	// UpdateCountsAndStatus 允许原子性地更新计数器和状态，或按需部分更新
 	UpdateCountsAndStatus(ctx context.Context, batchID string,
 		tokensToAdd int, emailsAttemptedToAdd int, emailsSucceededToAdd int, emailsFailedToAdd int,
@@ -31,12 +35,21 @@ func NewGormVerificationBatchTaskRepository(db *gorm.DB) VerificationBatchTaskRe
 
 // Create 在数据库中创建一个新的批处理任务记录
 func (r *gormVerificationBatchTaskRepository) Create(ctx context.Context, task *models.VerificationBatchTask) error {
-	return r.db.WithContext(ctx).Create(task).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(task).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, models.AuditActionVerificationInitiate, models.AuditEntityVerificationBatch, task.ID, nil, task)
+	})
 }
 
 // GetByID 从数据库中按 ID 获取批处理任务
 func (r *gormVerificationBatchTaskRepository) GetByID(ctx context.Context, batchID string) (*models.VerificationBatchTask, error) {
 	var task models.VerificationBatchTask
+	// 批处理任务不属于任何部门，限定了部门范围的请求（部门负责人）无法查看
+	if _, restricted := scope.Departments(ctx); restricted {
+		return nil, gorm.ErrRecordNotFound
+	}
 	if err := r.db.WithContext(ctx).Where("id = ?", batchID).First(&task).Error; err != nil {
 		return nil, err // 调用方应处理 gorm.ErrRecordNotFound
 	}
@@ -48,6 +61,13 @@ func (r *gormVerificationBatchTaskRepository) Update(ctx context.Context, task *
 	return r.db.WithContext(ctx).Save(task).Error
 }
 
+// FindByStatus 查询指定状态的批处理任务
+func (r *gormVerificationBatchTaskRepository) FindByStatus(ctx context.Context, status models.VerificationBatchTaskStatus) ([]models.VerificationBatchTask, error) {
+	var tasks []models.VerificationBatchTask
+	err := r.db.WithContext(ctx).Where("status = ?", status).Order("created_at ASC").Find(&tasks).Error
+	return tasks, err
+}
+
 // UpdateCountsAndStatus 更新任务的计数和状态。
 // 注意：这里的错误摘要更新逻辑做了简化，实际应用中可能需要更复杂的 JSON 数组追加逻辑。
 // 对于高并发或精确计数，可能需要使用 gorm.Expr("tokens_generated_count + ?", tokensToAdd) 等原子操作。
@@ -15,7 +15,7 @@ type VerificationSubmissionLogRepository interface {
 	// 批量创建日志记录
 	BatchCreate(ctx context.Context, logs []*models.VerificationSubmissionLog) error
 
-	// 统计不同操作类型的手机号码数量
+	// 统计不同操作类型的手机号码数量，以下查询均遵循 context 中的部门范围
 	CountTotalPhones(ctx context.Context) (int, error)
 	CountConfirmedPhones(ctx context.Context) (int, error)
 	CountReportedIssuePhones(ctx context.Context) (int, error)
@@ -27,6 +27,10 @@ type VerificationSubmissionLogRepository interface {
 
 	// 查询已确认使用的手机号码详情
 	FindConfirmedPhoneDetails(ctx context.Context) ([]models.ConfirmedPhoneDetail, error)
+
+	// 查询指定手机号码或员工的全部提交记录，按提交时间升序
+	FindByPhoneNumber(ctx context.Context, phoneNumber string) ([]models.VerificationSubmissionLog, error)
+	FindByEmployeeID(ctx context.Context, employeeID string) ([]models.VerificationSubmissionLog, error)
 }
 
 type gormVerificationSubmissionLogRepository struct {
@@ -53,7 +57,8 @@ func (r *gormVerificationSubmissionLogRepository) CountTotalPhones(ctx context.C
 	var count int64
 
 	// 统计mobile_numbers表中除"已注销"状态外的所有手机号码数量
-	err := r.db.WithContext(ctx).Model(&models.MobileNumber{}).
+	query := scopeMobileNumbers(ctx, r.db.WithContext(ctx), "mobile_numbers")
+	err := query.Model(&models.MobileNumber{}).
 		Where("status != ?", "已注销").
 		Count(&count).Error
 
@@ -70,10 +75,10 @@ func (r *gormVerificationSubmissionLogRepository) CountConfirmedPhones(ctx conte
 		Group("phone_number")
 
 	// 主查询统计最新操作为"confirm_usage"的手机号数量
-	err := r.db.WithContext(ctx).Table("(?) as latest", subQuery).
+	query := r.db.WithContext(ctx).Table("(?) as latest", subQuery).
 		Joins("JOIN verification_submissions_log vsl ON vsl.phone_number = latest.phone_number AND vsl.created_at = latest.latest_time").
-		Where("vsl.action_type = ?", models.ActionConfirmUsage).
-		Count(&count).Error
+		Where("vsl.action_type = ?", models.ActionConfirmUsage)
+	err := scopePhoneNumbers(ctx, query, "vsl.phone_number").Count(&count).Error
 
 	return int(count), err
 }
@@ -88,10 +93,10 @@ func (r *gormVerificationSubmissionLogRepository) CountReportedIssuePhones(ctx c
 		Group("phone_number")
 
 	// 主查询统计最新操作为"report_issue"的手机号数量
-	err := r.db.WithContext(ctx).Table("(?) as latest", subQuery).
+	query := r.db.WithContext(ctx).Table("(?) as latest", subQuery).
 		Joins("JOIN verification_submissions_log vsl ON vsl.phone_number = latest.phone_number AND vsl.created_at = latest.latest_time").
-		Where("vsl.action_type = ?", models.ActionReportIssue).
-		Count(&count).Error
+		Where("vsl.action_type = ?", models.ActionReportIssue)
+	err := scopePhoneNumbers(ctx, query, "vsl.phone_number").Count(&count).Error
 
 	return int(count), err
 }
@@ -101,7 +106,8 @@ func (r *gormVerificationSubmissionLogRepository) CountPendingPhones(ctx context
 	var count int64
 
 	// 查询所有在系统中但未在日志表中有记录的手机号
-	err := r.db.WithContext(ctx).Model(&models.MobileNumber{}).
+	query := scopeMobileNumbers(ctx, r.db.WithContext(ctx), "mobile_numbers")
+	err := query.Model(&models.MobileNumber{}).
 		Where("phone_number NOT IN (SELECT DISTINCT phone_number FROM verification_submissions_log)").
 		Count(&count).Error
 
@@ -112,8 +118,9 @@ func (r *gormVerificationSubmissionLogRepository) CountPendingPhones(ctx context
 func (r *gormVerificationSubmissionLogRepository) CountNewlyReportedPhones(ctx context.Context) (int, error) {
 	var count int64
 
-	// 统计类型为"report_unlisted"的不同手机号数量
-	err := r.db.WithContext(ctx).Model(&models.VerificationSubmissionLog{}).
+	// 统计类型为"report_unlisted"的不同手机号数量，上报的号码不在系统中，按上报人所属部门限定
+	query := scopeEmployees(ctx, r.db.WithContext(ctx), "employee_id")
+	err := query.Model(&models.VerificationSubmissionLog{}).
 		Where("action_type = ?", models.ActionReportUnlisted).
 		Distinct("phone_number").
 		Count(&count).Error
@@ -174,14 +181,15 @@ func (r *gormVerificationSubmissionLogRepository) FindConfirmedPhoneDetails(ctx
 	}
 
 	// 构建联合查询，获取手机号详情
-	err := r.db.WithContext(ctx).Table("(?) as latest", subQuery).
+	query := r.db.WithContext(ctx).Table("(?) as latest", subQuery).
 		Select("mn.id, mn.phone_number, e_current.department, e_current.full_name as current_user, mn.purpose, "+
 			"e_confirmed.full_name as confirmed_by, vsl.created_at as confirmed_at").
 		Joins("JOIN verification_submissions_log vsl ON vsl.phone_number = latest.phone_number AND vsl.created_at = latest.latest_time").
 		Joins("JOIN mobile_numbers mn ON mn.phone_number = vsl.phone_number").
 		Joins("JOIN employees e_confirmed ON e_confirmed.employee_id = vsl.employee_id").
 		Joins("LEFT JOIN employees e_current ON e_current.employee_id = mn.current_employee_id").
-		Where("vsl.action_type = ?", models.ActionConfirmUsage).
+		Where("vsl.action_type = ?", models.ActionConfirmUsage)
+	err := scopeMobileNumbers(ctx, query, "mn").
 		Order("vsl.created_at desc").
 		Scan(&results).Error
 
@@ -210,3 +218,23 @@ func (r *gormVerificationSubmissionLogRepository) FindConfirmedPhoneDetails(ctx
 
 	return details, nil
 }
+
+// FindByPhoneNumber 查询指定手机号码的全部提交记录，按提交时间升序
+func (r *gormVerificationSubmissionLogRepository) FindByPhoneNumber(ctx context.Context, phoneNumber string) ([]models.VerificationSubmissionLog, error) {
+	var logs []models.VerificationSubmissionLog
+	err := r.db.WithContext(ctx).
+		Where("phone_number = ?", phoneNumber).
+		Order("created_at asc").
+		Find(&logs).Error
+	return logs, err
+}
+
+// FindByEmployeeID 查询指定员工的全部提交记录，按提交时间升序
+func (r *gormVerificationSubmissionLogRepository) FindByEmployeeID(ctx context.Context, employeeID string) ([]models.VerificationSubmissionLog, error) {
+	var logs []models.VerificationSubmissionLog
+	err := r.db.WithContext(ctx).
+		Where("employee_id = ?", employeeID).
+		Order("created_at asc").
+		Find(&logs).Error
+	return logs, err
+}
@@ -13,7 +13,12 @@ type VerificationTokenRepository interface {
 	Create(ctx context.Context, token *models.VerificationToken) error
 	FindByToken(ctx context.Context, token string) (*models.VerificationToken, error)
 	UpdateStatus(ctx context.Context, token string, status models.VerificationTokenStatus) error
-	FindPendingTokensWithEmployeeInfo(ctx context.Context, employeeID, departmentName string) ([]models.PendingUserDetail, error)
+	// FindPendingTokensWithEmployeeInfo 查询未响应的令牌，遵循 context 中的部门范围
+	FindPendingTokensWithEmployeeInfo(ctx context.Context, employeeID string, departmentIDs []uint) ([]models.PendingUserDetail, error)
+	// FindLatestPendingByEmployeeID 查询员工最近发出的未过期待确认令牌，遵循 context 中的部门范围
+	FindLatestPendingByEmployeeID(ctx context.Context, employeeID string) (*models.VerificationToken, error)
+	// FindUnansweredBatchTokens 查询由确认批次生成、在 now 时未过期且员工尚未提交任何确认结果的待确认令牌
+	FindUnansweredBatchTokens(ctx context.Context, now time.Time) ([]models.VerificationToken, error)
 }
 
 type gormVerificationTokenRepository struct {
@@ -46,27 +51,30 @@ func (r *gormVerificationTokenRepository) UpdateStatus(ctx context.Context, toke
 }
 
 // FindPendingTokensWithEmployeeInfo 查询未响应的令牌及相关员工信息
-func (r *gormVerificationTokenRepository) FindPendingTokensWithEmployeeInfo(ctx context.Context, employeeID, departmentName string) ([]models.PendingUserDetail, error) {
+func (r *gormVerificationTokenRepository) FindPendingTokensWithEmployeeInfo(ctx context.Context, employeeID string, departmentIDs []uint) ([]models.PendingUserDetail, error) {
 	var results []struct {
-		TokenID    uint      `gorm:"column:id"`
-		EmployeeID string    `gorm:"column:employee_id"`
-		ExpiresAt  time.Time `gorm:"column:expires_at"`
-		FullName   string    `gorm:"column:full_name"`
-		Email      *string   `gorm:"column:email"`
-		Department *string   `gorm:"column:department"`
+		TokenID        uint       `gorm:"column:id"`
+		EmployeeID     string     `gorm:"column:employee_id"`
+		ExpiresAt      time.Time  `gorm:"column:expires_at"`
+		LastRemindedAt *time.Time `gorm:"column:last_reminded_at"`
+		ReminderCount  int        `gorm:"column:reminder_count"`
+		FullName       string     `gorm:"column:full_name"`
+		Email          *string    `gorm:"column:email"`
+		Department     *string    `gorm:"column:department"`
 	}
 
 	query := r.db.WithContext(ctx).Table("verification_tokens vt").
-		Select("vt.id, vt.employee_id, vt.expires_at, e.full_name, e.email, e.department").
+		Select("vt.id, vt.employee_id, vt.expires_at, vt.last_reminded_at, vt.reminder_count, e.full_name, e.email, e.department").
 		Joins("JOIN employees e ON vt.employee_id = e.employee_id").
 		Where("vt.status = ? AND vt.expires_at > ?", models.VerificationTokenStatusPending, time.Now())
+	query = scopeEmployees(ctx, query, "vt.employee_id")
 
 	// 应用过滤条件
 	if employeeID != "" {
 		query = query.Where("vt.employee_id = ?", employeeID)
 	}
-	if departmentName != "" {
-		query = query.Where("e.department = ?", departmentName)
+	if departmentIDs != nil {
+		query = query.Where("e.department_id IN ?", departmentIDs)
 	}
 
 	err := query.Order("vt.expires_at asc").Scan(&results).Error
@@ -79,13 +87,52 @@ func (r *gormVerificationTokenRepository) FindPendingTokensWithEmployeeInfo(ctx
 	for _, r := range results {
 		expiresAt := r.ExpiresAt
 		pendingUsers = append(pendingUsers, models.PendingUserDetail{
-			EmployeeID: r.EmployeeID,
-			FullName:   r.FullName,
-			Email:      r.Email,
-			TokenID:    r.TokenID,
-			ExpiresAt:  &expiresAt,
+			EmployeeID:     r.EmployeeID,
+			FullName:       r.FullName,
+			Email:          r.Email,
+			TokenID:        r.TokenID,
+			ExpiresAt:      &expiresAt,
+			LastRemindedAt: r.LastRemindedAt,
+			ReminderCount:  r.ReminderCount,
 		})
 	}
 
 	return pendingUsers, nil
 }
+
+// FindLatestPendingByEmployeeID 查询员工最近发出的未过期待确认令牌
+func (r *gormVerificationTokenRepository) FindLatestPendingByEmployeeID(ctx context.Context, employeeID string) (*models.VerificationToken, error) {
+	var token models.VerificationToken
+	query := scopeEmployees(ctx, r.db.WithContext(ctx), "employee_id")
+	err := query.
+		Where("employee_id = ? AND status = ? AND expires_at > ?", employeeID, models.VerificationTokenStatusPending, time.Now()).
+		Order("created_at desc").
+		First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// FindUnansweredBatchTokens 查询尚未提交确认结果的批次令牌，按过期时间升序
+func (r *gormVerificationTokenRepository) FindUnansweredBatchTokens(ctx context.Context, now time.Time) ([]models.VerificationToken, error) {
+	var tokens []models.VerificationToken
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND expires_at > ? AND batch_id IS NOT NULL", models.VerificationTokenStatusPending, now).
+		Where("NOT EXISTS (?)", r.db.Model(&models.VerificationSubmissionLog{}).
+			Select("1").
+			Where("verification_submissions_log.verification_token_id = verification_tokens.id")).
+		Order("expires_at ASC, id ASC").
+		Find(&tokens).Error
+	return tokens, err
+}
+
+// reminderSnapshot 返回催办审计事件记录的令牌字段，不包含令牌本身，避免确认链接通过审计日志泄露
+func reminderSnapshot(token models.VerificationToken) map[string]interface{} {
+	return map[string]interface{}{
+		"employeeId":     token.EmployeeID,
+		"expiresAt":      token.ExpiresAt,
+		"lastRemindedAt": token.LastRemindedAt,
+		"reminderCount":  token.ReminderCount,
+	}
+}
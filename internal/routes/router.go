@@ -2,6 +2,7 @@ package routes
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/phone_management/configs"
 	"github.com/phone_management/internal/auth"
 	"github.com/phone_management/internal/handlers"
 	"github.com/phone_management/internal/repositories"
@@ -50,6 +51,28 @@ func SetupRouter(db *gorm.DB) *gin.Engine {
 	// JWT 中间件实例化
 	jwtAuthMiddleware := auth.JWTMiddleware()
 
+	// 权限中间件：各路由所需的权限在此统一声明，角色与权限的对应关系见 auth.rolePermissions
+	numberRead := auth.RequirePermission(auth.PermissionMobileNumberRead)
+	numberWrite := auth.RequirePermission(auth.PermissionMobileNumberWrite)
+	numberAssign := auth.RequirePermission(auth.PermissionMobileNumberAssign)
+	employeeRead := auth.RequirePermission(auth.PermissionEmployeeRead)
+	employeeWrite := auth.RequirePermission(auth.PermissionEmployeeWrite)
+	verificationRead := auth.RequirePermission(auth.PermissionVerificationRead)
+	verificationManage := auth.RequirePermission(auth.PermissionVerificationManage)
+	verificationRemind := auth.RequirePermission(auth.PermissionVerificationRemind)
+
+	// 系统用户相关依赖
+	userRepo := repositories.NewGormUserRepository(db)
+	refreshTokenRepo := repositories.NewGormRefreshTokenRepository(db)
+	departmentRepo := repositories.NewGormDepartmentRepository(db)
+	userService := services.NewUserService(userRepo, refreshTokenRepo, departmentRepo)
+	userHandler := handlers.NewUserHandler(userService)
+	recoveryCodeRepo := repositories.NewGormRecoveryCodeRepository(db)
+	twoFactorService := services.NewTwoFactorService(userRepo, recoveryCodeRepo)
+	twoFactorHandler := handlers.NewTwoFactorHandler(twoFactorService)
+	authService := services.NewAuthService(userRepo, refreshTokenRepo, twoFactorService)
+	authHandler := handlers.NewAuthHandler(authService)
+
 	// 创建 /api/v1 路由组
 	apiV1 := r.Group("/api/v1")
 	{
@@ -57,13 +80,57 @@ func SetupRouter(db *gorm.DB) *gin.Engine {
 		authGroup := apiV1.Group("/auth")
 		{
 			// POST /api/v1/auth/login - 公开路由，不需要JWT
-			authGroup.POST("/login", handlers.Login)
+			authGroup.POST("/login", authHandler.Login)
+
+			// POST /api/v1/auth/login/2fa - 公开路由，凭临时令牌和验证码完成两步验证登录
+			authGroup.POST("/login/2fa", authHandler.LoginWithTOTP)
+
+			// POST /api/v1/auth/refresh - 公开路由，使用刷新令牌换取新的令牌
+			authGroup.POST("/refresh", authHandler.Refresh)
 
 			// POST /api/v1/auth/logout - 受保护路由，需要JWT
-			// LogoutHandler 内部会处理JTI，所以应用JWT中间件
-			authGroup.POST("/logout", jwtAuthMiddleware, handlers.LogoutHandler)
+			// Logout 内部会处理JTI，所以应用JWT中间件
+			authGroup.POST("/logout", jwtAuthMiddleware, authHandler.Logout)
+
+			// POST /api/v1/auth/change-password - 当前用户修改自己的密码
+			authGroup.POST("/change-password", jwtAuthMiddleware, userHandler.ChangePassword)
+
+			// /api/v1/auth/2fa/* - 当前用户管理自己的两步验证
+			twoFactorGroup := authGroup.Group("/2fa")
+			twoFactorGroup.Use(jwtAuthMiddleware)
+			{
+				twoFactorGroup.POST("/setup", twoFactorHandler.SetupTOTP)
+				twoFactorGroup.POST("/enable", twoFactorHandler.EnableTOTP)
+				twoFactorGroup.POST("/disable", twoFactorHandler.DisableTOTP)
+				twoFactorGroup.POST("/recovery-codes", twoFactorHandler.RegenerateRecoveryCodes)
+			}
 		}
 
+		// --- 系统用户路由 ---
+		usersGroup := apiV1.Group("/users")
+		usersGroup.Use(jwtAuthMiddleware, auth.RequirePermission(auth.PermissionUserManage))
+		{
+			usersGroup.POST("/", userHandler.CreateUser)
+			usersGroup.GET("/", userHandler.GetUsers)
+			usersGroup.GET("/:id", userHandler.GetUserByID)
+			// POST /api/v1/users/:id/update - 更新角色或启用/禁用
+			usersGroup.POST("/:id/update", userHandler.UpdateUser)
+			// POST /api/v1/users/:id/reset-password - 管理员重置密码
+			usersGroup.POST("/:id/reset-password", userHandler.ResetPassword)
+			// POST /api/v1/users/:id/unlock - 解除登录失败导致的锁定
+			usersGroup.POST("/:id/unlock", userHandler.UnlockUser)
+			// POST /api/v1/users/:id/reset-2fa - 清除用户的两步验证（丢失设备时使用）
+			usersGroup.POST("/:id/reset-2fa", twoFactorHandler.ResetUserTOTP)
+			usersGroup.DELETE("/:id", userHandler.DeleteUser)
+		}
+
+		// --- 操作审计日志路由 ---
+		auditEventRepo := repositories.NewGormAuditEventRepository(db)
+		auditService := services.NewAuditService(auditEventRepo)
+		auditHandler := handlers.NewAuditHandler(auditService)
+		// GET /api/v1/audit-events - 查询审计事件
+		apiV1.GET("/audit-events", jwtAuthMiddleware, auth.RequirePermission(auth.PermissionAuditRead), auditHandler.GetAuditEvents)
+
 		// --- 员工路由 (先初始化，因为 MobileNumberService 依赖它) ---
 		employeeRepo := repositories.NewGormEmployeeRepository(db)
 
@@ -72,52 +139,125 @@ func SetupRouter(db *gorm.DB) *gin.Engine {
 		mobileNumberRepo := repositories.NewGormMobileNumberRepository(db)
 
 		// 初始化employeeService，现在需要mobileNumberRepo依赖
-		employeeService := services.NewEmployeeService(employeeRepo, mobileNumberRepo)
+		scheduledDepartureRepo := repositories.NewGormScheduledDepartureRepository(db)
+		outboundMessageRepo := repositories.NewGormOutboundMessageRepository(db)
+		employeeService := services.NewEmployeeService(employeeRepo, mobileNumberRepo, scheduledDepartureRepo, departmentRepo, outboundMessageRepo)
 		employeeHandler := handlers.NewEmployeeHandler(employeeService)
 
 		// 将 employeeService 注入到 MobileNumberService
 		mobileNumberService := services.NewMobileNumberService(mobileNumberRepo, employeeService)
 		mobileNumberHandler := handlers.NewMobileNumberHandler(mobileNumberService)
 
+		// 号码历史汇总使用记录、办卡人变更、确认提交和审计日志
+		usageHistoryRepo := repositories.NewGormNumberUsageHistoryRepository(db)
+		applicantHistoryRepo := repositories.NewGormNumberApplicantHistoryRepository(db)
+		submissionLogRepo := repositories.NewGormVerificationSubmissionLogRepository(db)
+		numberHistoryService := services.NewNumberHistoryService(mobileNumberRepo, employeeRepo, usageHistoryRepo, applicantHistoryRepo, submissionLogRepo, auditEventRepo)
+		numberHistoryHandler := handlers.NewNumberHistoryHandler(numberHistoryService)
+
 		mobileNumbersGroup := apiV1.Group("/mobilenumbers")
 		mobileNumbersGroup.Use(jwtAuthMiddleware) // 对整个 /mobilenumbers 路由组应用 JWT 中间件
 		{
 			// POST /api/v1/mobilenumbers/
-			mobileNumbersGroup.POST("/", mobileNumberHandler.CreateMobileNumber)
+			mobileNumbersGroup.POST("/", numberWrite, mobileNumberHandler.CreateMobileNumber)
 			// GET /api/v1/mobilenumbers/
-			mobileNumbersGroup.GET("/", mobileNumberHandler.GetMobileNumbers)
+			mobileNumbersGroup.GET("/", numberRead, mobileNumberHandler.GetMobileNumbers)
 			// GET /api/v1/mobilenumbers/risk-pending - 获取风险号码列表
-			mobileNumbersGroup.GET("/risk-pending", mobileNumberHandler.GetRiskPendingNumbers)
+			mobileNumbersGroup.GET("/risk-pending", numberRead, mobileNumberHandler.GetRiskPendingNumbers)
+			// GET /api/v1/mobilenumbers/pending-deactivation - 获取未来N天内将注销的号码
+			mobileNumbersGroup.GET("/pending-deactivation", numberRead, mobileNumberHandler.GetUpcomingDeactivations)
 			// GET /api/v1/mobilenumbers/:phoneNumber
-			mobileNumbersGroup.GET("/:phoneNumber", mobileNumberHandler.GetMobileNumberByID)
-			mobileNumbersGroup.POST("/:phoneNumber/update", mobileNumberHandler.UpdateMobileNumber)
-			mobileNumbersGroup.POST("/:phoneNumber/assign", mobileNumberHandler.AssignMobileNumber)
-			mobileNumbersGroup.POST("/:phoneNumber/unassign", mobileNumberHandler.UnassignMobileNumber)
+			mobileNumbersGroup.GET("/:phoneNumber", numberRead, mobileNumberHandler.GetMobileNumberByID)
+			// GET /api/v1/mobilenumbers/:phoneNumber/timeline - 号码时间线
+			mobileNumbersGroup.GET("/:phoneNumber/timeline", numberRead, numberHistoryHandler.GetNumberTimeline)
+			mobileNumbersGroup.POST("/:phoneNumber/update", numberWrite, mobileNumberHandler.UpdateMobileNumber)
+			mobileNumbersGroup.POST("/:phoneNumber/assign", numberAssign, mobileNumberHandler.AssignMobileNumber)
+			mobileNumbersGroup.POST("/:phoneNumber/unassign", numberAssign, mobileNumberHandler.UnassignMobileNumber)
+			// POST /api/v1/mobilenumbers/:phoneNumber/transfer - 在员工之间直接转移号码
+			mobileNumbersGroup.POST("/:phoneNumber/transfer", numberAssign, mobileNumberHandler.TransferMobileNumber)
 			// POST /api/v1/mobilenumbers/:phoneNumber/handle-risk - 处理风险号码
-			mobileNumbersGroup.POST("/:phoneNumber/handle-risk", mobileNumberHandler.HandleRiskNumber)
+			mobileNumbersGroup.POST("/:phoneNumber/handle-risk", numberWrite, mobileNumberHandler.HandleRiskNumber)
+			// POST /api/v1/mobilenumbers/:phoneNumber/cancel-deactivation - 宽限期内取消注销
+			mobileNumbersGroup.POST("/:phoneNumber/cancel-deactivation", numberWrite, mobileNumberHandler.CancelDeactivation)
 			// POST /api/v1/mobilenumbers/import 批量导入手机号码
-			mobileNumbersGroup.POST("/import", mobileNumberHandler.BatchImportMobileNumbers)
+			mobileNumbersGroup.POST("/import", numberWrite, mobileNumberHandler.BatchImportMobileNumbers)
+			// POST /api/v1/mobilenumbers/bulk 对多个号码执行同一操作
+			mobileNumbersGroup.POST("/bulk", numberWrite, mobileNumberHandler.BulkMobileNumbers)
 		}
 
+		// 员工目录同步经由 employeeService 新建、更新员工和办理离职，目录中不存在的部门通过 departmentService 创建
+		departmentService := services.NewDepartmentService(departmentRepo, employeeRepo)
+		directorySyncService := services.NewDirectorySyncService(
+			services.NewLDAPDirectorySource(configs.AppConfig),
+			employeeService, employeeRepo, departmentService, departmentRepo,
+			repositories.NewGormDirectorySyncRunRepository(db),
+		)
+		directorySyncHandler := handlers.NewDirectorySyncHandler(directorySyncService)
+
 		// --- 员工路由组定义放在后面，但初始化已提前 ---
 		employeeRoutes := apiV1.Group("/employees")
 		employeeRoutes.Use(jwtAuthMiddleware)
 		{
-			employeeRoutes.POST("/", employeeHandler.CreateEmployee)
-			employeeRoutes.GET("/", employeeHandler.GetEmployees)
-			employeeRoutes.GET("/:employeeId", employeeHandler.GetEmployeeByID)
+			employeeRoutes.POST("/", employeeWrite, employeeHandler.CreateEmployee)
+			employeeRoutes.GET("/", employeeRead, employeeHandler.GetEmployees)
+			// GET /api/v1/employees/scheduled-departures - 尚未办理的预约离职
+			employeeRoutes.GET("/scheduled-departures", employeeRead, employeeHandler.GetScheduledDepartures)
+			// POST /api/v1/employees/directory-sync - 从员工目录同步员工
+			employeeRoutes.POST("/directory-sync", employeeWrite, directorySyncHandler.SyncDirectory)
+			// GET /api/v1/employees/directory-sync/runs - 目录同步记录
+			employeeRoutes.GET("/directory-sync/runs", employeeRead, directorySyncHandler.GetDirectorySyncRuns)
+			employeeRoutes.GET("/directory-sync/runs/:runId", employeeRead, directorySyncHandler.GetDirectorySyncRun)
+			employeeRoutes.GET("/:employeeId", employeeRead, employeeHandler.GetEmployeeByID)
+			// GET /api/v1/employees/:employeeId/number-history - 员工号码历史
+			employeeRoutes.GET("/:employeeId/number-history", employeeRead, numberHistoryHandler.GetEmployeeNumberHistory)
 			// POST /api/v1/employees/:employeeId/update
-			employeeRoutes.POST("/:employeeId/update", employeeHandler.UpdateEmployee)
+			employeeRoutes.POST("/:employeeId/update", employeeWrite, employeeHandler.UpdateEmployee)
+			// POST /api/v1/employees/:employeeId/departure-preview - 预览离职时的号码处置结果
+			employeeRoutes.POST("/:employeeId/departure-preview", employeeWrite, employeeHandler.PreviewDeparture)
+			// GET /api/v1/employees/:employeeId/rehire-preview - 预览复职时可恢复的风险号码
+			employeeRoutes.GET("/:employeeId/rehire-preview", employeeWrite, employeeHandler.PreviewRehire)
+			// POST /api/v1/employees/:employeeId/rehire - 办理离职员工复职
+			employeeRoutes.POST("/:employeeId/rehire", employeeWrite, employeeHandler.RehireEmployee)
 			// POST /api/v1/employees/import 批量导入员工
-			employeeRoutes.POST("/import", employeeHandler.BatchImportEmployees)
+			employeeRoutes.POST("/import", employeeWrite, employeeHandler.BatchImportEmployees)
+		}
+
+		// --- HR 系统集成路由 ---
+		// 事件经由 employeeService 新建或更新员工
+		hrWebhookService := services.NewHRWebhookService(repositories.NewGormHREventRepository(db), employeeService, employeeRepo)
+		hrWebhookHandler := handlers.NewHRWebhookHandler(hrWebhookService)
+		hrGroup := apiV1.Group("/integrations/hr")
+		{
+			// POST /api/v1/integrations/hr/events - 公开路由，以 HMAC 签名认证
+			hrGroup.POST("/events", hrWebhookHandler.ReceiveHREvent)
+			// GET /api/v1/integrations/hr/dead-letters - 处理失败的事件
+			hrGroup.GET("/dead-letters", jwtAuthMiddleware, employeeRead, hrWebhookHandler.GetHRDeadLetters)
+			// POST /api/v1/integrations/hr/dead-letters/:id/replay - 重放处理失败的事件
+			hrGroup.POST("/dead-letters/:id/replay", jwtAuthMiddleware, employeeWrite, hrWebhookHandler.ReplayHRDeadLetter)
+		}
+
+		// --- 部门路由 ---
+		departmentHandler := handlers.NewDepartmentHandler(departmentService)
+		departmentRoutes := apiV1.Group("/departments")
+		departmentRoutes.Use(jwtAuthMiddleware)
+		{
+			departmentRoutes.GET("/", employeeRead, departmentHandler.GetDepartments)
+			// GET /api/v1/departments/tree - 部门树
+			departmentRoutes.GET("/tree", employeeRead, departmentHandler.GetDepartmentTree)
+			departmentRoutes.POST("/", employeeWrite, departmentHandler.CreateDepartment)
+			departmentRoutes.POST("/:id/update", employeeWrite, departmentHandler.UpdateDepartment)
+			departmentRoutes.DELETE("/:id", employeeWrite, departmentHandler.DeleteDepartment)
+			// POST /api/v1/departments/:id/merge - 将其他部门合并到该部门
+			departmentRoutes.POST("/:id/merge", employeeWrite, departmentHandler.MergeDepartments)
 		}
 
 		// --- 号码验证路由 ---
 		verificationTokenRepo := repositories.NewGormVerificationTokenRepository(db)
 		verificationBatchTaskRepo := repositories.NewGormVerificationBatchTaskRepository(db)
 		userReportedIssueRepo := repositories.NewGormUserReportedIssueRepository(db)
-		submissionLogRepo := repositories.NewGormVerificationSubmissionLogRepository(db)
-		verificationService := services.NewVerificationService(employeeRepo, verificationTokenRepo, verificationBatchTaskRepo, mobileNumberRepo, userReportedIssueRepo, submissionLogRepo, db)
+		emailTemplateService := services.NewEmailTemplateService(repositories.NewGormEmailTemplateRepository(db))
+		notifier := services.NewNotifier(configs.AppConfig)
+		verificationService := services.NewVerificationService(employeeRepo, verificationTokenRepo, verificationBatchTaskRepo, mobileNumberRepo, userReportedIssueRepo, submissionLogRepo, departmentRepo, outboundMessageRepo, emailTemplateService, db)
 		verificationHandler := handlers.NewVerificationHandler(verificationService)
 
 		// 公开的验证接口，不需要JWT认证
@@ -128,14 +268,42 @@ func SetupRouter(db *gorm.DB) *gin.Engine {
 		verificationGroup.Use(jwtAuthMiddleware) // 对 /verification 路由组应用 JWT 中间件
 		{
 			// POST /api/v1/verification/initiate
-			verificationGroup.POST("/initiate", verificationHandler.InitiateVerification)
+			verificationGroup.POST("/initiate", verificationManage, verificationHandler.InitiateVerification)
 			// GET /api/v1/verification/batch/{batchId}/status
-			verificationGroup.GET("/batch/:batchId/status", verificationHandler.GetVerificationBatchStatus)
+			verificationGroup.GET("/batch/:batchId/status", verificationRead, verificationHandler.GetVerificationBatchStatus)
 			// GET /api/v1/verification/admin/phone-status - 基于手机号维度的确认状态
-			verificationGroup.GET("/admin/phone-status", verificationHandler.GetPhoneVerificationStatus)
+			verificationGroup.GET("/admin/phone-status", verificationRead, verificationHandler.GetPhoneVerificationStatus)
+			// POST /api/v1/verification/pending/{employeeId}/remind - 催办未确认的员工
+			verificationGroup.POST("/pending/:employeeId/remind", verificationRemind, verificationHandler.RemindPendingEmployee)
 			// 其他 /verification 子路由可以在这里添加，例如 GET /info, POST /submit, GET /admin/status
 		}
 
+		// --- 通知模板路由 ---
+		emailTemplateHandler := handlers.NewEmailTemplateHandler(emailTemplateService)
+		emailTemplateRoutes := apiV1.Group("/email-templates")
+		emailTemplateRoutes.Use(jwtAuthMiddleware, auth.RequirePermission(auth.PermissionNotificationManage))
+		{
+			emailTemplateRoutes.GET("/", emailTemplateHandler.GetEmailTemplates)
+			emailTemplateRoutes.POST("/", emailTemplateHandler.CreateEmailTemplate)
+			// POST /api/v1/email-templates/preview - 以示例数据预览模板
+			emailTemplateRoutes.POST("/preview", emailTemplateHandler.PreviewEmailTemplate)
+			emailTemplateRoutes.GET("/:id", emailTemplateHandler.GetEmailTemplate)
+			emailTemplateRoutes.POST("/:id/update", emailTemplateHandler.UpdateEmailTemplate)
+			emailTemplateRoutes.DELETE("/:id", emailTemplateHandler.DeleteEmailTemplate)
+		}
+
+		// --- 通知发送队列路由 ---
+		// 消息由 cmd/server 启动的工作协程发送，这里只提供查询和重试
+		outboundMessageHandler := handlers.NewOutboundMessageHandler(services.NewOutboundMessageService(outboundMessageRepo, notifier))
+		outboundMessageRoutes := apiV1.Group("/outbound-messages")
+		outboundMessageRoutes.Use(jwtAuthMiddleware, auth.RequirePermission(auth.PermissionNotificationManage))
+		{
+			outboundMessageRoutes.GET("/", outboundMessageHandler.GetOutboundMessages)
+			outboundMessageRoutes.GET("/:id", outboundMessageHandler.GetOutboundMessage)
+			// POST /api/v1/outbound-messages/{id}/retry - 重新发送失败的通知
+			outboundMessageRoutes.POST("/:id/retry", outboundMessageHandler.RetryOutboundMessage)
+		}
+
 	}
 
 	// Swagger 文档路由 (如果使用 swaggo)
@@ -0,0 +1,24 @@
+// Package scope 限定请求可访问的数据范围。
+// 数据范围通过 context 传递：JWT 中间件为部门负责人写入其负责的部门，
+// 仓库层在构建查询时读取并强制应用，处理器和服务层不需要也不能绕过。
+package scope
+
+import "context"
+
+type departmentsKey struct{}
+
+// WithDepartments 返回只能访问指定部门数据的 context，departmentIDs 为空表示不能访问任何部门的数据
+func WithDepartments(ctx context.Context, departmentIDs []uint) context.Context {
+	ids := make([]uint, len(departmentIDs))
+	copy(ids, departmentIDs)
+	return context.WithValue(ctx, departmentsKey{}, ids)
+}
+
+// Departments 返回 context 限定的部门ID，restricted 为 false 表示不限定部门（管理员、操作员和系统任务）
+func Departments(ctx context.Context) (departmentIDs []uint, restricted bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	departmentIDs, restricted = ctx.Value(departmentsKey{}).([]uint)
+	return departmentIDs, restricted
+}
@@ -0,0 +1,26 @@
+package services
+
+import (
+	"github.com/phone_management/internal/models"
+	"github.com/phone_management/internal/repositories"
+)
+
+// AuditService 定义了操作审计日志查询服务的接口
+type AuditService interface {
+	ListAuditEvents(filter models.AuditEventFilter, page, limit int) ([]models.AuditEvent, int64, error)
+}
+
+// auditService 是 AuditService 的实现
+type auditService struct {
+	repo repositories.AuditEventRepository
+}
+
+// NewAuditService 创建一个新的 auditService 实例
+func NewAuditService(repo repositories.AuditEventRepository) AuditService {
+	return &auditService{repo: repo}
+}
+
+// ListAuditEvents 按条件分页查询审计事件
+func (s *auditService) ListAuditEvents(filter models.AuditEventFilter, page, limit int) ([]models.AuditEvent, int64, error) {
+	return s.repo.List(filter, page, limit)
+}
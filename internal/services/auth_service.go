@@ -0,0 +1,382 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/phone_management/configs"
+	"github.com/phone_management/internal/auth"
+	"github.com/phone_management/internal/models"
+	"github.com/phone_management/internal/repositories"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// 认证相关错误
+var ErrInvalidCredentials = errors.New("无效的用户名或密码")
+var ErrUserDisabled = errors.New("账号已被禁用")
+var ErrInvalidRefreshToken = errors.New("刷新令牌无效或已过期")
+var ErrRefreshTokenReused = errors.New("检测到刷新令牌被重复使用，该登录会话已被吊销")
+var ErrAccountLocked = errors.New("登录失败次数过多，账号已被临时锁定")
+var ErrTooManyLoginAttempts = errors.New("登录尝试过于频繁，请稍后再试")
+var ErrInvalidMFAToken = errors.New("两步验证临时令牌无效或已过期，请重新登录")
+
+// LoginBlockedError 表示登录因锁定或退避被拒绝，RetryAt 为允许再次尝试的时间
+type LoginBlockedError struct {
+	Err     error // ErrAccountLocked 或 ErrTooManyLoginAttempts
+	RetryAt time.Time
+}
+
+func (e *LoginBlockedError) Error() string { return e.Err.Error() }
+
+func (e *LoginBlockedError) Unwrap() error { return e.Err }
+
+// dummyPasswordHash 用于不存在的用户名的密码比对，使其耗时与存在的用户名一致
+var dummyPasswordHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("phone-management-dummy-password"), bcrypt.DefaultCost)
+	return hash
+})
+
+// TokenPair 是登录或刷新后返回的一组令牌
+type TokenPair struct {
+	AccessToken          string
+	AccessTokenExpiresAt time.Time
+	RefreshToken         string
+}
+
+// LoginResult 是第一步（密码）登录的结果。
+// 用户启用了两步验证时 Tokens 为空，需要凭 MFAToken 调用 CompleteMFALogin。
+type LoginResult struct {
+	User              *models.User
+	Tokens            *TokenPair
+	MFAToken          string
+	MFATokenExpiresAt time.Time
+}
+
+// AuthService 定义了登录认证服务的接口
+type AuthService interface {
+	// Login 校验用户名密码，签发访问令牌和一个新令牌族的刷新令牌；启用两步验证的用户改为签发临时令牌。
+	// clientIP 用于按来源统计失败次数；被锁定或需要退避时返回 *LoginBlockedError。
+	Login(username, password, clientIP string) (*LoginResult, error)
+	// CompleteMFALogin 使用临时令牌和 TOTP 验证码（或恢复码）完成两步验证登录
+	CompleteMFALogin(mfaToken, code, clientIP string) (*TokenPair, *models.User, error)
+	// Refresh 使用刷新令牌换取新的令牌对，旧刷新令牌随即失效。
+	// 已失效的刷新令牌被再次使用时，吊销其所在的整个令牌族。
+	Refresh(refreshToken string) (*TokenPair, *models.User, error)
+	// RevokeRefreshToken 登出时吊销刷新令牌所在的令牌族
+	RevokeRefreshToken(refreshToken string) error
+}
+
+// authService 是 AuthService 的实现
+type authService struct {
+	userRepo         repositories.UserRepository
+	refreshTokenRepo repositories.RefreshTokenRepository
+	twoFactorService TwoFactorService
+	ipLimiter        *auth.LoginLimiter
+	unknownLimiter   *auth.UnknownUsernameLimiter // 不存在的用户名的失败计数、退避和锁定，与账号的规则相同
+}
+
+// NewAuthService 创建一个新的 authService 实例，登录限流参数取自 configs.AppConfig
+func NewAuthService(userRepo repositories.UserRepository, refreshTokenRepo repositories.RefreshTokenRepository, twoFactorService TwoFactorService) AuthService {
+	cfg := configs.AppConfig
+	return &authService{
+		userRepo:         userRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		twoFactorService: twoFactorService,
+		ipLimiter:        auth.NewLoginLimiter(cfg.LoginIPMaxFailures, cfg.LoginLockoutDuration, cfg.LoginBackoffBase, cfg.LoginBackoffMax),
+		unknownLimiter:   auth.NewUnknownUsernameLimiter(cfg.LoginMaxFailures, cfg.LoginLockoutDuration, cfg.LoginBackoffBase, cfg.LoginBackoffMax),
+	}
+}
+
+// Login 校验用户名密码并签发令牌
+func (s *authService) Login(username, password, clientIP string) (*LoginResult, error) {
+	now := time.Now()
+
+	if retryAt := s.ipLimiter.RetryAt(clientIP, now); !retryAt.IsZero() {
+		return nil, &LoginBlockedError{Err: ErrTooManyLoginAttempts, RetryAt: retryAt}
+	}
+
+	user, err := s.userRepo.GetUserByUsername(username)
+	if err != nil {
+		if errors.Is(err, repositories.ErrRecordNotFound) {
+			return nil, s.failUnknownUsername(username, password, clientIP, now)
+		}
+		return nil, err
+	}
+
+	if err := s.checkLoginAllowed(user, now); err != nil {
+		return nil, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		s.ipLimiter.RecordFailure(clientIP, now)
+		return nil, s.recordFailedLogin(user.ID, now)
+	}
+
+	if user.Status == models.UserStatusDisabled {
+		return nil, ErrUserDisabled
+	}
+
+	// 启用了两步验证：密码正确后只签发临时令牌，失败计数在第二步成功后才清零
+	if user.TOTPEnabled {
+		mfaToken, expiresAt, err := auth.GenerateMFAToken(user)
+		if err != nil {
+			return nil, err
+		}
+		return &LoginResult{User: user, MFAToken: mfaToken, MFATokenExpiresAt: expiresAt}, nil
+	}
+
+	pair, err := s.completeLogin(user)
+	if err != nil {
+		return nil, err
+	}
+	return &LoginResult{User: user, Tokens: pair}, nil
+}
+
+// CompleteMFALogin 完成两步验证登录
+func (s *authService) CompleteMFALogin(mfaToken, code, clientIP string) (*TokenPair, *models.User, error) {
+	now := time.Now()
+
+	claims, err := auth.ParseMFAToken(mfaToken)
+	if err != nil || claims.ID == "" {
+		return nil, nil, ErrInvalidMFAToken
+	}
+	// 临时令牌只能成功使用一次
+	used, err := auth.IsTokenDenylisted(claims.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if used {
+		return nil, nil, ErrInvalidMFAToken
+	}
+
+	if retryAt := s.ipLimiter.RetryAt(clientIP, now); !retryAt.IsZero() {
+		return nil, nil, &LoginBlockedError{Err: ErrTooManyLoginAttempts, RetryAt: retryAt}
+	}
+
+	user, err := s.userRepo.GetUserByID(int64(claims.UserID))
+	if err != nil {
+		if errors.Is(err, repositories.ErrRecordNotFound) {
+			return nil, nil, ErrInvalidMFAToken
+		}
+		return nil, nil, err
+	}
+	if err := s.checkLoginAllowed(user, now); err != nil {
+		return nil, nil, err
+	}
+	if user.Status == models.UserStatusDisabled {
+		return nil, nil, ErrUserDisabled
+	}
+	if !user.TOTPEnabled {
+		return nil, nil, ErrInvalidMFAToken
+	}
+
+	if err := s.twoFactorService.VerifySecondFactor(user, code); err != nil {
+		if !errors.Is(err, ErrInvalidTOTPCode) {
+			return nil, nil, err
+		}
+		s.ipLimiter.RecordFailure(clientIP, now)
+		if lockErr := s.recordFailedLogin(user.ID, now); !errors.Is(lockErr, ErrInvalidCredentials) {
+			return nil, nil, lockErr
+		}
+		return nil, nil, ErrInvalidTOTPCode
+	}
+
+	if err := auth.AddToDenylist(claims.ID, claims.ExpiresAt.Time); err != nil {
+		return nil, nil, err
+	}
+
+	pair, err := s.completeLogin(user)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pair, user, nil
+}
+
+// failUnknownUsername 拒绝不存在的用户名的登录，按与存在的账号相同的规则退避和锁定，
+// 并同样进行一次密码比对，避免通过错误类型或响应时间判断用户名是否存在
+func (s *authService) failUnknownUsername(username, password, clientIP string, now time.Time) error {
+	if retryAt, locked := s.unknownLimiter.RetryAt(username, now); !retryAt.IsZero() {
+		if locked {
+			return &LoginBlockedError{Err: ErrAccountLocked, RetryAt: retryAt}
+		}
+		return &LoginBlockedError{Err: ErrTooManyLoginAttempts, RetryAt: retryAt}
+	}
+
+	_ = bcrypt.CompareHashAndPassword(dummyPasswordHash(), []byte(password))
+	s.ipLimiter.RecordFailure(clientIP, now)
+	if lockedUntil := s.unknownLimiter.RecordFailure(username, now); !lockedUntil.IsZero() {
+		return &LoginBlockedError{Err: ErrAccountLocked, RetryAt: lockedUntil}
+	}
+	return ErrInvalidCredentials
+}
+
+// checkLoginAllowed 检查账号是否被锁定，或是否仍处于连续失败后的退避时间内
+func (s *authService) checkLoginAllowed(user *models.User, now time.Time) error {
+	if user.IsLocked(now) {
+		return &LoginBlockedError{Err: ErrAccountLocked, RetryAt: *user.LockedUntil}
+	}
+
+	// 同一用户名连续失败后，每次尝试之间需要等待指数增长的时间
+	if user.FailedLoginCount > 0 && user.LastFailedLoginAt != nil {
+		cfg := configs.AppConfig
+		delay := auth.BackoffDelay(user.FailedLoginCount, 1, cfg.LoginBackoffBase, cfg.LoginBackoffMax)
+		if retryAt := user.LastFailedLoginAt.Add(delay); now.Before(retryAt) {
+			return &LoginBlockedError{Err: ErrTooManyLoginAttempts, RetryAt: retryAt}
+		}
+	}
+	return nil
+}
+
+// completeLogin 登录成功：清零该用户名的失败计数，签发访问令牌和一个新令牌族的刷新令牌。
+// 来源IP的失败计数不清零，只随时间窗口过期，避免持有一个有效账号的人在猜测其他用户名的间隙重置IP限制
+func (s *authService) completeLogin(user *models.User) (*TokenPair, error) {
+	if user.FailedLoginCount > 0 || user.LockedUntil != nil {
+		if _, err := s.userRepo.UpdateLoginState(user.ID, map[string]interface{}{
+			"failed_login_count":   0,
+			"last_failed_login_at": nil,
+			"locked_until":         nil,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	refreshToken, record, err := newRefreshToken(user.ID, uuid.NewString())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.refreshTokenRepo.Create(record); err != nil {
+		return nil, err
+	}
+	return issueTokenPair(user, refreshToken)
+}
+
+// Refresh 轮换刷新令牌并签发新的访问令牌
+func (s *authService) Refresh(refreshToken string) (*TokenPair, *models.User, error) {
+	current, err := s.refreshTokenRepo.GetByHash(hashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, repositories.ErrRecordNotFound) {
+			return nil, nil, ErrInvalidRefreshToken
+		}
+		return nil, nil, err
+	}
+
+	// 已被轮换或吊销的令牌再次出现，说明令牌可能被盗用，吊销整个令牌族
+	if current.RevokedAt != nil {
+		if err := s.refreshTokenRepo.RevokeFamily(current.FamilyID); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, ErrRefreshTokenReused
+	}
+	if time.Now().After(current.ExpiresAt) {
+		return nil, nil, ErrInvalidRefreshToken
+	}
+
+	user, err := s.userRepo.GetUserByID(current.UserID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrRecordNotFound) {
+			_ = s.refreshTokenRepo.RevokeFamily(current.FamilyID)
+			return nil, nil, ErrInvalidRefreshToken
+		}
+		return nil, nil, err
+	}
+	if user.Status == models.UserStatusDisabled {
+		_ = s.refreshTokenRepo.RevokeFamily(current.FamilyID)
+		return nil, nil, ErrUserDisabled
+	}
+
+	newToken, record, err := newRefreshToken(user.ID, current.FamilyID)
+	if err != nil {
+		return nil, nil, err
+	}
+	rotated, err := s.refreshTokenRepo.Rotate(current.ID, record)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !rotated {
+		// 并发请求已抢先使用了该令牌，同样视为重复使用
+		if err := s.refreshTokenRepo.RevokeFamily(current.FamilyID); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, ErrRefreshTokenReused
+	}
+
+	pair, err := issueTokenPair(user, newToken)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pair, user, nil
+}
+
+// recordFailedLogin 累加用户名的失败次数，达到阈值时锁定账号并清零计数
+func (s *authService) recordFailedLogin(userID int64, now time.Time) error {
+	user, err := s.userRepo.IncrementFailedLogin(userID)
+	if err != nil {
+		return err
+	}
+	if user.FailedLoginCount < configs.AppConfig.LoginMaxFailures {
+		return ErrInvalidCredentials
+	}
+
+	lockedUntil := now.Add(configs.AppConfig.LoginLockoutDuration)
+	if _, err := s.userRepo.UpdateLoginState(userID, map[string]interface{}{
+		"failed_login_count":   0,
+		"last_failed_login_at": nil,
+		"locked_until":         lockedUntil,
+	}); err != nil {
+		return err
+	}
+	return &LoginBlockedError{Err: ErrAccountLocked, RetryAt: lockedUntil}
+}
+
+// RevokeRefreshToken 吊销刷新令牌所在的令牌族，令牌不存在时忽略
+func (s *authService) RevokeRefreshToken(refreshToken string) error {
+	current, err := s.refreshTokenRepo.GetByHash(hashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, repositories.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	return s.refreshTokenRepo.RevokeFamily(current.FamilyID)
+}
+
+// issueTokenPair 签发访问令牌并与刷新令牌组合
+func issueTokenPair(user *models.User, refreshToken string) (*TokenPair, error) {
+	accessToken, expiresAt, err := auth.GenerateAccessToken(user)
+	if err != nil {
+		return nil, err
+	}
+	return &TokenPair{
+		AccessToken:          accessToken,
+		AccessTokenExpiresAt: expiresAt,
+		RefreshToken:         refreshToken,
+	}, nil
+}
+
+// newRefreshToken 生成随机刷新令牌及其待保存的记录
+func newRefreshToken(userID int64, familyID string) (string, *models.RefreshToken, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", nil, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+	record := &models.RefreshToken{
+		UserID:    userID,
+		TokenHash: hashToken(token),
+		FamilyID:  familyID,
+		ExpiresAt: time.Now().Add(configs.AppConfig.RefreshTokenTTL),
+	}
+	return token, record, nil
+}
+
+// hashToken 计算令牌（刷新令牌、恢复码等）的 SHA-256 哈希，数据库中只保存哈希
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
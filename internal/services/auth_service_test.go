@@ -0,0 +1,73 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phone_management/configs"
+	"github.com/phone_management/internal/models"
+	"github.com/phone_management/internal/repositories"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// loginErrorKind 将登录错误归类，用于比较存在和不存在的用户名得到的响应
+func loginErrorKind(err error) string {
+	switch {
+	case errors.Is(err, ErrAccountLocked):
+		return "locked"
+	case errors.Is(err, ErrTooManyLoginAttempts):
+		return "backoff"
+	case errors.Is(err, ErrInvalidCredentials):
+		return "invalid"
+	case err == nil:
+		return "ok"
+	}
+	return err.Error()
+}
+
+func TestLoginUnknownUsernameMatchesExistingAccount(t *testing.T) {
+	tests := []struct {
+		name        string
+		backoffBase time.Duration
+		want        []string
+	}{
+		{"lockout", 0, []string{"invalid", "invalid", "locked", "locked"}},
+		{"backoff", time.Minute, []string{"invalid", "backoff", "backoff"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saved := configs.AppConfig
+			t.Cleanup(func() { configs.AppConfig = saved })
+			configs.AppConfig.LoginMaxFailures = 3
+			configs.AppConfig.LoginLockoutDuration = 15 * time.Minute
+			configs.AppConfig.LoginIPMaxFailures = 100
+			configs.AppConfig.LoginBackoffBase = tt.backoffBase
+			configs.AppConfig.LoginBackoffMax = time.Hour
+
+			db := newTestDB(t)
+			hash, err := bcrypt.GenerateFromPassword([]byte("correct-password"), bcrypt.MinCost)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := db.Create(&models.User{Username: "alice", PasswordHash: string(hash), Role: models.RoleAdmin, Status: models.UserStatusActive}).Error; err != nil {
+				t.Fatal(err)
+			}
+			svc := NewAuthService(repositories.NewGormUserRepository(db), repositories.NewGormRefreshTokenRepository(db), nil)
+
+			for _, username := range []string{"alice", "ghost"} {
+				var got []string
+				for range tt.want {
+					_, err := svc.Login(username, "wrong-password", "10.0.0.1")
+					got = append(got, loginErrorKind(err))
+				}
+				for i := range tt.want {
+					if got[i] != tt.want[i] {
+						t.Errorf("%s: attempts = %v, want %v", username, got, tt.want)
+						break
+					}
+				}
+			}
+		})
+	}
+}
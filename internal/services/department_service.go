@@ -0,0 +1,276 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/phone_management/internal/models"
+	"github.com/phone_management/internal/repositories"
+)
+
+// ErrDepartmentNotFound 表示部门未找到
+var ErrDepartmentNotFound = errors.New("部门未找到")
+
+// ErrDepartmentNameExists 表示部门名称已被其他部门或其别名使用
+var ErrDepartmentNameExists = errors.New("部门名称已存在")
+
+// ErrInvalidDepartmentName 表示部门名称规范化后为空
+var ErrInvalidDepartmentName = errors.New("部门名称不能为空")
+
+// ErrInvalidDepartmentParent 表示上级部门无效（不存在，或是部门自身及其下级部门）
+var ErrInvalidDepartmentParent = errors.New("上级部门无效")
+
+// ErrDepartmentManagerNotFound 表示指定的部门负责人员工不存在
+var ErrDepartmentManagerNotFound = errors.New("部门负责人员工未找到")
+
+// ErrDepartmentInUse 表示部门仍有下级部门或员工，不能删除
+var ErrDepartmentInUse = errors.New("部门仍有下级部门或员工，不能删除")
+
+// ErrInvalidDepartmentMerge 表示合并部门的请求无效
+var ErrInvalidDepartmentMerge = errors.New("合并部门请求无效")
+
+// DepartmentService 定义了部门服务的接口
+type DepartmentService interface {
+	CreateDepartment(ctx context.Context, payload models.CreateDepartmentPayload) (*models.Department, error)
+	// GetDepartments 返回全部部门（平铺），按名称升序
+	GetDepartments(ctx context.Context) ([]models.DepartmentResponse, error)
+	// GetDepartmentTree 返回部门树，顶级部门及各级下级部门均按名称升序
+	GetDepartmentTree(ctx context.Context) ([]models.DepartmentResponse, error)
+	UpdateDepartment(ctx context.Context, id uint, payload models.UpdateDepartmentPayload) (*models.Department, error)
+	DeleteDepartment(ctx context.Context, id uint) error
+	// MergeDepartments 将多个部门合并到目标部门，用于合并同一部门的不同写法
+	MergeDepartments(ctx context.Context, targetID uint, payload models.MergeDepartmentsPayload) (*models.Department, error)
+}
+
+// departmentService 是 DepartmentService 的实现
+type departmentService struct {
+	repo         repositories.DepartmentRepository
+	employeeRepo repositories.EmployeeRepository
+}
+
+// NewDepartmentService 创建一个新的 departmentService 实例
+func NewDepartmentService(repo repositories.DepartmentRepository, employeeRepo repositories.EmployeeRepository) DepartmentService {
+	return &departmentService{repo: repo, employeeRepo: employeeRepo}
+}
+
+// CreateDepartment 校验上级部门和负责人后创建部门，名称会先规范化
+func (s *departmentService) CreateDepartment(ctx context.Context, payload models.CreateDepartmentPayload) (*models.Department, error) {
+	name := models.NormalizeDepartmentName(payload.Name)
+	if name == "" {
+		return nil, ErrInvalidDepartmentName
+	}
+	if payload.ParentID != nil {
+		if _, err := s.repo.GetDepartmentByID(ctx, *payload.ParentID); err != nil {
+			if errors.Is(err, repositories.ErrRecordNotFound) {
+				return nil, fmt.Errorf("%w: 上级部门 %d 不存在", ErrInvalidDepartmentParent, *payload.ParentID)
+			}
+			return nil, err
+		}
+	}
+	if err := s.checkManager(payload.ManagerEmployeeID); err != nil {
+		return nil, err
+	}
+
+	department := &models.Department{
+		Name:              name,
+		ParentID:          payload.ParentID,
+		ManagerEmployeeID: emptyToNil(payload.ManagerEmployeeID),
+		CostCenter:        emptyToNil(payload.CostCenter),
+	}
+	created, err := s.repo.CreateDepartment(ctx, department)
+	if err != nil {
+		if errors.Is(err, repositories.ErrDepartmentNameExists) {
+			return nil, ErrDepartmentNameExists
+		}
+		return nil, err
+	}
+	return created, nil
+}
+
+// GetDepartments 返回全部部门
+func (s *departmentService) GetDepartments(ctx context.Context) ([]models.DepartmentResponse, error) {
+	return s.repo.GetDepartments(ctx)
+}
+
+// GetDepartmentTree 将平铺的部门列表按上级部门组装为树
+func (s *departmentService) GetDepartmentTree(ctx context.Context) ([]models.DepartmentResponse, error) {
+	departments, err := s.repo.GetDepartments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	children := make(map[uint][]models.DepartmentResponse)
+	var roots []models.DepartmentResponse
+	for _, d := range departments {
+		if d.ParentID == nil {
+			roots = append(roots, d)
+		} else {
+			children[*d.ParentID] = append(children[*d.ParentID], d)
+		}
+	}
+
+	var attach func(nodes []models.DepartmentResponse) []models.DepartmentResponse
+	attach = func(nodes []models.DepartmentResponse) []models.DepartmentResponse {
+		for i := range nodes {
+			nodes[i].Children = attach(children[nodes[i].ID])
+		}
+		return nodes
+	}
+	tree := attach(roots)
+	if tree == nil {
+		tree = []models.DepartmentResponse{}
+	}
+	return tree, nil
+}
+
+// UpdateDepartment 更新部门。修改上级部门时不能把部门移到自身或其下级部门之下
+func (s *departmentService) UpdateDepartment(ctx context.Context, id uint, payload models.UpdateDepartmentPayload) (*models.Department, error) {
+	if _, err := s.repo.GetDepartmentByID(ctx, id); err != nil {
+		if errors.Is(err, repositories.ErrRecordNotFound) {
+			return nil, ErrDepartmentNotFound
+		}
+		return nil, err
+	}
+
+	updates := make(map[string]interface{})
+	if payload.Name != nil {
+		name := models.NormalizeDepartmentName(*payload.Name)
+		if name == "" {
+			return nil, ErrInvalidDepartmentName
+		}
+		updates["name"] = name
+	}
+	if payload.ParentID != nil {
+		if *payload.ParentID == 0 {
+			updates["parent_id"] = nil
+		} else {
+			if err := s.checkParent(ctx, id, *payload.ParentID); err != nil {
+				return nil, err
+			}
+			updates["parent_id"] = *payload.ParentID
+		}
+	}
+	if payload.ManagerEmployeeID != nil {
+		if err := s.checkManager(payload.ManagerEmployeeID); err != nil {
+			return nil, err
+		}
+		updates["manager_employee_id"] = emptyToNil(payload.ManagerEmployeeID)
+	}
+	if payload.CostCenter != nil {
+		updates["cost_center"] = emptyToNil(payload.CostCenter)
+	}
+	if len(updates) == 0 {
+		return nil, ErrNoUpdateFields
+	}
+
+	updated, err := s.repo.UpdateDepartment(ctx, id, updates)
+	if err != nil {
+		if errors.Is(err, repositories.ErrDepartmentNameExists) {
+			return nil, ErrDepartmentNameExists
+		}
+		return nil, err
+	}
+	return updated, nil
+}
+
+// DeleteDepartment 删除没有下级部门和员工的部门
+func (s *departmentService) DeleteDepartment(ctx context.Context, id uint) error {
+	err := s.repo.DeleteDepartment(ctx, id)
+	if errors.Is(err, repositories.ErrRecordNotFound) {
+		return ErrDepartmentNotFound
+	}
+	if errors.Is(err, repositories.ErrDepartmentInUse) {
+		return ErrDepartmentInUse
+	}
+	return err
+}
+
+// MergeDepartments 合并部门。被合并的部门不能是目标部门本身或其上级部门
+func (s *departmentService) MergeDepartments(ctx context.Context, targetID uint, payload models.MergeDepartmentsPayload) (*models.Department, error) {
+	if _, err := s.repo.GetDepartmentByID(ctx, targetID); err != nil {
+		if errors.Is(err, repositories.ErrRecordNotFound) {
+			return nil, ErrDepartmentNotFound
+		}
+		return nil, err
+	}
+
+	seen := make(map[uint]bool, len(payload.SourceDepartmentIDs))
+	var sourceIDs []uint
+	for _, sourceID := range payload.SourceDepartmentIDs {
+		if sourceID == targetID {
+			return nil, fmt.Errorf("%w: 不能将部门合并到自身", ErrInvalidDepartmentMerge)
+		}
+		if seen[sourceID] {
+			continue
+		}
+		seen[sourceID] = true
+		if _, err := s.repo.GetDepartmentByID(ctx, sourceID); err != nil {
+			if errors.Is(err, repositories.ErrRecordNotFound) {
+				return nil, fmt.Errorf("%w: 部门 %d 不存在", ErrInvalidDepartmentMerge, sourceID)
+			}
+			return nil, err
+		}
+		descendants, err := s.repo.DescendantIDs(ctx, []uint{sourceID})
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range descendants {
+			if id == targetID {
+				return nil, fmt.Errorf("%w: 部门 %d 是目标部门的上级部门", ErrInvalidDepartmentMerge, sourceID)
+			}
+		}
+		sourceIDs = append(sourceIDs, sourceID)
+	}
+
+	merged, err := s.repo.MergeDepartments(ctx, targetID, sourceIDs)
+	if err != nil {
+		if errors.Is(err, repositories.ErrRecordNotFound) {
+			return nil, ErrDepartmentNotFound
+		}
+		return nil, err
+	}
+	return merged, nil
+}
+
+// checkParent 校验 parentID 存在，且不是部门 id 自身或其下级部门
+func (s *departmentService) checkParent(ctx context.Context, id, parentID uint) error {
+	if _, err := s.repo.GetDepartmentByID(ctx, parentID); err != nil {
+		if errors.Is(err, repositories.ErrRecordNotFound) {
+			return fmt.Errorf("%w: 上级部门 %d 不存在", ErrInvalidDepartmentParent, parentID)
+		}
+		return err
+	}
+	descendants, err := s.repo.DescendantIDs(ctx, []uint{id})
+	if err != nil {
+		return err
+	}
+	for _, d := range descendants {
+		if d == parentID {
+			return fmt.Errorf("%w: 不能将部门移到自身或其下级部门之下", ErrInvalidDepartmentParent)
+		}
+	}
+	return nil
+}
+
+// checkManager 校验部门负责人员工存在，未指定或为空字符串时不校验
+func (s *departmentService) checkManager(managerEmployeeID *string) error {
+	if managerEmployeeID == nil || *managerEmployeeID == "" {
+		return nil
+	}
+	if _, err := s.employeeRepo.GetEmployeeByEmployeeID(*managerEmployeeID); err != nil {
+		if errors.Is(err, repositories.ErrRecordNotFound) {
+			return ErrDepartmentManagerNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// emptyToNil 将空字符串指针转换为 nil，用于可清除的可选字段
+func emptyToNil(value *string) *string {
+	if value == nil || *value == "" {
+		return nil
+	}
+	return value
+}
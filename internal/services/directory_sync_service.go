@@ -0,0 +1,563 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/phone_management/configs"
+	"github.com/phone_management/internal/audit"
+	"github.com/phone_management/internal/models"
+	"github.com/phone_management/internal/repositories"
+	"github.com/phone_management/pkg/ldap"
+	"github.com/phone_management/pkg/utils"
+)
+
+// 员工目录同步相关错误
+var ErrDirectoryNotConfigured = errors.New("未配置员工目录服务器")
+var ErrDirectoryUnavailable = errors.New("读取员工目录失败")
+var ErrDirectorySyncInProgress = errors.New("员工目录同步正在进行中")
+var ErrDirectorySyncRunNotFound = errors.New("目录同步记录未找到")
+
+// directorySyncMu 保证同一时间只有一次目录同步，手动触发和定时任务使用不同的服务实例，因此为包级变量
+var directorySyncMu sync.Mutex
+
+// DirectoryUser 是从员工目录读取的一个用户，字段为空表示目录中没有该属性
+type DirectoryUser struct {
+	DN          string
+	EmployeeID  string
+	FullName    string
+	Email       string
+	PhoneNumber string
+	Department  string
+	Disabled    bool // 账号已禁用，视为离职
+}
+
+// DirectorySource 是员工目录的数据来源
+type DirectorySource interface {
+	// FetchUsers 读取目录中的全部用户
+	FetchUsers(ctx context.Context) ([]DirectoryUser, error)
+}
+
+// DirectorySyncService 定义了员工目录同步服务的接口
+type DirectorySyncService interface {
+	// Sync 从目录读取用户并与员工档案对账：新建目录中新增的员工，更新信息有变化的员工，
+	// 为目录中已禁用或已删除的员工办理离职。dryRun 为 true 时只生成报告，不修改员工数据
+	Sync(ctx context.Context, trigger models.DirectorySyncTrigger, dryRun bool) (*models.DirectorySyncRun, error)
+	// GetRuns 返回最近的同步记录，不含报告条目
+	GetRuns(ctx context.Context, limit int) ([]models.DirectorySyncRun, error)
+	// GetRun 返回一次同步的记录及报告条目
+	GetRun(ctx context.Context, id uint) (*models.DirectorySyncRun, error)
+}
+
+// directorySyncService 是 DirectorySyncService 的实现
+type directorySyncService struct {
+	source            DirectorySource // 未配置目录服务器时为 nil
+	employeeService   EmployeeService
+	employeeRepo      repositories.EmployeeRepository
+	departmentService DepartmentService
+	departmentRepo    repositories.DepartmentRepository
+	runRepo           repositories.DirectorySyncRunRepository
+	matchBy           string // 目录用户与员工的匹配方式
+	maxDepartures     int    // 单次同步最多办理的离职数，不大于 0 时不限制
+}
+
+// NewDirectorySyncService 创建一个新的 directorySyncService 实例，匹配方式和离职上限取自 configs.AppConfig。
+// 员工的新建、更新和离职都经由 employeeService 办理，与手动操作的校验和副作用一致
+func NewDirectorySyncService(source DirectorySource, employeeService EmployeeService, employeeRepo repositories.EmployeeRepository, departmentService DepartmentService, departmentRepo repositories.DepartmentRepository, runRepo repositories.DirectorySyncRunRepository) DirectorySyncService {
+	matchBy := configs.AppConfig.LDAPMatchBy
+	if matchBy == "" {
+		matchBy = "email"
+	}
+	return &directorySyncService{
+		source:            source,
+		employeeService:   employeeService,
+		employeeRepo:      employeeRepo,
+		departmentService: departmentService,
+		departmentRepo:    departmentRepo,
+		runRepo:           runRepo,
+		matchBy:           matchBy,
+		maxDepartures:     configs.AppConfig.DirectorySyncMaxDepartures,
+	}
+}
+
+// pendingDeparture 是待办理离职的员工及原因
+type pendingDeparture struct {
+	employee models.Employee
+	dn       string
+	reason   string
+}
+
+// Sync 执行一次目录同步并保存同步报告。读取目录失败时同步记录为失败状态，同时返回 ErrDirectoryUnavailable
+func (s *directorySyncService) Sync(ctx context.Context, trigger models.DirectorySyncTrigger, dryRun bool) (*models.DirectorySyncRun, error) {
+	if s.source == nil {
+		return nil, ErrDirectoryNotConfigured
+	}
+	if !directorySyncMu.TryLock() {
+		return nil, ErrDirectorySyncInProgress
+	}
+	defer directorySyncMu.Unlock()
+
+	run := &models.DirectorySyncRun{
+		Trigger:     trigger,
+		DryRun:      dryRun,
+		Status:      models.DirectorySyncRunning,
+		TriggeredBy: audit.ActorFromContext(ctx).Username,
+		StartedAt:   time.Now().UTC(),
+	}
+	if err := s.runRepo.Create(ctx, run); err != nil {
+		return nil, err
+	}
+	// 同步产生的审计事件标记来源为目录同步
+	ctx = audit.WithSource(ctx, audit.SourceDirectory)
+
+	users, err := s.source.FetchUsers(ctx)
+	if err != nil {
+		syncErr := fmt.Errorf("%w: %v", ErrDirectoryUnavailable, err)
+		return s.finish(ctx, run, syncErr)
+	}
+	employees, err := s.employeeRepo.FindAll(ctx)
+	if err != nil {
+		return s.finish(ctx, run, err)
+	}
+	s.reconcile(ctx, run, users, employees)
+	return s.finish(ctx, run, nil)
+}
+
+// reconcile 将目录用户与员工逐一对应并处理，结果计入 run
+func (s *directorySyncService) reconcile(ctx context.Context, run *models.DirectorySyncRun, users []DirectoryUser, employees []models.Employee) {
+	byDN := make(map[string]*models.Employee)
+	byKey := make(map[string]*models.Employee)
+	for i := range employees {
+		e := &employees[i]
+		if e.DirectoryDN != nil {
+			byDN[strings.ToLower(*e.DirectoryDN)] = e
+		}
+		if key := s.employeeKey(e); key != "" {
+			byKey[key] = e
+		}
+	}
+
+	// 先按已关联的 DN 匹配，再按配置的属性匹配，避免 DN 变化（如调整 OU）的用户抢先占用其他用户关联的员工
+	matched := make([]*models.Employee, len(users))
+	claimed := make(map[string]bool)
+	for i, u := range users {
+		if e := byDN[strings.ToLower(u.DN)]; e != nil {
+			matched[i] = e
+			claimed[e.EmployeeID] = true
+		}
+	}
+
+	var departures []pendingDeparture
+	for i, u := range users {
+		run.ScannedCount++
+		e := matched[i]
+		if e == nil {
+			if key := s.userKey(u); key != "" {
+				e = byKey[key]
+			}
+			if e != nil && claimed[e.EmployeeID] {
+				s.record(run, models.DirectorySyncItem{Action: models.DirectorySyncSkip, EmployeeID: e.EmployeeID, DirectoryDN: u.DN, FullName: u.FullName,
+					Reason: "匹配到的员工已关联其他目录用户，请检查目录中是否有重复的账号"})
+				continue
+			}
+			if e != nil {
+				claimed[e.EmployeeID] = true
+			}
+		}
+
+		switch {
+		case e == nil && u.Disabled:
+			// 已禁用且没有对应员工的账号无需处理
+			run.UnchangedCount++
+		case e == nil:
+			s.create(ctx, run, u)
+		case u.Disabled:
+			if e.EmploymentStatus == "Departed" {
+				run.UnchangedCount++
+			} else {
+				departures = append(departures, pendingDeparture{employee: *e, dn: u.DN, reason: "目录账号已禁用"})
+			}
+		case e.EmploymentStatus == "Departed":
+			s.record(run, models.DirectorySyncItem{Action: models.DirectorySyncSkip, EmployeeID: e.EmployeeID, DirectoryDN: u.DN, FullName: e.FullName,
+				Reason: "员工已离职，但目录账号仍为启用状态，如需复职请手动办理"})
+		default:
+			s.update(ctx, run, *e, u)
+		}
+	}
+
+	// 曾关联目录、但本次目录中已不存在的在职员工视为离职
+	for _, e := range employees {
+		if e.DirectoryDN != nil && !claimed[e.EmployeeID] && e.EmploymentStatus != "Departed" {
+			departures = append(departures, pendingDeparture{employee: e, reason: "目录中已不存在该用户"})
+		}
+	}
+
+	if s.maxDepartures > 0 && len(departures) > s.maxDepartures {
+		message := fmt.Sprintf("本次同步需办理 %d 个离职，超过上限 %d，未办理任何离职，请检查目录配置或手动办理", len(departures), s.maxDepartures)
+		run.ErrorMessage = &message
+		for _, d := range departures {
+			s.record(run, models.DirectorySyncItem{Action: models.DirectorySyncSkip, EmployeeID: d.employee.EmployeeID, DirectoryDN: d.dn, FullName: d.employee.FullName,
+				Reason: d.reason + "，因离职数超过上限未办理"})
+		}
+		return
+	}
+	for _, d := range departures {
+		s.depart(ctx, run, d)
+	}
+}
+
+// employeeKey 返回员工按匹配方式用于匹配的值
+func (s *directorySyncService) employeeKey(e *models.Employee) string {
+	switch s.matchBy {
+	case "employeeId":
+		return e.EmployeeID
+	case "phoneNumber":
+		if e.PhoneNumber != nil {
+			return *e.PhoneNumber
+		}
+	default:
+		if e.Email != nil {
+			return strings.ToLower(*e.Email)
+		}
+	}
+	return ""
+}
+
+// userKey 返回目录用户按匹配方式用于匹配的值
+func (s *directorySyncService) userKey(u DirectoryUser) string {
+	switch s.matchBy {
+	case "employeeId":
+		return u.EmployeeID
+	case "phoneNumber":
+		return u.PhoneNumber
+	default:
+		return strings.ToLower(u.Email)
+	}
+}
+
+// validateUser 校验目录用户的姓名、手机号码和邮箱，返回不合格的原因
+func validateUser(u DirectoryUser) string {
+	if u.FullName == "" {
+		return "目录中的姓名为空"
+	}
+	if u.PhoneNumber != "" {
+		if err := utils.ValidatePhoneNumber(u.PhoneNumber); err != nil {
+			return "目录中的手机号码 " + u.PhoneNumber + " 无效: " + err.Error()
+		}
+	}
+	if u.Email != "" && !utils.ValidateEmailFormat(u.Email) {
+		return "目录中的邮箱 " + u.Email + " 无效"
+	}
+	return ""
+}
+
+// create 为目录中新增的用户新建员工，部门不存在时自动创建
+func (s *directorySyncService) create(ctx context.Context, run *models.DirectorySyncRun, u DirectoryUser) {
+	item := models.DirectorySyncItem{Action: models.DirectorySyncCreate, DirectoryDN: u.DN, FullName: u.FullName}
+	if reason := validateUser(u); reason != "" {
+		item.Action, item.Reason = models.DirectorySyncFail, reason
+		s.record(run, item)
+		return
+	}
+	if run.DryRun {
+		s.record(run, item)
+		return
+	}
+
+	employee := &models.Employee{FullName: u.FullName, DirectoryDN: &u.DN}
+	if u.PhoneNumber != "" {
+		employee.PhoneNumber = &u.PhoneNumber
+	}
+	if u.Email != "" {
+		employee.Email = &u.Email
+	}
+	if u.Department != "" {
+		if err := s.ensureDepartment(ctx, u.Department); err != nil {
+			item.Action, item.Reason = models.DirectorySyncFail, err.Error()
+			s.record(run, item)
+			return
+		}
+		employee.Department = &u.Department
+	}
+
+	created, err := s.employeeService.CreateEmployee(ctx, employee)
+	if err != nil {
+		item.Action, item.Reason = models.DirectorySyncFail, err.Error()
+	} else {
+		item.EmployeeID = created.EmployeeID
+	}
+	s.record(run, item)
+}
+
+// update 将目录中有变化的姓名、手机号码、邮箱和部门更新到员工，目录中为空的属性不会清除员工已有的信息
+func (s *directorySyncService) update(ctx context.Context, run *models.DirectorySyncRun, e models.Employee, u DirectoryUser) {
+	item := models.DirectorySyncItem{Action: models.DirectorySyncUpdate, EmployeeID: e.EmployeeID, DirectoryDN: u.DN, FullName: e.FullName}
+	if reason := validateUser(u); reason != "" {
+		item.Action, item.Reason = models.DirectorySyncFail, reason
+		s.record(run, item)
+		return
+	}
+
+	var payload models.UpdateEmployeePayload
+	if u.FullName != e.FullName {
+		payload.FullName = &u.FullName
+		item.Changes = append(item.Changes, "fullName")
+	}
+	if u.PhoneNumber != "" && (e.PhoneNumber == nil || *e.PhoneNumber != u.PhoneNumber) {
+		payload.PhoneNumber = &u.PhoneNumber
+		item.Changes = append(item.Changes, "phoneNumber")
+	}
+	if u.Email != "" && (e.Email == nil || !strings.EqualFold(*e.Email, u.Email)) {
+		payload.Email = &u.Email
+		item.Changes = append(item.Changes, "email")
+	}
+	if u.Department != "" {
+		changed, err := s.departmentChanged(ctx, e, u.Department)
+		if err != nil {
+			item.Action, item.Reason = models.DirectorySyncFail, err.Error()
+			s.record(run, item)
+			return
+		}
+		if changed {
+			payload.Department = &u.Department
+			item.Changes = append(item.Changes, "department")
+		}
+	}
+	relink := e.DirectoryDN == nil || *e.DirectoryDN != u.DN
+	if relink {
+		item.Changes = append(item.Changes, "directoryDn")
+	}
+
+	if len(item.Changes) == 0 {
+		run.UnchangedCount++
+		return
+	}
+	if run.DryRun {
+		s.record(run, item)
+		return
+	}
+
+	if payload.Department != nil {
+		if err := s.ensureDepartment(ctx, u.Department); err != nil {
+			item.Action, item.Reason = models.DirectorySyncFail, err.Error()
+			s.record(run, item)
+			return
+		}
+	}
+	if payload.FullName != nil || payload.PhoneNumber != nil || payload.Email != nil || payload.Department != nil {
+		if _, err := s.employeeService.UpdateEmployee(ctx, e.EmployeeID, payload); err != nil {
+			item.Action, item.Reason = models.DirectorySyncFail, err.Error()
+			s.record(run, item)
+			return
+		}
+	}
+	if relink {
+		if _, err := s.employeeRepo.UpdateEmployee(ctx, e.EmployeeID, map[string]interface{}{"directory_dn": u.DN}); err != nil {
+			item.Action, item.Reason = models.DirectorySyncFail, err.Error()
+		}
+	}
+	s.record(run, item)
+}
+
+// departmentChanged 判断目录中的部门（名称或别名）是否与员工当前的部门不同
+func (s *directorySyncService) departmentChanged(ctx context.Context, e models.Employee, name string) (bool, error) {
+	department, err := s.departmentRepo.FindByName(ctx, name)
+	if err != nil {
+		if errors.Is(err, repositories.ErrRecordNotFound) {
+			return true, nil
+		}
+		return false, err
+	}
+	return e.DepartmentID == nil || *e.DepartmentID != department.ID, nil
+}
+
+// ensureDepartment 在部门（名称或别名）不存在时创建为顶级部门
+func (s *directorySyncService) ensureDepartment(ctx context.Context, name string) error {
+	_, err := s.departmentRepo.FindByName(ctx, name)
+	if !errors.Is(err, repositories.ErrRecordNotFound) {
+		return err
+	}
+	_, err = s.departmentService.CreateDepartment(ctx, models.CreateDepartmentPayload{Name: name})
+	return err
+}
+
+// depart 为员工办理离职。员工仍在使用号码时需要人工指定号码处置方式，记为失败
+func (s *directorySyncService) depart(ctx context.Context, run *models.DirectorySyncRun, d pendingDeparture) {
+	item := models.DirectorySyncItem{Action: models.DirectorySyncDepart, EmployeeID: d.employee.EmployeeID, DirectoryDN: d.dn, FullName: d.employee.FullName, Reason: d.reason}
+
+	var err error
+	if run.DryRun {
+		var preview *models.DeparturePreviewResponse
+		preview, err = s.employeeService.PreviewDeparture(ctx, d.employee.EmployeeID, models.DeparturePreviewPayload{})
+		if err == nil && !preview.CanDepart {
+			err = ErrEmployeeHasActiveNumbers
+		}
+	} else {
+		status := "Departed"
+		_, err = s.employeeService.UpdateEmployee(ctx, d.employee.EmployeeID, models.UpdateEmployeePayload{EmploymentStatus: &status})
+	}
+	if err != nil {
+		item.Action = models.DirectorySyncFail
+		item.Reason = d.reason + "，但无法自动办理离职: " + err.Error()
+		if errors.Is(err, ErrEmployeeHasActiveNumbers) {
+			item.Reason = d.reason + "，员工仍在使用手机号码，请手动办理离职并处置号码"
+		}
+	}
+	s.record(run, item)
+}
+
+// record 将条目加入报告并计数
+func (s *directorySyncService) record(run *models.DirectorySyncRun, item models.DirectorySyncItem) {
+	switch item.Action {
+	case models.DirectorySyncCreate:
+		run.CreatedCount++
+	case models.DirectorySyncUpdate:
+		run.UpdatedCount++
+	case models.DirectorySyncDepart:
+		run.DepartedCount++
+	case models.DirectorySyncSkip:
+		run.SkippedCount++
+	case models.DirectorySyncFail:
+		run.FailedCount++
+	}
+	run.Items = append(run.Items, item)
+}
+
+// finish 确定同步状态并保存同步记录
+func (s *directorySyncService) finish(ctx context.Context, run *models.DirectorySyncRun, syncErr error) (*models.DirectorySyncRun, error) {
+	switch {
+	case syncErr != nil:
+		run.Status = models.DirectorySyncFailed
+		message := syncErr.Error()
+		run.ErrorMessage = &message
+	case run.FailedCount > 0 || run.ErrorMessage != nil:
+		run.Status = models.DirectorySyncPartial
+	default:
+		run.Status = models.DirectorySyncSucceeded
+	}
+	finishedAt := time.Now().UTC()
+	run.FinishedAt = &finishedAt
+
+	if len(run.Items) > 0 {
+		items, err := json.Marshal(run.Items)
+		if err != nil {
+			return nil, err
+		}
+		itemsJSON := string(items)
+		run.ItemsJSON = &itemsJSON
+	}
+	// 使用不会被取消的 context 保存结果，避免请求中断导致同步记录停留在同步中状态
+	if err := s.runRepo.Save(context.WithoutCancel(ctx), run); err != nil {
+		log.Printf("保存目录同步记录 %d 失败: %v", run.ID, err)
+		if syncErr == nil {
+			syncErr = err
+		}
+	}
+	return run, syncErr
+}
+
+// GetRuns 返回最近的同步记录
+func (s *directorySyncService) GetRuns(ctx context.Context, limit int) ([]models.DirectorySyncRun, error) {
+	return s.runRepo.FindRecent(ctx, limit)
+}
+
+// GetRun 返回一次同步的记录及报告条目
+func (s *directorySyncService) GetRun(ctx context.Context, id uint) (*models.DirectorySyncRun, error) {
+	run, err := s.runRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrRecordNotFound) {
+			return nil, ErrDirectorySyncRunNotFound
+		}
+		return nil, err
+	}
+	if run.ItemsJSON != nil {
+		if err := json.Unmarshal([]byte(*run.ItemsJSON), &run.Items); err != nil {
+			return nil, fmt.Errorf("解析目录同步报告失败: %w", err)
+		}
+	}
+	return run, nil
+}
+
+// ldapDirectorySource 从 LDAP/Active Directory 读取员工目录
+type ldapDirectorySource struct {
+	cfg configs.Configuration
+}
+
+// NewLDAPDirectorySource 按 cfg 中的 LDAP 配置创建目录数据来源，未配置 LDAPURL 时返回 nil
+func NewLDAPDirectorySource(cfg configs.Configuration) DirectorySource {
+	if cfg.LDAPURL == "" {
+		return nil
+	}
+	return &ldapDirectorySource{cfg: cfg}
+}
+
+// adAccountDisabled 是 Active Directory userAccountControl 中表示账号已禁用的标志位
+const adAccountDisabled = 0x2
+
+// FetchUsers 绑定目录服务器并按用户过滤器分页读取全部用户
+func (s *ldapDirectorySource) FetchUsers(ctx context.Context) ([]DirectoryUser, error) {
+	conn, err := ldap.Dial(s.cfg.LDAPURL, s.cfg.LDAPTimeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if err := conn.Bind(s.cfg.LDAPBindDN, s.cfg.LDAPBindPassword); err != nil {
+		return nil, err
+	}
+
+	entries, err := conn.Search(ldap.SearchRequest{
+		BaseDN: s.cfg.LDAPBaseDN,
+		Filter: s.cfg.LDAPUserFilter,
+		Attributes: []string{
+			"cn", "userAccountControl",
+			s.cfg.LDAPAttrFullName, s.cfg.LDAPAttrEmail, s.cfg.LDAPAttrPhoneNumber,
+			s.cfg.LDAPAttrDepartment, s.cfg.LDAPAttrEmployeeID,
+		},
+		PageSize: s.cfg.LDAPPageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]DirectoryUser, 0, len(entries))
+	for _, entry := range entries {
+		user := DirectoryUser{
+			DN:          entry.DN,
+			EmployeeID:  strings.TrimSpace(entry.Value(s.cfg.LDAPAttrEmployeeID)),
+			FullName:    strings.TrimSpace(entry.Value(s.cfg.LDAPAttrFullName)),
+			Email:       strings.TrimSpace(entry.Value(s.cfg.LDAPAttrEmail)),
+			PhoneNumber: normalizeDirectoryPhone(entry.Value(s.cfg.LDAPAttrPhoneNumber)),
+			Department:  models.NormalizeDepartmentName(entry.Value(s.cfg.LDAPAttrDepartment)),
+		}
+		if user.FullName == "" {
+			user.FullName = strings.TrimSpace(entry.Value("cn"))
+		}
+		if flags, err := strconv.ParseInt(entry.Value("userAccountControl"), 10, 64); err == nil {
+			user.Disabled = flags&adAccountDisabled != 0
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+// normalizeDirectoryPhone 去掉目录中手机号码的空格、连字符等分隔符和 +86 国家码
+func normalizeDirectoryPhone(phone string) string {
+	var digits strings.Builder
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			digits.WriteRune(r)
+		}
+	}
+	normalized := digits.String()
+	if len(normalized) == 13 && strings.HasPrefix(normalized, "86") {
+		normalized = normalized[2:]
+	}
+	return normalized
+}
@@ -0,0 +1,260 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/phone_management/configs"
+	"github.com/phone_management/internal/models"
+	"github.com/phone_management/internal/repositories"
+	"github.com/phone_management/pkg/email"
+)
+
+// ErrEmailTemplateNotFound 表示通知模板不存在
+var ErrEmailTemplateNotFound = errors.New("通知模板不存在")
+
+// ErrEmailTemplateExists 表示同名称、同语言的通知模板已存在
+var ErrEmailTemplateExists = errors.New("该名称和语言的通知模板已存在")
+
+// ErrUnknownEmailTemplate 表示模板名称不是系统使用的通知模板
+var ErrUnknownEmailTemplate = errors.New("未知的通知模板名称，可选 verification")
+
+// ErrInvalidEmailTemplate 表示模板语法错误、缺少纯文本正文、标题引用了链接或号码，或以示例数据渲染失败（如引用了不存在的变量）
+var ErrInvalidEmailTemplate = errors.New("通知模板无效")
+
+// EmailTemplateService 定义了通知模板服务的接口。
+// 按名称和语言查找模板时，数据库中的模板优先，其次为 EMAIL_TEMPLATE_DIR 中的模板文件，最后为内置模板
+type EmailTemplateService interface {
+	// GetTemplates 返回数据库中保存的全部模板，按名称、语言升序
+	GetTemplates(ctx context.Context) ([]models.EmailTemplate, error)
+	GetTemplate(ctx context.Context, id uint) (*models.EmailTemplate, error)
+	// CreateTemplate 校验并保存模板，保存后覆盖同名称、同语言的模板文件和内置模板
+	CreateTemplate(ctx context.Context, payload models.CreateEmailTemplatePayload) (*models.EmailTemplate, error)
+	UpdateTemplate(ctx context.Context, id uint, payload models.UpdateEmailTemplatePayload) (*models.EmailTemplate, error)
+	// DeleteTemplate 删除数据库中的模板，恢复使用模板文件或内置模板
+	DeleteTemplate(ctx context.Context, id uint) error
+	// PreviewTemplate 以示例数据渲染提交的模板或当前生效的模板
+	PreviewTemplate(ctx context.Context, payload models.PreviewEmailTemplatePayload) (*models.EmailTemplatePreview, error)
+	// Render 渲染指定名称和语言的生效模板，该语言没有模板时使用默认语言 zh-CN 的模板
+	Render(ctx context.Context, name, locale string, data interface{}) (email.Message, error)
+}
+
+// emailTemplateService 是 EmailTemplateService 的实现
+type emailTemplateService struct {
+	repo      repositories.EmailTemplateRepository
+	appConfig *configs.Configuration
+}
+
+// NewEmailTemplateService 创建一个新的 emailTemplateService 实例
+func NewEmailTemplateService(repo repositories.EmailTemplateRepository) EmailTemplateService {
+	return &emailTemplateService{repo: repo, appConfig: &configs.AppConfig}
+}
+
+// sampleData 返回模板的示例数据，用于校验和预览模板；模板名称未知时返回 ErrUnknownEmailTemplate
+func (s *emailTemplateService) sampleData(name string) (interface{}, error) {
+	switch name {
+	case email.TemplateVerification:
+		return email.VerificationData{
+			EmployeeName: "张三",
+			EmployeeID:   "EMP0000001",
+			Link:         s.appConfig.FrontendBaseURL + "/verify-numbers?token=preview",
+			Numbers: []email.VerificationNumber{
+				{PhoneNumber: "13800000001", Status: string(models.StatusInUse)},
+				{PhoneNumber: "13800000002", Status: string(models.StatusInUse)},
+			},
+			ExpiresAt: time.Now().AddDate(0, 0, 7),
+			ValidDays: 7,
+			Contact:   s.appConfig.VerificationContact,
+		}, nil
+	}
+	return nil, ErrUnknownEmailTemplate
+}
+
+// validate 解析模板并以示例数据渲染，确保模板在实际发送时可用
+func (s *emailTemplateService) validate(name string, tmpl email.Template) (email.Message, error) {
+	data, err := s.sampleData(name)
+	if err != nil {
+		return email.Message{}, err
+	}
+	msg, err := tmpl.Render(data)
+	if err != nil {
+		return email.Message{}, fmt.Errorf("%w: %v", ErrInvalidEmailTemplate, err)
+	}
+	return msg, nil
+}
+
+func toEmailTemplate(t *models.EmailTemplate) email.Template {
+	return email.Template{Subject: t.Subject, HTML: t.HTMLBody, Text: t.TextBody}
+}
+
+// GetTemplates 返回全部模板
+func (s *emailTemplateService) GetTemplates(ctx context.Context) ([]models.EmailTemplate, error) {
+	return s.repo.FindAll(ctx)
+}
+
+// GetTemplate 根据ID返回模板
+func (s *emailTemplateService) GetTemplate(ctx context.Context, id uint) (*models.EmailTemplate, error) {
+	tmpl, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrRecordNotFound) {
+			return nil, ErrEmailTemplateNotFound
+		}
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// CreateTemplate 校验并保存模板
+func (s *emailTemplateService) CreateTemplate(ctx context.Context, payload models.CreateEmailTemplatePayload) (*models.EmailTemplate, error) {
+	if !models.IsValidLocale(payload.Locale) {
+		return nil, ErrInvalidLocale
+	}
+	tmpl := &models.EmailTemplate{
+		Name:     payload.Name,
+		Locale:   payload.Locale,
+		Subject:  payload.Subject,
+		HTMLBody: payload.HTMLBody,
+		TextBody: payload.TextBody,
+	}
+	if _, err := s.validate(tmpl.Name, toEmailTemplate(tmpl)); err != nil {
+		return nil, err
+	}
+
+	created, err := s.repo.Create(ctx, tmpl)
+	if err != nil {
+		if errors.Is(err, repositories.ErrEmailTemplateExists) {
+			return nil, ErrEmailTemplateExists
+		}
+		return nil, err
+	}
+	return created, nil
+}
+
+// UpdateTemplate 合并更新内容后校验模板，再保存
+func (s *emailTemplateService) UpdateTemplate(ctx context.Context, id uint, payload models.UpdateEmailTemplatePayload) (*models.EmailTemplate, error) {
+	tmpl, err := s.GetTemplate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(map[string]interface{})
+	if payload.Subject != nil {
+		tmpl.Subject = *payload.Subject
+		updates["subject"] = *payload.Subject
+	}
+	if payload.HTMLBody != nil {
+		tmpl.HTMLBody = *payload.HTMLBody
+		updates["html_body"] = *payload.HTMLBody
+	}
+	if payload.TextBody != nil {
+		tmpl.TextBody = *payload.TextBody
+		updates["text_body"] = *payload.TextBody
+	}
+	if len(updates) == 0 {
+		return tmpl, nil
+	}
+	if _, err := s.validate(tmpl.Name, toEmailTemplate(tmpl)); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.repo.Update(ctx, id, updates)
+	if err != nil {
+		if errors.Is(err, repositories.ErrRecordNotFound) {
+			return nil, ErrEmailTemplateNotFound
+		}
+		return nil, err
+	}
+	return updated, nil
+}
+
+// DeleteTemplate 删除模板
+func (s *emailTemplateService) DeleteTemplate(ctx context.Context, id uint) error {
+	if err := s.repo.Delete(ctx, id); err != nil {
+		if errors.Is(err, repositories.ErrRecordNotFound) {
+			return ErrEmailTemplateNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// PreviewTemplate 以示例数据渲染模板
+func (s *emailTemplateService) PreviewTemplate(ctx context.Context, payload models.PreviewEmailTemplatePayload) (*models.EmailTemplatePreview, error) {
+	if !models.IsValidLocale(payload.Locale) {
+		return nil, ErrInvalidLocale
+	}
+
+	tmpl := email.Template{Subject: payload.Subject, HTML: payload.HTMLBody, Text: payload.TextBody}
+	locale, source := payload.Locale, models.EmailTemplateSourceDraft
+	if tmpl == (email.Template{}) {
+		var err error
+		if tmpl, locale, source, err = s.resolve(ctx, payload.Name, payload.Locale); err != nil {
+			return nil, err
+		}
+	}
+
+	msg, err := s.validate(payload.Name, tmpl)
+	if err != nil {
+		return nil, err
+	}
+	return &models.EmailTemplatePreview{
+		Name:    payload.Name,
+		Locale:  locale,
+		Source:  source,
+		Subject: msg.Subject,
+		HTML:    msg.HTML,
+		Text:    msg.Text,
+	}, nil
+}
+
+// Render 渲染生效的模板
+func (s *emailTemplateService) Render(ctx context.Context, name, locale string, data interface{}) (email.Message, error) {
+	tmpl, _, _, err := s.resolve(ctx, name, locale)
+	if err != nil {
+		return email.Message{}, err
+	}
+	return tmpl.Render(data)
+}
+
+// resolve 查找指定名称和语言的生效模板，返回模板、实际使用的语言和模板来源
+func (s *emailTemplateService) resolve(ctx context.Context, name, locale string) (email.Template, string, string, error) {
+	if _, err := s.sampleData(name); err != nil {
+		return email.Template{}, "", "", err
+	}
+
+	locales := []string{email.DefaultLocale}
+	if locale != "" && locale != email.DefaultLocale {
+		locales = []string{locale, email.DefaultLocale}
+	}
+	for _, loc := range locales {
+		stored, err := s.repo.FindByNameAndLocale(ctx, name, loc)
+		if err == nil {
+			return toEmailTemplate(stored), loc, models.EmailTemplateSourceDatabase, nil
+		}
+		if !errors.Is(err, repositories.ErrRecordNotFound) {
+			return email.Template{}, "", "", fmt.Errorf("查询通知模板失败: %w", err)
+		}
+
+		if s.appConfig.EmailTemplateDir != "" {
+			tmpl, err := email.LoadTemplate(os.DirFS(s.appConfig.EmailTemplateDir), name, loc)
+			if err == nil {
+				return tmpl, loc, models.EmailTemplateSourceFile, nil
+			}
+			if !errors.Is(err, email.ErrTemplateNotFound) {
+				return email.Template{}, "", "", fmt.Errorf("读取通知模板文件失败: %w", err)
+			}
+		}
+
+		tmpl, err := email.BuiltinTemplate(name, loc)
+		if err == nil {
+			return tmpl, loc, models.EmailTemplateSourceBuiltin, nil
+		}
+		if !errors.Is(err, email.ErrTemplateNotFound) {
+			return email.Template{}, "", "", err
+		}
+	}
+	return email.Template{}, "", "", ErrEmailTemplateNotFound
+}
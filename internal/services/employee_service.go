@@ -2,14 +2,19 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	// "unicode" // 移除 unicode, isNumeric 已移到 utils
 
 	// "github.com/phone_management/internal/handlers" // 移除此导入
+	"github.com/phone_management/configs"
 	"github.com/phone_management/internal/models"
 	"github.com/phone_management/internal/repositories"
+	"github.com/phone_management/pkg/email"
 	"github.com/phone_management/pkg/utils" // 导入 utils 包
 )
 
@@ -24,35 +29,68 @@ var ErrEmailExists = errors.New("邮箱已存在")
 
 var ErrEmployeeNameNotFound = errors.New("按姓名未找到员工记录")
 
+// ErrInvalidNotificationChannel 表示通知渠道无效
+var ErrInvalidNotificationChannel = errors.New("无效的通知渠道，可选 email、im、sms")
+
+// ErrInvalidLocale 表示通知语言无效
+var ErrInvalidLocale = errors.New("无效的通知语言，可选 zh-CN、en")
+
 // 员工离职相关错误
 var ErrEmployeeHasActiveNumbers = errors.New("员工当前正在使用手机号码，无法办理离职")
+var ErrInvalidDeparturePlan = errors.New("离职号码处置方案无效")
+var ErrDepartureDispositionFailed = errors.New("离职号码处置失败")
+var ErrEmployeeAlreadyDeparted = errors.New("员工已是离职状态")
+var ErrEmployeeNotDeparted = errors.New("员工不是离职状态，无法办理复职")
+var ErrInvalidRehireRequest = errors.New("复职请求无效")
+var ErrRehireRequired = errors.New("离职员工不能直接更新为在职，请通过 POST /employees/{employeeId}/rehire 办理复职")
+
+// errDeparturePreviewRollback 用于在离职预览结束后回滚事务
+var errDeparturePreviewRollback = errors.New("离职预览已回滚")
 
 // EmployeeService 定义了员工服务的接口
 type EmployeeService interface {
-	CreateEmployee(employee *models.Employee) (*models.Employee, error)
-	GetEmployees(page, limit int, sortBy, sortOrder, search, employmentStatus string) ([]models.Employee, int64, error)
+	CreateEmployee(ctx context.Context, employee *models.Employee) (*models.Employee, error)
+	// GetEmployees 获取员工列表，department 为空时不按部门筛选
+	GetEmployees(ctx context.Context, page, limit int, sortBy, sortOrder, search, employmentStatus string, department models.DepartmentFilter) ([]models.Employee, int64, error)
 	GetEmployeeDetailByEmployeeID(employeeID string) (*models.EmployeeDetailResponse, error)
 	GetEmployeeByEmployeeID(employeeID string) (*models.Employee, error)
-	UpdateEmployee(employeeID string, payload models.UpdateEmployeePayload) (*models.Employee, error)
+	UpdateEmployee(ctx context.Context, employeeID string, payload models.UpdateEmployeePayload) (*models.Employee, error)
+	// PreviewDeparture 按号码处置方案模拟办理离职并回滚，返回各号码的处置结果
+	PreviewDeparture(ctx context.Context, employeeID string, payload models.DeparturePreviewPayload) (*models.DeparturePreviewResponse, error)
+	GetScheduledDepartures(ctx context.Context) ([]models.ScheduledDepartureResponse, error)
+	// PreviewRehire 返回离职员工复职时可恢复的风险号码，不修改任何数据
+	PreviewRehire(ctx context.Context, employeeID string) (*models.RehireResponse, error)
+	// Rehire 办理离职员工复职，并按请求恢复其作为办卡人的风险号码
+	Rehire(ctx context.Context, employeeID string, payload models.RehirePayload) (*models.RehireResponse, error)
+	// ProcessScheduledDepartures 由定时任务调用，办理离职日期已到的预约离职
+	ProcessScheduledDepartures(ctx context.Context, asOf time.Time) (int, error)
+	// SendDepartureReminders 由定时任务调用，在离职日期前将提醒 IT 处理员工号码的邮件加入发送队列
+	SendDepartureReminders(ctx context.Context, asOf time.Time) (int, error)
 	GetEmployeesByFullName(fullName string) ([]*models.Employee, error)
 }
 
 // employeeService 是 EmployeeService 的实现
 type employeeService struct {
-	repo             repositories.EmployeeRepository
-	mobileNumberRepo repositories.MobileNumberRepository
+	repo                   repositories.EmployeeRepository
+	mobileNumberRepo       repositories.MobileNumberRepository
+	scheduledDepartureRepo repositories.ScheduledDepartureRepository
+	departmentRepo         repositories.DepartmentRepository
+	outboundRepo           repositories.OutboundMessageRepository // 通知发送队列，离职提醒邮件由队列发送
 }
 
 // NewEmployeeService 创建一个新的 employeeService 实例
-func NewEmployeeService(repo repositories.EmployeeRepository, mobileNumberRepo repositories.MobileNumberRepository) EmployeeService {
+func NewEmployeeService(repo repositories.EmployeeRepository, mobileNumberRepo repositories.MobileNumberRepository, scheduledDepartureRepo repositories.ScheduledDepartureRepository, departmentRepo repositories.DepartmentRepository, outboundRepo repositories.OutboundMessageRepository) EmployeeService {
 	return &employeeService{
-		repo:             repo,
-		mobileNumberRepo: mobileNumberRepo,
+		repo:                   repo,
+		mobileNumberRepo:       mobileNumberRepo,
+		scheduledDepartureRepo: scheduledDepartureRepo,
+		departmentRepo:         departmentRepo,
+		outboundRepo:           outboundRepo,
 	}
 }
 
 // CreateEmployee 处理创建员工的业务逻辑
-func (s *employeeService) CreateEmployee(employee *models.Employee) (*models.Employee, error) {
+func (s *employeeService) CreateEmployee(ctx context.Context, employee *models.Employee) (*models.Employee, error) {
 	if employee.PhoneNumber != nil && *employee.PhoneNumber != "" {
 		phone := *employee.PhoneNumber
 		// 使用 utils 中的校验函数
@@ -81,11 +119,35 @@ func (s *employeeService) CreateEmployee(employee *models.Employee) (*models.Emp
 		}
 	}
 
+	if employee.Department != nil {
+		department, err := s.resolveDepartment(ctx, *employee.Department)
+		if err != nil {
+			return nil, err
+		}
+		if department == nil {
+			employee.Department = nil
+			employee.DepartmentID = nil
+		} else {
+			employee.Department = &department.Name
+			employee.DepartmentID = &department.ID
+		}
+	}
+
 	if employee.EmploymentStatus == "" {
 		employee.EmploymentStatus = "Active"
 	}
+	if employee.NotificationChannel == "" {
+		employee.NotificationChannel = models.NotificationChannelEmail
+	} else if !models.IsValidNotificationChannel(employee.NotificationChannel) {
+		return nil, ErrInvalidNotificationChannel
+	}
+	if employee.Locale == "" {
+		employee.Locale = models.LocaleZhCN
+	} else if !models.IsValidLocale(employee.Locale) {
+		return nil, ErrInvalidLocale
+	}
 
-	createdEmployee, err := s.repo.CreateEmployee(employee)
+	createdEmployee, err := s.repo.CreateEmployee(ctx, employee)
 	if err != nil {
 		if errors.Is(err, repositories.ErrEmployeePhoneNumberConflict) {
 			return nil, ErrPhoneNumberExists
@@ -99,10 +161,28 @@ func (s *employeeService) CreateEmployee(employee *models.Employee) (*models.Emp
 }
 
 // GetEmployees 处理获取员工列表的业务逻辑
-func (s *employeeService) GetEmployees(page, limit int, sortBy, sortOrder, search, employmentStatus string) ([]models.Employee, int64, error) {
-	// 当前业务逻辑主要是参数传递和调用仓库层
-	// 未来可在这里添加更复杂的业务规则，如数据转换或权限校验等
-	return s.repo.GetEmployees(page, limit, sortBy, sortOrder, search, employmentStatus)
+func (s *employeeService) GetEmployees(ctx context.Context, page, limit int, sortBy, sortOrder, search, employmentStatus string, department models.DepartmentFilter) ([]models.Employee, int64, error) {
+	departmentIDs, err := s.departmentRepo.ResolveDepartmentIDs(ctx, department)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.repo.GetEmployees(page, limit, sortBy, sortOrder, search, employmentStatus, departmentIDs)
+}
+
+// resolveDepartment 按名称或别名查找部门，名称为空时返回 nil 表示清除员工的部门
+func (s *employeeService) resolveDepartment(ctx context.Context, name string) (*models.Department, error) {
+	name = models.NormalizeDepartmentName(name)
+	if name == "" {
+		return nil, nil
+	}
+	department, err := s.departmentRepo.FindByName(ctx, name)
+	if err != nil {
+		if errors.Is(err, repositories.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrDepartmentNotFound, name)
+		}
+		return nil, err
+	}
+	return department, nil
 }
 
 // GetEmployeeDetailByEmployeeID 处理根据业务工号获取员工详情的业务逻辑
@@ -142,7 +222,7 @@ func (s *employeeService) GetEmployeesByFullName(fullName string) ([]*models.Emp
 }
 
 // UpdateEmployee 处理更新员工信息的业务逻辑
-func (s *employeeService) UpdateEmployee(employeeID string, payload models.UpdateEmployeePayload) (*models.Employee, error) {
+func (s *employeeService) UpdateEmployee(ctx context.Context, employeeID string, payload models.UpdateEmployeePayload) (*models.Employee, error) {
 	// 首先，确保员工存在
 	currentEmployee, err := s.repo.GetEmployeeByEmployeeID(employeeID)
 	if err != nil {
@@ -154,8 +234,76 @@ func (s *employeeService) UpdateEmployee(employeeID string, payload models.Updat
 
 	updates := make(map[string]interface{})
 
+	if payload.FullName != nil {
+		fullName := strings.TrimSpace(*payload.FullName)
+		if fullName == "" {
+			return nil, errors.New("姓名不能为空")
+		}
+		updates["full_name"] = fullName
+	}
+
+	if payload.PhoneNumber != nil {
+		phone := strings.TrimSpace(*payload.PhoneNumber)
+		if phone == "" {
+			updates["phone_number"] = nil
+		} else {
+			if err := utils.ValidatePhoneNumber(phone); err != nil {
+				return nil, err
+			}
+			existing, err := s.repo.GetEmployeeByPhoneNumber(phone)
+			if err == nil && existing.EmployeeID != employeeID {
+				return nil, ErrPhoneNumberExists
+			} else if err != nil && !errors.Is(err, repositories.ErrRecordNotFound) {
+				return nil, err
+			}
+			updates["phone_number"] = phone
+		}
+	}
+
+	if payload.Email != nil {
+		emailAddr := strings.TrimSpace(*payload.Email)
+		if emailAddr == "" {
+			updates["email"] = nil
+		} else {
+			if !utils.ValidateEmailFormat(emailAddr) {
+				return nil, utils.ErrInvalidEmailFormat
+			}
+			existing, err := s.repo.GetEmployeeByEmail(emailAddr)
+			if err == nil && existing.EmployeeID != employeeID {
+				return nil, ErrEmailExists
+			} else if err != nil && !errors.Is(err, repositories.ErrRecordNotFound) {
+				return nil, err
+			}
+			updates["email"] = emailAddr
+		}
+	}
+
 	if payload.Department != nil {
-		updates["department"] = *payload.Department
+		department, err := s.resolveDepartment(ctx, *payload.Department)
+		if err != nil {
+			return nil, err
+		}
+		if department == nil {
+			updates["department"] = nil
+			updates["department_id"] = nil
+		} else {
+			updates["department"] = department.Name
+			updates["department_id"] = department.ID
+		}
+	}
+
+	if payload.NotificationChannel != nil {
+		if !models.IsValidNotificationChannel(*payload.NotificationChannel) {
+			return nil, ErrInvalidNotificationChannel
+		}
+		updates["notification_channel"] = *payload.NotificationChannel
+	}
+
+	if payload.Locale != nil {
+		if !models.IsValidLocale(*payload.Locale) {
+			return nil, ErrInvalidLocale
+		}
+		updates["locale"] = *payload.Locale
 	}
 
 	if payload.HireDate != nil {
@@ -170,16 +318,18 @@ func (s *employeeService) UpdateEmployee(employeeID string, payload models.Updat
 		}
 	}
 
+	// 离职员工恢复在职须办理复职，以记录任职期间并可恢复风险号码
+	if payload.EmploymentStatus != nil && *payload.EmploymentStatus != "Departed" && currentEmployee.EmploymentStatus == "Departed" {
+		return nil, ErrRehireRequired
+	}
+
+	departing := payload.EmploymentStatus != nil && *payload.EmploymentStatus == "Departed" && currentEmployee.EmploymentStatus != "Departed"
+	if len(payload.NumberDispositions) > 0 && !departing {
+		return nil, fmt.Errorf("%w: 只有办理离职时才能提供号码处置方案", ErrInvalidDeparturePlan)
+	}
+
 	statusUpdated := false
 	if payload.EmploymentStatus != nil {
-		// 如果要将员工状态更新为"Departed"，需要进行离职检查
-		if *payload.EmploymentStatus == "Departed" && currentEmployee.EmploymentStatus != "Departed" {
-			// 执行离职前检查和处理
-			if err := s.handleEmployeeDeparture(employeeID); err != nil {
-				return nil, err
-			}
-		}
-
 		updates["employment_status"] = *payload.EmploymentStatus
 		statusUpdated = true
 
@@ -218,55 +368,614 @@ func (s *employeeService) UpdateEmployee(employeeID string, payload models.Updat
 		return nil, errors.New("没有提供任何有效的更新字段")
 	}
 
-	return s.repo.UpdateEmployee(employeeID, updates)
+	if !departing {
+		updatedEmployee, err := s.repo.UpdateEmployee(ctx, employeeID, updates)
+		if err != nil {
+			return nil, err
+		}
+		// 清除离职日期（如恢复在职）即取消尚未办理的预约离职
+		if termDate, ok := updates["termination_date"]; ok && termDate == nil {
+			if err := s.scheduledDepartureRepo.CancelPending(ctx, employeeID); err != nil {
+				return nil, err
+			}
+		}
+		return updatedEmployee, nil
+	}
+
+	// 离职日期在今天之后时只登记预约，员工保持在职，由定时任务在离职日期办理（未提供离职日期时为当前时间，当天办理）
+	terminationDate := *updates["termination_date"].(*time.Time)
+	if !terminationDate.Before(today().AddDate(0, 0, 1)) {
+		return s.scheduleDeparture(ctx, employeeID, payload.NumberDispositions, terminationDate, updates)
+	}
+
+	// 直接办理离职时取消尚未办理的预约离职
+	return s.depart(ctx, employeeID, payload.NumberDispositions, terminationDate, updates, func(departureRepo repositories.ScheduledDepartureRepository) error {
+		return departureRepo.CancelPending(ctx, employeeID)
+	})
 }
 
-// handleEmployeeDeparture 处理员工离职时的业务逻辑
-func (s *employeeService) handleEmployeeDeparture(employeeID string) error {
-	ctx := context.Background()
+// depart 办理离职：号码处置、办卡人号码转为风险号码、员工信息更新和 finishDeparture（更新预约离职）在同一事务中完成
+func (s *employeeService) depart(ctx context.Context, employeeID string, dispositions []models.NumberDisposition, terminationDate time.Time, updates map[string]interface{}, finishDeparture func(departureRepo repositories.ScheduledDepartureRepository) error) (*models.Employee, error) {
+	var updatedEmployee *models.Employee
+	err := s.repo.WithinTransaction(ctx, func(employeeRepo repositories.EmployeeRepository, mobileNumberRepo repositories.MobileNumberRepository, departureRepo repositories.ScheduledDepartureRepository) error {
+		if _, _, err := applyDeparturePlan(ctx, mobileNumberRepo, employeeID, dispositions, terminationDate, false); err != nil {
+			return err
+		}
+		updated, err := employeeRepo.UpdateEmployee(ctx, employeeID, updates)
+		if err != nil {
+			return err
+		}
+		updatedEmployee = updated
+		return finishDeparture(departureRepo)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updatedEmployee, nil
+}
+
+// scheduleDeparture 登记预约离职：保存号码处置方案，员工信息中只更新离职日期等字段，在职状态保持不变。
+// 登记前按当前号码预演处置方案，方案缺少或包含无效的号码处置时拒绝登记，避免到期时才办理失败
+func (s *employeeService) scheduleDeparture(ctx context.Context, employeeID string, dispositions []models.NumberDisposition, terminationDate time.Time, updates map[string]interface{}) (*models.Employee, error) {
+	outcomes, _, err := s.previewDeparturePlan(ctx, employeeID, dispositions, terminationDate)
+	if err != nil {
+		return nil, err
+	}
+	var problems []string
+	for _, outcome := range outcomes {
+		if outcome.Error != "" {
+			problems = append(problems, strings.TrimPrefix(outcome.Error, ErrInvalidDeparturePlan.Error()+": "))
+		}
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidDeparturePlan, strings.Join(problems, "；"))
+	}
 
-	// 1. 检查员工是否有使用中的手机号码（状态为 in_use）
-	assignedNumbers, err := s.mobileNumberRepo.FindAssignedToEmployee(ctx, employeeID)
+	departure := &models.ScheduledDeparture{EmployeeID: employeeID, TerminationDate: terminationDate}
+	if len(dispositions) > 0 {
+		encoded, err := json.Marshal(dispositions)
+		if err != nil {
+			return nil, err
+		}
+		plan := string(encoded)
+		departure.NumberDispositions = &plan
+	}
+	delete(updates, "employment_status")
+
+	updatedEmployee, err := s.scheduledDepartureRepo.Schedule(ctx, departure, updates)
 	if err != nil {
+		if errors.Is(err, repositories.ErrRecordNotFound) {
+			return nil, ErrEmployeeNotFound
+		}
+		return nil, err
+	}
+	return updatedEmployee, nil
+}
+
+// GetScheduledDepartures 返回所有尚未办理的预约离职，按离职日期升序
+func (s *employeeService) GetScheduledDepartures(ctx context.Context) ([]models.ScheduledDepartureResponse, error) {
+	departures, err := s.scheduledDepartureRepo.FindPending(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.ScheduledDepartureResponse, 0, len(departures))
+	for _, d := range departures {
+		dispositions, err := decodeDispositions(d)
+		if err != nil {
+			return nil, err
+		}
+		response := models.ScheduledDepartureResponse{ScheduledDeparture: d, NumberDispositions: dispositions}
+		if employee, err := s.repo.GetEmployeeByEmployeeID(d.EmployeeID); err == nil {
+			response.FullName = employee.FullName
+		}
+		responses = append(responses, response)
+	}
+	return responses, nil
+}
+
+// ProcessScheduledDepartures 办理离职日期不晚于 asOf 的预约离职，返回成功办理的数量。
+// 单个员工办理失败时记录失败原因并继续处理其他员工，下次执行时重试
+func (s *employeeService) ProcessScheduledDepartures(ctx context.Context, asOf time.Time) (int, error) {
+	departures, err := s.scheduledDepartureRepo.FindDue(ctx, asOf)
+	if err != nil {
+		return 0, err
+	}
+
+	processed := 0
+	var errs []error
+	for _, d := range departures {
+		if err := s.processScheduledDeparture(ctx, d); err != nil {
+			errs = append(errs, fmt.Errorf("员工 %s: %w", d.EmployeeID, err))
+			continue
+		}
+		processed++
+	}
+	return processed, errors.Join(errs...)
+}
+
+// processScheduledDeparture 按保存的方案办理一条预约离职。员工已不存在或已离职时取消该预约
+func (s *employeeService) processScheduledDeparture(ctx context.Context, d models.ScheduledDeparture) error {
+	employee, err := s.repo.GetEmployeeByEmployeeID(d.EmployeeID)
+	if err != nil && !errors.Is(err, repositories.ErrRecordNotFound) {
 		return err
 	}
+	if err != nil || employee.EmploymentStatus == "Departed" {
+		return s.scheduledDepartureRepo.MarkFinished(ctx, d.ID, models.ScheduledDepartureCancelled, time.Now())
+	}
+
+	dispositions, err := decodeDispositions(d)
+	if err == nil {
+		terminationDate := d.TerminationDate
+		_, err = s.depart(ctx, d.EmployeeID, dispositions, terminationDate, map[string]interface{}{
+			"employment_status": "Departed",
+			"termination_date":  &terminationDate,
+		}, func(departureRepo repositories.ScheduledDepartureRepository) error {
+			return departureRepo.MarkFinished(ctx, d.ID, models.ScheduledDepartureCompleted, time.Now())
+		})
+	}
+	if err != nil {
+		if recordErr := s.scheduledDepartureRepo.RecordFailure(ctx, d.ID, err.Error()); recordErr != nil {
+			return errors.Join(err, recordErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// SendDepartureReminders 将离职日期在 asOf 之后 DepartureReminderDays 天内的预约离职提醒加入发送队列，
+// 每个 IT 邮箱一条，由队列发送和失败重试。每条预约只提醒一次，返回加入队列的预约数量。未配置 IT 邮箱时不提醒
+func (s *employeeService) SendDepartureReminders(ctx context.Context, asOf time.Time) (int, error) {
+	recipients := configs.AppConfig.ITTeamEmails
+	if len(recipients) == 0 {
+		return 0, nil
+	}
+
+	departures, err := s.scheduledDepartureRepo.FindReminderDue(ctx, asOf.AddDate(0, 0, configs.AppConfig.DepartureReminderDays))
+	if err != nil {
+		return 0, err
+	}
+
+	reminded := 0
+	var errs []error
+	for _, d := range departures {
+		employee, err := s.repo.GetEmployeeByEmployeeID(d.EmployeeID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("员工 %s: %w", d.EmployeeID, err))
+			continue
+		}
+		numbers, err := s.departureReminderNumbers(ctx, d)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("员工 %s: %w", d.EmployeeID, err))
+			continue
+		}
+		content := email.DepartureReminderMessage(employee.FullName, employee.EmployeeID, d.TerminationDate.Format("2006-01-02"), numbers)
+		now := time.Now()
+		msgs := make([]models.OutboundMessage, 0, len(recipients))
+		for _, recipient := range recipients {
+			msgs = append(msgs, models.OutboundMessage{
+				Kind:           models.OutboundMessageKindDepartureReminder,
+				EmployeeID:     employee.EmployeeID,
+				RecipientEmail: recipient,
+				Channel:        string(email.ChannelEmail),
+				Subject:        content.Subject,
+				HTMLBody:       content.HTML,
+				TextBody:       content.Text,
+				Status:         models.OutboundMessagePending,
+				MaxAttempts:    configs.AppConfig.OutboundMaxAttempts,
+				NextAttemptAt:  now,
+			})
+		}
+		ok, err := s.outboundRepo.EnqueueDepartureReminder(ctx, d.ID, now, msgs)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("员工 %s: %w", d.EmployeeID, err))
+			continue
+		}
+		if ok {
+			reminded++
+		}
+	}
+	return reminded, errors.Join(errs...)
+}
+
+// departureReminderNumbers 列出员工使用中和作为办卡人的号码，以及离职时对每个号码的处理
+func (s *employeeService) departureReminderNumbers(ctx context.Context, d models.ScheduledDeparture) ([]email.DepartureReminderNumber, error) {
+	dispositions, err := decodeDispositions(d)
+	if err != nil {
+		return nil, err
+	}
+	planned := make(map[string]models.NumberDisposition, len(dispositions))
+	for _, disposition := range dispositions {
+		planned[disposition.PhoneNumber] = disposition
+	}
+
+	assignedNumbers, err := s.mobileNumberRepo.FindAssignedToEmployee(ctx, d.EmployeeID)
+	if err != nil {
+		return nil, err
+	}
+	applicantNumbers, err := s.mobileNumberRepo.FindByApplicantEmployeeID(ctx, d.EmployeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	var numbers []email.DepartureReminderNumber
+	for _, n := range assignedNumbers {
+		plan := "未提供处置方式，届时将无法办理离职"
+		if disposition, ok := planned[n.PhoneNumber]; ok {
+			plan = dispositionDescription(disposition)
+		}
+		numbers = append(numbers, email.DepartureReminderNumber{PhoneNumber: n.PhoneNumber, Role: "使用人", Status: n.Status, Plan: plan})
+	}
+	for _, n := range applicantNumbers {
+		plan := "转为风险号码，需变更办卡人、回收或注销"
+		if disposition, ok := planned[n.PhoneNumber]; ok && disposition.Action == string(models.DepartureScheduleDeactivation) {
+			plan = dispositionDescription(disposition)
+		} else if !models.CanTransition(models.NumberStatus(n.Status), models.StatusRiskPending) {
+			plan = "不做处理"
+		}
+		numbers = append(numbers, email.DepartureReminderNumber{PhoneNumber: n.PhoneNumber, Role: "办卡人", Status: n.Status, Plan: plan})
+	}
+	return numbers, nil
+}
+
+// dispositionDescription 返回号码处置方式的说明文字
+func dispositionDescription(d models.NumberDisposition) string {
+	switch models.DepartureNumberAction(d.Action) {
+	case models.DepartureReclaim:
+		return "回收为闲置"
+	case models.DepartureTransfer:
+		if d.TransferToEmployeeID != nil {
+			return "转移给 " + *d.TransferToEmployeeID
+		}
+		return "转移"
+	case models.DepartureScheduleDeactivation:
+		if d.ScheduledDeactivationDate != nil {
+			return "回收并计划于 " + *d.ScheduledDeactivationDate + " 注销"
+		}
+		return "回收并计划注销"
+	}
+	return d.Action
+}
+
+// decodeDispositions 解析预约离职中保存的号码处置方案
+func decodeDispositions(d models.ScheduledDeparture) ([]models.NumberDisposition, error) {
+	var dispositions []models.NumberDisposition
+	if d.NumberDispositions == nil || *d.NumberDispositions == "" {
+		return dispositions, nil
+	}
+	if err := json.Unmarshal([]byte(*d.NumberDispositions), &dispositions); err != nil {
+		return nil, fmt.Errorf("解析号码处置方案失败: %w", err)
+	}
+	return dispositions, nil
+}
+
+// PreviewDeparture 在事务中按方案执行号码处置和风险号码标记后回滚，不修改任何数据。
+// 与实际办理离职不同，某个号码无法处置时继续处理其余号码，以便一次展示方案中的全部问题
+func (s *employeeService) PreviewDeparture(ctx context.Context, employeeID string, payload models.DeparturePreviewPayload) (*models.DeparturePreviewResponse, error) {
+	employee, err := s.repo.GetEmployeeByEmployeeID(employeeID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrRecordNotFound) {
+			return nil, ErrEmployeeNotFound
+		}
+		return nil, err
+	}
+	if employee.EmploymentStatus == "Departed" {
+		return nil, ErrEmployeeAlreadyDeparted
+	}
+
+	terminationDate := time.Now()
+	if payload.TerminationDate != nil && *payload.TerminationDate != "" {
+		terminationDate, err = time.Parse("2006-01-02", *payload.TerminationDate)
+		if err != nil {
+			return nil, errors.New("无效的离职日期格式: " + *payload.TerminationDate)
+		}
+	}
+
+	preview := &models.DeparturePreviewResponse{
+		EmployeeID:      employee.EmployeeID,
+		FullName:        employee.FullName,
+		TerminationDate: terminationDate,
+	}
+	preview.Numbers, preview.RiskPendingNumbers, err = s.previewDeparturePlan(ctx, employeeID, payload.NumberDispositions, terminationDate)
+	if err != nil {
+		return nil, err
+	}
+
+	preview.CanDepart = true
+	for _, outcome := range preview.Numbers {
+		if outcome.Error != "" {
+			preview.CanDepart = false
+			break
+		}
+	}
+	return preview, nil
+}
+
+// previewDeparturePlan 在事务中预演号码处置方案后回滚，返回每个号码的处置结果（含错误）和将转为风险号码的号码
+func (s *employeeService) previewDeparturePlan(ctx context.Context, employeeID string, dispositions []models.NumberDisposition, terminationDate time.Time) ([]models.DepartureNumberOutcome, []string, error) {
+	var outcomes []models.DepartureNumberOutcome
+	var riskNumbers []string
+	err := s.repo.WithinTransaction(ctx, func(_ repositories.EmployeeRepository, mobileNumberRepo repositories.MobileNumberRepository, _ repositories.ScheduledDepartureRepository) error {
+		var err error
+		outcomes, riskNumbers, err = applyDeparturePlan(ctx, mobileNumberRepo, employeeID, dispositions, terminationDate, true)
+		if err != nil {
+			return err
+		}
+		return errDeparturePreviewRollback
+	})
+	if err != nil && !errors.Is(err, errDeparturePreviewRollback) {
+		return nil, nil, err
+	}
+	return outcomes, riskNumbers, nil
+}
 
-	// 过滤出状态为"使用中"的号码
-	var activeNumbers []models.MobileNumber
+// PreviewRehire 列出离职员工作为办卡人的风险待核实号码及复职后各号码将恢复的状态
+func (s *employeeService) PreviewRehire(ctx context.Context, employeeID string) (*models.RehireResponse, error) {
+	employee, err := s.departedEmployee(employeeID)
+	if err != nil {
+		return nil, err
+	}
+	riskNumbers, err := riskNumbersOfApplicant(ctx, s.mobileNumberRepo, employeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	options := make([]models.RehireNumberOption, 0, len(riskNumbers))
+	for i := range riskNumbers {
+		options = append(options, rehireNumberOption(&riskNumbers[i], false))
+	}
+	return &models.RehireResponse{Employee: *employee, Numbers: options}, nil
+}
+
+// Rehire 办理复职：记录上一段任职期间、恢复在职，并把选中的风险号码恢复为进入风险待核实前的状态，
+// 均在同一事务中完成。未选中的号码保持风险待核实，仍可通过风险号码处理
+func (s *employeeService) Rehire(ctx context.Context, employeeID string, payload models.RehirePayload) (*models.RehireResponse, error) {
+	employee, err := s.departedEmployee(employeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	rehireDate := today()
+	if payload.RehireDate != nil && *payload.RehireDate != "" {
+		rehireDate, err = utils.ParseDate(*payload.RehireDate)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidRehireRequest, err)
+		}
+	}
+	if employee.TerminationDate != nil && rehireDate.Before(employee.TerminationDate.Truncate(24*time.Hour)) {
+		return nil, fmt.Errorf("%w: 复职日期不能早于离职日期", ErrInvalidRehireRequest)
+	}
+
+	restore := make(map[string]bool, len(payload.RestorePhoneNumbers))
+	for _, phoneNumber := range payload.RestorePhoneNumbers {
+		restore[phoneNumber] = true
+	}
+	scheduledDeactivationDate := today().AddDate(0, 0, configs.AppConfig.NumberDeactivationGraceDays)
+
+	response := &models.RehireResponse{Numbers: []models.RehireNumberOption{}}
+	err = s.repo.WithinTransaction(ctx, func(employeeRepo repositories.EmployeeRepository, mobileNumberRepo repositories.MobileNumberRepository, _ repositories.ScheduledDepartureRepository) error {
+		riskNumbers, err := riskNumbersOfApplicant(ctx, mobileNumberRepo, employeeID)
+		if err != nil {
+			return err
+		}
+		restorable := make(map[string]bool, len(riskNumbers))
+		for _, number := range riskNumbers {
+			restorable[number.PhoneNumber] = true
+		}
+		for _, phoneNumber := range payload.RestorePhoneNumbers {
+			if !restorable[phoneNumber] {
+				return fmt.Errorf("%w: 号码 %s 不是该员工作为办卡人的风险待核实号码", ErrInvalidRehireRequest, phoneNumber)
+			}
+		}
+
+		updated, err := employeeRepo.Rehire(ctx, employeeID, rehireDate)
+		if errors.Is(err, repositories.ErrEmployeeNotDeparted) {
+			// 其他请求已办理复职
+			return ErrEmployeeNotDeparted
+		}
+		if err != nil {
+			return err
+		}
+		response.Employee = *updated
+
+		for i := range riskNumbers {
+			number := &riskNumbers[i]
+			if !payload.RestoreAllNumbers && !restore[number.PhoneNumber] {
+				response.Numbers = append(response.Numbers, rehireNumberOption(number, false))
+				continue
+			}
+			if _, err := mobileNumberRepo.RestoreFromRisk(ctx, number.ID, scheduledDeactivationDate); err != nil {
+				return fmt.Errorf("恢复号码 %s 失败: %w", number.PhoneNumber, err)
+			}
+			response.Numbers = append(response.Numbers, rehireNumberOption(number, true))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// departedEmployee 查询员工并确认其已离职
+func (s *employeeService) departedEmployee(employeeID string) (*models.Employee, error) {
+	employee, err := s.repo.GetEmployeeByEmployeeID(employeeID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrRecordNotFound) {
+			return nil, ErrEmployeeNotFound
+		}
+		return nil, err
+	}
+	if employee.EmploymentStatus != "Departed" {
+		return nil, ErrEmployeeNotDeparted
+	}
+	return employee, nil
+}
+
+// riskNumbersOfApplicant 查询员工作为办卡人的风险待核实号码
+func riskNumbersOfApplicant(ctx context.Context, repo repositories.MobileNumberRepository, employeeID string) ([]models.MobileNumber, error) {
+	numbers, err := repo.FindByApplicantEmployeeID(ctx, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	riskNumbers := make([]models.MobileNumber, 0, len(numbers))
+	for _, number := range numbers {
+		if number.Status == string(models.StatusRiskPending) {
+			riskNumbers = append(riskNumbers, number)
+		}
+	}
+	return riskNumbers, nil
+}
+
+// rehireNumberOption 将风险号码转换为复职响应中的可恢复号码
+func rehireNumberOption(number *models.MobileNumber, restored bool) models.RehireNumberOption {
+	return models.RehireNumberOption{
+		PhoneNumber:       number.PhoneNumber,
+		CurrentEmployeeID: number.CurrentEmployeeID,
+		RestoreStatus:     models.RiskRestoreStatus(number),
+		Restored:          restored,
+	}
+}
+
+// applyDeparturePlan 处理员工离职时的号码：按处置方案回收、转移或计划注销员工使用中的号码，
+// 然后将其作为办卡人的号码转为风险待核实（已按方案计划注销的号码除外）。
+// 使用中的号码没有处置方式时不允许离职。preview 为 false 时遇到第一个错误即返回，
+// 为 true 时把错误记录在对应号码的处置结果中并继续处理
+func applyDeparturePlan(ctx context.Context, repo repositories.MobileNumberRepository, employeeID string, dispositions []models.NumberDisposition, effectiveDate time.Time, preview bool) ([]models.DepartureNumberOutcome, []string, error) {
+	// 1. 查找员工使用中（状态为 in_use）的号码
+	assignedNumbers, err := repo.FindAssignedToEmployee(ctx, employeeID)
+	if err != nil {
+		return nil, nil, err
+	}
+	activeNumbers := make(map[string]models.MobileNumber)
+	var outcomes []models.DepartureNumberOutcome
 	for _, number := range assignedNumbers {
 		if number.Status == string(models.StatusInUse) {
-			activeNumbers = append(activeNumbers, number)
+			activeNumbers[number.PhoneNumber] = number
+			outcomes = append(outcomes, models.DepartureNumberOutcome{PhoneNumber: number.PhoneNumber})
 		}
 	}
 
-	// 如果有使用中的号码，拒绝离职
-	if len(activeNumbers) > 0 {
-		return ErrEmployeeHasActiveNumbers
+	// 2. 将处置方案与使用中的号码对应，方案中不属于该员工使用中的号码或重复的号码视为无效
+	planned := make(map[string]models.NumberDisposition, len(dispositions))
+	for _, d := range dispositions {
+		if _, ok := activeNumbers[d.PhoneNumber]; !ok {
+			err := fmt.Errorf("%w: 号码 %s 不是该员工使用中的号码", ErrInvalidDeparturePlan, d.PhoneNumber)
+			if !preview {
+				return nil, nil, err
+			}
+			outcomes = append(outcomes, models.DepartureNumberOutcome{PhoneNumber: d.PhoneNumber, Action: models.DepartureNumberAction(d.Action), Error: err.Error()})
+			continue
+		}
+		if _, ok := planned[d.PhoneNumber]; ok {
+			err := fmt.Errorf("%w: 号码 %s 重复提供了处置方式", ErrInvalidDeparturePlan, d.PhoneNumber)
+			if !preview {
+				return nil, nil, err
+			}
+			outcomes = append(outcomes, models.DepartureNumberOutcome{PhoneNumber: d.PhoneNumber, Action: models.DepartureNumberAction(d.Action), Error: err.Error()})
+			continue
+		}
+		planned[d.PhoneNumber] = d
 	}
 
-	// 2. 查找该员工作为办卡人的所有号码
-	applicantNumbers, err := s.mobileNumberRepo.FindByApplicantEmployeeID(ctx, employeeID)
-	if err != nil {
-		return err
+	// 3. 逐个处置使用中的号码
+	scheduledForDeactivation := make(map[uint]bool)
+	for i := range outcomes {
+		outcome := &outcomes[i]
+		number, ok := activeNumbers[outcome.PhoneNumber]
+		if !ok || outcome.Error != "" {
+			continue
+		}
+		d, ok := planned[outcome.PhoneNumber]
+		if !ok {
+			err := fmt.Errorf("%w: %s 未提供处置方式", ErrEmployeeHasActiveNumbers, outcome.PhoneNumber)
+			if !preview {
+				return nil, nil, err
+			}
+			outcome.Error = err.Error()
+			continue
+		}
+
+		outcome.Action = models.DepartureNumberAction(d.Action)
+		if d.TransferToEmployeeID != nil {
+			outcome.TransferToEmployeeID = *d.TransferToEmployeeID
+		}
+		result, err := applyNumberDisposition(ctx, repo, employeeID, number, d, effectiveDate)
+		if err != nil {
+			err = fmt.Errorf("%w: 号码 %s: %w", ErrDepartureDispositionFailed, number.PhoneNumber, err)
+			if !preview {
+				return nil, nil, err
+			}
+			outcome.Error = err.Error()
+			continue
+		}
+		outcome.ResultStatus = result.Status
+		if outcome.Action == models.DepartureScheduleDeactivation {
+			scheduledForDeactivation[number.ID] = true
+		}
 	}
 
-	// 3. 将办卡人的号码状态更新为 risk_pending（除了已经是终止状态的）
+	// 4. 将办卡人的号码状态更新为 risk_pending（只处理生命周期允许进入该状态的号码）
+	applicantNumbers, err := repo.FindByApplicantEmployeeID(ctx, employeeID)
+	if err != nil {
+		return nil, nil, err
+	}
 	var numberIDsToUpdate []uint
+	riskNumbers := []string{}
 	for _, number := range applicantNumbers {
-		// 只更新非终止状态的号码
-		if number.Status != string(models.StatusDeactivated) &&
-			number.Status != string(models.StatusRiskPending) &&
-			number.Status != string(models.StatusUserReport) {
+		if scheduledForDeactivation[number.ID] {
+			continue
+		}
+		if models.CanTransition(models.NumberStatus(number.Status), models.StatusRiskPending) {
 			numberIDsToUpdate = append(numberIDsToUpdate, number.ID)
+			riskNumbers = append(riskNumbers, number.PhoneNumber)
 		}
 	}
-
-	// 批量更新号码状态
 	if len(numberIDsToUpdate) > 0 {
-		if err := s.mobileNumberRepo.BatchUpdateStatus(ctx, numberIDsToUpdate, string(models.StatusRiskPending)); err != nil {
-			return err
+		if err := repo.BatchUpdateStatus(ctx, numberIDsToUpdate, string(models.StatusRiskPending)); err != nil {
+			return nil, nil, err
 		}
 	}
 
-	return nil
+	return outcomes, riskNumbers, nil
+}
+
+// applyNumberDisposition 按处置方式处理离职员工的单个使用中号码，回收和转移都在离职日期生效
+func applyNumberDisposition(ctx context.Context, repo repositories.MobileNumberRepository, employeeID string, number models.MobileNumber, d models.NumberDisposition, effectiveDate time.Time) (*models.MobileNumber, error) {
+	switch models.DepartureNumberAction(d.Action) {
+	case models.DepartureReclaim:
+		return repo.UnassignMobileNumber(ctx, number.ID, effectiveDate)
+	case models.DepartureTransfer:
+		if d.TransferToEmployeeID == nil || *d.TransferToEmployeeID == "" {
+			return nil, errors.New("转移号码时必须提供接收员工工号")
+		}
+		if *d.TransferToEmployeeID == employeeID {
+			return nil, errors.New("不能将号码转移给离职员工本人")
+		}
+		reason := d.Remarks
+		if reason == "" {
+			reason = "原使用人离职，号码转移"
+		}
+		return repo.TransferMobileNumber(ctx, number.ID, *d.TransferToEmployeeID, effectiveDate, reason)
+	case models.DepartureScheduleDeactivation:
+		scheduledDate := today().AddDate(0, 0, configs.AppConfig.NumberDeactivationGraceDays)
+		if d.ScheduledDeactivationDate != nil {
+			parsedDate, err := utils.ParseDate(*d.ScheduledDeactivationDate)
+			if err != nil {
+				return nil, err
+			}
+			if parsedDate.Before(today()) {
+				return nil, ErrDeactivationDateInPast
+			}
+			scheduledDate = parsedDate
+		}
+		if _, err := repo.UnassignMobileNumber(ctx, number.ID, effectiveDate); err != nil {
+			return nil, err
+		}
+		return repo.UpdateMobileNumber(ctx, number.ID, map[string]interface{}{
+			"status":                      string(models.StatusPendingDeactivation),
+			"scheduled_deactivation_date": scheduledDate,
+		})
+	}
+	return nil, fmt.Errorf("无效的处置方式: %s", d.Action)
 }
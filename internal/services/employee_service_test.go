@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/phone_management/internal/models"
+	"gorm.io/gorm"
+)
+
+// departureFixture 离职测试数据：离职员工 departing 使用 reclaimed、transferred、deactivated 三个号码，
+// 另有一个办卡人为 departing、由 receiver 使用的号码 lent
+type departureFixture struct {
+	db                                        *gorm.DB
+	departing, receiver                       *models.Employee
+	reclaimed, transferred, deactivated, lent *models.MobileNumber
+}
+
+func newDepartureFixture(t *testing.T) *departureFixture {
+	t.Helper()
+	db := newTestDB(t)
+	f := &departureFixture{db: db}
+	f.departing = createTestEmployee(t, db, "张三")
+	f.receiver = createTestEmployee(t, db, "李四")
+	f.reclaimed = createTestNumber(t, db, "13800000001", f.departing, f.departing)
+	f.transferred = createTestNumber(t, db, "13800000002", f.receiver, f.departing)
+	f.deactivated = createTestNumber(t, db, "13800000003", f.departing, f.departing)
+	f.lent = createTestNumber(t, db, "13800000004", f.departing, f.receiver)
+	return f
+}
+
+// depart 以给定的号码处置方案为离职员工办理当天离职
+func (f *departureFixture) depart(deactivationDate string) error {
+	departed := "Departed"
+	terminationDate := today().Format("2006-01-02")
+	_, err := newTestEmployeeService(f.db).UpdateEmployee(context.Background(), f.departing.EmployeeID, models.UpdateEmployeePayload{
+		EmploymentStatus: &departed,
+		TerminationDate:  &terminationDate,
+		NumberDispositions: []models.NumberDisposition{
+			{PhoneNumber: f.reclaimed.PhoneNumber, Action: string(models.DepartureReclaim)},
+			{PhoneNumber: f.transferred.PhoneNumber, Action: string(models.DepartureTransfer), TransferToEmployeeID: &f.receiver.EmployeeID},
+			{PhoneNumber: f.deactivated.PhoneNumber, Action: string(models.DepartureScheduleDeactivation), ScheduledDeactivationDate: &deactivationDate},
+		},
+	})
+	return err
+}
+
+func (f *departureFixture) number(t *testing.T, n *models.MobileNumber) models.MobileNumber {
+	t.Helper()
+	var got models.MobileNumber
+	if err := f.db.First(&got, n.ID).Error; err != nil {
+		t.Fatalf("查询号码 %s 失败: %v", n.PhoneNumber, err)
+	}
+	return got
+}
+
+// usage 返回号码的使用记录，按开始时间和 ID 升序
+func (f *departureFixture) usage(t *testing.T, n *models.MobileNumber) []models.NumberUsageHistory {
+	t.Helper()
+	var histories []models.NumberUsageHistory
+	if err := f.db.Where("mobile_number_db_id = ?", n.ID).Order("start_date, id").Find(&histories).Error; err != nil {
+		t.Fatalf("查询号码 %s 的使用记录失败: %v", n.PhoneNumber, err)
+	}
+	return histories
+}
+
+func (f *departureFixture) employeeStatus(t *testing.T) string {
+	t.Helper()
+	var employee models.Employee
+	if err := f.db.Where("employee_id = ?", f.departing.EmployeeID).First(&employee).Error; err != nil {
+		t.Fatalf("查询员工失败: %v", err)
+	}
+	return employee.EmploymentStatus
+}
+
+func TestDepartAppliesDispositionsAndMarksRiskNumbers(t *testing.T) {
+	f := newDepartureFixture(t)
+	deactivationDate := today().AddDate(0, 0, 30).Format("2006-01-02")
+	if err := f.depart(deactivationDate); err != nil {
+		t.Fatalf("办理离职失败: %v", err)
+	}
+
+	if status := f.employeeStatus(t); status != "Departed" {
+		t.Errorf("employment status = %s, want Departed", status)
+	}
+
+	// 回收的号码办卡人为离职员工，回收后转为风险待核实
+	reclaimed := f.number(t, f.reclaimed)
+	if reclaimed.Status != string(models.StatusRiskPending) || reclaimed.CurrentEmployeeID != nil {
+		t.Errorf("reclaimed number: status = %s, user = %v, want risk_pending without user", reclaimed.Status, reclaimed.CurrentEmployeeID)
+	}
+	if h := f.usage(t, f.reclaimed); len(h) != 1 || h[0].EndDate == nil {
+		t.Errorf("reclaimed number usage = %+v, want one closed record", h)
+	}
+
+	// 转移的号码办卡人为接收员工，转移后由接收员工使用
+	transferred := f.number(t, f.transferred)
+	if transferred.Status != string(models.StatusInUse) || transferred.CurrentEmployeeID == nil || *transferred.CurrentEmployeeID != f.receiver.EmployeeID {
+		t.Errorf("transferred number: status = %s, user = %v, want in_use by %s", transferred.Status, transferred.CurrentEmployeeID, f.receiver.EmployeeID)
+	}
+	h := f.usage(t, f.transferred)
+	if len(h) != 2 || h[0].EmployeeID != f.departing.EmployeeID || h[0].EndDate == nil ||
+		h[1].EmployeeID != f.receiver.EmployeeID || h[1].EndDate != nil {
+		t.Errorf("transferred number usage = %+v, want a closed record for the departing employee and an open one for the receiver", h)
+	}
+
+	// 计划注销的号码不转为风险号码
+	deactivated := f.number(t, f.deactivated)
+	if deactivated.Status != string(models.StatusPendingDeactivation) || deactivated.ScheduledDeactivationDate == nil ||
+		deactivated.ScheduledDeactivationDate.Format("2006-01-02") != deactivationDate {
+		t.Errorf("deactivated number: status = %s, scheduled = %v, want pending_deactivation on %s", deactivated.Status, deactivated.ScheduledDeactivationDate, deactivationDate)
+	}
+	if h := f.usage(t, f.deactivated); len(h) != 1 || h[0].EndDate == nil {
+		t.Errorf("deactivated number usage = %+v, want one closed record", h)
+	}
+
+	// 其他员工使用的办卡人号码转为风险待核实
+	if lent := f.number(t, f.lent); lent.Status != string(models.StatusRiskPending) {
+		t.Errorf("lent number status = %s, want risk_pending", lent.Status)
+	}
+}
+
+func TestDepartRollsBackOnInvalidDisposition(t *testing.T) {
+	f := newDepartureFixture(t)
+	// 计划注销日期已过，最后一个号码的处置失败
+	if err := f.depart(today().AddDate(0, 0, -1).Format("2006-01-02")); !errors.Is(err, ErrDeactivationDateInPast) {
+		t.Fatalf("err = %v, want ErrDeactivationDateInPast", err)
+	}
+
+	if status := f.employeeStatus(t); status != "Active" {
+		t.Errorf("employment status = %s, want Active", status)
+	}
+	for _, n := range []*models.MobileNumber{f.reclaimed, f.transferred, f.deactivated, f.lent} {
+		got := f.number(t, n)
+		if got.Status != string(models.StatusInUse) {
+			t.Errorf("number %s status = %s, want in_use", n.PhoneNumber, got.Status)
+		}
+		if h := f.usage(t, n); len(h) != 1 || h[0].EndDate != nil {
+			t.Errorf("number %s usage = %+v, want one open record", n.PhoneNumber, h)
+		}
+	}
+	if got := f.number(t, f.transferred); got.CurrentEmployeeID == nil || *got.CurrentEmployeeID != f.departing.EmployeeID {
+		t.Errorf("transferred number user = %v, want %s", got.CurrentEmployeeID, f.departing.EmployeeID)
+	}
+}
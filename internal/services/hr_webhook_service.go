@@ -0,0 +1,291 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/phone_management/configs"
+	"github.com/phone_management/internal/audit"
+	"github.com/phone_management/internal/models"
+	"github.com/phone_management/internal/repositories"
+)
+
+// HR 事件相关错误
+var ErrHRWebhookNotConfigured = errors.New("未配置 HR Webhook 签名密钥")
+var ErrInvalidHRSignature = errors.New("HR 事件签名无效")
+var ErrInvalidHREvent = errors.New("HR 事件格式无效")
+var ErrHRDeadLetterNotFound = errors.New("HR 事件死信未找到")
+var ErrHRDeadLetterResolved = errors.New("该 HR 事件已处理成功，无需重放")
+
+// HRWebhookSignaturePrefix 是签名请求头中签名值的前缀
+const HRWebhookSignaturePrefix = "sha256="
+
+// hrEventStaleClaimAfter 事件认领后超过该时间仍未处理完成时，视为处理中断（如服务崩溃）。
+// 再次推送时不自动重新处理，而是转入死信，由管理员确认员工数据后重放，避免重复新建员工
+const hrEventStaleClaimAfter = 10 * time.Minute
+
+// HRWebhookService 定义了处理 HR 系统推送的员工事件的服务接口
+type HRWebhookService interface {
+	// VerifySignature 校验请求签名：签名为以密钥对 "时间戳.请求体" 计算的 HMAC-SHA256（十六进制），
+	// 时间戳为 Unix 秒，与 now 的偏差不能超过配置的范围
+	VerifySignature(timestamp, signature string, body []byte, now time.Time) error
+	// HandleEvent 处理一个事件。同一事件ID只处理一次，其他请求正在处理时结果状态为 processing；
+	// 处理失败的事件转入死信，结果状态为 failed
+	HandleEvent(ctx context.Context, body []byte) (*models.HREventResult, error)
+	// GetDeadLetters 查询死信，includeResolved 为 false 时只返回尚未解决的死信
+	GetDeadLetters(ctx context.Context, includeResolved bool) ([]models.HREventDeadLetter, error)
+	// ReplayDeadLetter 重新处理死信中保存的事件
+	ReplayDeadLetter(ctx context.Context, id uint) (*models.HREventResult, error)
+}
+
+// hrWebhookService 是 HRWebhookService 的实现
+type hrWebhookService struct {
+	repo            repositories.HREventRepository
+	employeeService EmployeeService
+	employeeRepo    repositories.EmployeeRepository
+}
+
+// NewHRWebhookService 创建一个新的 hrWebhookService 实例，签名密钥和允许的时间偏差取自 configs.AppConfig。
+// 事件经由 employeeService 新建或更新员工，与手动操作的校验和副作用一致
+func NewHRWebhookService(repo repositories.HREventRepository, employeeService EmployeeService, employeeRepo repositories.EmployeeRepository) HRWebhookService {
+	return &hrWebhookService{repo: repo, employeeService: employeeService, employeeRepo: employeeRepo}
+}
+
+// VerifySignature 校验请求签名和时间戳
+func (s *hrWebhookService) VerifySignature(timestamp, signature string, body []byte, now time.Time) error {
+	secret := configs.AppConfig.HRWebhookSecret
+	if secret == "" {
+		return ErrHRWebhookNotConfigured
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: 时间戳无效", ErrInvalidHRSignature)
+	}
+	skew := now.Sub(time.Unix(seconds, 0))
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew > configs.AppConfig.HRWebhookMaxClockSkew {
+		return fmt.Errorf("%w: 时间戳超出允许范围", ErrInvalidHRSignature)
+	}
+
+	got, err := hex.DecodeString(strings.TrimPrefix(signature, HRWebhookSignaturePrefix))
+	if err != nil || len(got) == 0 {
+		return fmt.Errorf("%w: 签名格式无效", ErrInvalidHRSignature)
+	}
+	if !hmac.Equal(got, SignHREvent(secret, timestamp, body)) {
+		return ErrInvalidHRSignature
+	}
+	return nil
+}
+
+// SignHREvent 计算事件请求的签名，供 HR 系统对接和测试使用
+func SignHREvent(secret, timestamp string, body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// HandleEvent 解析并处理 HR 系统推送的事件
+func (s *hrWebhookService) HandleEvent(ctx context.Context, body []byte) (*models.HREventResult, error) {
+	event, err := parseHREvent(body)
+	if err != nil {
+		return nil, err
+	}
+	return s.process(ctx, event, string(body))
+}
+
+// parseHREvent 解析事件并校验事件ID和类型，事件数据在处理时校验，不合格时转入死信
+func parseHREvent(body []byte) (*models.HREventPayload, error) {
+	var event models.HREventPayload
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidHREvent, err)
+	}
+	event.ID = strings.TrimSpace(event.ID)
+	if event.ID == "" || len(event.ID) > 100 {
+		return nil, fmt.Errorf("%w: 事件ID不能为空且不能超过100个字符", ErrInvalidHREvent)
+	}
+	switch event.Type {
+	case models.HREventHired, models.HREventDepartmentChanged, models.HREventTerminated:
+	default:
+		return nil, fmt.Errorf("%w: 不支持的事件类型 %q", ErrInvalidHREvent, event.Type)
+	}
+	return &event, nil
+}
+
+// process 先认领事件再处理并记录结果：认领保证同一事件在多个实例间只处理一次，
+// 成功时将认领记录标记为已处理以便识别重复推送，失败时释放认领并保存到死信
+func (s *hrWebhookService) process(ctx context.Context, event *models.HREventPayload, payload string) (*models.HREventResult, error) {
+	result := &models.HREventResult{EventID: event.ID}
+	now := time.Now().UTC()
+	existing, claimed, err := s.repo.Claim(ctx, &models.HREvent{EventID: event.ID, EventType: event.Type, ProcessedAt: now})
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		result.EmployeeID = existing.EmployeeID
+		switch {
+		case existing.Status == models.HREventClaimProcessed:
+			result.Status = models.HREventDuplicate
+		case now.Sub(existing.ProcessedAt) < hrEventStaleClaimAfter:
+			result.Status = models.HREventInProgress
+		default:
+			return s.recordFailure(ctx, result, event, payload, errors.New("上次处理在完成前中断，员工可能已经新建或更新，请核对员工数据后再重放"))
+		}
+		return result, nil
+	}
+
+	// 事件产生的审计事件标记来源为 HR 系统
+	employeeID, applyErr := s.apply(audit.WithSource(ctx, audit.SourceHR), event)
+	result.EmployeeID = employeeID
+	if applyErr != nil {
+		return s.recordFailure(ctx, result, event, payload, applyErr)
+	}
+
+	if err := s.repo.RecordProcessed(ctx, &models.HREvent{
+		EventID:     event.ID,
+		EventType:   event.Type,
+		EmployeeID:  employeeID,
+		ProcessedAt: time.Now().UTC(),
+	}); err != nil {
+		return nil, err
+	}
+	result.Status = models.HREventProcessed
+	return result, nil
+}
+
+// recordFailure 释放事件的认领并将事件保存到死信
+func (s *hrWebhookService) recordFailure(ctx context.Context, result *models.HREventResult, event *models.HREventPayload, payload string, cause error) (*models.HREventResult, error) {
+	deadLetter, err := s.repo.RecordFailure(ctx, &models.HREventDeadLetter{
+		EventID:   event.ID,
+		EventType: event.Type,
+		Payload:   payload,
+		LastError: cause.Error(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Status = models.HREventFailed
+	result.Error = cause.Error()
+	result.DeadLetterID = &deadLetter.ID
+	return result, nil
+}
+
+// apply 按事件类型新建或更新员工，返回员工业务工号
+func (s *hrWebhookService) apply(ctx context.Context, event *models.HREventPayload) (string, error) {
+	data := event.Data
+	if event.Type == models.HREventHired {
+		return s.hire(ctx, data)
+	}
+
+	employee, err := s.findEmployee(data)
+	if err != nil {
+		return "", err
+	}
+	var payload models.UpdateEmployeePayload
+	switch event.Type {
+	case models.HREventDepartmentChanged:
+		if strings.TrimSpace(data.Department) == "" {
+			return employee.EmployeeID, errors.New("调岗事件缺少部门")
+		}
+		payload.Department = &data.Department
+	case models.HREventTerminated:
+		status := "Departed"
+		payload.EmploymentStatus = &status
+		if data.TerminationDate != "" {
+			payload.TerminationDate = &data.TerminationDate
+		}
+	}
+	if _, err := s.employeeService.UpdateEmployee(ctx, employee.EmployeeID, payload); err != nil {
+		return employee.EmployeeID, err
+	}
+	return employee.EmployeeID, nil
+}
+
+// hire 处理入职事件，新建员工
+func (s *hrWebhookService) hire(ctx context.Context, data models.HREventData) (string, error) {
+	fullName := strings.TrimSpace(data.FullName)
+	if fullName == "" {
+		return "", errors.New("入职事件缺少姓名")
+	}
+	employee := &models.Employee{FullName: fullName}
+	if data.PhoneNumber != "" {
+		employee.PhoneNumber = &data.PhoneNumber
+	}
+	if data.Email != "" {
+		employee.Email = &data.Email
+	}
+	if data.Department != "" {
+		employee.Department = &data.Department
+	}
+	if data.HireDate != "" {
+		hireDate, err := time.Parse("2006-01-02", data.HireDate)
+		if err != nil {
+			return "", errors.New("无效的入职日期格式: " + data.HireDate)
+		}
+		employee.HireDate = &hireDate
+	}
+
+	created, err := s.employeeService.CreateEmployee(ctx, employee)
+	if err != nil {
+		return "", err
+	}
+	return created.EmployeeID, nil
+}
+
+// findEmployee 按员工业务工号或邮箱确定事件对应的员工
+func (s *hrWebhookService) findEmployee(data models.HREventData) (*models.Employee, error) {
+	var employee *models.Employee
+	var err error
+	switch {
+	case data.EmployeeID != "":
+		employee, err = s.employeeRepo.GetEmployeeByEmployeeID(data.EmployeeID)
+	case data.Email != "":
+		employee, err = s.employeeRepo.GetEmployeeByEmail(data.Email)
+	default:
+		return nil, errors.New("事件缺少员工业务工号或邮箱")
+	}
+	if errors.Is(err, repositories.ErrRecordNotFound) {
+		return nil, ErrEmployeeNotFound
+	}
+	return employee, err
+}
+
+// GetDeadLetters 查询死信
+func (s *hrWebhookService) GetDeadLetters(ctx context.Context, includeResolved bool) ([]models.HREventDeadLetter, error) {
+	return s.repo.FindDeadLetters(ctx, includeResolved)
+}
+
+// ReplayDeadLetter 重新处理死信中保存的事件，再次失败时更新死信的失败原因和次数
+func (s *hrWebhookService) ReplayDeadLetter(ctx context.Context, id uint) (*models.HREventResult, error) {
+	deadLetter, err := s.repo.GetDeadLetter(ctx, id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrRecordNotFound) {
+			return nil, ErrHRDeadLetterNotFound
+		}
+		return nil, err
+	}
+	if deadLetter.ResolvedAt != nil {
+		return nil, ErrHRDeadLetterResolved
+	}
+
+	event, err := parseHREvent([]byte(deadLetter.Payload))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.MarkReplayed(ctx, id, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	return s.process(ctx, event, deadLetter.Payload)
+}
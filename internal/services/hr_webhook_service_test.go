@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/phone_management/configs"
+	"github.com/phone_management/internal/models"
+	"github.com/phone_management/internal/repositories"
+	"gorm.io/gorm"
+)
+
+const testHRSecret = "hr-secret"
+
+// newTestHRWebhookService 配置 HR Webhook 签名密钥并创建服务和一个名为 IT 的部门
+func newTestHRWebhookService(t *testing.T) (HRWebhookService, DepartmentService, *gorm.DB) {
+	t.Helper()
+	saved := configs.AppConfig
+	t.Cleanup(func() { configs.AppConfig = saved })
+	configs.AppConfig.HRWebhookSecret = testHRSecret
+	configs.AppConfig.HRWebhookMaxClockSkew = 5 * time.Minute
+
+	db := newTestDB(t)
+	employeeRepo := repositories.NewGormEmployeeRepository(db)
+	departmentService := NewDepartmentService(repositories.NewGormDepartmentRepository(db), employeeRepo)
+	if _, err := departmentService.CreateDepartment(context.Background(), models.CreateDepartmentPayload{Name: "IT"}); err != nil {
+		t.Fatalf("创建部门失败: %v", err)
+	}
+	svc := NewHRWebhookService(repositories.NewGormHREventRepository(db), newTestEmployeeService(db), employeeRepo)
+	return svc, departmentService, db
+}
+
+func signHREventHeader(timestamp string, body []byte) string {
+	return HRWebhookSignaturePrefix + hex.EncodeToString(SignHREvent(testHRSecret, timestamp, body))
+}
+
+func TestHRWebhookVerifySignature(t *testing.T) {
+	svc, _, _ := newTestHRWebhookService(t)
+	body := []byte(`{"id":"e1","type":"employee.hired","data":{"fullName":"张三"}}`)
+	now := time.Now()
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	signature := signHREventHeader(timestamp, body)
+
+	tests := []struct {
+		name      string
+		timestamp string
+		signature string
+		body      []byte
+		now       time.Time
+		wantErr   bool
+	}{
+		{"valid", timestamp, signature, body, now, false},
+		{"tampered body", timestamp, signature, []byte(`{"id":"e2","type":"employee.hired","data":{"fullName":"张三"}}`), now, true},
+		{"wrong secret", timestamp, HRWebhookSignaturePrefix + hex.EncodeToString(SignHREvent("other", timestamp, body)), body, now, true},
+		{"malformed signature", timestamp, "sha256=zz", body, now, true},
+		{"invalid timestamp", "yesterday", signature, body, now, true},
+		{"timestamp too old", timestamp, signature, body, now.Add(6 * time.Minute), true},
+		{"timestamp in the future", timestamp, signature, body, now.Add(-6 * time.Minute), true},
+		{"within clock skew", timestamp, signature, body, now.Add(4 * time.Minute), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := svc.VerifySignature(tt.timestamp, tt.signature, tt.body, tt.now)
+			if tt.wantErr != errors.Is(err, ErrInvalidHRSignature) || (!tt.wantErr && err != nil) {
+				t.Errorf("VerifySignature() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	configs.AppConfig.HRWebhookSecret = ""
+	if err := svc.VerifySignature(timestamp, signature, body, now); !errors.Is(err, ErrHRWebhookNotConfigured) {
+		t.Errorf("VerifySignature() without a secret = %v, want ErrHRWebhookNotConfigured", err)
+	}
+}
+
+func TestHRWebhookDuplicateEvent(t *testing.T) {
+	svc, _, db := newTestHRWebhookService(t)
+	ctx := context.Background()
+	body := []byte(`{"id":"e1","type":"employee.hired","data":{"fullName":"张三","email":"zhangsan@example.com","department":"IT","hireDate":"2026-01-02"}}`)
+
+	first, err := svc.HandleEvent(ctx, body)
+	if err != nil || first.Status != models.HREventProcessed || first.EmployeeID == "" {
+		t.Fatalf("HandleEvent = %+v, %v, want processed", first, err)
+	}
+	second, err := svc.HandleEvent(ctx, body)
+	if err != nil || second.Status != models.HREventDuplicate || second.EmployeeID != first.EmployeeID {
+		t.Fatalf("HandleEvent again = %+v, %v, want duplicate for %s", second, err, first.EmployeeID)
+	}
+
+	var count int64
+	if err := db.Model(&models.Employee{}).Count(&count).Error; err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Errorf("employees = %d, want 1", count)
+	}
+}
+
+func TestHRWebhookFailureThenReplay(t *testing.T) {
+	svc, departmentService, db := newTestHRWebhookService(t)
+	ctx := context.Background()
+	hired, err := svc.HandleEvent(ctx, []byte(`{"id":"e1","type":"employee.hired","data":{"fullName":"张三","email":"zhangsan@example.com","department":"IT"}}`))
+	if err != nil || hired.Status != models.HREventProcessed {
+		t.Fatalf("HandleEvent = %+v, %v", hired, err)
+	}
+
+	// 目标部门不存在，事件转入死信；再次推送时累计失败次数
+	move := []byte(`{"id":"e2","type":"employee.department_changed","data":{"email":"zhangsan@example.com","department":"Sales"}}`)
+	for attempt := 1; attempt <= 2; attempt++ {
+		failed, err := svc.HandleEvent(ctx, move)
+		if err != nil || failed.Status != models.HREventFailed || failed.DeadLetterID == nil {
+			t.Fatalf("attempt %d: HandleEvent = %+v, %v, want failed with a dead letter", attempt, failed, err)
+		}
+	}
+	deadLetters, err := svc.GetDeadLetters(ctx, false)
+	if err != nil || len(deadLetters) != 1 || deadLetters[0].Attempts != 2 {
+		t.Fatalf("GetDeadLetters = %+v, %v, want one dead letter with 2 attempts", deadLetters, err)
+	}
+
+	// 创建部门后重放成功，死信标记为已解决
+	if _, err := departmentService.CreateDepartment(ctx, models.CreateDepartmentPayload{Name: "Sales"}); err != nil {
+		t.Fatal(err)
+	}
+	replayed, err := svc.ReplayDeadLetter(ctx, deadLetters[0].ID)
+	if err != nil || replayed.Status != models.HREventProcessed || replayed.EmployeeID != hired.EmployeeID {
+		t.Fatalf("ReplayDeadLetter = %+v, %v, want processed", replayed, err)
+	}
+	var employee models.Employee
+	if err := db.Where("employee_id = ?", hired.EmployeeID).First(&employee).Error; err != nil {
+		t.Fatal(err)
+	}
+	if employee.Department == nil || *employee.Department != "Sales" {
+		t.Errorf("department = %v, want Sales", employee.Department)
+	}
+
+	if _, err := svc.ReplayDeadLetter(ctx, deadLetters[0].ID); !errors.Is(err, ErrHRDeadLetterResolved) {
+		t.Errorf("ReplayDeadLetter again = %v, want ErrHRDeadLetterResolved", err)
+	}
+	if open, err := svc.GetDeadLetters(ctx, false); err != nil || len(open) != 0 {
+		t.Errorf("open dead letters = %+v, %v, want none", open, err)
+	}
+	// 已成功处理的事件再次推送时视为重复
+	if again, err := svc.HandleEvent(ctx, move); err != nil || again.Status != models.HREventDuplicate {
+		t.Errorf("HandleEvent after replay = %+v, %v, want duplicate", again, err)
+	}
+}
@@ -3,8 +3,11 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
+	"github.com/phone_management/configs"
 	"github.com/phone_management/internal/models"
 	"github.com/phone_management/internal/repositories"
 	"github.com/phone_management/pkg/utils"
@@ -16,23 +19,42 @@ var ErrMobileNumberNotFound = errors.New("手机号码未找到")
 // 错误定义
 var ErrApplicantNameNotFound = errors.New("办卡人姓名未找到")
 var ErrApplicantNameNotUnique = errors.New("办卡人姓名存在重名，无法唯一确定员工，请在系统中确保该姓名唯一或联系管理员处理")
+var ErrDeactivationDateNotApplicable = errors.New("只有待注销号码可以设置计划注销日期")
+var ErrDeactivationDateInPast = errors.New("计划注销日期不能早于今天")
+
+// 批量操作相关错误
+var ErrInvalidBulkRequest = errors.New("批量操作参数无效")
+var ErrBulkNoMatchingNumbers = errors.New("没有匹配的手机号码")
+
+// maxBulkNumbers 单次批量操作允许的最大号码数
+const maxBulkNumbers = 500
+
+// errBulkRollback 用于在预览或整批原子执行失败时回滚批量操作的事务
+var errBulkRollback = errors.New("批量操作已回滚")
 
 // MobileNumberService 定义了手机号码服务的接口
 type MobileNumberService interface {
 	// CreateMobileNumber 的 mobileNumber 参数中已包含 ApplicantEmployeeID (string)
-	CreateMobileNumber(mobileNumber *models.MobileNumber) (*models.MobileNumber, error)
-	GetMobileNumbers(page, limit int, sortBy, sortOrder, search, status, applicantStatus string) ([]models.MobileNumberResponse, int64, error)
-	GetMobileNumberByPhoneNumberDetail(phoneNumber string) (*models.MobileNumberResponse, error)
-	UpdateMobileNumberByPhoneNumber(phoneNumber string, payload models.MobileNumberUpdatePayload) (*models.MobileNumber, error)
+	CreateMobileNumber(ctx context.Context, mobileNumber *models.MobileNumber) (*models.MobileNumber, error)
+	GetMobileNumbers(ctx context.Context, page, limit int, sortBy, sortOrder, search, status, applicantStatus string) ([]models.MobileNumberResponse, int64, error)
+	GetMobileNumberByPhoneNumberDetail(ctx context.Context, phoneNumber string) (*models.MobileNumberResponse, error)
+	UpdateMobileNumberByPhoneNumber(ctx context.Context, phoneNumber string, payload models.MobileNumberUpdatePayload) (*models.MobileNumber, error)
 	// AssignMobileNumber 的 employeeBusinessID 参数是 string (业务工号)
 	// 第一个参数从 numberID uint 修改为 phoneNumber string
-	AssignMobileNumber(phoneNumber string, employeeBusinessID string, assignmentDate time.Time, purpose string) (*models.MobileNumber, error)
+	AssignMobileNumber(ctx context.Context, phoneNumber string, employeeBusinessID string, assignmentDate time.Time, purpose string) (*models.MobileNumber, error)
 	// UnassignMobileNumber(numberID uint, reclaimDate time.Time) (*models.MobileNumber, error) // 旧方法
-	UnassignMobileNumberByPhoneNumber(phoneNumber string, reclaimDate time.Time) (*models.MobileNumber, error) //
-	ResolveApplicantNameToID(applicantName string) (string, error)                                             //
+	UnassignMobileNumberByPhoneNumber(ctx context.Context, phoneNumber string, reclaimDate time.Time) (*models.MobileNumber, error) //
+	ResolveApplicantNameToID(applicantName string) (string, error)                                                                  //
+	// TransferMobileNumber 将使用中的号码直接转移给另一名在职员工，使用历史无间断
+	TransferMobileNumber(ctx context.Context, phoneNumber string, employeeBusinessID string, effectiveDate time.Time, reason string) (*models.MobileNumber, error)
 	// 风险号码处理相关方法
-	GetRiskPendingNumbers(page, limit int, sortBy, sortOrder, search, applicantStatus string) ([]models.RiskNumberResponse, int64, error)
-	HandleRiskNumber(phoneNumber string, payload models.HandleRiskNumberPayload, operatorUsername string) (*models.MobileNumber, error)
+	GetRiskPendingNumbers(ctx context.Context, page, limit int, sortBy, sortOrder, search, applicantStatus string) ([]models.RiskNumberResponse, int64, error)
+	HandleRiskNumber(ctx context.Context, phoneNumber string, payload models.HandleRiskNumberPayload, operatorUsername string) (*models.MobileNumber, error)
+	// 待注销号码相关方法
+	CancelDeactivation(ctx context.Context, phoneNumber string) (*models.MobileNumber, error)
+	GetUpcomingDeactivations(ctx context.Context, days int) ([]models.MobileNumberResponse, time.Time, error)
+	// BulkOperate 对一组号码执行同一批量操作，每个号码单独成败，可选整批原子执行或仅预览
+	BulkOperate(ctx context.Context, payload models.MobileNumberBulkPayload, operatorUsername string) (*models.MobileNumberBulkResult, error)
 }
 
 // mobileNumberService 是 MobileNumberService 的实现
@@ -48,7 +70,7 @@ func NewMobileNumberService(repo repositories.MobileNumberRepository, empService
 
 // CreateMobileNumber 处理创建手机号码的业务逻辑
 // mobileNumber.ApplicantEmployeeID (string) 已经由 handler 层从 payload 设置
-func (s *mobileNumberService) CreateMobileNumber(mobileNumber *models.MobileNumber) (*models.MobileNumber, error) {
+func (s *mobileNumberService) CreateMobileNumber(ctx context.Context, mobileNumber *models.MobileNumber) (*models.MobileNumber, error) {
 	// 0. 校验手机号码格式 (使用 utils 中的校验函数)
 	if err := utils.ValidatePhoneNumber(mobileNumber.PhoneNumber); err != nil {
 		return nil, err // 直接返回 utils 包中定义的错误
@@ -65,7 +87,7 @@ func (s *mobileNumberService) CreateMobileNumber(mobileNumber *models.MobileNumb
 	// if queriedApplicant.EmploymentStatus != "Active" { ... }
 
 	// ApplicantEmployeeID (string) 已在 mobileNumber 对象中，直接传递给仓库层创建
-	createdMobileNumber, err := s.repo.CreateMobileNumber(mobileNumber)
+	createdMobileNumber, err := s.repo.CreateMobileNumber(ctx, mobileNumber)
 	if err != nil {
 		return nil, err
 	}
@@ -73,15 +95,15 @@ func (s *mobileNumberService) CreateMobileNumber(mobileNumber *models.MobileNumb
 }
 
 // GetMobileNumbers 处理获取手机号码列表的业务逻辑
-func (s *mobileNumberService) GetMobileNumbers(page, limit int, sortBy, sortOrder, search, status, applicantStatus string) ([]models.MobileNumberResponse, int64, error) {
+func (s *mobileNumberService) GetMobileNumbers(ctx context.Context, page, limit int, sortBy, sortOrder, search, status, applicantStatus string) ([]models.MobileNumberResponse, int64, error) {
 	// 当前业务逻辑主要是参数传递和调用仓库层
 	// 未来可在这里添加更复杂的业务规则
-	return s.repo.GetMobileNumbers(page, limit, sortBy, sortOrder, search, status, applicantStatus)
+	return s.repo.GetMobileNumbers(ctx, page, limit, sortBy, sortOrder, search, status, applicantStatus)
 }
 
 // GetMobileNumberByPhoneNumberDetail 处理根据手机号码字符串获取手机号码详情的业务逻辑
-func (s *mobileNumberService) GetMobileNumberByPhoneNumberDetail(phoneNumber string) (*models.MobileNumberResponse, error) {
-	mobileNumberDetail, err := s.repo.GetMobileNumberResponseByPhoneNumber(phoneNumber) // 假设 repo 有此方法
+func (s *mobileNumberService) GetMobileNumberByPhoneNumberDetail(ctx context.Context, phoneNumber string) (*models.MobileNumberResponse, error) {
+	mobileNumberDetail, err := s.repo.GetMobileNumberResponseByPhoneNumber(ctx, phoneNumber) // 假设 repo 有此方法
 	if err != nil {
 		if errors.Is(err, repositories.ErrRecordNotFound) {
 			return nil, ErrMobileNumberNotFound
@@ -92,9 +114,9 @@ func (s *mobileNumberService) GetMobileNumberByPhoneNumberDetail(phoneNumber str
 }
 
 // UpdateMobileNumberByPhoneNumber 处理根据手机号码字符串更新手机号码的业务逻辑
-func (s *mobileNumberService) UpdateMobileNumberByPhoneNumber(phoneNumber string, payload models.MobileNumberUpdatePayload) (*models.MobileNumber, error) {
+func (s *mobileNumberService) UpdateMobileNumberByPhoneNumber(ctx context.Context, phoneNumber string, payload models.MobileNumberUpdatePayload) (*models.MobileNumber, error) {
 	// 0. 通过 phoneNumber 获取 MobileNumber 实体及其 ID
-	mobileNumber, err := s.repo.GetMobileNumberByPhoneNumber(phoneNumber)
+	mobileNumber, err := s.repo.GetMobileNumberByPhoneNumber(ctx, phoneNumber)
 	if err != nil {
 		if errors.Is(err, repositories.ErrRecordNotFound) {
 			return nil, ErrMobileNumberNotFound
@@ -114,21 +136,29 @@ func (s *mobileNumberService) UpdateMobileNumberByPhoneNumber(phoneNumber string
 			return nil, errors.New("无效的状态值")
 		}
 
-		// 业务规则：不能直接更新为"使用中"状态
-		if *payload.Status == string(models.StatusInUse) {
-			return nil, errors.New("不能直接将状态更新为'使用中'，请使用分配操作")
-		}
+		// 状态流转的合法性及其副作用（如注销时记录注销日期）由仓库层按生命周期流转表统一处理
+		updates["status"] = *payload.Status
+	}
 
-		// 业务规则：如果当前状态是"使用中"，不能直接更新状态
-		if mobileNumber.Status == string(models.StatusInUse) {
-			return nil, errors.New("号码当前为'使用中'状态，不能直接更新状态，请先进行回收操作")
+	// 计划注销日期只适用于（变更后）处于待注销状态的号码；变更为待注销且未指定日期时按宽限期计算
+	targetStatus := mobileNumber.Status
+	if payload.Status != nil {
+		targetStatus = *payload.Status
+	}
+	if payload.ScheduledDeactivationDate != nil {
+		if targetStatus != string(models.StatusPendingDeactivation) {
+			return nil, ErrDeactivationDateNotApplicable
 		}
-
-		updates["status"] = *payload.Status
-		if *payload.Status == string(models.StatusDeactivated) {
-			now := time.Now()
-			updates["cancellation_date"] = &now
+		scheduledDate, err := utils.ParseDate(*payload.ScheduledDeactivationDate)
+		if err != nil {
+			return nil, err
+		}
+		if scheduledDate.Before(today()) {
+			return nil, ErrDeactivationDateInPast
 		}
+		updates["scheduled_deactivation_date"] = scheduledDate
+	} else if targetStatus == string(models.StatusPendingDeactivation) && mobileNumber.Status != targetStatus {
+		updates["scheduled_deactivation_date"] = today().AddDate(0, 0, configs.AppConfig.NumberDeactivationGraceDays)
 	}
 	if payload.Purpose != nil {
 		updates["purpose"] = *payload.Purpose
@@ -145,7 +175,7 @@ func (s *mobileNumberService) UpdateMobileNumberByPhoneNumber(phoneNumber string
 	}
 
 	// 使用获取到的 mobileNumber.ID 进行更新
-	updatedMobileNumber, err := s.repo.UpdateMobileNumber(mobileNumber.ID, updates)
+	updatedMobileNumber, err := s.repo.UpdateMobileNumber(ctx, mobileNumber.ID, updates)
 	if err != nil {
 		// repo.UpdateMobileNumber 内部会处理 ErrRecordNotFound，这里不需要再次转换
 		// 如果发生，通常意味着在 GetMobileNumberByPhoneNumber 和 UpdateMobileNumber 之间记录被删除，是一个竞争条件
@@ -157,9 +187,9 @@ func (s *mobileNumberService) UpdateMobileNumberByPhoneNumber(phoneNumber string
 // AssignMobileNumber 处理将手机号码分配给员工的业务逻辑
 // employeeBusinessID 是员工的业务工号 (string)
 // 第一个参数从 numberID uint 修改为 phoneNumber string
-func (s *mobileNumberService) AssignMobileNumber(phoneNumber string, employeeBusinessID string, assignmentDate time.Time, purpose string) (*models.MobileNumber, error) {
+func (s *mobileNumberService) AssignMobileNumber(ctx context.Context, phoneNumber string, employeeBusinessID string, assignmentDate time.Time, purpose string) (*models.MobileNumber, error) {
 	// 0. 通过 phoneNumber 获取 MobileNumber 实体及其 ID
-	mobileNumber, err := s.repo.GetMobileNumberByPhoneNumber(phoneNumber)
+	mobileNumber, err := s.repo.GetMobileNumberByPhoneNumber(ctx, phoneNumber)
 	if err != nil {
 		if errors.Is(err, repositories.ErrRecordNotFound) {
 			return nil, ErrMobileNumberNotFound
@@ -177,7 +207,7 @@ func (s *mobileNumberService) AssignMobileNumber(phoneNumber string, employeeBus
 	}
 
 	// 使用从 phoneNumber 查询到的 mobileNumber.ID 进行分配，并传递用途
-	assignedMobileNumber, err := s.repo.AssignMobileNumber(mobileNumber.ID, employeeBusinessID, assignmentDate, purpose)
+	assignedMobileNumber, err := s.repo.AssignMobileNumber(ctx, mobileNumber.ID, employeeBusinessID, assignmentDate, purpose)
 	if err != nil {
 		if errors.Is(err, repositories.ErrRecordNotFound) {
 			// 此处的 ErrRecordNotFound 是针对 MobileNumber 的，由 repo.AssignMobileNumber 返回
@@ -192,9 +222,9 @@ func (s *mobileNumberService) AssignMobileNumber(phoneNumber string, employeeBus
 }
 
 // UnassignMobileNumberByPhoneNumber 处理根据手机号码字符串从当前用户回收手机号码的业务逻辑
-func (s *mobileNumberService) UnassignMobileNumberByPhoneNumber(phoneNumber string, reclaimDate time.Time) (*models.MobileNumber, error) {
+func (s *mobileNumberService) UnassignMobileNumberByPhoneNumber(ctx context.Context, phoneNumber string, reclaimDate time.Time) (*models.MobileNumber, error) {
 	// 0. 通过 phoneNumber 获取 MobileNumber 实体及其 ID
-	mobileNumber, err := s.repo.GetMobileNumberByPhoneNumber(phoneNumber)
+	mobileNumber, err := s.repo.GetMobileNumberByPhoneNumber(ctx, phoneNumber)
 	if err != nil {
 		if errors.Is(err, repositories.ErrRecordNotFound) {
 			return nil, ErrMobileNumberNotFound
@@ -203,7 +233,7 @@ func (s *mobileNumberService) UnassignMobileNumberByPhoneNumber(phoneNumber stri
 	}
 
 	// 使用获取到的 mobileNumber.ID 进行回收
-	unassignedMobileNumber, err := s.repo.UnassignMobileNumber(mobileNumber.ID, reclaimDate)
+	unassignedMobileNumber, err := s.repo.UnassignMobileNumber(ctx, mobileNumber.ID, reclaimDate)
 	if err != nil {
 		// repo.UnassignMobileNumber 内部会处理 ErrRecordNotFound，这里不需要再次转换
 		// 其他特定错误如 ErrMobileNumberNotRecoverable, ErrNoActiveUsageHistoryFound 会直接从 repo 传递上来
@@ -212,6 +242,31 @@ func (s *mobileNumberService) UnassignMobileNumberByPhoneNumber(phoneNumber stri
 	return unassignedMobileNumber, nil
 }
 
+// TransferMobileNumber 处理将号码从当前使用人转移给另一名员工的业务逻辑
+func (s *mobileNumberService) TransferMobileNumber(ctx context.Context, phoneNumber string, employeeBusinessID string, effectiveDate time.Time, reason string) (*models.MobileNumber, error) {
+	mobileNumber, err := s.repo.GetMobileNumberByPhoneNumber(ctx, phoneNumber)
+	if err != nil {
+		if errors.Is(err, repositories.ErrRecordNotFound) {
+			return nil, ErrMobileNumberNotFound
+		}
+		return nil, err
+	}
+
+	// 员工存在性及在职状态由仓库层在事务中再次校验
+	if _, err := s.employeeService.GetEmployeeByEmployeeID(employeeBusinessID); err != nil {
+		return nil, err
+	}
+
+	transferredMobileNumber, err := s.repo.TransferMobileNumber(ctx, mobileNumber.ID, employeeBusinessID, effectiveDate, reason)
+	if err != nil {
+		if errors.Is(err, repositories.ErrRecordNotFound) {
+			return nil, ErrMobileNumberNotFound
+		}
+		return nil, err
+	}
+	return transferredMobileNumber, nil
+}
+
 // ResolveApplicantNameToID 根据办卡人姓名解析为唯一的员工业务工号
 func (s *mobileNumberService) ResolveApplicantNameToID(applicantName string) (string, error) {
 	employees, err := s.employeeService.GetEmployeesByFullName(applicantName)
@@ -236,19 +291,18 @@ func (s *mobileNumberService) ResolveApplicantNameToID(applicantName string) (st
 }
 
 // GetRiskPendingNumbers 处理获取风险号码列表的业务逻辑
-func (s *mobileNumberService) GetRiskPendingNumbers(page, limit int, sortBy, sortOrder, search, applicantStatus string) ([]models.RiskNumberResponse, int64, error) {
+func (s *mobileNumberService) GetRiskPendingNumbers(ctx context.Context, page, limit int, sortBy, sortOrder, search, applicantStatus string) ([]models.RiskNumberResponse, int64, error) {
 	// 当前业务逻辑主要是参数传递和调用仓库层
 	// 未来可在这里添加更复杂的业务规则
-	return s.repo.GetRiskPendingNumbers(page, limit, sortBy, sortOrder, search, applicantStatus)
+	return s.repo.GetRiskPendingNumbers(ctx, page, limit, sortBy, sortOrder, search, applicantStatus)
 }
 
 // HandleRiskNumber 处理处理风险号码的业务逻辑
-func (s *mobileNumberService) HandleRiskNumber(phoneNumber string, payload models.HandleRiskNumberPayload, operatorUsername string) (*models.MobileNumber, error) {
+func (s *mobileNumberService) HandleRiskNumber(ctx context.Context, phoneNumber string, payload models.HandleRiskNumberPayload, operatorUsername string) (*models.MobileNumber, error) {
 	// 注意：operatorUsername 是系统用户名，不是公司员工ID
 	// 系统用户验证应该在认证中间件中完成，这里直接使用传入的用户名
 
 	// 调用仓库层处理风险号码
-	ctx := context.Background()
 	handledMobileNumber, err := s.repo.HandleRiskNumber(ctx, phoneNumber, payload, operatorUsername)
 	if err != nil {
 		if errors.Is(err, repositories.ErrRecordNotFound) {
@@ -259,3 +313,202 @@ func (s *mobileNumberService) HandleRiskNumber(phoneNumber string, payload model
 	}
 	return handledMobileNumber, nil
 }
+
+// CancelDeactivation 在宽限期内取消待注销号码的注销计划，号码恢复为闲置状态
+func (s *mobileNumberService) CancelDeactivation(ctx context.Context, phoneNumber string) (*models.MobileNumber, error) {
+	mobileNumber, err := s.repo.GetMobileNumberByPhoneNumber(ctx, phoneNumber)
+	if err != nil {
+		if errors.Is(err, repositories.ErrRecordNotFound) {
+			return nil, ErrMobileNumberNotFound
+		}
+		return nil, err
+	}
+
+	cancelledMobileNumber, err := s.repo.CancelDeactivation(ctx, mobileNumber.ID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrRecordNotFound) {
+			return nil, ErrMobileNumberNotFound
+		}
+		return nil, err
+	}
+	return cancelledMobileNumber, nil
+}
+
+// GetUpcomingDeactivations 获取未来 days 天内（含今天及已到期未处理的）将被注销的号码，同时返回统计截止日期
+func (s *mobileNumberService) GetUpcomingDeactivations(ctx context.Context, days int) ([]models.MobileNumberResponse, time.Time, error) {
+	until := today().AddDate(0, 0, days)
+	numbers, err := s.repo.GetPendingDeactivations(ctx, until)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return numbers, until, nil
+}
+
+// BulkOperate 对一组号码执行同一批量操作。
+// 每个号码的操作在各自的事务（整批执行时为保存点）中完成，单个号码失败不影响其他号码；
+// Atomic 时任一号码失败则整批回滚，DryRun 时执行全部操作后回滚，用于预览结果
+func (s *mobileNumberService) BulkOperate(ctx context.Context, payload models.MobileNumberBulkPayload, operatorUsername string) (*models.MobileNumberBulkResult, error) {
+	if err := validateBulkPayload(payload); err != nil {
+		return nil, err
+	}
+	reclaimDate := today()
+	if payload.ReclaimDate != "" {
+		parsedDate, err := utils.ParseDate(payload.ReclaimDate)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidBulkRequest, err)
+		}
+		reclaimDate = parsedDate
+	}
+
+	phoneNumbers, err := s.resolveBulkTargets(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.MobileNumberBulkResult{TotalCount: len(phoneNumbers)}
+	run := func(repo repositories.MobileNumberRepository) error {
+		svc := &mobileNumberService{repo: repo, employeeService: s.employeeService}
+		for i, phoneNumber := range phoneNumbers {
+			if err := svc.applyBulkAction(ctx, phoneNumber, payload, reclaimDate, operatorUsername); err != nil {
+				result.Failures = append(result.Failures, models.MobileNumberBulkFailure{Index: i + 1, PhoneNumber: phoneNumber, Reason: err.Error()})
+				continue
+			}
+			result.SuccessCount++
+		}
+		if payload.DryRun || (payload.Atomic && len(result.Failures) > 0) {
+			return errBulkRollback
+		}
+		return nil
+	}
+
+	// 非原子且非预览时各号码独立提交，无需外层事务
+	if !payload.DryRun && !payload.Atomic {
+		if err := run(s.repo); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+
+	err = s.repo.WithinTransaction(ctx, run)
+	if errors.Is(err, errBulkRollback) {
+		result.RolledBack = true
+		return result, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// validateBulkPayload 校验批量操作类型及其所需参数
+func validateBulkPayload(payload models.MobileNumberBulkPayload) error {
+	if len(payload.PhoneNumbers) > 0 && payload.Filter != nil {
+		return fmt.Errorf("%w: phoneNumbers 与 filter 只能提供其中之一", ErrInvalidBulkRequest)
+	}
+	if len(payload.PhoneNumbers) == 0 && payload.Filter == nil {
+		return fmt.Errorf("%w: 必须提供 phoneNumbers 或 filter", ErrInvalidBulkRequest)
+	}
+	if !models.IsValidBulkAction(payload.Action) {
+		return fmt.Errorf("%w: 无效的操作类型 %s", ErrInvalidBulkRequest, payload.Action)
+	}
+
+	switch models.MobileNumberBulkAction(payload.Action) {
+	case models.BulkActionStatusChange:
+		if payload.Status == nil || !models.IsValidStatus(*payload.Status) {
+			return fmt.Errorf("%w: 变更状态时必须提供有效的目标状态", ErrInvalidBulkRequest)
+		}
+	case models.BulkActionChangeApplicant:
+		if payload.NewApplicantEmployeeID == nil || *payload.NewApplicantEmployeeID == "" {
+			return fmt.Errorf("%w: 变更办卡人时必须提供新办卡人员工ID", ErrInvalidBulkRequest)
+		}
+	case models.BulkActionSetVendor:
+		if payload.Vendor == nil {
+			return fmt.Errorf("%w: 设置运营商时必须提供 vendor", ErrInvalidBulkRequest)
+		}
+	}
+	return nil
+}
+
+// resolveBulkTargets 解析批量操作的号码列表：去除重复的手机号，或在 context 的部门范围内按筛选条件查询
+func (s *mobileNumberService) resolveBulkTargets(ctx context.Context, payload models.MobileNumberBulkPayload) ([]string, error) {
+	var phoneNumbers []string
+	if payload.Filter != nil {
+		found, err := s.repo.FindPhoneNumbersByFilter(ctx, payload.Filter.Search, payload.Filter.Status, payload.Filter.ApplicantStatus)
+		if err != nil {
+			return nil, err
+		}
+		phoneNumbers = found
+	} else {
+		seen := make(map[string]bool, len(payload.PhoneNumbers))
+		for _, phoneNumber := range payload.PhoneNumbers {
+			phoneNumber = strings.TrimSpace(phoneNumber)
+			if phoneNumber == "" || seen[phoneNumber] {
+				continue
+			}
+			seen[phoneNumber] = true
+			phoneNumbers = append(phoneNumbers, phoneNumber)
+		}
+	}
+
+	if len(phoneNumbers) == 0 {
+		return nil, ErrBulkNoMatchingNumbers
+	}
+	if len(phoneNumbers) > maxBulkNumbers {
+		return nil, fmt.Errorf("%w: 单次最多操作 %d 个号码，本次匹配 %d 个", ErrInvalidBulkRequest, maxBulkNumbers, len(phoneNumbers))
+	}
+	return phoneNumbers, nil
+}
+
+// applyBulkAction 对单个号码执行批量操作，复用单号码接口的业务规则。
+// 风险号码的变更办卡人和注销按风险号码处理流程执行，以便同时恢复或终止号码状态
+func (s *mobileNumberService) applyBulkAction(ctx context.Context, phoneNumber string, payload models.MobileNumberBulkPayload, reclaimDate time.Time, operatorUsername string) error {
+	var err error
+	switch models.MobileNumberBulkAction(payload.Action) {
+	case models.BulkActionStatusChange:
+		_, err = s.UpdateMobileNumberByPhoneNumber(ctx, phoneNumber, models.MobileNumberUpdatePayload{
+			Status:                    payload.Status,
+			ScheduledDeactivationDate: payload.ScheduledDeactivationDate,
+		})
+	case models.BulkActionSetVendor:
+		_, err = s.UpdateMobileNumberByPhoneNumber(ctx, phoneNumber, models.MobileNumberUpdatePayload{Vendor: payload.Vendor})
+	case models.BulkActionUnassign:
+		_, err = s.UnassignMobileNumberByPhoneNumber(ctx, phoneNumber, reclaimDate)
+	case models.BulkActionChangeApplicant, models.BulkActionDeactivate:
+		err = s.changeApplicantOrDeactivate(ctx, phoneNumber, payload, operatorUsername)
+	}
+	return err
+}
+
+// changeApplicantOrDeactivate 执行批量变更办卡人或注销
+func (s *mobileNumberService) changeApplicantOrDeactivate(ctx context.Context, phoneNumber string, payload models.MobileNumberBulkPayload, operatorUsername string) error {
+	mobileNumber, err := s.repo.GetMobileNumberByPhoneNumber(ctx, phoneNumber)
+	if err != nil {
+		if errors.Is(err, repositories.ErrRecordNotFound) {
+			return ErrMobileNumberNotFound
+		}
+		return err
+	}
+
+	if mobileNumber.Status == string(models.StatusRiskPending) {
+		riskPayload := models.HandleRiskNumberPayload{Action: string(models.ActionDeactivate), Remarks: payload.Remarks}
+		if payload.Action == string(models.BulkActionChangeApplicant) {
+			riskPayload.Action = string(models.ActionChangeApplicant)
+			riskPayload.NewApplicantEmployeeID = payload.NewApplicantEmployeeID
+		}
+		_, err = s.HandleRiskNumber(ctx, phoneNumber, riskPayload, operatorUsername)
+		return err
+	}
+
+	if payload.Action == string(models.BulkActionChangeApplicant) {
+		_, err = s.repo.ChangeApplicant(ctx, mobileNumber.ID, *payload.NewApplicantEmployeeID, payload.Remarks, operatorUsername)
+		return err
+	}
+	deactivated := string(models.StatusDeactivated)
+	_, err = s.UpdateMobileNumberByPhoneNumber(ctx, phoneNumber, models.MobileNumberUpdatePayload{Status: &deactivated})
+	return err
+}
+
+// today 返回当天零点，与 utils.ParseDate 解析出的日期保持一致（UTC）
+func today() time.Time {
+	return time.Now().UTC().Truncate(24 * time.Hour)
+}
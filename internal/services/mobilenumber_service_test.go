@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/phone_management/internal/models"
+	"github.com/phone_management/internal/repositories"
+	"gorm.io/gorm"
+)
+
+// runBulkSetVendor 对两个已有号码和一个不存在的号码批量设置运营商，返回执行结果和执行后两个号码的运营商
+func runBulkSetVendor(t *testing.T, dryRun, atomic bool) (*models.MobileNumberBulkResult, []string) {
+	t.Helper()
+	db := newTestDB(t)
+	employee := createTestEmployee(t, db, "张三")
+	createTestNumber(t, db, "13800000001", employee, nil)
+	createTestNumber(t, db, "13800000002", employee, employee)
+
+	svc := NewMobileNumberService(repositories.NewGormMobileNumberRepository(db), newTestEmployeeService(db))
+	vendor := "中国移动"
+	result, err := svc.BulkOperate(context.Background(), models.MobileNumberBulkPayload{
+		PhoneNumbers: []string{"13800000001", "13899999999", "13800000002"},
+		Action:       string(models.BulkActionSetVendor),
+		Vendor:       &vendor,
+		DryRun:       dryRun,
+		Atomic:       atomic,
+	}, "admin")
+	if err != nil {
+		t.Fatalf("BulkOperate 返回错误: %v", err)
+	}
+	return result, numberVendors(t, db, "13800000001", "13800000002")
+}
+
+func numberVendors(t *testing.T, db *gorm.DB, phoneNumbers ...string) []string {
+	t.Helper()
+	vendors := make([]string, 0, len(phoneNumbers))
+	for _, phoneNumber := range phoneNumbers {
+		var number models.MobileNumber
+		if err := db.Where("phone_number = ?", phoneNumber).First(&number).Error; err != nil {
+			t.Fatalf("查询号码 %s 失败: %v", phoneNumber, err)
+		}
+		vendors = append(vendors, number.Vendor)
+	}
+	return vendors
+}
+
+func checkBulkFailure(t *testing.T, result *models.MobileNumberBulkResult) {
+	t.Helper()
+	if result.TotalCount != 3 || result.SuccessCount != 2 || len(result.Failures) != 1 {
+		t.Fatalf("result = %+v, want 3 total, 2 succeeded and 1 failure", result)
+	}
+	if failure := result.Failures[0]; failure.Index != 2 || failure.PhoneNumber != "13899999999" {
+		t.Errorf("failure = %+v, want index 2 for 13899999999", failure)
+	}
+}
+
+func TestBulkOperateDryRunLeavesNumbersUnchanged(t *testing.T) {
+	result, vendors := runBulkSetVendor(t, true, false)
+	checkBulkFailure(t, result)
+	if !result.RolledBack {
+		t.Error("dry run was not rolled back")
+	}
+	for i, vendor := range vendors {
+		if vendor != "" {
+			t.Errorf("number %d vendor = %q after dry run, want unchanged", i+1, vendor)
+		}
+	}
+}
+
+func TestBulkOperateAtomicRollsBackOnFailure(t *testing.T) {
+	result, vendors := runBulkSetVendor(t, false, true)
+	checkBulkFailure(t, result)
+	if !result.RolledBack {
+		t.Error("atomic run with a failure was not rolled back")
+	}
+	for i, vendor := range vendors {
+		if vendor != "" {
+			t.Errorf("number %d vendor = %q after rollback, want unchanged", i+1, vendor)
+		}
+	}
+}
+
+func TestBulkOperateNonAtomicCommitsSuccesses(t *testing.T) {
+	result, vendors := runBulkSetVendor(t, false, false)
+	checkBulkFailure(t, result)
+	if result.RolledBack {
+		t.Error("non-atomic run was rolled back")
+	}
+	for i, vendor := range vendors {
+		if vendor != "中国移动" {
+			t.Errorf("number %d vendor = %q, want 中国移动", i+1, vendor)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"log"
+
+	"github.com/phone_management/configs"
+	"github.com/phone_management/internal/models"
+	"github.com/phone_management/pkg/email"
+)
+
+// NewNotifier 按配置创建通知发送器：SMTP 配置有效时启用邮件渠道，配置了 IMWebhookURL 或 SMSGatewayURL 时启用对应渠道。
+// 员工偏好的渠道未启用或员工缺少该渠道所需的联系方式时改用邮件。
+// IM 群机器人的消息全群可见，只发送不含链接的通知标题作为提醒，完整内容（含确认链接）仍通过邮件发送
+func NewNotifier(cfg configs.Configuration) email.Notifier {
+	senders := make(map[email.Channel]email.Sender)
+
+	// 与离职提醒邮件共用同一个 SMTP 会话
+	if sender, err := email.DefaultSMTPSender(); err != nil {
+		log.Printf("警告: 邮件通知渠道未启用: %v", err)
+	} else {
+		senders[email.ChannelEmail] = sender
+	}
+
+	if cfg.IMWebhookURL != "" {
+		sender, err := email.NewIMWebhookSender(cfg.IMWebhookURL, email.IMFlavor(cfg.IMWebhookFlavor), cfg.IMWebhookSecret, cfg.NotificationTimeout)
+		if err != nil {
+			log.Printf("警告: IM 通知渠道未启用: %v", err)
+		} else {
+			senders[email.ChannelIM] = sender
+		}
+	}
+
+	if cfg.SMSGatewayURL != "" {
+		sender, err := email.NewSMSGatewaySender(cfg.SMSGatewayURL, cfg.SMSGatewayToken, cfg.NotificationTimeout)
+		if err != nil {
+			log.Printf("警告: 短信通知渠道未启用: %v", err)
+		} else {
+			senders[email.ChannelSMS] = sender
+		}
+	}
+
+	return email.NewNotifier(senders, email.ChannelEmail)
+}
+
+// notificationRecipient 返回员工作为通知收件人的联系方式和偏好的渠道
+func notificationRecipient(emp *models.Employee) (email.Recipient, email.Channel) {
+	recipient := email.Recipient{Name: emp.FullName}
+	if emp.Email != nil {
+		recipient.Email = *emp.Email
+	}
+	if emp.PhoneNumber != nil {
+		recipient.Phone = *emp.PhoneNumber
+	}
+	return recipient, email.Channel(emp.NotificationChannel)
+}
@@ -0,0 +1,245 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+
+	"github.com/phone_management/internal/models"
+	"github.com/phone_management/internal/repositories"
+)
+
+// NumberHistoryService 定义了号码历史查询服务的接口
+type NumberHistoryService interface {
+	// GetNumberTimeline 汇总号码的使用、办卡人变更、状态变更及员工确认记录，按时间升序
+	GetNumberTimeline(ctx context.Context, phoneNumber string) (*models.NumberTimelineResponse, error)
+	// GetEmployeeNumberHistory 汇总员工使用过的号码、作为办卡人的变更及其确认记录，按时间升序
+	GetEmployeeNumberHistory(ctx context.Context, employeeID string) (*models.EmployeeNumberHistoryResponse, error)
+}
+
+// numberHistoryService 是 NumberHistoryService 的实现
+type numberHistoryService struct {
+	mobileNumberRepo     repositories.MobileNumberRepository
+	employeeRepo         repositories.EmployeeRepository
+	usageHistoryRepo     repositories.NumberUsageHistoryRepository
+	applicantHistoryRepo repositories.NumberApplicantHistoryRepository
+	submissionLogRepo    repositories.VerificationSubmissionLogRepository
+	auditEventRepo       repositories.AuditEventRepository
+}
+
+// NewNumberHistoryService 创建一个新的 numberHistoryService 实例
+func NewNumberHistoryService(
+	mobileNumberRepo repositories.MobileNumberRepository,
+	employeeRepo repositories.EmployeeRepository,
+	usageHistoryRepo repositories.NumberUsageHistoryRepository,
+	applicantHistoryRepo repositories.NumberApplicantHistoryRepository,
+	submissionLogRepo repositories.VerificationSubmissionLogRepository,
+	auditEventRepo repositories.AuditEventRepository,
+) NumberHistoryService {
+	return &numberHistoryService{
+		mobileNumberRepo:     mobileNumberRepo,
+		employeeRepo:         employeeRepo,
+		usageHistoryRepo:     usageHistoryRepo,
+		applicantHistoryRepo: applicantHistoryRepo,
+		submissionLogRepo:    submissionLogRepo,
+		auditEventRepo:       auditEventRepo,
+	}
+}
+
+// GetNumberTimeline 汇总号码的时间线。状态变更取自号码的审计事件中前后快照的状态差异
+func (s *numberHistoryService) GetNumberTimeline(ctx context.Context, phoneNumber string) (*models.NumberTimelineResponse, error) {
+	mobileNumber, err := s.mobileNumberRepo.GetMobileNumberByPhoneNumber(ctx, phoneNumber)
+	if err != nil {
+		if errors.Is(err, repositories.ErrRecordNotFound) {
+			return nil, ErrMobileNumberNotFound
+		}
+		return nil, err
+	}
+	phoneNumbers := map[uint]string{mobileNumber.ID: mobileNumber.PhoneNumber}
+
+	usages, err := s.usageHistoryRepo.GetByMobileNumberID(ctx, mobileNumber.ID)
+	if err != nil {
+		return nil, err
+	}
+	applicantChanges, err := s.applicantHistoryRepo.GetByMobileNumberID(ctx, mobileNumber.ID)
+	if err != nil {
+		return nil, err
+	}
+	submissions, err := s.submissionLogRepo.FindByPhoneNumber(ctx, mobileNumber.PhoneNumber)
+	if err != nil {
+		return nil, err
+	}
+	auditEvents, err := s.auditEventRepo.ListByEntity(models.AuditEntityMobileNumber, mobileNumber.PhoneNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	events := usageEvents(usages, phoneNumbers)
+	events = append(events, applicantChangeEvents(applicantChanges, phoneNumbers)...)
+	events = append(events, verificationEvents(submissions)...)
+	events = append(events, statusChangeEvents(auditEvents)...)
+	sortTimeline(events)
+
+	return &models.NumberTimelineResponse{PhoneNumber: mobileNumber.PhoneNumber, Events: events}, nil
+}
+
+// GetEmployeeNumberHistory 汇总员工的号码历史
+func (s *numberHistoryService) GetEmployeeNumberHistory(ctx context.Context, employeeID string) (*models.EmployeeNumberHistoryResponse, error) {
+	employee, err := s.employeeRepo.GetEmployeeByEmployeeID(employeeID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrRecordNotFound) {
+			return nil, ErrEmployeeNotFound
+		}
+		return nil, err
+	}
+
+	usages, err := s.usageHistoryRepo.GetByEmployeeID(ctx, employee.EmployeeID)
+	if err != nil {
+		return nil, err
+	}
+	applicantChanges, err := s.applicantHistoryRepo.GetByApplicantID(ctx, employee.EmployeeID)
+	if err != nil {
+		return nil, err
+	}
+	submissions, err := s.submissionLogRepo.FindByEmployeeID(ctx, employee.EmployeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 历史记录只保存号码ID，需批量查出对应的手机号码
+	var numberIDs []uint
+	for _, u := range usages {
+		numberIDs = append(numberIDs, uint(u.MobileNumberDbID))
+	}
+	for _, c := range applicantChanges {
+		numberIDs = append(numberIDs, c.MobileNumberDbID)
+	}
+	numbers, err := s.mobileNumberRepo.FindByIDs(ctx, numberIDs)
+	if err != nil {
+		return nil, err
+	}
+	phoneNumbers := make(map[uint]string, len(numbers))
+	for _, n := range numbers {
+		phoneNumbers[n.ID] = n.PhoneNumber
+	}
+
+	events := usageEvents(usages, phoneNumbers)
+	events = append(events, applicantChangeEvents(applicantChanges, phoneNumbers)...)
+	events = append(events, verificationEvents(submissions)...)
+	sortTimeline(events)
+
+	return &models.EmployeeNumberHistoryResponse{
+		EmployeeID: employee.EmployeeID,
+		FullName:   employee.FullName,
+		Events:     events,
+	}, nil
+}
+
+// usageEvents 将每段使用历史转换为开始使用和结束使用（如已结束）两条事件
+func usageEvents(usages []models.NumberUsageHistory, phoneNumbers map[uint]string) []models.NumberTimelineEvent {
+	events := make([]models.NumberTimelineEvent, 0, len(usages)*2)
+	for _, u := range usages {
+		phoneNumber := phoneNumbers[uint(u.MobileNumberDbID)]
+		events = append(events, models.NumberTimelineEvent{
+			Type:        models.TimelineUsageStarted,
+			OccurredAt:  u.StartDate,
+			PhoneNumber: phoneNumber,
+			EmployeeID:  u.EmployeeID,
+			Remarks:     u.Remarks,
+		})
+		if u.EndDate != nil {
+			events = append(events, models.NumberTimelineEvent{
+				Type:        models.TimelineUsageEnded,
+				OccurredAt:  *u.EndDate,
+				PhoneNumber: phoneNumber,
+				EmployeeID:  u.EmployeeID,
+			})
+		}
+	}
+	return events
+}
+
+// applicantChangeEvents 将办卡人变更历史转换为时间线事件
+func applicantChangeEvents(changes []models.NumberApplicantHistory, phoneNumbers map[uint]string) []models.NumberTimelineEvent {
+	events := make([]models.NumberTimelineEvent, 0, len(changes))
+	for _, c := range changes {
+		event := models.NumberTimelineEvent{
+			Type:                models.TimelineApplicantChanged,
+			OccurredAt:          c.ChangeDate,
+			PhoneNumber:         phoneNumbers[c.MobileNumberDbID],
+			PreviousApplicantID: c.PreviousApplicantID,
+			NewApplicantID:      c.NewApplicantID,
+			Remarks:             c.Remarks,
+		}
+		if c.OperatorUsername != nil {
+			event.Operator = *c.OperatorUsername
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// verificationEvents 将员工的号码确认提交记录转换为时间线事件
+func verificationEvents(submissions []models.VerificationSubmissionLog) []models.NumberTimelineEvent {
+	events := make([]models.NumberTimelineEvent, 0, len(submissions))
+	for _, sub := range submissions {
+		event := models.NumberTimelineEvent{
+			Type:               models.TimelineVerificationSubmission,
+			OccurredAt:         sub.CreatedAt,
+			PhoneNumber:        sub.PhoneNumber,
+			EmployeeID:         sub.EmployeeID,
+			VerificationAction: sub.ActionType,
+		}
+		if sub.UserComment != nil {
+			event.Remarks = *sub.UserComment
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// statusChangeEvents 从号码的审计事件中提取状态变更，前后快照状态相同的事件忽略
+func statusChangeEvents(auditEvents []models.AuditEvent) []models.NumberTimelineEvent {
+	var events []models.NumberTimelineEvent
+	for _, e := range auditEvents {
+		from, to := snapshotStatus(e.Before), snapshotStatus(e.After)
+		if to == "" || from == to {
+			continue
+		}
+		events = append(events, models.NumberTimelineEvent{
+			Type:        models.TimelineStatusChanged,
+			OccurredAt:  e.CreatedAt,
+			PhoneNumber: e.EntityID,
+			FromStatus:  from,
+			ToStatus:    to,
+			Action:      e.Action,
+			Operator:    e.ActorUsername,
+		})
+	}
+	return events
+}
+
+// snapshotStatus 读取审计快照中的号码状态，快照为空或无法解析时返回空字符串
+func snapshotStatus(snapshot *string) string {
+	if snapshot == nil {
+		return ""
+	}
+	var number struct {
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal([]byte(*snapshot), &number); err != nil {
+		return ""
+	}
+	return number.Status
+}
+
+// sortTimeline 按发生时间升序排列事件。同一时间的结束使用排在其他事件之前（如号码转移），其余保持原有顺序
+func sortTimeline(events []models.NumberTimelineEvent) {
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].OccurredAt.Equal(events[j].OccurredAt) {
+			return events[i].OccurredAt.Before(events[j].OccurredAt)
+		}
+		return events[i].Type == models.TimelineUsageEnded && events[j].Type != models.TimelineUsageEnded
+	})
+}
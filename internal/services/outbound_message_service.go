@@ -0,0 +1,229 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/phone_management/configs"
+	"github.com/phone_management/internal/models"
+	"github.com/phone_management/internal/repositories"
+	"github.com/phone_management/pkg/email"
+)
+
+// ErrOutboundMessageNotFound 表示待发送通知不存在
+var ErrOutboundMessageNotFound = errors.New("通知不存在")
+
+// ErrOutboundMessageNotRetryable 表示通知不是发送失败状态，不能重试
+var ErrOutboundMessageNotRetryable = errors.New("只有发送失败的通知可以重试")
+
+// ErrOutboundMessageNoContent 表示通知在生成时失败，没有可发送的内容
+var ErrOutboundMessageNoContent = errors.New("通知生成失败，没有可发送的内容，请修正通知模板后重新发起号码确认")
+
+// OutboundMessageService 定义了通知发送队列服务的接口。
+// 通知先保存到 outbound_messages 表，再由工作协程按员工偏好的渠道发送；发送失败时按指数退避重新排队，
+// 达到最多尝试次数或员工缺少联系方式时标记为失败，可通过 RetryMessage 重新排队
+type OutboundMessageService interface {
+	// Start 启动工作协程定期发送到期的消息，并将领取后超过 OutboundSendTimeout 仍未完成的消息
+	// （领取的实例已退出或崩溃）重新排队。返回的 stop 函数停止领取新消息，并等待正在发送的消息完成
+	Start() (stop func())
+	// GetMessages 按条件分页查询消息，按创建时间倒序
+	GetMessages(ctx context.Context, filter models.OutboundMessageFilter, page, limit int) ([]models.OutboundMessage, int64, error)
+	GetMessage(ctx context.Context, id uint) (*models.OutboundMessage, error)
+	// RetryMessage 将发送失败的消息重新排队，尝试次数从零开始计算
+	RetryMessage(ctx context.Context, id uint) (*models.OutboundMessage, error)
+}
+
+// outboundMessageService 是 OutboundMessageService 的实现
+type outboundMessageService struct {
+	repo      repositories.OutboundMessageRepository
+	notifier  email.Notifier
+	appConfig *configs.Configuration
+}
+
+// NewOutboundMessageService 创建一个新的 outboundMessageService 实例
+func NewOutboundMessageService(repo repositories.OutboundMessageRepository, notifier email.Notifier) OutboundMessageService {
+	return &outboundMessageService{repo: repo, notifier: notifier, appConfig: &configs.AppConfig}
+}
+
+// Start 启动 OutboundWorkers 个工作协程，由一个分发协程每隔 OutboundPollInterval 领取到期的消息交给空闲的工作协程。
+// 每次领取的消息数不超过工作协程数，停止时已领取但未发送的消息保持 sending 状态，超过发送超时后由任一实例重新排队
+func (s *outboundMessageService) Start() (stop func()) {
+	ctx := context.Background()
+	workers := s.appConfig.OutboundWorkers
+	if workers <= 0 {
+		workers = 1
+	}
+	jobs := make(chan models.OutboundMessage)
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for msg := range jobs {
+				s.deliver(ctx, &msg)
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		ticker := time.NewTicker(s.appConfig.OutboundPollInterval)
+		defer ticker.Stop()
+		for {
+			s.resetStale(ctx)
+			s.dispatch(ctx, jobs, done, workers)
+			select {
+			case <-ticker.C:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	log.Printf("通知发送队列已启动，%d 个发送协程，查询间隔 %s", workers, s.appConfig.OutboundPollInterval)
+	return func() {
+		close(done)
+		wg.Wait()
+	}
+}
+
+// resetStale 将领取后超过发送超时仍未完成的消息重新排队。
+// 每次发送都受发送超时限制，超时未完成说明领取该消息的实例已退出或崩溃
+func (s *outboundMessageService) resetStale(ctx context.Context) {
+	now := time.Now()
+	reset, err := s.repo.ResetSending(ctx, now, now.Add(-s.appConfig.OutboundSendTimeout))
+	if err != nil {
+		log.Printf("通知发送队列：恢复未完成的发送失败: %v", err)
+	} else if reset > 0 {
+		log.Printf("通知发送队列：已将 %d 条未完成发送的通知重新排队", reset)
+	}
+}
+
+// dispatch 反复领取到期的消息交给工作协程，直到没有到期的消息或收到停止信号
+func (s *outboundMessageService) dispatch(ctx context.Context, jobs chan<- models.OutboundMessage, done <-chan struct{}, batchSize int) {
+	for {
+		claimed, err := s.repo.ClaimDue(ctx, time.Now(), batchSize)
+		if err != nil {
+			log.Printf("通知发送队列：领取到期的通知失败: %v", err)
+		}
+		for _, msg := range claimed {
+			select {
+			case jobs <- msg:
+			case <-done:
+				return
+			}
+		}
+		if err != nil || len(claimed) < batchSize {
+			return
+		}
+	}
+}
+
+// deliver 发送一条已领取的消息并记录结果。员工缺少联系方式时不再重试
+func (s *outboundMessageService) deliver(ctx context.Context, msg *models.OutboundMessage) {
+	// 消息领取后可能在等待空闲的工作协程，开始发送时重新记录领取时间，发送超时从此时开始计算
+	startedAt := time.Now()
+	started, err := s.repo.StartSending(ctx, msg.ID, *msg.ClaimedAt, startedAt)
+	if err != nil {
+		log.Printf("通知发送队列：开始发送通知 #%d 失败: %v", msg.ID, err)
+		return
+	}
+	if !started {
+		log.Printf("通知发送队列：通知 #%d 在等待发送期间已被重新排队，跳过本次发送", msg.ID)
+		return
+	}
+
+	recipient := email.Recipient{Name: msg.RecipientName, Email: msg.RecipientEmail, Phone: msg.RecipientPhone}
+	content := email.Message{Subject: msg.Subject, HTML: msg.HTMLBody, Text: msg.TextBody}
+	// 发送（包括等待 SMTP 会话）不能超过发送超时，否则消息可能在发送过程中被重新排队，由其他实例重复发送
+	sendCtx, cancel := context.WithTimeout(ctx, s.appConfig.OutboundSendTimeout)
+	channel, err := s.notifier.Notify(sendCtx, recipient, email.Channel(msg.Channel), content)
+	cancel()
+	if err == nil {
+		recorded, markErr := s.repo.MarkSent(ctx, msg.ID, startedAt, string(channel), time.Now())
+		if markErr != nil {
+			log.Printf("通知发送队列：记录通知 #%d 发送成功失败: %v", msg.ID, markErr)
+		} else if !recorded {
+			log.Printf("通知发送队列：通知 #%d 发送完成前已被重新排队，不记录本次结果", msg.ID)
+		}
+		return
+	}
+
+	maxAttempts := msg.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = s.appConfig.OutboundMaxAttempts
+	}
+	var retryAt *time.Time
+	if msg.Attempts < maxAttempts && !errors.Is(err, email.ErrNoAddress) {
+		next := time.Now().Add(s.backoff(msg.Attempts))
+		retryAt = &next
+	}
+	if retryAt != nil {
+		log.Printf("通知发送队列：通过 %s 发送通知 #%d 给 %s 失败（第 %d 次），将于 %s 重试: %v",
+			channel, msg.ID, msg.EmployeeID, msg.Attempts, retryAt.Format(time.RFC3339), err)
+	} else {
+		log.Printf("通知发送队列：通过 %s 发送通知 #%d 给 %s 失败（第 %d 次），不再重试: %v", channel, msg.ID, msg.EmployeeID, msg.Attempts, err)
+	}
+	recorded, recordErr := s.repo.RecordFailure(ctx, msg.ID, startedAt, string(channel), err.Error(), retryAt)
+	if recordErr != nil {
+		log.Printf("通知发送队列：记录通知 #%d 发送失败出错: %v", msg.ID, recordErr)
+	} else if !recorded {
+		log.Printf("通知发送队列：通知 #%d 发送完成前已被重新排队，不记录本次结果", msg.ID)
+	}
+}
+
+// backoff 返回第 attempts 次发送失败后的重试间隔：首次为 OutboundRetryBaseBackoff，之后每次翻倍，不超过 OutboundRetryMaxBackoff
+func (s *outboundMessageService) backoff(attempts int) time.Duration {
+	delay, maxDelay := s.appConfig.OutboundRetryBaseBackoff, s.appConfig.OutboundRetryMaxBackoff
+	for i := 1; i < attempts && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	return delay
+}
+
+// GetMessages 按条件分页查询消息
+func (s *outboundMessageService) GetMessages(ctx context.Context, filter models.OutboundMessageFilter, page, limit int) ([]models.OutboundMessage, int64, error) {
+	return s.repo.List(ctx, filter, page, limit)
+}
+
+// GetMessage 根据ID查询消息
+func (s *outboundMessageService) GetMessage(ctx context.Context, id uint) (*models.OutboundMessage, error) {
+	msg, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrRecordNotFound) {
+			return nil, ErrOutboundMessageNotFound
+		}
+		return nil, err
+	}
+	return msg, nil
+}
+
+// RetryMessage 将发送失败的消息重新排队
+func (s *outboundMessageService) RetryMessage(ctx context.Context, id uint) (*models.OutboundMessage, error) {
+	msg, err := s.GetMessage(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !msg.HasContent() {
+		return nil, ErrOutboundMessageNoContent
+	}
+
+	retried, err := s.repo.Retry(ctx, id, time.Now())
+	if err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrRecordNotFound):
+			return nil, ErrOutboundMessageNotFound
+		case errors.Is(err, repositories.ErrOutboundMessageNotRetryable):
+			return nil, ErrOutboundMessageNotRetryable
+		}
+		return nil, err
+	}
+	return retried, nil
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/phone_management/internal/models"
+	"github.com/phone_management/internal/repositories"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestDB 创建一个位于临时目录的 SQLite 数据库并迁移全部业务表
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("打开测试数据库失败: %v", err)
+	}
+	err = db.AutoMigrate(
+		&models.User{},
+		&models.UserDepartment{},
+		&models.Employee{},
+		&models.MobileNumber{},
+		&models.NumberUsageHistory{},
+		&models.NumberApplicantHistory{},
+		&models.AuditEvent{},
+		&models.ScheduledDeparture{},
+		&models.EmploymentPeriod{},
+		&models.Department{},
+		&models.DepartmentAlias{},
+		&models.HREvent{},
+		&models.HREventDeadLetter{},
+		&models.OutboundMessage{},
+	)
+	if err != nil {
+		t.Fatalf("迁移测试数据库失败: %v", err)
+	}
+	return db
+}
+
+// newTestEmployeeService 基于测试数据库创建员工服务
+func newTestEmployeeService(db *gorm.DB) EmployeeService {
+	return NewEmployeeService(
+		repositories.NewGormEmployeeRepository(db),
+		repositories.NewGormMobileNumberRepository(db),
+		repositories.NewGormScheduledDepartureRepository(db),
+		repositories.NewGormDepartmentRepository(db),
+		repositories.NewGormOutboundMessageRepository(db),
+	)
+}
+
+// createTestEmployee 创建一名在职员工，返回生成业务工号后的记录
+func createTestEmployee(t *testing.T, db *gorm.DB, fullName string) *models.Employee {
+	t.Helper()
+	hireDate := time.Now().UTC().Truncate(24*time.Hour).AddDate(-1, 0, 0)
+	employee := &models.Employee{FullName: fullName, EmploymentStatus: "Active", HireDate: &hireDate}
+	if err := db.Create(employee).Error; err != nil {
+		t.Fatalf("创建员工失败: %v", err)
+	}
+	return employee
+}
+
+// createTestNumber 创建一个办卡人为 applicant 的号码，user 不为空时分配给该员工使用
+func createTestNumber(t *testing.T, db *gorm.DB, phoneNumber string, applicant, user *models.Employee) *models.MobileNumber {
+	t.Helper()
+	ctx := context.Background()
+	repo := repositories.NewGormMobileNumberRepository(db)
+	number := &models.MobileNumber{PhoneNumber: phoneNumber, ApplicantEmployeeID: applicant.EmployeeID, ApplicationDate: time.Now().AddDate(0, -6, 0), Status: string(models.StatusIdle)}
+	if _, err := repo.CreateMobileNumber(ctx, number); err != nil {
+		t.Fatalf("创建号码 %s 失败: %v", phoneNumber, err)
+	}
+	if user != nil {
+		if _, err := repo.AssignMobileNumber(ctx, number.ID, user.EmployeeID, time.Now().UTC().Truncate(24*time.Hour).AddDate(0, -1, 0), "办公"); err != nil {
+			t.Fatalf("分配号码 %s 失败: %v", phoneNumber, err)
+		}
+	}
+	return number
+}
@@ -0,0 +1,248 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/phone_management/internal/models"
+	"github.com/phone_management/internal/repositories"
+	"github.com/phone_management/pkg/totp"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// 两步验证相关错误
+var ErrTOTPAlreadyEnabled = errors.New("两步验证已启用")
+var ErrTOTPNotEnabled = errors.New("两步验证未启用")
+var ErrTOTPNotSetup = errors.New("请先生成两步验证密钥")
+var ErrInvalidTOTPCode = errors.New("验证码或恢复码无效")
+
+const (
+	totpIssuer        = "Phone Management" // 验证器 App 中显示的签发者名称
+	totpSkew          = 1                  // 允许前后各一个时间步的时钟偏差
+	recoveryCodeCount = 10                 // 每次生成的恢复码数量
+)
+
+// TOTPSetupResult 是生成两步验证密钥后返回给用户的信息
+type TOTPSetupResult struct {
+	Secret     string `json:"secret"`     // Base32 密钥，无法扫码时手动输入
+	OtpauthURI string `json:"otpauthUri"` // 用于生成二维码的 otpauth:// URI
+}
+
+// TwoFactorService 定义了 TOTP 两步验证服务的接口
+type TwoFactorService interface {
+	// Setup 为用户生成新的待确认密钥，启用前可重复调用
+	Setup(userID int64) (*TOTPSetupResult, error)
+	// Enable 使用验证码确认密钥并启用两步验证，返回一组新的恢复码
+	Enable(ctx context.Context, userID int64, code string) ([]string, error)
+	// Disable 校验密码和验证码（或恢复码）后关闭两步验证
+	Disable(ctx context.Context, userID int64, password, code string) error
+	// RegenerateRecoveryCodes 校验验证码后重新生成恢复码，旧恢复码全部失效
+	RegenerateRecoveryCodes(userID int64, code string) ([]string, error)
+	// VerifySecondFactor 校验登录时提交的验证码或恢复码
+	VerifySecondFactor(user *models.User, code string) error
+	// Reset 由管理员清除用户的两步验证，用于用户丢失设备的情况
+	Reset(ctx context.Context, userID int64) error
+}
+
+// twoFactorService 是 TwoFactorService 的实现
+type twoFactorService struct {
+	userRepo         repositories.UserRepository
+	recoveryCodeRepo repositories.RecoveryCodeRepository
+}
+
+// NewTwoFactorService 创建一个新的 twoFactorService 实例
+func NewTwoFactorService(userRepo repositories.UserRepository, recoveryCodeRepo repositories.RecoveryCodeRepository) TwoFactorService {
+	return &twoFactorService{userRepo: userRepo, recoveryCodeRepo: recoveryCodeRepo}
+}
+
+// Setup 生成新的待确认密钥
+func (s *twoFactorService) Setup(userID int64) (*TOTPSetupResult, error) {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabled {
+		return nil, ErrTOTPAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.userRepo.UpdateLoginState(userID, map[string]interface{}{
+		"totp_secret":         secret,
+		"totp_last_used_step": 0,
+	}); err != nil {
+		return nil, err
+	}
+
+	return &TOTPSetupResult{
+		Secret:     secret,
+		OtpauthURI: totp.KeyURI(totpIssuer, user.Username, secret),
+	}, nil
+}
+
+// Enable 确认密钥并启用两步验证
+func (s *twoFactorService) Enable(ctx context.Context, userID int64, code string) ([]string, error) {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabled {
+		return nil, ErrTOTPAlreadyEnabled
+	}
+	if user.TOTPSecret == "" {
+		return nil, ErrTOTPNotSetup
+	}
+
+	step, ok := totp.Validate(user.TOTPSecret, code, time.Now(), totpSkew)
+	if !ok {
+		return nil, ErrInvalidTOTPCode
+	}
+
+	if _, err := s.userRepo.UpdateUser(ctx, userID, models.AuditActionUserTOTPEnable, map[string]interface{}{
+		"totp_enabled":        true,
+		"totp_last_used_step": step,
+	}); err != nil {
+		return nil, err
+	}
+	return s.generateRecoveryCodes(userID)
+}
+
+// Disable 关闭两步验证
+func (s *twoFactorService) Disable(ctx context.Context, userID int64, password, code string) error {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return err
+	}
+	if !user.TOTPEnabled {
+		return ErrTOTPNotEnabled
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return ErrIncorrectPassword
+	}
+	if err := s.VerifySecondFactor(user, code); err != nil {
+		return err
+	}
+	return s.Reset(ctx, userID)
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码
+func (s *twoFactorService) RegenerateRecoveryCodes(userID int64, code string) ([]string, error) {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.TOTPEnabled {
+		return nil, ErrTOTPNotEnabled
+	}
+	if err := s.VerifySecondFactor(user, code); err != nil {
+		return nil, err
+	}
+	return s.generateRecoveryCodes(userID)
+}
+
+// VerifySecondFactor 校验 TOTP 验证码或恢复码，同一验证码不能重复使用
+func (s *twoFactorService) VerifySecondFactor(user *models.User, code string) error {
+	if !user.TOTPEnabled {
+		return ErrTOTPNotEnabled
+	}
+
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits {
+		step, ok := totp.Validate(user.TOTPSecret, code, time.Now(), totpSkew)
+		if !ok {
+			return ErrInvalidTOTPCode
+		}
+		used, err := s.userRepo.UseTOTPStep(user.ID, step)
+		if err != nil {
+			return err
+		}
+		if !used {
+			return ErrInvalidTOTPCode
+		}
+		return nil
+	}
+
+	used, err := s.recoveryCodeRepo.UseCode(user.ID, hashRecoveryCode(code))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidTOTPCode
+	}
+	return nil
+}
+
+// Reset 清除用户的两步验证设置和恢复码
+func (s *twoFactorService) Reset(ctx context.Context, userID int64) error {
+	if _, err := s.getUser(userID); err != nil {
+		return err
+	}
+	if _, err := s.userRepo.UpdateUser(ctx, userID, models.AuditActionUserTOTPReset, map[string]interface{}{
+		"totp_enabled":        false,
+		"totp_secret":         "",
+		"totp_last_used_step": 0,
+	}); err != nil {
+		return err
+	}
+	return s.recoveryCodeRepo.DeleteForUser(userID)
+}
+
+// getUser 查询用户，不存在时返回 ErrUserNotFound
+func (s *twoFactorService) getUser(userID int64) (*models.User, error) {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// generateRecoveryCodes 生成一组新的恢复码，明文只在此时返回一次
+func (s *twoFactorService) generateRecoveryCodes(userID int64) ([]string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := newRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	if err := s.recoveryCodeRepo.ReplaceForUser(userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// recoveryCodeAlphabet 去掉了容易混淆的字符 (0/O, 1/I/L)
+const recoveryCodeAlphabet = "23456789ABCDEFGHJKMNPQRSTUVWXYZ"
+
+// newRecoveryCode 生成形如 XXXXX-XXXXX 的随机恢复码
+func newRecoveryCode() (string, error) {
+	buf := make([]byte, 10)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	for i, b := range buf {
+		if i == 5 {
+			sb.WriteByte('-')
+		}
+		sb.WriteByte(recoveryCodeAlphabet[int(b)%len(recoveryCodeAlphabet)])
+	}
+	return sb.String(), nil
+}
+
+// hashRecoveryCode 规范化恢复码（忽略大小写和分隔符）后计算哈希
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+	return hashToken(normalized)
+}
@@ -0,0 +1,237 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/phone_management/internal/models"
+	"github.com/phone_management/internal/repositories"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// 系统用户相关错误
+var ErrUserNotFound = errors.New("用户未找到")
+var ErrInvalidRole = errors.New("无效的角色")
+var ErrLastAdmin = errors.New("不能删除、禁用或降级最后一个启用状态的管理员")
+var ErrCannotDeleteSelf = errors.New("不能删除当前登录的账号")
+var ErrIncorrectPassword = errors.New("原密码不正确")
+var ErrInvalidUserDepartments = errors.New("所负责的部门无效")
+var ErrNoUpdateFields = errors.New("没有提供任何有效的更新字段")
+
+// UserService 定义了系统用户管理服务的接口
+type UserService interface {
+	CreateUser(ctx context.Context, payload models.CreateUserPayload) (*models.User, error)
+	GetUsers(page, limit int, search, role, status string) ([]models.User, int64, error)
+	GetUserByID(id int64) (*models.User, error)
+	UpdateUser(ctx context.Context, id int64, payload models.UpdateUserPayload) (*models.User, error)
+	DeleteUser(ctx context.Context, id int64, currentUserID int64) error
+	ResetPassword(ctx context.Context, id int64, newPassword string) error
+	ChangePassword(ctx context.Context, id int64, oldPassword, newPassword string) error
+	// UnlockUser 解除账号的登录锁定并清零失败次数
+	UnlockUser(ctx context.Context, id int64) (*models.User, error)
+}
+
+// userService 是 UserService 的实现
+type userService struct {
+	repo             repositories.UserRepository
+	refreshTokenRepo repositories.RefreshTokenRepository
+	departmentRepo   repositories.DepartmentRepository
+}
+
+// NewUserService 创建一个新的 userService 实例
+func NewUserService(repo repositories.UserRepository, refreshTokenRepo repositories.RefreshTokenRepository, departmentRepo repositories.DepartmentRepository) UserService {
+	return &userService{repo: repo, refreshTokenRepo: refreshTokenRepo, departmentRepo: departmentRepo}
+}
+
+// CreateUser 创建系统用户，密码以 bcrypt 哈希存储
+func (s *userService) CreateUser(ctx context.Context, payload models.CreateUserPayload) (*models.User, error) {
+	if !models.IsValidRole(payload.Role) {
+		return nil, ErrInvalidRole
+	}
+	departmentIDs, err := s.checkDepartments(ctx, payload.Role, payload.DepartmentIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(payload.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &models.User{
+		Username:      payload.Username,
+		PasswordHash:  string(passwordHash),
+		Role:          payload.Role,
+		Status:        models.UserStatusActive,
+		DepartmentIDs: departmentIDs,
+	}
+	return s.repo.CreateUser(ctx, user)
+}
+
+// GetUsers 获取系统用户列表
+func (s *userService) GetUsers(page, limit int, search, role, status string) ([]models.User, int64, error) {
+	return s.repo.GetUsers(page, limit, search, role, status)
+}
+
+// GetUserByID 根据ID获取系统用户
+func (s *userService) GetUserByID(id int64) (*models.User, error) {
+	user, err := s.repo.GetUserByID(id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// UpdateUser 更新系统用户的角色或状态
+func (s *userService) UpdateUser(ctx context.Context, id int64, payload models.UpdateUserPayload) (*models.User, error) {
+	user, err := s.GetUserByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(map[string]interface{})
+	if payload.Role != nil {
+		if !models.IsValidRole(*payload.Role) {
+			return nil, ErrInvalidRole
+		}
+		updates["role"] = *payload.Role
+	}
+	if payload.Status != nil {
+		updates["status"] = *payload.Status
+	}
+
+	// 部门只对部门负责人有意义：角色改为其他角色时清除，改为部门负责人或修改部门时重新校验
+	role := user.Role
+	if payload.Role != nil {
+		role = *payload.Role
+	}
+	if payload.DepartmentIDs != nil || role != user.Role {
+		requested := user.DepartmentIDs
+		if payload.DepartmentIDs != nil {
+			requested = *payload.DepartmentIDs
+		} else if role != models.RoleDepartmentManager {
+			requested = nil
+		}
+		departmentIDs, err := s.checkDepartments(ctx, role, requested)
+		if err != nil {
+			return nil, err
+		}
+		updates["department_ids"] = departmentIDs
+	}
+	if len(updates) == 0 {
+		return nil, ErrNoUpdateFields
+	}
+
+	// 降级或禁用管理员时，仓库在同一事务中确保至少保留一个启用状态的管理员
+	updatedUser, err := s.repo.UpdateUser(ctx, id, models.AuditActionUserUpdate, updates)
+	if errors.Is(err, repositories.ErrLastActiveAdmin) {
+		return nil, ErrLastAdmin
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// 禁用账号后吊销其所有刷新令牌，使其无法继续续期
+	if updatedUser.Status == models.UserStatusDisabled {
+		if err := s.refreshTokenRepo.RevokeAllForUser(id); err != nil {
+			return nil, err
+		}
+	}
+	return updatedUser, nil
+}
+
+// DeleteUser 删除系统用户，不允许删除自己或最后一个管理员
+func (s *userService) DeleteUser(ctx context.Context, id int64, currentUserID int64) error {
+	if id == currentUserID {
+		return ErrCannotDeleteSelf
+	}
+	if _, err := s.GetUserByID(id); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteUser(ctx, id); err != nil {
+		if errors.Is(err, repositories.ErrLastActiveAdmin) {
+			return ErrLastAdmin
+		}
+		return err
+	}
+	return s.refreshTokenRepo.RevokeAllForUser(id)
+}
+
+// ResetPassword 由管理员为指定用户设置新密码
+func (s *userService) ResetPassword(ctx context.Context, id int64, newPassword string) error {
+	if _, err := s.GetUserByID(id); err != nil {
+		return err
+	}
+	return s.updatePassword(ctx, id, models.AuditActionUserResetPassword, newPassword)
+}
+
+// ChangePassword 当前用户校验原密码后修改自己的密码
+func (s *userService) ChangePassword(ctx context.Context, id int64, oldPassword, newPassword string) error {
+	user, err := s.GetUserByID(id)
+	if err != nil {
+		return err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(oldPassword)); err != nil {
+		return ErrIncorrectPassword
+	}
+	return s.updatePassword(ctx, id, models.AuditActionUserChangePassword, newPassword)
+}
+
+// UnlockUser 解除账号的登录锁定并清零失败次数
+func (s *userService) UnlockUser(ctx context.Context, id int64) (*models.User, error) {
+	if _, err := s.GetUserByID(id); err != nil {
+		return nil, err
+	}
+	return s.repo.UpdateUser(ctx, id, models.AuditActionUserUnlock, map[string]interface{}{
+		"failed_login_count":   0,
+		"last_failed_login_at": nil,
+		"locked_until":         nil,
+	})
+}
+
+// updatePassword 哈希并保存新密码，并吊销该用户已有的刷新令牌
+func (s *userService) updatePassword(ctx context.Context, id int64, action, newPassword string) error {
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	if _, err := s.repo.UpdateUser(ctx, id, action, map[string]interface{}{"password_hash": string(passwordHash)}); err != nil {
+		return err
+	}
+	return s.refreshTokenRepo.RevokeAllForUser(id)
+}
+
+// checkDepartments 校验角色与所负责部门的组合：部门负责人至少负责一个已存在的部门，其他角色不能指定部门。
+// 返回去重后的部门ID
+func (s *userService) checkDepartments(ctx context.Context, role string, departmentIDs []uint) ([]uint, error) {
+	if role != models.RoleDepartmentManager {
+		if len(departmentIDs) > 0 {
+			return nil, fmt.Errorf("%w: 只有 %s 角色可以指定所负责的部门", ErrInvalidUserDepartments, models.RoleDepartmentManager)
+		}
+		return nil, nil
+	}
+	if len(departmentIDs) == 0 {
+		return nil, fmt.Errorf("%w: %s 角色至少需要负责一个部门", ErrInvalidUserDepartments, models.RoleDepartmentManager)
+	}
+
+	seen := make(map[uint]bool, len(departmentIDs))
+	unique := make([]uint, 0, len(departmentIDs))
+	for _, id := range departmentIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if _, err := s.departmentRepo.GetDepartmentByID(ctx, id); err != nil {
+			if errors.Is(err, repositories.ErrRecordNotFound) {
+				return nil, fmt.Errorf("%w: 部门 %d 不存在", ErrInvalidUserDepartments, id)
+			}
+			return nil, err
+		}
+		unique = append(unique, id)
+	}
+	return unique, nil
+}
@@ -5,10 +5,13 @@ import (
 	"encoding/json" // 用于序列化 RequestedScopeValues
 	"errors"
 	"fmt"
+	"math"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/phone_management/configs"
+	"github.com/phone_management/internal/audit"
 	"github.com/phone_management/internal/models"
 	"github.com/phone_management/internal/repositories"
 	"github.com/phone_management/pkg/email"
@@ -16,10 +19,11 @@ import (
 )
 
 // 定义一些服务层特定的错误，如果需要的话
-var ErrEmailDispatchFailed = errors.New("邮件发送失败")
 var ErrBatchTaskNotFound = errors.New("批处理任务未找到")
 var ErrTokenNotFound = errors.New("验证令牌不存在")
 var ErrTokenExpired = errors.New("验证令牌已过期")
+var ErrNoPendingVerification = errors.New("该员工没有待确认的号码确认请求")
+var ErrEmployeeEmailMissing = errors.New("员工未登记可用的邮箱或手机号码，无法发送催办通知")
 
 // 号码确认信息结构
 type VerificationInfoResponse struct {
@@ -38,19 +42,34 @@ type NumberToVerify struct {
 // VerificationService 定义了号码验证服务的接口
 type VerificationService interface {
 	// InitiateVerificationProcess 启动一个新的验证批处理任务，并返回批处理ID
-	InitiateVerificationProcess(ctx context.Context, scopeType models.VerificationScopeType, scopeValues []string, durationDays int) (batchID string, err error)
+	// 按部门发起时 scopeValues 为部门名称或别名，includeSubDepartments 为 true 时同时包含其下级部门的员工；
+	// reminders 为批次的自动催办计划，未指定的字段使用配置的默认值
+	InitiateVerificationProcess(ctx context.Context, scopeType models.VerificationScopeType, scopeValues []string, includeSubDepartments bool, durationDays int, reminders ReminderOptions) (batchID string, err error)
 	// GetVerificationBatchStatus 获取指定批处理任务的当前状态和统计信息
 	GetVerificationBatchStatus(ctx context.Context, batchID string) (*models.VerificationBatchTask, error)
+	// ResumePendingBatches 在服务启动时继续生成尚未完成的批处理任务的通知，返回恢复的任务数
+	ResumePendingBatches(ctx context.Context) (int, error)
 	// GetVerificationInfo 获取待确认的号码信息
 	GetVerificationInfo(ctx context.Context, token string) (*models.VerificationInfo, error)
 	// SubmitVerificationResult 提交号码确认结果
 	SubmitVerificationResult(ctx context.Context, token string, request *models.VerificationSubmission) error
-	// GetPhoneVerificationStatus 获取基于手机号码维度的管理员视图
-	GetPhoneVerificationStatus(ctx context.Context, employeeID, departmentName string) (*models.PhoneVerificationStatusResponse, error)
+	// GetPhoneVerificationStatus 获取基于手机号码维度的管理员视图，department 为空时不按部门筛选
+	GetPhoneVerificationStatus(ctx context.Context, employeeID string, department models.DepartmentFilter) (*models.PhoneVerificationStatusResponse, error)
+	// RemindPendingEmployee 将催办尚未确认的员工的通知加入发送队列，返回催办后的待确认信息
+	RemindPendingEmployee(ctx context.Context, employeeID string) (*models.PendingUserDetail, error)
+	// SendScheduledReminders 按各批次的催办计划，将到期的催办通知加入发送队列，返回加入队列的通知数
+	SendScheduledReminders(ctx context.Context, now time.Time) (int, error)
 	// ProcessVerificationBatch (内部方法，可不由接口暴露，或仅为测试暴露)
 	// processVerificationBatch(batchID string) // 改为非导出，由 InitiateVerificationProcess 内部 goroutine 调用
 }
 
+// ReminderOptions 发起确认批次时指定的自动催办计划，字段为 nil 时使用配置的默认值
+type ReminderOptions struct {
+	AfterSendDays    []int // 发出确认通知后第几天催办
+	BeforeExpiryDays []int // 链接过期前几天催办
+	MaxReminders     *int  // 每位员工最多催办次数，0 表示不自动催办
+}
+
 // verificationService 结构体现已包含 appConfig
 type verificationService struct {
 	employeeRepo          repositories.EmployeeRepository
@@ -59,12 +78,15 @@ type verificationService struct {
 	mobileNumberRepo      repositories.MobileNumberRepository              // 手机号码仓库
 	userReportedIssueRepo repositories.UserReportedIssueRepository         // 用户报告问题仓库
 	submissionLogRepo     repositories.VerificationSubmissionLogRepository // 验证提交日志仓库
+	departmentRepo        repositories.DepartmentRepository                // 部门仓库
+	outboundRepo          repositories.OutboundMessageRepository           // 通知发送队列，确认通知和催办通知均由队列发送
+	templates             EmailTemplateService                             // 按员工的通知语言渲染确认通知
 	appConfig             *configs.Configuration
 	db                    *gorm.DB
 }
 
 // NewVerificationService 构造函数现已注入 appConfig
-func NewVerificationService(employeeRepo repositories.EmployeeRepository, verificationTokenRepo repositories.VerificationTokenRepository, batchTaskRepo repositories.VerificationBatchTaskRepository, mobileNumberRepo repositories.MobileNumberRepository, userReportedIssueRepo repositories.UserReportedIssueRepository, submissionLogRepo repositories.VerificationSubmissionLogRepository, db *gorm.DB) VerificationService {
+func NewVerificationService(employeeRepo repositories.EmployeeRepository, verificationTokenRepo repositories.VerificationTokenRepository, batchTaskRepo repositories.VerificationBatchTaskRepository, mobileNumberRepo repositories.MobileNumberRepository, userReportedIssueRepo repositories.UserReportedIssueRepository, submissionLogRepo repositories.VerificationSubmissionLogRepository, departmentRepo repositories.DepartmentRepository, outboundRepo repositories.OutboundMessageRepository, templates EmailTemplateService, db *gorm.DB) VerificationService {
 	return &verificationService{
 		employeeRepo:          employeeRepo,
 		verificationTokenRepo: verificationTokenRepo,
@@ -72,6 +94,9 @@ func NewVerificationService(employeeRepo repositories.EmployeeRepository, verifi
 		mobileNumberRepo:      mobileNumberRepo,
 		userReportedIssueRepo: userReportedIssueRepo,
 		submissionLogRepo:     submissionLogRepo,
+		departmentRepo:        departmentRepo,
+		outboundRepo:          outboundRepo,
+		templates:             templates,
 		appConfig:             &configs.AppConfig,
 		db:                    db,
 	}
@@ -204,7 +229,8 @@ func (s *verificationService) GetVerificationInfo(ctx context.Context, token str
 	}, nil
 }
 
-// GetVerificationBatchStatus 获取批处理任务的状态
+// GetVerificationBatchStatus 获取批处理任务的状态，令牌数和通知发送统计由发送队列中该批次的消息计算得出，不写回任务记录。
+// 通知全部进入发送队列后，队列中没有待发送的通知时批次完成；重试失败的通知后批次恢复为 InProgress
 func (s *verificationService) GetVerificationBatchStatus(ctx context.Context, batchID string) (*models.VerificationBatchTask, error) {
 	task, err := s.batchTaskRepo.GetByID(ctx, batchID)
 	if err != nil {
@@ -213,11 +239,93 @@ func (s *verificationService) GetVerificationBatchStatus(ctx context.Context, ba
 		}
 		return nil, fmt.Errorf("获取批处理任务失败: %w", err)
 	}
+	if err := s.fillBatchCounts(ctx, task); err != nil {
+		return nil, fmt.Errorf("统计批处理任务的通知失败: %w", err)
+	}
 	return task, nil
 }
 
-// processVerificationBatch 是实际执行批量处理的内部方法
-// 它将在一个单独的 goroutine 中运行
+// fillBatchCounts 按发送队列中该批次的消息填充返回任务的计数、错误摘要和状态，只读不写。
+// 批次仍在生成通知 (Pending) 时只填充计数，状态由生成通知的协程维护
+func (s *verificationService) fillBatchCounts(ctx context.Context, task *models.VerificationBatchTask) error {
+	counts, err := s.outboundRepo.CountByBatch(ctx, task.ID)
+	if err != nil {
+		return err
+	}
+	if counts.Total == 0 {
+		// 失败或没有员工的批次、尚未生成通知的批次以及启用发送队列前的批次没有队列消息，保留已记录的统计
+		return nil
+	}
+	task.TokensGeneratedCount = counts.Total
+	task.EmailsAttemptedCount = counts.Attempted
+	task.EmailsSucceededCount = counts.Sent
+	task.EmailsFailedCount = counts.Failed
+	task.EmailsPendingCount = counts.Pending + counts.Sending
+
+	task.ErrorSummary = nil
+	if counts.Failed > 0 {
+		failed, err := s.outboundRepo.FindFailedByBatch(ctx, task.ID)
+		if err != nil {
+			return err
+		}
+		lines := make([]string, 0, len(failed))
+		for _, msg := range failed {
+			lines = append(lines, outboundFailureDetail(&msg))
+		}
+		summary := strings.Join(lines, "\n")
+		task.ErrorSummary = &summary
+	}
+
+	if task.Status == models.BatchTaskStatusPending {
+		return nil
+	}
+	switch {
+	case task.EmailsPendingCount > 0:
+		task.Status = models.BatchTaskStatusInProgress
+	case task.EmailsFailedCount > 0:
+		task.Status = models.BatchTaskStatusCompletedWithErrors
+	default:
+		task.Status = models.BatchTaskStatusCompleted
+	}
+	return nil
+}
+
+// outboundFailureDetail 将发送失败的通知转换为错误摘要中的一行 JSON
+func outboundFailureDetail(msg *models.OutboundMessage) string {
+	recipient := email.Recipient{Email: msg.RecipientEmail, Phone: msg.RecipientPhone}
+	channel := email.Channel(msg.SentChannel)
+	if channel == "" {
+		channel = email.ChannelEmail
+	}
+	address := recipient.Address(channel)
+	if address == "" {
+		address = "N/A"
+	}
+	detail, _ := json.Marshal(models.EmailFailureDetail{
+		EmployeeID:   msg.EmployeeID,
+		EmployeeName: msg.RecipientName,
+		EmailAddress: address,
+		Channel:      msg.SentChannel,
+		Reason:       msg.LastError,
+	})
+	return string(detail)
+}
+
+// ResumePendingBatches 继续处理服务退出时仍在生成通知的批处理任务，已生成通知的员工会被跳过
+func (s *verificationService) ResumePendingBatches(ctx context.Context) (int, error) {
+	tasks, err := s.batchTaskRepo.FindByStatus(ctx, models.BatchTaskStatusPending)
+	if err != nil {
+		return 0, fmt.Errorf("查询未完成的批处理任务失败: %w", err)
+	}
+	for i := range tasks {
+		go s.processVerificationBatch(&tasks[i])
+	}
+	return len(tasks), nil
+}
+
+// processVerificationBatch 是实际执行批量处理的内部方法，它将在一个单独的 goroutine 中运行。
+// 为每位员工生成令牌并渲染确认通知，令牌和通知在同一事务中写入发送队列，由发送队列的工作协程负责发送和重试。
+// 全部员工处理完后批次变为 InProgress；写入失败时批次保持 Pending，服务下次启动时为缺少通知的员工重新生成
 func (s *verificationService) processVerificationBatch(initialTask *models.VerificationBatchTask) {
 	ctx := context.Background() // 为后台任务创建一个新的上下文
 	batchID := initialTask.ID
@@ -239,7 +347,7 @@ func (s *verificationService) processVerificationBatch(initialTask *models.Verif
 	case models.VerificationScopeAllUsers:
 		employees, err = s.employeeRepo.FindAllActive(ctx)
 	case models.VerificationScopeDepartment:
-		employees, err = s.employeeRepo.FindActiveByDepartmentNames(ctx, actualScopeValues)
+		employees, err = s.findActiveEmployeesInDepartments(ctx, actualScopeValues, initialTask.IncludeSubDepartments)
 	case models.VerificationScopeEmployeeIDs:
 		employees, err = s.employeeRepo.FindActiveByEmployeeIDs(ctx, actualScopeValues)
 	default:
@@ -265,10 +373,25 @@ func (s *verificationService) processVerificationBatch(initialTask *models.Verif
 		_ = s.batchTaskRepo.Update(ctx, initialTask) // 更新总数
 	}
 
+	// 继续处理中断的批次时，跳过已生成通知的员工
+	enqueuedEmployeeIDs, err := s.outboundRepo.FindEmployeeIDsByBatch(ctx, batchID)
+	if err != nil {
+		fmt.Printf("处理批处理 %s 失败：查询已生成的通知失败: %v\n", batchID, err)
+		return
+	}
+	enqueued := make(map[string]bool, len(enqueuedEmployeeIDs))
+	for _, id := range enqueuedEmployeeIDs {
+		enqueued[id] = true
+	}
+
 	frontendBaseURL := s.appConfig.FrontendBaseURL
-	var localTokensGenerated, localEmailsAttempted, localEmailsSucceeded, localEmailsFailed int
+	var localEnqueued, localRenderFailed, localEnqueueFailed int
 
 	for _, emp := range employees {
+		if enqueued[emp.EmployeeID] {
+			continue
+		}
+
 		token := uuid.NewString()
 		expiresAt := time.Now().AddDate(0, 0, initialTask.RequestedDurationDays)
 		verificationToken := &models.VerificationToken{
@@ -276,71 +399,56 @@ func (s *verificationService) processVerificationBatch(initialTask *models.Verif
 			Token:      token,
 			Status:     models.VerificationTokenStatusPending,
 			ExpiresAt:  expiresAt,
+			BatchID:    &batchID,
 		}
 
-		createErr := s.verificationTokenRepo.Create(ctx, verificationToken)
-		if createErr != nil {
-			fmt.Printf("批处理 %s：为员工 %s 创建令牌失败: %v\n", batchID, emp.EmployeeID, createErr)
-			// 即使令牌创建失败，也尝试记录为一次尝试（或根据业务逻辑定义）
-			// 这里我们不直接更新数据库，而是在循环结束后批量更新或根据 newStatus 决定
-			// 但需要记录这个失败，可能影响邮件发送的尝试
-			// 简单起见，如果令牌创建失败，我们就不尝试发邮件了
-			_ = s.batchTaskRepo.UpdateCountsAndStatus(ctx, batchID, 0, 0, 0, 1, models.BatchTaskStatusInProgress, &models.EmailFailureDetail{
-				EmployeeID: emp.EmployeeID, EmployeeName: emp.FullName, EmailAddress: "N/A (Token creation failed)", Reason: "Token creation failed: " + createErr.Error(),
-			})
-			localEmailsFailed++ // 算作邮件处理失败的一部分
-			continue            // 继续处理下一个员工
-		}
-		localTokensGenerated++
-
-		if emp.Email != nil && *emp.Email != "" {
-			localEmailsAttempted++
-			verificationLink := fmt.Sprintf("%s/verify-numbers?token=%s", frontendBaseURL, token)
-			sendErr := email.SendVerificationEmail(*emp.Email, emp.FullName, verificationLink)
-			if sendErr != nil {
-				fmt.Printf("批处理 %s：发送确认邮件给 %s (%s) 失败: %v\n", batchID, emp.FullName, *emp.Email, sendErr)
-				localEmailsFailed++
-				_ = s.batchTaskRepo.UpdateCountsAndStatus(ctx, batchID, 1, 1, 0, 1, models.BatchTaskStatusInProgress, &models.EmailFailureDetail{
-					EmployeeID: emp.EmployeeID, EmployeeName: emp.FullName, EmailAddress: *emp.Email, Reason: sendErr.Error(),
-				})
-			} else {
-				localEmailsSucceeded++
-				_ = s.batchTaskRepo.UpdateCountsAndStatus(ctx, batchID, 1, 1, 1, 0, models.BatchTaskStatusInProgress, nil)
-			}
+		// 按员工偏好的渠道发送，渠道不可用或员工缺少对应联系方式时改用邮件
+		recipient, preferred := notificationRecipient(&emp)
+		msg := &models.OutboundMessage{
+			Kind:           models.OutboundMessageKindVerification,
+			BatchID:        &batchID,
+			EmployeeID:     emp.EmployeeID,
+			RecipientName:  recipient.Name,
+			RecipientEmail: recipient.Email,
+			RecipientPhone: recipient.Phone,
+			Channel:        string(preferred),
+			Status:         models.OutboundMessagePending,
+			MaxAttempts:    s.appConfig.OutboundMaxAttempts,
+			NextAttemptAt:  time.Now(),
+		}
+		verificationLink := fmt.Sprintf("%s/verify-numbers?token=%s", frontendBaseURL, token)
+		content, renderErr := s.verificationMessage(ctx, &emp, verificationLink, expiresAt, initialTask.RequestedDurationDays, false)
+		if renderErr != nil {
+			// 渲染失败的通知直接记为发送失败，计入批次的失败数
+			fmt.Printf("批处理 %s：为员工 %s 生成确认通知失败: %v\n", batchID, emp.EmployeeID, renderErr)
+			msg.Status = models.OutboundMessageFailed
+			msg.LastError = "生成确认通知失败: " + renderErr.Error()
+			localRenderFailed++
 		} else {
-			fmt.Printf("批处理 %s：员工 %s (%s) 缺少邮箱地址，跳过发送确认邮件\n", batchID, emp.FullName, emp.EmployeeID)
-			// 这种情况也算作一次尝试，但失败了（因为没有邮箱）
-			localEmailsAttempted++
-			localEmailsFailed++
-			_ = s.batchTaskRepo.UpdateCountsAndStatus(ctx, batchID, 1, 1, 0, 1, models.BatchTaskStatusInProgress, &models.EmailFailureDetail{
-				EmployeeID: emp.EmployeeID, EmployeeName: emp.FullName, EmailAddress: "N/A", Reason: "Missing email address",
-			})
-		}
-		// 这里的 UpdateCountsAndStatus 是在每次循环中调用，对于大量员工可能导致频繁DB操作。
-		// 更优化的方式是本地累积计数，然后定期（例如每 N 个员工或每隔 X 秒）或在最后统一更新数据库。
-		// 当前的简化实现是每次都更新。
-	}
-
-	// 所有员工处理完毕，决定最终状态
-	finalStatus := models.BatchTaskStatusCompleted
-	if localEmailsFailed > 0 {
-		finalStatus = models.BatchTaskStatusCompletedWithErrors
-	}
-	// 最后一次更新，确保所有计数准确，并设置最终状态
-	// 注意：如果之前的 UpdateCountsAndStatus 已经是原子增量，这里可能只需要更新最终状态和 ErrorSummary (如果 ErrorSummary 是累积的)
-	// 当前 UpdateCountsAndStatus 已经是增量，所以这里主要是设置最终状态
-	// 为了简化，我们假设最后一次调用 UpdateCountsAndStatus 来设置状态，并不再传递增量计数（因为它们已在循环中处理）
-	// 更好的做法是服务层维护 task 对象，并在最后调用一次 taskRepo.Update(ctx, task)
-	finalUpdateErr := s.batchTaskRepo.UpdateCountsAndStatus(ctx, batchID, 0, 0, 0, 0, finalStatus, nil) // 只更新状态
-	if finalUpdateErr != nil {
-		fmt.Printf("批处理 %s：更新最终状态失败: %v\n", batchID, finalUpdateErr)
-	}
-	fmt.Printf("批处理 %s 完成。总员工: %d, 令牌生成: %d, 邮件尝试: %d, 成功: %d, 失败: %d. 最终状态: %s\n",
-		batchID, len(employees), localTokensGenerated, localEmailsAttempted, localEmailsSucceeded, localEmailsFailed, finalStatus)
+			msg.Subject, msg.HTMLBody, msg.TextBody = content.Subject, content.HTML, content.Text
+		}
+
+		if err := s.outboundRepo.EnqueueWithToken(ctx, verificationToken, msg); err != nil {
+			fmt.Printf("批处理 %s：为员工 %s 生成令牌和通知失败: %v\n", batchID, emp.EmployeeID, err)
+			localEnqueueFailed++
+			continue // 继续处理下一个员工
+		}
+		localEnqueued++
+	}
+
+	if localEnqueueFailed > 0 {
+		fmt.Printf("批处理 %s：%d 名员工的令牌和通知未能生成，批次保持 Pending，将在服务下次启动时重新生成\n", batchID, localEnqueueFailed)
+		return
+	}
+	if err := s.batchTaskRepo.UpdateCountsAndStatus(ctx, batchID, 0, 0, 0, 0, models.BatchTaskStatusInProgress, nil); err != nil {
+		fmt.Printf("批处理 %s：更新状态失败: %v\n", batchID, err)
+	}
+	fmt.Printf("批处理 %s 已生成通知。总员工: %d, 本次生成: %d, 此前已生成: %d, 生成失败: %d\n",
+		batchID, len(employees), localEnqueued, len(enqueuedEmployeeIDs), localRenderFailed)
 }
 
 // InitiateVerificationProcess 创建一个新的批处理任务并异步启动它
-func (s *verificationService) InitiateVerificationProcess(ctx context.Context, scopeType models.VerificationScopeType, scopeValues []string, durationDays int) (batchID string, err error) {
+func (s *verificationService) InitiateVerificationProcess(ctx context.Context, scopeType models.VerificationScopeType, scopeValues []string, includeSubDepartments bool, durationDays int, reminders ReminderOptions) (batchID string, err error) {
 	// 1. 查找员工 (预检查，获取总数，但不在这里处理每个员工的细节)
 	// 这一步主要是为了得到 TotalEmployeesToProcess 的初始值和校验请求是否有效
 	var preliminaryEmployees []models.Employee
@@ -351,7 +459,7 @@ func (s *verificationService) InitiateVerificationProcess(ctx context.Context, s
 		if len(scopeValues) == 0 {
 			return "", fmt.Errorf("部门名称列表不能为空")
 		}
-		preliminaryEmployees, err = s.employeeRepo.FindActiveByDepartmentNames(ctx, scopeValues)
+		preliminaryEmployees, err = s.findActiveEmployeesInDepartments(ctx, scopeValues, includeSubDepartments)
 	case models.VerificationScopeEmployeeIDs:
 		if len(scopeValues) == 0 {
 			return "", fmt.Errorf("员工ID列表不能为空")
@@ -380,6 +488,11 @@ func (s *verificationService) InitiateVerificationProcess(ctx context.Context, s
 		scopeValuesJSON = &s
 	}
 
+	reminderScheduleJSON, err := s.reminderScheduleJSON(reminders)
+	if err != nil {
+		return "", err
+	}
+
 	// 2. 创建 VerificationBatchTask 记录
 	newTask := &models.VerificationBatchTask{
 		// ID 会在 BeforeCreate hook 中生成
@@ -392,6 +505,8 @@ func (s *verificationService) InitiateVerificationProcess(ctx context.Context, s
 		RequestedScopeType:      scopeType,
 		RequestedScopeValues:    scopeValuesJSON,
 		RequestedDurationDays:   durationDays,
+		IncludeSubDepartments:   includeSubDepartments && scopeType == models.VerificationScopeDepartment,
+		ReminderSchedule:        reminderScheduleJSON,
 	}
 
 	if err := s.batchTaskRepo.Create(ctx, newTask); err != nil {
@@ -404,6 +519,56 @@ func (s *verificationService) InitiateVerificationProcess(ctx context.Context, s
 	return newTask.ID, nil
 }
 
+// reminderScheduleJSON 使用配置的默认值补全催办计划并序列化；计划中没有催办时间或最多催办次数为 0 时返回 nil，批次不自动催办
+func (s *verificationService) reminderScheduleJSON(reminders ReminderOptions) (*string, error) {
+	schedule := models.ReminderSchedule{
+		AfterSendDays:    s.appConfig.VerificationRemindAfterDays,
+		BeforeExpiryDays: s.appConfig.VerificationRemindBeforeExpiryDays,
+		MaxReminders:     s.appConfig.VerificationMaxReminders,
+	}
+	if reminders.AfterSendDays != nil {
+		schedule.AfterSendDays = reminders.AfterSendDays
+	}
+	if reminders.BeforeExpiryDays != nil {
+		schedule.BeforeExpiryDays = reminders.BeforeExpiryDays
+	}
+	if reminders.MaxReminders != nil {
+		schedule.MaxReminders = *reminders.MaxReminders
+	}
+	if schedule.MaxReminders <= 0 || (len(schedule.AfterSendDays) == 0 && len(schedule.BeforeExpiryDays) == 0) {
+		return nil, nil
+	}
+
+	jsonBytes, err := json.Marshal(schedule)
+	if err != nil {
+		return nil, fmt.Errorf("序列化催办计划失败: %w", err)
+	}
+	scheduleJSON := string(jsonBytes)
+	return &scheduleJSON, nil
+}
+
+// findActiveEmployeesInDepartments 按部门名称或别名查找在职员工，includeSubDepartments 为 true 时包含下级部门
+func (s *verificationService) findActiveEmployeesInDepartments(ctx context.Context, departmentNames []string, includeSubDepartments bool) ([]models.Employee, error) {
+	departmentIDs := make([]uint, 0, len(departmentNames))
+	for _, name := range departmentNames {
+		department, err := s.departmentRepo.FindByName(ctx, name)
+		if err != nil {
+			if errors.Is(err, repositories.ErrRecordNotFound) {
+				return nil, fmt.Errorf("%w: %s", ErrDepartmentNotFound, models.NormalizeDepartmentName(name))
+			}
+			return nil, err
+		}
+		departmentIDs = append(departmentIDs, department.ID)
+	}
+	if includeSubDepartments {
+		var err error
+		if departmentIDs, err = s.departmentRepo.DescendantIDs(ctx, departmentIDs); err != nil {
+			return nil, err
+		}
+	}
+	return s.employeeRepo.FindActiveByDepartmentIDs(ctx, departmentIDs)
+}
+
 // SubmitVerificationResult 提交号码确认结果
 func (s *verificationService) SubmitVerificationResult(ctx context.Context, token string, request *models.VerificationSubmission) error {
 	// 1. 验证token的有效性
@@ -425,6 +590,9 @@ func (s *verificationService) SubmitVerificationResult(ctx context.Context, toke
 		return ErrTokenExpired
 	}
 
+	// 员工通过确认链接提交，审计事件的操作者记为该员工
+	ctx = audit.WithActor(ctx, audit.Actor{Username: verificationToken.EmployeeID, Source: audit.SourceVerification})
+
 	// 收集需要创建的日志记录
 	var submissionLogs []*models.VerificationSubmissionLog
 
@@ -469,7 +637,7 @@ func (s *verificationService) SubmitVerificationResult(ctx context.Context, toke
 				updates := map[string]interface{}{
 					"purpose": verifiedNumber.Purpose,
 				}
-				if _, err := s.mobileNumberRepo.UpdateMobileNumber(verifiedNumber.MobileNumberId, updates); err != nil {
+				if _, err := s.mobileNumberRepo.UpdateMobileNumber(ctx, verifiedNumber.MobileNumberId, updates); err != nil {
 					return fmt.Errorf("更新号码用途失败: %w", err)
 				}
 			}
@@ -569,9 +737,14 @@ func (s *verificationService) SubmitVerificationResult(ctx context.Context, toke
 }
 
 // GetPhoneVerificationStatus 获取基于手机号码维度的管理员视图
-func (s *verificationService) GetPhoneVerificationStatus(ctx context.Context, employeeID, departmentName string) (*models.PhoneVerificationStatusResponse, error) {
+func (s *verificationService) GetPhoneVerificationStatus(ctx context.Context, employeeID string, department models.DepartmentFilter) (*models.PhoneVerificationStatusResponse, error) {
 	response := &models.PhoneVerificationStatusResponse{}
 
+	departmentIDs, err := s.departmentRepo.ResolveDepartmentIDs(ctx, department)
+	if err != nil {
+		return nil, fmt.Errorf("解析部门筛选条件失败: %w", err)
+	}
+
 	// 1. 获取统计摘要
 	// 1.0 计算系统中可用手机号码总数量（排除已注销）
 	totalCount, err := s.submissionLogRepo.CountTotalPhones(ctx)
@@ -620,21 +793,21 @@ func (s *verificationService) GetPhoneVerificationStatus(ctx context.Context, em
 	}
 
 	// 2. 获取未响应用户列表
-	pendingUsers, err := s.verificationTokenRepo.FindPendingTokensWithEmployeeInfo(ctx, employeeID, departmentName)
+	pendingUsers, err := s.verificationTokenRepo.FindPendingTokensWithEmployeeInfo(ctx, employeeID, departmentIDs)
 	if err != nil {
 		return nil, fmt.Errorf("获取未响应用户列表失败: %w", err)
 	}
 	response.PendingUsers = pendingUsers
 
 	// 3. 获取用户报告的问题列表
-	reportedIssues, err := s.userReportedIssueRepo.FindReportedIssuesWithDetails(ctx, employeeID, departmentName)
+	reportedIssues, err := s.userReportedIssueRepo.FindReportedIssuesWithDetails(ctx, employeeID, departmentIDs)
 	if err != nil {
 		return nil, fmt.Errorf("获取用户报告的问题列表失败: %w", err)
 	}
 	response.ReportedIssues = reportedIssues
 
 	// 4. 获取用户报告的未列出号码列表
-	unlistedNumbers, err := s.userReportedIssueRepo.FindUnlistedNumbersWithDetails(ctx, employeeID, departmentName)
+	unlistedNumbers, err := s.userReportedIssueRepo.FindUnlistedNumbersWithDetails(ctx, employeeID, departmentIDs)
 	if err != nil {
 		return nil, fmt.Errorf("获取用户报告的未列出号码列表失败: %w", err)
 	}
@@ -642,3 +815,173 @@ func (s *verificationService) GetPhoneVerificationStatus(ctx context.Context, em
 
 	return response, nil
 }
+
+// verificationMessage 按员工的通知语言渲染号码确认通知，列出员工名下登记使用的号码
+func (s *verificationService) verificationMessage(ctx context.Context, emp *models.Employee, link string, expiresAt time.Time, validDays int, reminder bool) (email.Message, error) {
+	assigned, err := s.mobileNumberRepo.FindAssignedToEmployee(ctx, emp.EmployeeID)
+	if err != nil {
+		return email.Message{}, fmt.Errorf("获取号码列表失败: %w", err)
+	}
+	numbers := make([]email.VerificationNumber, 0, len(assigned))
+	for _, n := range assigned {
+		numbers = append(numbers, email.VerificationNumber{PhoneNumber: n.PhoneNumber, Status: n.Status})
+	}
+
+	return s.templates.Render(ctx, email.TemplateVerification, emp.Locale, email.VerificationData{
+		EmployeeName: emp.FullName,
+		EmployeeID:   emp.EmployeeID,
+		Link:         link,
+		Numbers:      numbers,
+		ExpiresAt:    expiresAt,
+		ValidDays:    validDays,
+		Contact:      s.appConfig.VerificationContact,
+		Reminder:     reminder,
+	})
+}
+
+// RemindPendingEmployee 使用员工最近一次未过期的待确认令牌生成催办通知，与催办时间和次数在同一事务中加入发送队列，
+// 与自动催办一样由队列发送、失败重试并可在待发送通知列表中查看。
+// 令牌查询遵循 context 中的部门范围，部门负责人不能催办其所负责部门以外的员工
+func (s *verificationService) RemindPendingEmployee(ctx context.Context, employeeID string) (*models.PendingUserDetail, error) {
+	token, err := s.verificationTokenRepo.FindLatestPendingByEmployeeID(ctx, employeeID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNoPendingVerification
+		}
+		return nil, fmt.Errorf("查询待确认令牌失败: %w", err)
+	}
+
+	emp, err := s.employeeRepo.GetEmployeeByEmployeeID(employeeID)
+	if err != nil {
+		return nil, fmt.Errorf("查询员工信息失败: %w", err)
+	}
+
+	// 员工偏好的渠道不可用时改用邮件，两者都没有联系方式时通知无法发送
+	recipient, preferred := notificationRecipient(emp)
+	if recipient.Address(preferred) == "" && recipient.Email == "" {
+		return nil, ErrEmployeeEmailMissing
+	}
+
+	now := time.Now()
+	msg, err := s.reminderMessage(ctx, emp, token, now)
+	if err != nil {
+		return nil, fmt.Errorf("生成确认通知失败: %w", err)
+	}
+	if _, err := s.outboundRepo.EnqueueReminder(ctx, token, now, msg); err != nil {
+		return nil, fmt.Errorf("加入催办通知失败: %w", err)
+	}
+
+	// 同时有其他催办时本次未加入队列，返回的是其他催办记录后的状态
+	reminded, err := s.verificationTokenRepo.FindLatestPendingByEmployeeID(ctx, employeeID)
+	if err != nil {
+		return nil, fmt.Errorf("查询待确认令牌失败: %w", err)
+	}
+	return &models.PendingUserDetail{
+		EmployeeID:     emp.EmployeeID,
+		FullName:       emp.FullName,
+		Email:          emp.Email,
+		TokenID:        reminded.ID,
+		ExpiresAt:      &reminded.ExpiresAt,
+		LastRemindedAt: reminded.LastRemindedAt,
+		ReminderCount:  reminded.ReminderCount,
+	}, nil
+}
+
+// SendScheduledReminders 查找由确认批次生成、尚未提交任何确认结果的待确认令牌，按批次的催办计划将到期的催办通知加入发送队列，
+// 并记录催办时间和次数。催办次数已达上限、员工已离职或批次未设置催办计划的令牌会被跳过；单个令牌失败不影响其他令牌
+func (s *verificationService) SendScheduledReminders(ctx context.Context, now time.Time) (int, error) {
+	ctx = audit.WithSource(ctx, audit.SourceSystem)
+	tokens, err := s.verificationTokenRepo.FindUnansweredBatchTokens(ctx, now)
+	if err != nil {
+		return 0, fmt.Errorf("查询待催办令牌失败: %w", err)
+	}
+
+	schedules := make(map[string]*models.ReminderSchedule) // 按批次缓存催办计划，nil 表示不自动催办
+	var errs []error
+	enqueued := 0
+	for i := range tokens {
+		token := &tokens[i]
+		schedule, ok := schedules[*token.BatchID]
+		if !ok {
+			schedule, err = s.batchReminderSchedule(ctx, *token.BatchID)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			schedules[*token.BatchID] = schedule
+		}
+		if schedule == nil || !schedule.IsDue(token.CreatedAt, token.ExpiresAt, token.LastRemindedAt, token.ReminderCount, now) {
+			continue
+		}
+
+		emp, err := s.employeeRepo.GetEmployeeByEmployeeID(token.EmployeeID)
+		if err != nil {
+			if !errors.Is(err, repositories.ErrRecordNotFound) {
+				errs = append(errs, fmt.Errorf("查询员工 %s 失败: %w", token.EmployeeID, err))
+			}
+			continue
+		}
+		if emp.EmploymentStatus == "Departed" {
+			continue
+		}
+
+		msg, err := s.reminderMessage(ctx, emp, token, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("为员工 %s 生成催办通知失败: %w", token.EmployeeID, err))
+			continue
+		}
+		ok, err = s.outboundRepo.EnqueueReminder(ctx, token, now, msg)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("为员工 %s 加入催办通知失败: %w", token.EmployeeID, err))
+			continue
+		}
+		if ok {
+			enqueued++
+		}
+	}
+	return enqueued, errors.Join(errs...)
+}
+
+// reminderMessage 为待确认令牌生成催办通知，返回待加入发送队列的消息
+func (s *verificationService) reminderMessage(ctx context.Context, emp *models.Employee, token *models.VerificationToken, now time.Time) (*models.OutboundMessage, error) {
+	recipient, preferred := notificationRecipient(emp)
+	verificationLink := fmt.Sprintf("%s/verify-numbers?token=%s", s.appConfig.FrontendBaseURL, token.Token)
+	validDays := int(math.Ceil(token.ExpiresAt.Sub(now).Hours() / 24))
+	content, err := s.verificationMessage(ctx, emp, verificationLink, token.ExpiresAt, validDays, true)
+	if err != nil {
+		return nil, err
+	}
+	return &models.OutboundMessage{
+		Kind:           models.OutboundMessageKindVerificationReminder,
+		EmployeeID:     emp.EmployeeID,
+		RecipientName:  recipient.Name,
+		RecipientEmail: recipient.Email,
+		RecipientPhone: recipient.Phone,
+		Channel:        string(preferred),
+		Subject:        content.Subject,
+		HTMLBody:       content.HTML,
+		TextBody:       content.Text,
+		Status:         models.OutboundMessagePending,
+		MaxAttempts:    s.appConfig.OutboundMaxAttempts,
+		NextAttemptAt:  now,
+	}, nil
+}
+
+// batchReminderSchedule 返回确认批次的催办计划，批次不存在或未设置催办计划时返回 nil
+func (s *verificationService) batchReminderSchedule(ctx context.Context, batchID string) (*models.ReminderSchedule, error) {
+	task, err := s.batchTaskRepo.GetByID(ctx, batchID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("查询批处理任务 %s 失败: %w", batchID, err)
+	}
+	if task.ReminderSchedule == nil || *task.ReminderSchedule == "" {
+		return nil, nil
+	}
+	var schedule models.ReminderSchedule
+	if err := json.Unmarshal([]byte(*task.ReminderSchedule), &schedule); err != nil {
+		return nil, fmt.Errorf("解析批处理任务 %s 的催办计划失败: %w", batchID, err)
+	}
+	return &schedule, nil
+}
@@ -100,6 +100,20 @@ func InitDB() {
 		&models.UserReportedIssue{},
 		&models.VerificationBatchTask{},
 		&models.VerificationSubmissionLog{},
+		&models.RevokedToken{},
+		&models.RefreshToken{},
+		&models.RecoveryCode{},
+		&models.AuditEvent{},
+		&models.ScheduledDeparture{},
+		&models.EmploymentPeriod{},
+		&models.Department{},
+		&models.DepartmentAlias{},
+		&models.UserDepartment{},
+		&models.DirectorySyncRun{},
+		&models.HREvent{},
+		&models.HREventDeadLetter{},
+		&models.EmailTemplate{},
+		&models.OutboundMessage{},
 	)
 	if err != nil {
 		log.Fatalf("Failed to auto migrate database tables: %v", err)
@@ -0,0 +1,57 @@
+package email
+
+import (
+	"context"
+	"sync"
+)
+
+// CapturedMessage is a message recorded by a CaptureSender.
+type CapturedMessage struct {
+	To      Recipient
+	Message Message
+}
+
+// CaptureSender records messages in memory instead of delivering them. It is
+// meant for tests and for running without any notification backend.
+type CaptureSender struct {
+	mu   sync.Mutex
+	sent []CapturedMessage
+	err  error
+}
+
+// NewCaptureSender creates an empty CaptureSender.
+func NewCaptureSender() *CaptureSender {
+	return &CaptureSender{}
+}
+
+// Send records msg. If FailWith has been set, the message is not recorded and that error is returned.
+func (s *CaptureSender) Send(ctx context.Context, to Recipient, msg Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.sent = append(s.sent, CapturedMessage{To: to, Message: msg})
+	return nil
+}
+
+// FailWith makes subsequent sends fail with err; nil restores normal behaviour.
+func (s *CaptureSender) FailWith(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+// Sent returns a copy of the messages recorded so far.
+func (s *CaptureSender) Sent() []CapturedMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]CapturedMessage(nil), s.sent...)
+}
+
+// Reset discards the recorded messages.
+func (s *CaptureSender) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sent = nil
+}
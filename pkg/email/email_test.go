@@ -3,6 +3,7 @@ package email
 import (
 	"os"
 	"testing"
+	"time"
 )
 
 func TestSendVerificationEmail(t *testing.T) {
@@ -28,7 +29,12 @@ func TestSendVerificationEmail(t *testing.T) {
 		recipientEmail, os.Getenv("SMTP_HOST"), os.Getenv("SMTP_PORT"))
 	t.Log("Ensure SMTP environment variables are set: SMTP_HOST, SMTP_PORT, SMTP_SENDER_EMAIL, SMTP_USERNAME, SMTP_PASSWORD")
 
-	err := SendVerificationEmail(recipientEmail, employeeName, verificationLink)
+	err := SendVerificationEmail(recipientEmail, VerificationData{
+		EmployeeName: employeeName,
+		Link:         verificationLink,
+		ValidDays:    7,
+		ExpiresAt:    time.Now().AddDate(0, 0, 7),
+	})
 	if err != nil {
 		// 如果邮件发送失败，记录错误，并提示检查 SMTP 配置
 		t.Errorf("SendVerificationEmail failed: %v", err)
@@ -0,0 +1,126 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Channel identifies how a notification reaches its recipient.
+type Channel string
+
+const (
+	ChannelEmail Channel = "email" // SMTP email
+	ChannelIM    Channel = "im"    // Link-free nudge through an enterprise IM group robot (WeCom, DingTalk, Feishu)
+	ChannelSMS   Channel = "sms"   // Text message through an SMS gateway
+)
+
+// ErrChannelNotConfigured is returned when no sender is registered for a channel.
+var ErrChannelNotConfigured = errors.New("notification channel is not configured")
+
+// ErrNoAddress is returned when the recipient has no address usable on a channel,
+// e.g. no email address for ChannelEmail or no phone number for ChannelSMS.
+var ErrNoAddress = errors.New("recipient has no address for the notification channel")
+
+// Recipient describes who a notification is sent to.
+type Recipient struct {
+	Name  string
+	Email string
+	Phone string // Used by ChannelSMS, and to mention the recipient on ChannelIM
+}
+
+// Message is a channel-neutral notification. Email uses Subject and HTML, with
+// Text as the plain-text alternative; SMS uses Text. IM posts only Subject, so
+// Subject must not contain links or other content meant for the recipient alone.
+type Message struct {
+	Subject string
+	HTML    string
+	Text    string
+}
+
+// Sender delivers messages over a single channel.
+type Sender interface {
+	// Send delivers msg to the recipient, returning ErrNoAddress when the
+	// recipient cannot be reached on this channel.
+	Send(ctx context.Context, to Recipient, msg Message) error
+}
+
+// Address returns the recipient's address on the given channel, or "" if there is none.
+func (r Recipient) Address(channel Channel) string {
+	switch channel {
+	case ChannelEmail:
+		return r.Email
+	case ChannelIM, ChannelSMS:
+		return r.Phone
+	}
+	return ""
+}
+
+// Notifier delivers messages over a per-recipient channel.
+type Notifier interface {
+	// Notify delivers msg over the preferred channel. When that channel is not
+	// configured or the recipient has no address on it, the notifier falls back
+	// to its default channel. It returns the channel actually used.
+	//
+	// Group robot messages are visible to everyone in the group, so ChannelIM
+	// only carries a nudge: the full message is delivered over the default
+	// channel as well, and IM is usable only when that channel is.
+	Notify(ctx context.Context, to Recipient, preferred Channel, msg Message) (Channel, error)
+}
+
+// channelNotifier routes each message to the Sender registered for the chosen channel.
+type channelNotifier struct {
+	senders  map[Channel]Sender
+	fallback Channel
+}
+
+// NewNotifier creates a Notifier that picks a sender from senders per recipient,
+// falling back to the fallback channel.
+func NewNotifier(senders map[Channel]Sender, fallback Channel) Notifier {
+	registered := make(map[Channel]Sender, len(senders))
+	for channel, sender := range senders {
+		if sender != nil {
+			registered[channel] = sender
+		}
+	}
+	return &channelNotifier{senders: registered, fallback: fallback}
+}
+
+// Notify delivers msg over the preferred channel or the fallback channel.
+func (n *channelNotifier) Notify(ctx context.Context, to Recipient, preferred Channel, msg Message) (Channel, error) {
+	channel := n.choose(to, preferred)
+	sender, ok := n.senders[channel]
+	if !ok {
+		return channel, fmt.Errorf("%w: %s", ErrChannelNotConfigured, channel)
+	}
+	if to.Address(channel) == "" {
+		return channel, fmt.Errorf("%w: %s", ErrNoAddress, channel)
+	}
+	if err := sender.Send(ctx, to, msg); err != nil {
+		return channel, err
+	}
+	if channel == ChannelIM {
+		// Send the nudge first: if the full message then fails, a retry repeats only the nudge
+		if err := n.senders[n.fallback].Send(ctx, to, msg); err != nil {
+			return channel, err
+		}
+	}
+	return channel, nil
+}
+
+// choose returns the preferred channel if it is usable for the recipient, otherwise the fallback.
+func (n *channelNotifier) choose(to Recipient, preferred Channel) Channel {
+	if preferred == "" {
+		return n.fallback
+	}
+	if _, ok := n.senders[preferred]; !ok || to.Address(preferred) == "" {
+		return n.fallback
+	}
+	if preferred == ChannelIM {
+		// The nudge is useless without the full message on the recipient's own channel
+		if _, ok := n.senders[n.fallback]; !ok || to.Address(n.fallback) == "" {
+			return n.fallback
+		}
+	}
+	return preferred
+}
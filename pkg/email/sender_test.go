@@ -0,0 +1,178 @@
+package email
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNotifierChoosesChannel(t *testing.T) {
+	mail, sms := NewCaptureSender(), NewCaptureSender()
+	notifier := NewNotifier(map[Channel]Sender{ChannelEmail: mail, ChannelSMS: sms}, ChannelEmail)
+	msg := Message{Subject: "hi", HTML: "<p>hi</p>", Text: "hi"}
+
+	tests := []struct {
+		name      string
+		to        Recipient
+		preferred Channel
+		want      Channel
+		wantErr   error
+	}{
+		{"preferred channel", Recipient{Email: "a@example.com", Phone: "13800000001"}, ChannelSMS, ChannelSMS, nil},
+		{"no preference", Recipient{Email: "a@example.com"}, "", ChannelEmail, nil},
+		{"missing phone falls back", Recipient{Email: "a@example.com"}, ChannelSMS, ChannelEmail, nil},
+		{"unconfigured channel falls back", Recipient{Email: "a@example.com", Phone: "13800000001"}, ChannelIM, ChannelEmail, nil},
+		{"no address", Recipient{Phone: "13800000001"}, ChannelIM, ChannelEmail, ErrNoAddress},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := notifier.Notify(context.Background(), tt.to, tt.preferred, msg)
+			if got != tt.want {
+				t.Errorf("channel = %q, want %q", got, tt.want)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if n := len(sms.Sent()); n != 1 {
+		t.Errorf("sms sent %d messages, want 1", n)
+	}
+	if n := len(mail.Sent()); n != 3 {
+		t.Errorf("email sent %d messages, want 3", n)
+	}
+}
+
+func TestNotifierSendsIMNudgeWithFullMessage(t *testing.T) {
+	mail, im := NewCaptureSender(), NewCaptureSender()
+	notifier := NewNotifier(map[Channel]Sender{ChannelEmail: mail, ChannelIM: im}, ChannelEmail)
+	msg := Message{Subject: "hi", HTML: "<p>hi</p>", Text: "hi"}
+
+	got, err := notifier.Notify(context.Background(), Recipient{Email: "a@example.com", Phone: "13800000001"}, ChannelIM, msg)
+	if err != nil || got != ChannelIM {
+		t.Fatalf("Notify = %q, %v, want im", got, err)
+	}
+	if len(im.Sent()) != 1 || len(mail.Sent()) != 1 {
+		t.Fatalf("sent %d nudges and %d emails, want 1 and 1", len(im.Sent()), len(mail.Sent()))
+	}
+
+	// Without an email address the recipient would only get the nudge
+	if _, err := notifier.Notify(context.Background(), Recipient{Phone: "13800000001"}, ChannelIM, msg); !errors.Is(err, ErrNoAddress) {
+		t.Fatalf("err = %v, want ErrNoAddress", err)
+	}
+	if len(im.Sent()) != 1 {
+		t.Errorf("sent %d nudges, want 1", len(im.Sent()))
+	}
+}
+
+func TestNotifierReturnsSendError(t *testing.T) {
+	mail := NewCaptureSender()
+	mail.FailWith(errors.New("boom"))
+	notifier := NewNotifier(map[Channel]Sender{ChannelEmail: mail}, ChannelEmail)
+	if _, err := notifier.Notify(context.Background(), Recipient{Email: "a@example.com"}, ChannelEmail, Message{}); err == nil || err.Error() != "boom" {
+		t.Fatalf("err = %v, want boom", err)
+	}
+	if _, err := NewNotifier(nil, ChannelEmail).Notify(context.Background(), Recipient{Email: "a@example.com"}, "", Message{}); !errors.Is(err, ErrChannelNotConfigured) {
+		t.Fatalf("err = %v, want ErrChannelNotConfigured", err)
+	}
+}
+
+func TestIMWebhookSender(t *testing.T) {
+	tests := []struct {
+		flavor   IMFlavor
+		secret   string
+		response string
+		check    func(t *testing.T, r *http.Request, body map[string]interface{})
+		wantErr  bool
+	}{
+		{IMFlavorWeCom, "", `{"errcode":0,"errmsg":"ok"}`, func(t *testing.T, r *http.Request, body map[string]interface{}) {
+			text := body["text"].(map[string]interface{})
+			if body["msgtype"] != "text" || text["content"] != "subject" || text["mentioned_mobile_list"].([]interface{})[0] != "13800000001" {
+				t.Errorf("unexpected wecom payload: %v", body)
+			}
+		}, false},
+		{IMFlavorDingTalk, "sec", `{"errcode":0,"errmsg":"ok"}`, func(t *testing.T, r *http.Request, body map[string]interface{}) {
+			at := body["at"].(map[string]interface{})
+			if at["atMobiles"].([]interface{})[0] != "13800000001" {
+				t.Errorf("unexpected dingtalk payload: %v", body)
+			}
+			want := signBase64([]byte("sec"), "1700000000000\nsec")
+			if r.URL.Query().Get("timestamp") != "1700000000000" || r.URL.Query().Get("sign") != want {
+				t.Errorf("unexpected dingtalk signature: %s", r.URL.RawQuery)
+			}
+		}, false},
+		{IMFlavorFeishu, "sec", `{"code":0,"msg":"success"}`, func(t *testing.T, r *http.Request, body map[string]interface{}) {
+			content := body["content"].(map[string]interface{})
+			if body["msg_type"] != "text" || content["text"] != "subject" {
+				t.Errorf("unexpected feishu payload: %v", body)
+			}
+			if body["timestamp"] != "1700000000" || body["sign"] != signBase64([]byte("1700000000\nsec"), "") {
+				t.Errorf("unexpected feishu signature: %v", body)
+			}
+		}, false},
+		{IMFlavorWeCom, "", `{"errcode":93000,"errmsg":"invalid webhook url"}`, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.flavor), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var body map[string]interface{}
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Errorf("decode request: %v", err)
+				}
+				if tt.check != nil {
+					tt.check(t, r, body)
+				}
+				w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			sender, err := NewIMWebhookSender(server.URL+"/robot/send", tt.flavor, tt.secret, time.Second)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sender.now = func() time.Time { return time.UnixMilli(1700000000000) }
+			err = sender.Send(context.Background(), Recipient{Phone: "13800000001"}, Message{Subject: "subject", Text: "hello https://example.com/verify/token"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := NewIMWebhookSender("http://example.com", "slack", "", time.Second); err == nil {
+		t.Error("expected error for unsupported flavor")
+	}
+}
+
+func TestSMSGatewaySender(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		if r.Header.Get("Authorization") != "Bearer tok" || body["phoneNumber"] != "13800000001" || body["message"] != "hello" {
+			t.Errorf("unexpected request: %v %v", r.Header, body)
+		}
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	sender, err := NewSMSGatewaySender(server.URL, "tok", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	to := Recipient{Phone: "13800000001"}
+	if err := sender.Send(context.Background(), to, Message{Text: "hello"}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	status = http.StatusBadGateway
+	if err := sender.Send(context.Background(), to, Message{Text: "hello"}); err == nil {
+		t.Fatal("expected error for non-2xx response")
+	}
+	if err := sender.Send(context.Background(), Recipient{}, Message{Text: "hello"}); !errors.Is(err, ErrNoAddress) {
+		t.Fatalf("err = %v, want ErrNoAddress", err)
+	}
+}
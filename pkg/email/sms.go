@@ -0,0 +1,47 @@
+package email
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// SMSGatewaySender sends text messages through an HTTP SMS gateway. Each message is
+// posted as {"phoneNumber": "...", "message": "..."} with an optional bearer token;
+// any 2xx response counts as accepted.
+type SMSGatewaySender struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+// NewSMSGatewaySender creates an SMSGatewaySender for the given gateway URL.
+func NewSMSGatewaySender(gatewayURL string, token string, timeout time.Duration) (*SMSGatewaySender, error) {
+	if _, err := url.ParseRequestURI(gatewayURL); err != nil {
+		return nil, fmt.Errorf("invalid SMS gateway URL: %w", err)
+	}
+	return &SMSGatewaySender{url: gatewayURL, token: token, client: &http.Client{Timeout: timeout}}, nil
+}
+
+// Send posts msg.Text to the recipient's phone number.
+func (s *SMSGatewaySender) Send(ctx context.Context, to Recipient, msg Message) error {
+	if to.Phone == "" {
+		return ErrNoAddress
+	}
+
+	body, err := json.Marshal(map[string]string{"phoneNumber": to.Phone, "message": msg.Text})
+	if err != nil {
+		return err
+	}
+	header := http.Header{}
+	if s.token != "" {
+		header.Set("Authorization", "Bearer "+s.token)
+	}
+	if err := postJSON(ctx, s.client, s.url, header, body, nil); err != nil {
+		return fmt.Errorf("SMS gateway request failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,519 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SMTPSecurity selects how the connection to the SMTP server is protected.
+type SMTPSecurity string
+
+const (
+	SMTPSecurityTLS      SMTPSecurity = "tls"      // Implicit TLS from the first byte, usually port 465
+	SMTPSecuritySTARTTLS SMTPSecurity = "starttls" // Plain connection upgraded with STARTTLS, usually port 587
+	SMTPSecurityNone     SMTPSecurity = "none"     // No encryption, for internal relays on port 25
+)
+
+// SMTPAuthMechanism selects the SASL mechanism used to authenticate.
+type SMTPAuthMechanism string
+
+const (
+	SMTPAuthPlain   SMTPAuthMechanism = "plain"
+	SMTPAuthLogin   SMTPAuthMechanism = "login"
+	SMTPAuthCRAMMD5 SMTPAuthMechanism = "cram-md5"
+	SMTPAuthNone    SMTPAuthMechanism = "none"
+)
+
+// Defaults applied to zero-valued SMTPConfig fields.
+const (
+	defaultSMTPTimeout            = 30 * time.Second
+	defaultSMTPIdleTimeout        = 30 * time.Second
+	defaultSMTPMaxMessagesPerConn = 100
+)
+
+// SMTPConfig holds the SMTP server configuration
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string // Authentication is skipped when empty
+	Password string
+	Sender   string
+
+	Security           SMTPSecurity      // Defaults from the port: 465 uses TLS, 25 uses none, anything else STARTTLS
+	AuthMechanism      SMTPAuthMechanism // Defaults to plain
+	Timeout            time.Duration     // Dial timeout and I/O deadline for each message
+	IdleTimeout        time.Duration     // How long an unused connection is kept open
+	MaxMessagesPerConn int               // Reconnect after this many messages, as many servers cap a session
+}
+
+// LoadSMTPConfigFromEnv loads SMTP configuration from environment variables.
+func LoadSMTPConfigFromEnv() (*SMTPConfig, error) {
+	host := os.Getenv("SMTP_HOST")
+	portStr := os.Getenv("SMTP_PORT")
+	sender := os.Getenv("SMTP_SENDER_EMAIL")
+
+	if host == "" || portStr == "" || sender == "" {
+		return nil, fmt.Errorf("SMTP_HOST, SMTP_PORT, and SMTP_SENDER_EMAIL must be set")
+	}
+
+	port, err := strconv.Atoi(portStr)
+	if err != nil || port <= 0 || port > 65535 {
+		return nil, fmt.Errorf("invalid SMTP_PORT: %q", portStr)
+	}
+
+	config := &SMTPConfig{
+		Host:          host,
+		Port:          port,
+		Username:      os.Getenv("SMTP_USERNAME"),
+		Password:      os.Getenv("SMTP_PASSWORD"),
+		Sender:        sender,
+		Security:      SMTPSecurity(strings.ToLower(os.Getenv("SMTP_SECURITY"))),
+		AuthMechanism: SMTPAuthMechanism(strings.ToLower(os.Getenv("SMTP_AUTH_MECHANISM"))),
+	}
+	if config.Timeout, err = durationEnv("SMTP_TIMEOUT"); err != nil {
+		return nil, err
+	}
+	if config.IdleTimeout, err = durationEnv("SMTP_IDLE_TIMEOUT"); err != nil {
+		return nil, err
+	}
+	if value := os.Getenv("SMTP_MAX_MESSAGES_PER_CONN"); value != "" {
+		if config.MaxMessagesPerConn, err = strconv.Atoi(value); err != nil || config.MaxMessagesPerConn <= 0 {
+			return nil, fmt.Errorf("invalid SMTP_MAX_MESSAGES_PER_CONN: %q", value)
+		}
+	}
+
+	config.setDefaults()
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// durationEnv reads an optional Go duration from the environment, returning 0 when unset.
+func durationEnv(key string) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid %s: %q", key, value)
+	}
+	return d, nil
+}
+
+// setDefaults fills in zero-valued fields.
+func (c *SMTPConfig) setDefaults() {
+	if c.Security == "" {
+		switch c.Port {
+		case 465:
+			c.Security = SMTPSecurityTLS
+		case 25:
+			c.Security = SMTPSecurityNone
+		default:
+			c.Security = SMTPSecuritySTARTTLS
+		}
+	}
+	if c.AuthMechanism == "" {
+		c.AuthMechanism = SMTPAuthPlain
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = defaultSMTPTimeout
+	}
+	if c.IdleTimeout <= 0 {
+		c.IdleTimeout = defaultSMTPIdleTimeout
+	}
+	if c.MaxMessagesPerConn <= 0 {
+		c.MaxMessagesPerConn = defaultSMTPMaxMessagesPerConn
+	}
+}
+
+// validate rejects unknown security modes and auth mechanisms.
+func (c *SMTPConfig) validate() error {
+	switch c.Security {
+	case SMTPSecurityTLS, SMTPSecuritySTARTTLS, SMTPSecurityNone:
+	default:
+		return fmt.Errorf("invalid SMTP security %q: must be tls, starttls or none", c.Security)
+	}
+	switch c.AuthMechanism {
+	case SMTPAuthPlain, SMTPAuthLogin, SMTPAuthCRAMMD5, SMTPAuthNone:
+	default:
+		return fmt.Errorf("invalid SMTP auth mechanism %q: must be plain, login, cram-md5 or none", c.AuthMechanism)
+	}
+	return nil
+}
+
+var (
+	defaultSenderOnce sync.Once
+	defaultSender     *SMTPSender
+	defaultSenderErr  error
+)
+
+// DefaultSMTPSender returns the process-wide SMTPSender configured from the environment.
+// The environment is read once, and all callers share the sender's connection.
+func DefaultSMTPSender() (*SMTPSender, error) {
+	defaultSenderOnce.Do(func() {
+		config, err := LoadSMTPConfigFromEnv()
+		if err != nil {
+			defaultSenderErr = fmt.Errorf("failed to load SMTP config: %w", err)
+			return
+		}
+		defaultSender = NewSMTPSender(config)
+	})
+	return defaultSender, defaultSenderErr
+}
+
+// SMTPSender delivers messages as HTML email. It keeps one authenticated session
+// open and reuses it for consecutive messages, so a batch only pays for one
+// handshake. Sends are serialized; the session is closed after IdleTimeout without
+// use, after MaxMessagesPerConn messages, or by Close.
+type SMTPSender struct {
+	config    SMTPConfig
+	tlsConfig *tls.Config
+
+	mu       chan struct{} // Held while using the session; a channel so waiting for it honours the send context
+	client   *smtp.Client
+	conn     net.Conn
+	sent     int       // Messages sent on the current session
+	lastUsed time.Time // When the current session last finished a message
+	idle     *time.Timer
+}
+
+// NewSMTPSender creates an SMTPSender using the given server configuration.
+func NewSMTPSender(config *SMTPConfig) *SMTPSender {
+	c := *config
+	c.setDefaults()
+	return &SMTPSender{
+		config: c,
+		mu:     make(chan struct{}, 1),
+		tlsConfig: &tls.Config{
+			ServerName: c.Host,
+			MinVersion: tls.VersionTLS12, // Explicitly set minimum TLS version
+		},
+	}
+}
+
+// Send emails msg to the recipient's email address.
+func (s *SMTPSender) Send(ctx context.Context, to Recipient, msg Message) error {
+	if to.Email == "" {
+		return ErrNoAddress
+	}
+	return s.SendTo(ctx, []string{to.Email}, msg)
+}
+
+// SendTo emails msg to all the given addresses in a single message.
+func (s *SMTPSender) SendTo(ctx context.Context, toEmails []string, msg Message) error {
+	if len(toEmails) == 0 {
+		return ErrNoAddress
+	}
+	fullMsg, err := buildMessage(s.config.Sender, toEmails, msg)
+	if err != nil {
+		return err
+	}
+
+	// Waiting behind other sends counts against the caller's deadline
+	select {
+	case s.mu <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer s.unlock()
+	if s.idle != nil {
+		s.idle.Stop()
+	}
+
+	reused := s.client != nil
+	err = s.deliver(ctx, toEmails, fullMsg)
+	if err != nil && reused && retryable(err) {
+		// The server may have dropped the idle session; retry once on a fresh connection
+		s.closeLocked()
+		err = s.deliver(ctx, toEmails, fullMsg)
+	}
+	if err != nil {
+		s.abortLocked(err)
+		return err
+	}
+
+	s.sent++
+	s.lastUsed = time.Now()
+	if s.sent >= s.config.MaxMessagesPerConn {
+		s.quitLocked()
+	} else {
+		s.idle = time.AfterFunc(s.config.IdleTimeout, s.closeIdle)
+	}
+	return nil
+}
+
+// Close ends the current session, if any. The sender reconnects on the next Send.
+func (s *SMTPSender) Close() error {
+	s.lock()
+	defer s.unlock()
+	if s.idle != nil {
+		s.idle.Stop()
+	}
+	return s.quitLocked()
+}
+
+// deliver sends one message on the current session, connecting first if needed.
+func (s *SMTPSender) deliver(ctx context.Context, toEmails []string, fullMsg []byte) error {
+	if s.client == nil {
+		if err := s.connect(ctx); err != nil {
+			return err
+		}
+	}
+	s.conn.SetDeadline(s.deadline(ctx))
+
+	if err := s.client.Mail(s.config.Sender); err != nil {
+		return fmt.Errorf("SMTP mail from failed: %w", err)
+	}
+	for _, toEmail := range toEmails {
+		if err := s.client.Rcpt(toEmail); err != nil {
+			return fmt.Errorf("SMTP rcpt to %s failed: %w", toEmail, err)
+		}
+	}
+
+	w, err := s.client.Data()
+	if err != nil {
+		return fmt.Errorf("SMTP data command failed: %w", err)
+	}
+	if _, err := w.Write(fullMsg); err != nil {
+		return fmt.Errorf("failed to write email body: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to close email data writer: %w", err)
+	}
+	return nil
+}
+
+// connect dials the server, negotiates TLS as configured and authenticates.
+func (s *SMTPSender) connect(ctx context.Context) error {
+	addr := net.JoinHostPort(s.config.Host, strconv.Itoa(s.config.Port))
+	dialer := &net.Dialer{Timeout: s.config.Timeout}
+
+	var conn net.Conn
+	var err error
+	if s.config.Security == SMTPSecurityTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, s.tlsConfig)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server %s: %w", addr, err)
+	}
+	conn.SetDeadline(s.deadline(ctx))
+
+	client, err := smtp.NewClient(conn, s.config.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to create SMTP client: %w", err)
+	}
+
+	if s.config.Security == SMTPSecuritySTARTTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			client.Close()
+			return errors.New("SMTP server does not support STARTTLS")
+		}
+		if err := client.StartTLS(s.tlsConfig); err != nil {
+			client.Close()
+			return fmt.Errorf("SMTP STARTTLS failed: %w", err)
+		}
+	}
+
+	if auth := s.auth(); auth != nil {
+		if err := client.Auth(auth); err != nil {
+			client.Close()
+			return fmt.Errorf("SMTP auth failed: %w", err)
+		}
+	}
+
+	s.client, s.conn, s.sent = client, conn, 0
+	return nil
+}
+
+// auth returns the configured authentication, or nil when no username is set.
+func (s *SMTPSender) auth() smtp.Auth {
+	if s.config.Username == "" {
+		return nil
+	}
+	switch s.config.AuthMechanism {
+	case SMTPAuthNone:
+		return nil
+	case SMTPAuthLogin:
+		return &loginAuth{username: s.config.Username, password: s.config.Password, host: s.config.Host}
+	case SMTPAuthCRAMMD5:
+		return smtp.CRAMMD5Auth(s.config.Username, s.config.Password)
+	default:
+		return smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)
+	}
+}
+
+// deadline returns the I/O deadline for the next exchange, bounded by ctx.
+func (s *SMTPSender) deadline(ctx context.Context) time.Time {
+	deadline := time.Now().Add(s.config.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	return deadline
+}
+
+// retryable reports whether a failure on a reused session may succeed on a new one:
+// network errors and 421 (service closing) replies, but not other server replies.
+func retryable(err error) bool {
+	var replyErr *textproto.Error
+	if errors.As(err, &replyErr) {
+		return replyErr.Code == 421
+	}
+	return true
+}
+
+// abortLocked leaves the session ready for the next message after a failure. A rejected
+// message (a server reply) only needs RSET; anything else leaves the session in an unknown state.
+func (s *SMTPSender) abortLocked(err error) {
+	if s.client == nil {
+		return
+	}
+	var replyErr *textproto.Error
+	if errors.As(err, &replyErr) && replyErr.Code != 421 && s.client.Reset() == nil {
+		s.lastUsed = time.Now()
+		s.idle = time.AfterFunc(s.config.IdleTimeout, s.closeIdle)
+		return
+	}
+	s.closeLocked()
+}
+
+// closeIdle ends the session if it has not been used for IdleTimeout.
+func (s *SMTPSender) closeIdle() {
+	s.lock()
+	defer s.unlock()
+	if s.client != nil && time.Since(s.lastUsed) >= s.config.IdleTimeout {
+		s.quitLocked()
+	}
+}
+
+// lock takes the session lock, waiting as long as needed.
+func (s *SMTPSender) lock() {
+	s.mu <- struct{}{}
+}
+
+// unlock releases the session lock.
+func (s *SMTPSender) unlock() {
+	<-s.mu
+}
+
+// quitLocked ends the session politely with QUIT.
+func (s *SMTPSender) quitLocked() error {
+	if s.client == nil {
+		return nil
+	}
+	s.conn.SetDeadline(time.Now().Add(s.config.Timeout))
+	err := s.client.Quit()
+	s.closeLocked()
+	return err
+}
+
+// closeLocked drops the connection without QUIT.
+func (s *SMTPSender) closeLocked() {
+	if s.client != nil {
+		s.client.Close()
+	}
+	s.client, s.conn, s.sent = nil, nil, 0
+}
+
+// buildMessage formats msg as a MIME message. When msg has both HTML and Text it is sent
+// as multipart/alternative so clients that do not render HTML show the plain text.
+func buildMessage(from string, toEmails []string, msg Message) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("To: " + strings.Join(toEmails, ", ") + "\r\n")
+	buf.WriteString("From: " + from + "\r\n")
+	buf.WriteString("Subject: " + mime.BEncoding.Encode("UTF-8", msg.Subject) + "\r\n")
+	buf.WriteString("MIME-Version: 1.0\r\n")
+
+	if msg.HTML == "" || msg.Text == "" {
+		contentType, body := "text/html", msg.HTML
+		if msg.HTML == "" {
+			contentType, body = "text/plain", msg.Text
+		}
+		buf.WriteString("Content-Type: " + contentType + "; charset=\"UTF-8\"\r\n")
+		buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+		if err := writeQuotedPrintable(&buf, body); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	mw := multipart.NewWriter(&buf)
+	buf.WriteString("Content-Type: multipart/alternative; boundary=\"" + mw.Boundary() + "\"\r\n\r\n")
+	// Parts are ordered from least to most preferred (RFC 2046)
+	for _, part := range []struct{ contentType, body string }{{"text/plain", msg.Text}, {"text/html", msg.HTML}} {
+		w, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType + "; charset=\"UTF-8\""},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeQuotedPrintable(w, part.body); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeQuotedPrintable(w io.Writer, body string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(body)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+// loginAuth implements the LOGIN mechanism, which net/smtp does not provide.
+// Like smtp.PlainAuth, it only sends credentials over TLS or to localhost.
+type loginAuth struct {
+	username, password, host string
+}
+
+func (a *loginAuth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	if !server.TLS && !isLocalhost(server.Name) {
+		return "", nil, errors.New("unencrypted connection")
+	}
+	if server.Name != a.host {
+		return "", nil, errors.New("wrong host name")
+	}
+	return "LOGIN", nil, nil
+}
+
+func (a *loginAuth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if !more {
+		return nil, nil
+	}
+	switch strings.ToLower(strings.TrimSpace(string(fromServer))) {
+	case "username:":
+		return []byte(a.username), nil
+	case "password:":
+		return []byte(a.password), nil
+	}
+	return nil, fmt.Errorf("unexpected LOGIN challenge %q", fromServer)
+}
+
+func isLocalhost(name string) bool {
+	return name == "localhost" || name == "127.0.0.1" || name == "::1"
+}
@@ -0,0 +1,349 @@
+package email
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSMTPServer is a minimal SMTP server that records sessions and messages.
+type fakeSMTPServer struct {
+	ln        net.Listener
+	tlsConfig *tls.Config // Offers STARTTLS when set
+	dropAfter int         // Drops each connection after this many messages when > 0
+
+	mu       sync.Mutex
+	conns    int
+	quits    int
+	messages []string
+	authUser string
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &fakeSMTPServer{ln: ln}
+	go s.serve()
+	t.Cleanup(func() { ln.Close() })
+	return s
+}
+
+func (s *fakeSMTPServer) config() *SMTPConfig {
+	addr := s.ln.Addr().(*net.TCPAddr)
+	return &SMTPConfig{Host: "127.0.0.1", Port: addr.Port, Sender: "noreply@example.com", Security: SMTPSecurityNone, Timeout: time.Second}
+}
+
+func (s *fakeSMTPServer) stats() (conns, quits, messages int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.conns, s.quits, len(s.messages)
+}
+
+func (s *fakeSMTPServer) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns++
+		s.mu.Unlock()
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeSMTPServer) handle(conn net.Conn) {
+	defer conn.Close()
+	tc := textproto.NewConn(conn)
+	tc.PrintfLine("220 localhost ESMTP")
+	secure, sent := false, 0
+	for {
+		line, err := tc.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			tc.PrintfLine("250-localhost")
+			if s.tlsConfig != nil && !secure {
+				tc.PrintfLine("250-STARTTLS")
+			}
+			tc.PrintfLine("250 AUTH PLAIN LOGIN")
+		case "STARTTLS":
+			tc.PrintfLine("220 Ready to start TLS")
+			tlsConn := tls.Server(conn, s.tlsConfig)
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			conn, tc, secure = tlsConn, textproto.NewConn(tlsConn), true
+		case "AUTH":
+			user, ok := s.auth(tc, arg)
+			if !ok {
+				tc.PrintfLine("535 Authentication failed")
+				continue
+			}
+			s.mu.Lock()
+			s.authUser = user
+			s.mu.Unlock()
+			tc.PrintfLine("235 Authentication successful")
+		case "RCPT":
+			if strings.Contains(arg, "reject@") {
+				tc.PrintfLine("550 No such user")
+				continue
+			}
+			tc.PrintfLine("250 OK")
+		case "DATA":
+			tc.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+			data, err := tc.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, string(data))
+			s.mu.Unlock()
+			tc.PrintfLine("250 OK")
+			if sent++; s.dropAfter > 0 && sent >= s.dropAfter {
+				return
+			}
+		case "QUIT":
+			s.mu.Lock()
+			s.quits++
+			s.mu.Unlock()
+			tc.PrintfLine("221 Bye")
+			return
+		default: // MAIL, RSET, NOOP
+			tc.PrintfLine("250 OK")
+		}
+	}
+}
+
+// auth handles AUTH PLAIN and AUTH LOGIN, accepting the password "secret".
+func (s *fakeSMTPServer) auth(tc *textproto.Conn, arg string) (string, bool) {
+	mechanism, initial, _ := strings.Cut(arg, " ")
+	switch strings.ToUpper(mechanism) {
+	case "PLAIN":
+		raw, _ := base64.StdEncoding.DecodeString(initial)
+		parts := strings.Split(string(raw), "\x00")
+		if len(parts) != 3 {
+			return "", false
+		}
+		return parts[1], parts[2] == "secret"
+	case "LOGIN":
+		tc.PrintfLine("334 %s", base64.StdEncoding.EncodeToString([]byte("Username:")))
+		line, _ := tc.ReadLine()
+		user, _ := base64.StdEncoding.DecodeString(line)
+		tc.PrintfLine("334 %s", base64.StdEncoding.EncodeToString([]byte("Password:")))
+		line, _ = tc.ReadLine()
+		password, _ := base64.StdEncoding.DecodeString(line)
+		return string(user), string(password) == "secret"
+	}
+	return "", false
+}
+
+// selfSignedTLS returns server and client TLS configs for 127.0.0.1.
+func selfSignedTLS(t *testing.T) (server, client *tls.Config) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	server = &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}}
+	client = &tls.Config{ServerName: "127.0.0.1", RootCAs: pool}
+	return server, client
+}
+
+func sendN(t *testing.T, sender *SMTPSender, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		if err := sender.Send(context.Background(), Recipient{Email: "a@example.com"}, Message{Subject: "hi", HTML: "<p>hi</p>"}); err != nil {
+			t.Fatalf("Send #%d: %v", i+1, err)
+		}
+	}
+}
+
+func TestSMTPSenderReusesConnection(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	sender := NewSMTPSender(server.config())
+	defer sender.Close()
+
+	sendN(t, sender, 3)
+	if conns, _, messages := server.stats(); conns != 1 || messages != 3 {
+		t.Fatalf("conns = %d, messages = %d, want 1 and 3", conns, messages)
+	}
+
+	// A rejected recipient resets the session instead of dropping it
+	err := sender.SendTo(context.Background(), []string{"reject@example.com"}, Message{Subject: "hi"})
+	if err == nil {
+		t.Fatal("expected error for rejected recipient")
+	}
+	sendN(t, sender, 1)
+	if conns, _, messages := server.stats(); conns != 1 || messages != 4 {
+		t.Fatalf("conns = %d, messages = %d, want 1 and 4", conns, messages)
+	}
+
+	if err := sender.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if _, quits, _ := server.stats(); quits != 1 {
+		t.Fatalf("quits = %d, want 1", quits)
+	}
+}
+
+func TestSMTPSenderReconnectsAfterDrop(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	server.dropAfter = 2
+	sender := NewSMTPSender(server.config())
+	defer sender.Close()
+
+	sendN(t, sender, 5)
+	if conns, _, messages := server.stats(); conns != 3 || messages != 5 {
+		t.Fatalf("conns = %d, messages = %d, want 3 and 5", conns, messages)
+	}
+}
+
+func TestSMTPSenderWaitHonoursContext(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	sender := NewSMTPSender(server.config())
+	defer sender.Close()
+
+	// Another send holds the session
+	sender.lock()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := sender.Send(ctx, Recipient{Email: "a@example.com"}, Message{Subject: "hi", Text: "hi"})
+	sender.unlock()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if _, _, messages := server.stats(); messages != 0 {
+		t.Fatalf("messages = %d, want 0", messages)
+	}
+}
+
+func TestSMTPSenderConnectionLimits(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	config := server.config()
+	config.MaxMessagesPerConn = 2
+	config.IdleTimeout = 200 * time.Millisecond
+	sender := NewSMTPSender(config)
+	defer sender.Close()
+
+	sendN(t, sender, 4)
+	if conns, quits, _ := server.stats(); conns != 2 || quits != 2 {
+		t.Fatalf("conns = %d, quits = %d, want 2 and 2", conns, quits)
+	}
+
+	sendN(t, sender, 1)
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, quits, _ := server.stats(); quits == 3 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("idle connection was not closed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestSMTPSenderSTARTTLSWithLoginAuth(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	serverTLS, clientTLS := selfSignedTLS(t)
+	server.tlsConfig = serverTLS
+
+	config := server.config()
+	config.Security = SMTPSecuritySTARTTLS
+	config.AuthMechanism = SMTPAuthLogin
+	config.Username = "mailer"
+	config.Password = "secret"
+	sender := NewSMTPSender(config)
+	sender.tlsConfig = clientTLS
+	defer sender.Close()
+
+	sendN(t, sender, 2)
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if server.conns != 1 || server.authUser != "mailer" {
+		t.Fatalf("conns = %d, authUser = %q, want 1 and mailer", server.conns, server.authUser)
+	}
+	if !strings.Contains(server.messages[0], "Subject: hi") {
+		t.Fatalf("unexpected message: %q", server.messages[0])
+	}
+
+	// STARTTLS is required, not opportunistic
+	config.Port = newFakeSMTPServer(t).config().Port
+	if err := NewSMTPSender(config).Send(context.Background(), Recipient{Email: "a@example.com"}, Message{}); err == nil {
+		t.Fatal("expected error when server does not offer STARTTLS")
+	}
+}
+
+func TestLoadSMTPConfigFromEnv(t *testing.T) {
+	t.Setenv("SMTP_HOST", "smtp.example.com")
+	t.Setenv("SMTP_SENDER_EMAIL", "noreply@example.com")
+
+	for port, want := range map[string]SMTPSecurity{"465": SMTPSecurityTLS, "587": SMTPSecuritySTARTTLS, "25": SMTPSecurityNone} {
+		t.Setenv("SMTP_PORT", port)
+		config, err := LoadSMTPConfigFromEnv()
+		if err != nil {
+			t.Fatalf("port %s: %v", port, err)
+		}
+		if config.Security != want || config.AuthMechanism != SMTPAuthPlain || config.MaxMessagesPerConn != defaultSMTPMaxMessagesPerConn {
+			t.Errorf("port %s: got %+v", port, config)
+		}
+	}
+
+	t.Setenv("SMTP_PORT", "2525")
+	t.Setenv("SMTP_SECURITY", "NONE")
+	t.Setenv("SMTP_AUTH_MECHANISM", "cram-md5")
+	t.Setenv("SMTP_IDLE_TIMEOUT", "5s")
+	config, err := LoadSMTPConfigFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Security != SMTPSecurityNone || config.AuthMechanism != SMTPAuthCRAMMD5 || config.IdleTimeout != 5*time.Second {
+		t.Errorf("got %+v", config)
+	}
+
+	for key, value := range map[string]string{"SMTP_SECURITY": "ssl", "SMTP_AUTH_MECHANISM": "xoauth2", "SMTP_TIMEOUT": "soon", "SMTP_PORT": "70000"} {
+		t.Run(key, func(t *testing.T) {
+			t.Setenv(key, value)
+			if _, err := LoadSMTPConfigFromEnv(); err == nil {
+				t.Errorf("expected error for %s=%s", key, value)
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package email
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"path"
+	"strings"
+	texttemplate "text/template"
+	"text/template/parse"
+	"time"
+)
+
+// Template names.
+const (
+	TemplateVerification = "verification" // Asks an employee to confirm their numbers; data is VerificationData
+)
+
+// Supported template locales.
+const (
+	LocaleZhCN    = "zh-CN"
+	LocaleEN      = "en"
+	DefaultLocale = LocaleZhCN
+)
+
+// IsSupportedLocale reports whether templates can be localized to locale.
+func IsSupportedLocale(locale string) bool {
+	return locale == LocaleZhCN || locale == LocaleEN
+}
+
+// ErrTemplateNotFound is returned when no template exists for a name and locale.
+var ErrTemplateNotFound = errors.New("email template not found")
+
+//go:embed templates
+var builtinTemplates embed.FS
+
+// Template holds the sources of a notification template. Subject and Text are
+// text/template sources; HTML is an html/template source, so values are escaped.
+// Text is required: it is sent as the plain-text alternative of the email and
+// used by SMS. IM only sends the subject, so the subject may not reference the
+// personal link or the numbers.
+type Template struct {
+	Subject string
+	HTML    string
+	Text    string
+}
+
+// VerificationData is the data passed to the verification template.
+type VerificationData struct {
+	EmployeeName string
+	EmployeeID   string
+	Link         string // Personal verification link
+	Numbers      []VerificationNumber
+	ExpiresAt    time.Time // When the link expires
+	ValidDays    int       // Days the link is valid for, counted from when the message is sent
+	Contact      string    // Who to contact with questions
+	Reminder     bool      // The message is a reminder for a request the employee has not answered yet
+}
+
+// VerificationNumber is one number listed in a verification message.
+type VerificationNumber struct {
+	PhoneNumber string
+	Status      string // Status code in the system, e.g. in_use
+}
+
+// LoadTemplate reads the template for name and locale from fsys, where each
+// template is a directory <name>/<locale>/ holding subject.tmpl, html.tmpl and
+// text.tmpl. It returns ErrTemplateNotFound when subject.tmpl is missing; a
+// missing text.tmpl is reported by Validate and Render.
+func LoadTemplate(fsys fs.FS, name, locale string) (Template, error) {
+	dir := path.Join(name, locale)
+	read := func(file string, required bool) (string, error) {
+		data, err := fs.ReadFile(fsys, path.Join(dir, file))
+		if errors.Is(err, fs.ErrNotExist) {
+			if required {
+				return "", fmt.Errorf("%w: %s/%s", ErrTemplateNotFound, name, locale)
+			}
+			return "", nil
+		}
+		return string(data), err
+	}
+
+	var t Template
+	var err error
+	if t.Subject, err = read("subject.tmpl", true); err != nil {
+		return Template{}, err
+	}
+	if t.HTML, err = read("html.tmpl", false); err != nil {
+		return Template{}, err
+	}
+	if t.Text, err = read("text.tmpl", false); err != nil {
+		return Template{}, err
+	}
+	return t, nil
+}
+
+// BuiltinTemplate returns the template shipped with the package for name and locale.
+func BuiltinTemplate(name, locale string) (Template, error) {
+	sub, err := fs.Sub(builtinTemplates, "templates")
+	if err != nil {
+		return Template{}, err
+	}
+	return LoadTemplate(sub, name, locale)
+}
+
+// Validate parses all parts of the template without executing them.
+func (t Template) Validate() error {
+	_, err := t.parse()
+	return err
+}
+
+// Render executes the template with data. The rendered subject is collapsed to
+// a single line.
+func (t Template) Render(data interface{}) (Message, error) {
+	p, err := t.parse()
+	if err != nil {
+		return Message{}, err
+	}
+
+	var msg Message
+	var buf bytes.Buffer
+	if err := p.subject.Execute(&buf, data); err != nil {
+		return Message{}, fmt.Errorf("render subject: %w", err)
+	}
+	msg.Subject = strings.Join(strings.Fields(buf.String()), " ")
+
+	if p.html != nil {
+		buf.Reset()
+		if err := p.html.Execute(&buf, data); err != nil {
+			return Message{}, fmt.Errorf("render html: %w", err)
+		}
+		msg.HTML = buf.String()
+	}
+	if p.text != nil {
+		buf.Reset()
+		if err := p.text.Execute(&buf, data); err != nil {
+			return Message{}, fmt.Errorf("render text: %w", err)
+		}
+		msg.Text = strings.TrimSpace(buf.String())
+	}
+	return msg, nil
+}
+
+// parsedTemplate holds the parsed parts of a Template; html and text are nil when empty.
+type parsedTemplate struct {
+	subject *texttemplate.Template
+	html    *htmltemplate.Template
+	text    *texttemplate.Template
+}
+
+func (t Template) parse() (*parsedTemplate, error) {
+	if strings.TrimSpace(t.Subject) == "" {
+		return nil, errors.New("subject template is empty")
+	}
+	if strings.TrimSpace(t.Text) == "" {
+		return nil, errors.New("text template is empty")
+	}
+
+	var p parsedTemplate
+	var err error
+	if p.subject, err = texttemplate.New("subject").Option("missingkey=error").Parse(t.Subject); err != nil {
+		return nil, fmt.Errorf("parse subject: %w", err)
+	}
+	for _, tmpl := range p.subject.Templates() { // includes templates defined inside the subject
+		for _, field := range subjectForbiddenFields {
+			if tmpl.Tree != nil && referencesField(tmpl.Tree.Root, field) {
+				return nil, fmt.Errorf("subject template may not reference .%s", field)
+			}
+		}
+	}
+	if strings.TrimSpace(t.HTML) != "" {
+		if p.html, err = htmltemplate.New("html").Option("missingkey=error").Parse(t.HTML); err != nil {
+			return nil, fmt.Errorf("parse html: %w", err)
+		}
+	}
+	if p.text, err = texttemplate.New("text").Option("missingkey=error").Parse(t.Text); err != nil {
+		return nil, fmt.Errorf("parse text: %w", err)
+	}
+	return &p, nil
+}
+
+// subjectForbiddenFields are the data fields a subject may not use, because IM
+// messages carry only the subject and are not private to the recipient.
+var subjectForbiddenFields = []string{"Link", "Numbers"}
+
+// referencesField reports whether any field chain under node, such as .Link,
+// $.Link or $v.Link, includes field.
+func referencesField(node parse.Node, field string) bool {
+	has := func(idents []string) bool {
+		for _, ident := range idents {
+			if ident == field {
+				return true
+			}
+		}
+		return false
+	}
+	switch n := node.(type) {
+	case nil:
+		return false
+	case *parse.ListNode:
+		if n == nil {
+			return false
+		}
+		for _, child := range n.Nodes {
+			if referencesField(child, field) {
+				return true
+			}
+		}
+	case *parse.ActionNode:
+		return referencesField(n.Pipe, field)
+	case *parse.PipeNode:
+		if n == nil {
+			return false
+		}
+		for _, cmd := range n.Cmds {
+			if referencesField(cmd, field) {
+				return true
+			}
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			if referencesField(arg, field) {
+				return true
+			}
+		}
+	case *parse.FieldNode:
+		return has(n.Ident)
+	case *parse.VariableNode:
+		return has(n.Ident[1:])
+	case *parse.ChainNode:
+		return has(n.Field) || referencesField(n.Node, field)
+	case *parse.IfNode:
+		return referencesField(&n.BranchNode, field)
+	case *parse.RangeNode:
+		return referencesField(&n.BranchNode, field)
+	case *parse.WithNode:
+		return referencesField(&n.BranchNode, field)
+	case *parse.BranchNode:
+		return referencesField(n.Pipe, field) || referencesField(n.List, field) || referencesField(n.ElseList, field)
+	case *parse.TemplateNode:
+		return referencesField(n.Pipe, field)
+	}
+	return false
+}
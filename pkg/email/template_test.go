@@ -0,0 +1,159 @@
+package email
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestBuiltinVerificationTemplates(t *testing.T) {
+	data := VerificationData{
+		EmployeeName: "<张三>",
+		Link:         "http://localhost/verify-numbers?token=abc&x=1",
+		Numbers:      []VerificationNumber{{PhoneNumber: "13800000001"}, {PhoneNumber: "13800000002"}},
+		ExpiresAt:    time.Date(2026, 1, 8, 9, 30, 0, 0, time.UTC),
+		ValidDays:    3,
+		Contact:      "IT 服务台",
+	}
+	for _, locale := range []string{LocaleZhCN, LocaleEN} {
+		t.Run(locale, func(t *testing.T) {
+			tmpl, err := BuiltinTemplate(TemplateVerification, locale)
+			if err != nil {
+				t.Fatal(err)
+			}
+			msg, err := tmpl.Render(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if msg.Subject == "" || strings.Contains(msg.Subject, "\n") {
+				t.Errorf("subject = %q", msg.Subject)
+			}
+			for _, want := range []string{"&lt;张三&gt;", "13800000002", "2026-01-08 09:30", "IT 服务台", "token=abc&amp;x=1", "3"} {
+				if !strings.Contains(msg.HTML, want) {
+					t.Errorf("html missing %q", want)
+				}
+			}
+			for _, want := range []string{"<张三>", "13800000001", data.Link} {
+				if !strings.Contains(msg.Text, want) {
+					t.Errorf("text missing %q", want)
+				}
+			}
+
+			reminderData := data
+			reminderData.Reminder = true
+			reminder, err := tmpl.Render(reminderData)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if reminder.Subject == msg.Subject || !strings.HasSuffix(reminder.Subject, msg.Subject) {
+				t.Errorf("reminder subject = %q, want a prefix before %q", reminder.Subject, msg.Subject)
+			}
+		})
+	}
+
+	if _, err := BuiltinTemplate(TemplateVerification, "fr"); !errors.Is(err, ErrTemplateNotFound) {
+		t.Errorf("err = %v, want ErrTemplateNotFound", err)
+	}
+}
+
+func TestTemplateValidateAndRender(t *testing.T) {
+	tests := []struct {
+		name    string
+		tmpl    Template
+		wantErr bool
+	}{
+		{"valid", Template{Subject: "Hi {{.EmployeeName}}", Text: "{{.Link}}"}, false},
+		{"empty subject", Template{Text: "x"}, true},
+		{"no body", Template{Subject: "x"}, true},
+		{"html only", Template{Subject: "x", HTML: "<p>{{.Link}}</p>"}, true},
+		{"link in subject", Template{Subject: "Confirm at {{.Link}}", Text: "x"}, true},
+		{"link via root variable", Template{Subject: "{{with .EmployeeName}}{{$.Link}}{{end}}", Text: "x"}, true},
+		{"link in defined template", Template{Subject: `{{define "l"}}{{.Link}}{{end}}{{template "l" .}}`, Text: "x"}, true},
+		{"numbers in subject", Template{Subject: "{{range .Numbers}}{{.PhoneNumber}}{{end}}", Text: "x"}, true},
+		{"reminder in subject", Template{Subject: "{{if .Reminder}}Reminder: {{end}}Hi {{.EmployeeName}}", Text: "x"}, false},
+		{"syntax error", Template{Subject: "x", HTML: "{{if .Link}}", Text: "x"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.tmpl.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Unknown fields parse but fail to render
+	tmpl := Template{Subject: "x", Text: "{{.Nope}}"}
+	if err := tmpl.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tmpl.Render(VerificationData{}); err == nil {
+		t.Error("expected error for unknown field")
+	}
+}
+
+func TestLoadTemplate(t *testing.T) {
+	fsys := fstest.MapFS{
+		"verification/en/subject.tmpl": {Data: []byte("Custom\n")},
+		"verification/en/text.tmpl":    {Data: []byte("{{.Link}}")},
+	}
+	tmpl, err := LoadTemplate(fsys, TemplateVerification, LocaleEN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tmpl.Subject != "Custom\n" || tmpl.HTML != "" || tmpl.Text != "{{.Link}}" {
+		t.Errorf("got %+v", tmpl)
+	}
+	if _, err := LoadTemplate(fsys, TemplateVerification, LocaleZhCN); !errors.Is(err, ErrTemplateNotFound) {
+		t.Errorf("err = %v, want ErrTemplateNotFound", err)
+	}
+}
+
+func TestBuildMessageMultipart(t *testing.T) {
+	raw, err := buildMessage("noreply@example.com", []string{"a@example.com"}, Message{Subject: "号码确认", HTML: "<p>你好</p>", Text: "你好"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := mail.ReadMessage(strings.NewReader(string(raw)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject, _ := new(mime.WordDecoder).DecodeHeader(m.Header.Get("Subject")); subject != "号码确认" {
+		t.Errorf("subject = %q", subject)
+	}
+	mediaType, params, err := mime.ParseMediaType(m.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("content type = %q, %v", mediaType, err)
+	}
+
+	r := multipart.NewReader(m.Body, params["boundary"])
+	var got []string
+	for {
+		part, err := r.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(part) // NextPart decodes quoted-printable
+		got = append(got, strings.SplitN(part.Header.Get("Content-Type"), ";", 2)[0]+" "+string(body))
+	}
+	want := []string{"text/plain 你好", "text/html <p>你好</p>"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("parts = %q, want %q", got, want)
+	}
+
+	raw, err = buildMessage("noreply@example.com", []string{"a@example.com"}, Message{Subject: "hi", HTML: "<p>hi</p>"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(raw), "Content-Type: text/html; charset=\"UTF-8\"") {
+		t.Errorf("expected single-part html message, got %q", raw)
+	}
+}
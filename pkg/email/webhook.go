@@ -0,0 +1,161 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IMFlavor selects the JSON dialect of an enterprise IM robot webhook.
+type IMFlavor string
+
+const (
+	IMFlavorWeCom    IMFlavor = "wecom"
+	IMFlavorDingTalk IMFlavor = "dingtalk"
+	IMFlavorFeishu   IMFlavor = "feishu"
+)
+
+// IMWebhookSender posts a nudge to a group robot webhook, mentioning the
+// recipient by phone number where the platform supports it. Everyone in the
+// group can read robot messages, so only the message subject is posted; the
+// body, which may carry personal links, is left to the recipient's own channel.
+type IMWebhookSender struct {
+	url    string
+	flavor IMFlavor
+	secret string // Optional signing secret (DingTalk and Feishu)
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewIMWebhookSender creates an IMWebhookSender for the given robot URL and flavor.
+func NewIMWebhookSender(webhookURL string, flavor IMFlavor, secret string, timeout time.Duration) (*IMWebhookSender, error) {
+	switch flavor {
+	case IMFlavorWeCom, IMFlavorDingTalk, IMFlavorFeishu:
+	default:
+		return nil, fmt.Errorf("unsupported IM webhook flavor %q", flavor)
+	}
+	if _, err := url.ParseRequestURI(webhookURL); err != nil {
+		return nil, fmt.Errorf("invalid IM webhook URL: %w", err)
+	}
+	return &IMWebhookSender{url: webhookURL, flavor: flavor, secret: secret, client: &http.Client{Timeout: timeout}, now: time.Now}, nil
+}
+
+// Send posts msg.Subject to the webhook.
+func (s *IMWebhookSender) Send(ctx context.Context, to Recipient, msg Message) error {
+	if to.Phone == "" {
+		return ErrNoAddress
+	}
+	text := msg.Subject
+
+	target := s.url
+	var payload map[string]interface{}
+	switch s.flavor {
+	case IMFlavorWeCom:
+		payload = map[string]interface{}{
+			"msgtype": "text",
+			"text":    map[string]interface{}{"content": text, "mentioned_mobile_list": []string{to.Phone}},
+		}
+	case IMFlavorDingTalk:
+		payload = map[string]interface{}{
+			"msgtype": "text",
+			"text":    map[string]interface{}{"content": text},
+			"at":      map[string]interface{}{"atMobiles": []string{to.Phone}},
+		}
+		if s.secret != "" {
+			// DingTalk signs "timestamp\nsecret" (milliseconds) with the secret and expects it in the query string
+			timestamp := strconv.FormatInt(s.now().UnixMilli(), 10)
+			sign := signBase64([]byte(s.secret), timestamp+"\n"+s.secret)
+			separator := "?"
+			if strings.Contains(target, "?") {
+				separator = "&"
+			}
+			target += separator + "timestamp=" + timestamp + "&sign=" + url.QueryEscape(sign)
+		}
+	case IMFlavorFeishu:
+		payload = map[string]interface{}{
+			"msg_type": "text",
+			"content":  map[string]interface{}{"text": text},
+		}
+		if s.secret != "" {
+			// Feishu uses "timestamp\nsecret" (seconds) as the key over an empty message
+			timestamp := strconv.FormatInt(s.now().Unix(), 10)
+			payload["timestamp"] = timestamp
+			payload["sign"] = signBase64([]byte(timestamp+"\n"+s.secret), "")
+		}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	var result struct {
+		ErrCode int    `json:"errcode"` // WeCom, DingTalk
+		ErrMsg  string `json:"errmsg"`
+		Code    int    `json:"code"` // Feishu
+		Msg     string `json:"msg"`
+	}
+	if err := postJSON(ctx, s.client, target, nil, body, &result); err != nil {
+		return fmt.Errorf("IM webhook request failed: %w", err)
+	}
+	if result.ErrCode != 0 {
+		return fmt.Errorf("IM webhook rejected message: %d %s", result.ErrCode, result.ErrMsg)
+	}
+	if result.Code != 0 {
+		return fmt.Errorf("IM webhook rejected message: %d %s", result.Code, result.Msg)
+	}
+	return nil
+}
+
+// signBase64 returns the base64 HMAC-SHA256 of data.
+func signBase64(key []byte, data string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// postJSON posts body and decodes a JSON response into out when out is not nil.
+// Non-2xx responses are returned as errors including the start of the response body.
+func postJSON(ctx context.Context, client *http.Client, target string, header http.Header, body []byte, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		snippet := string(respBody)
+		if len(snippet) > 200 {
+			snippet = snippet[:200]
+		}
+		return fmt.Errorf("unexpected status %s: %s", resp.Status, strings.TrimSpace(snippet))
+	}
+	if out != nil && len(bytes.TrimSpace(respBody)) > 0 {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return fmt.Errorf("invalid response: %w", err)
+		}
+	}
+	return nil
+}
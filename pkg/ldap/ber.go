@@ -0,0 +1,191 @@
+package ldap
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// BER 标签的类别和构造位，LDAP 只用到单字节标签（标签号小于 31）
+const (
+	classApplication byte = 0x40
+	classContext     byte = 0x80
+	constructed      byte = 0x20
+)
+
+// LDAP 用到的通用类型标签
+const (
+	tagBoolean     byte = 0x01
+	tagInteger     byte = 0x02
+	tagOctetString byte = 0x04
+	tagEnumerated  byte = 0x0a
+	tagSequence    byte = 0x30
+	tagSet         byte = 0x31
+)
+
+// maxPacketSize 限制单个报文的长度，避免异常的长度字段导致分配过多内存
+const maxPacketSize = 16 << 20
+
+// packet 是一个 BER 编码的 TLV 元素。构造类型的元素由 children 组成，基本类型的元素内容在 value 中
+type packet struct {
+	tag      byte
+	value    []byte
+	children []*packet
+}
+
+func newPrimitive(tag byte, value []byte) *packet {
+	return &packet{tag: tag, value: value}
+}
+
+func newString(tag byte, s string) *packet {
+	return newPrimitive(tag, []byte(s))
+}
+
+func newInteger(tag byte, n int64) *packet {
+	return newPrimitive(tag, encodeInteger(n))
+}
+
+func newBoolean(b bool) *packet {
+	if b {
+		return newPrimitive(tagBoolean, []byte{0xff})
+	}
+	return newPrimitive(tagBoolean, []byte{0x00})
+}
+
+func newConstructed(tag byte, children ...*packet) *packet {
+	return &packet{tag: tag | constructed, children: children}
+}
+
+func (p *packet) isConstructed() bool {
+	return p.tag&constructed != 0
+}
+
+// encode 返回元素的 BER 编码
+func (p *packet) encode() []byte {
+	content := p.value
+	if p.isConstructed() {
+		content = nil
+		for _, child := range p.children {
+			content = append(content, child.encode()...)
+		}
+	}
+	out := append([]byte{p.tag}, encodeLength(len(content))...)
+	return append(out, content...)
+}
+
+// str 返回基本类型元素的字符串内容
+func (p *packet) str() string {
+	return string(p.value)
+}
+
+// int 解析 INTEGER 或 ENUMERATED 元素
+func (p *packet) int() (int64, error) {
+	if len(p.value) == 0 || len(p.value) > 8 {
+		return 0, fmt.Errorf("ldap: 无效的整数长度 %d", len(p.value))
+	}
+	n := int64(int8(p.value[0]))
+	for _, b := range p.value[1:] {
+		n = n<<8 | int64(b)
+	}
+	return n, nil
+}
+
+// child 返回第 i 个子元素，不存在时返回错误
+func (p *packet) child(i int) (*packet, error) {
+	if i >= len(p.children) {
+		return nil, fmt.Errorf("ldap: 报文缺少第 %d 个元素 (标签 0x%02x)", i+1, p.tag)
+	}
+	return p.children[i], nil
+}
+
+func encodeInteger(n int64) []byte {
+	var out []byte
+	for {
+		out = append([]byte{byte(n)}, out...)
+		n >>= 8
+		// 剩余部分全为符号位时结束
+		if (n == 0 && out[0]&0x80 == 0) || (n == -1 && out[0]&0x80 != 0) {
+			return out
+		}
+	}
+}
+
+func encodeLength(n int) []byte {
+	if n < 0x80 {
+		return []byte{byte(n)}
+	}
+	var out []byte
+	for ; n > 0; n >>= 8 {
+		out = append([]byte{byte(n)}, out...)
+	}
+	return append([]byte{0x80 | byte(len(out))}, out...)
+}
+
+// readPacket 从 r 读取一个完整的 BER 元素
+func readPacket(r *bufio.Reader) (*packet, error) {
+	tag, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	if tag&0x1f == 0x1f {
+		return nil, errors.New("ldap: 不支持多字节标签")
+	}
+
+	first, err := r.ReadByte()
+	if err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	length := int(first)
+	if first&0x80 != 0 {
+		size := int(first & 0x7f)
+		if size == 0 || size > 4 {
+			return nil, fmt.Errorf("ldap: 不支持的长度编码 0x%02x", first)
+		}
+		length = 0
+		for i := 0; i < size; i++ {
+			b, err := r.ReadByte()
+			if err != nil {
+				return nil, unexpectedEOF(err)
+			}
+			length = length<<8 | int(b)
+		}
+	}
+	if length > maxPacketSize {
+		return nil, fmt.Errorf("ldap: 报文长度 %d 超过上限", length)
+	}
+
+	content := make([]byte, length)
+	if _, err := io.ReadFull(r, content); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	return decodePacket(tag, content)
+}
+
+// decodePacket 由标签和内容构造元素，构造类型会递归解析子元素
+func decodePacket(tag byte, content []byte) (*packet, error) {
+	p := &packet{tag: tag}
+	if tag&constructed == 0 {
+		p.value = content
+		return p, nil
+	}
+	r := bufio.NewReader(bytes.NewReader(content))
+	for {
+		child, err := readPacket(r)
+		if err == io.EOF {
+			return p, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		p.children = append(p.children, child)
+	}
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,178 @@
+package ldap
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// 搜索过滤器的选择标签 (RFC 4511 4.5.1)
+const (
+	filterAnd            = classContext | constructed | 0
+	filterOr             = classContext | constructed | 1
+	filterNot            = classContext | constructed | 2
+	filterEqualityMatch  = classContext | constructed | 3
+	filterSubstrings     = classContext | constructed | 4
+	filterGreaterOrEqual = classContext | constructed | 5
+	filterLessOrEqual    = classContext | constructed | 6
+	filterPresent        = classContext | 7
+	filterApproxMatch    = classContext | constructed | 8
+
+	substringInitial = classContext | 0
+	substringAny     = classContext | 1
+	substringFinal   = classContext | 2
+)
+
+// compileFilter 将 RFC 4515 字符串形式的过滤器编码为 BER，
+// 支持与、或、非、等于、存在、子串、大于等于、小于等于和近似匹配，不支持扩展匹配
+func compileFilter(filter string) (*packet, error) {
+	filter = strings.TrimSpace(filter)
+	if filter == "" {
+		return nil, fmt.Errorf("ldap: 过滤器不能为空")
+	}
+	p, rest, err := parseFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	if rest != "" {
+		return nil, fmt.Errorf("ldap: 过滤器 %q 末尾有多余内容 %q", filter, rest)
+	}
+	return p, nil
+}
+
+// parseFilter 解析以 "(" 开头的一个过滤器，返回剩余未解析的内容
+func parseFilter(s string) (*packet, string, error) {
+	if !strings.HasPrefix(s, "(") {
+		return nil, "", fmt.Errorf("ldap: 过滤器应以 ( 开头: %q", s)
+	}
+	s = s[1:]
+	if s == "" {
+		return nil, "", fmt.Errorf("ldap: 过滤器不完整")
+	}
+
+	switch s[0] {
+	case '&', '|':
+		tag := byte(filterAnd)
+		if s[0] == '|' {
+			tag = filterOr
+		}
+		p := newConstructed(tag)
+		s = s[1:]
+		for strings.HasPrefix(s, "(") {
+			child, rest, err := parseFilter(s)
+			if err != nil {
+				return nil, "", err
+			}
+			p.children = append(p.children, child)
+			s = rest
+		}
+		return closeFilter(p, s)
+	case '!':
+		child, rest, err := parseFilter(s[1:])
+		if err != nil {
+			return nil, "", err
+		}
+		return closeFilter(newConstructed(filterNot, child), rest)
+	}
+
+	end := strings.IndexByte(s, ')')
+	if end < 0 {
+		return nil, "", fmt.Errorf("ldap: 过滤器缺少 )")
+	}
+	p, err := parseItem(s[:end])
+	if err != nil {
+		return nil, "", err
+	}
+	return p, s[end+1:], nil
+}
+
+func closeFilter(p *packet, rest string) (*packet, string, error) {
+	if !strings.HasPrefix(rest, ")") {
+		return nil, "", fmt.Errorf("ldap: 过滤器缺少 )")
+	}
+	return p, rest[1:], nil
+}
+
+// parseItem 解析不含括号的简单条件，例如 mail=*、cn=张*、uSNChanged>=100
+func parseItem(item string) (*packet, error) {
+	eq := strings.IndexByte(item, '=')
+	if eq <= 0 {
+		return nil, fmt.Errorf("ldap: 无效的过滤条件 %q", item)
+	}
+	attr, value := item[:eq], item[eq+1:]
+
+	tag := byte(filterEqualityMatch)
+	switch attr[len(attr)-1] {
+	case '>':
+		tag, attr = filterGreaterOrEqual, attr[:len(attr)-1]
+	case '<':
+		tag, attr = filterLessOrEqual, attr[:len(attr)-1]
+	case '~':
+		tag, attr = filterApproxMatch, attr[:len(attr)-1]
+	case ':':
+		return nil, fmt.Errorf("ldap: 不支持扩展匹配过滤条件 %q", item)
+	}
+	if attr == "" {
+		return nil, fmt.Errorf("ldap: 无效的过滤条件 %q", item)
+	}
+
+	if tag == filterEqualityMatch && value == "*" {
+		return newString(filterPresent, attr), nil
+	}
+	if tag == filterEqualityMatch && strings.Contains(value, "*") {
+		return parseSubstrings(attr, value)
+	}
+	v, err := unescapeValue(value)
+	if err != nil {
+		return nil, err
+	}
+	return newConstructed(tag, newString(tagOctetString, attr), newString(tagOctetString, v)), nil
+}
+
+// parseSubstrings 解析包含 * 的子串匹配条件
+func parseSubstrings(attr, value string) (*packet, error) {
+	parts := strings.Split(value, "*")
+	substrings := newConstructed(tagSequence)
+	for i, part := range parts {
+		if part == "" {
+			continue
+		}
+		v, err := unescapeValue(part)
+		if err != nil {
+			return nil, err
+		}
+		tag := byte(substringAny)
+		switch i {
+		case 0:
+			tag = substringInitial
+		case len(parts) - 1:
+			tag = substringFinal
+		}
+		substrings.children = append(substrings.children, newString(tag, v))
+	}
+	return newConstructed(filterSubstrings, newString(tagOctetString, attr), substrings), nil
+}
+
+// unescapeValue 处理过滤器值中 \XX 形式的转义
+func unescapeValue(value string) (string, error) {
+	if !strings.Contains(value, `\`) {
+		return value, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' {
+			b.WriteByte(value[i])
+			continue
+		}
+		if i+2 >= len(value) {
+			return "", fmt.Errorf("ldap: 过滤器值中的转义不完整: %q", value)
+		}
+		decoded, err := hex.DecodeString(value[i+1 : i+3])
+		if err != nil {
+			return "", fmt.Errorf("ldap: 过滤器值中的转义无效: %q", value)
+		}
+		b.Write(decoded)
+		i += 2
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,338 @@
+// Package ldap 实现访问 LDAP/Active Directory 目录所需的最小 LDAPv3 客户端（RFC 4511）：
+// 简单绑定和带分页的子树搜索，不依赖任何外部库。
+package ldap
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// 协议操作标签
+const (
+	opBindRequest           = classApplication | constructed | 0
+	opBindResponse          = classApplication | constructed | 1
+	opUnbindRequest         = classApplication | 2
+	opSearchRequest         = classApplication | constructed | 3
+	opSearchResultEntry     = classApplication | constructed | 4
+	opSearchResultDone      = classApplication | constructed | 5
+	opSearchResultReference = classApplication | constructed | 19
+
+	tagControls         = classContext | constructed | 0
+	tagSimpleAuth       = classContext | 0
+	protocolVersion     = 3
+	scopeWholeSubtree   = 2
+	derefAliasesNever   = 0
+	resultSuccess       = 0
+	pagedResultsControl = "1.2.840.113556.1.4.319" // RFC 2696 分页控制
+)
+
+// Error 表示服务器返回的非成功结果
+type Error struct {
+	ResultCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("ldap: 结果码 %d", e.ResultCode)
+	}
+	return fmt.Sprintf("ldap: 结果码 %d: %s", e.ResultCode, e.Message)
+}
+
+// Conn 是到目录服务器的一个连接，请求按顺序发送，不能被多个 goroutine 同时使用
+type Conn struct {
+	conn    net.Conn
+	r       *bufio.Reader
+	timeout time.Duration
+	msgID   int64
+}
+
+// Dial 连接 ldap:// 或 ldaps:// 地址，未指定端口时分别使用 389 和 636。
+// timeout 同时作为连接超时和每个请求的读写超时
+func Dial(rawURL string, timeout time.Duration) (*Conn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("ldap: 无效的地址 %q: %w", rawURL, err)
+	}
+
+	host := u.Host
+	dialer := &net.Dialer{Timeout: timeout}
+	var conn net.Conn
+	switch strings.ToLower(u.Scheme) {
+	case "ldap":
+		if u.Port() == "" {
+			host = net.JoinHostPort(u.Hostname(), "389")
+		}
+		conn, err = dialer.Dial("tcp", host)
+	case "ldaps":
+		if u.Port() == "" {
+			host = net.JoinHostPort(u.Hostname(), "636")
+		}
+		conn, err = tls.DialWithDialer(dialer, "tcp", host, &tls.Config{ServerName: u.Hostname()})
+	default:
+		return nil, fmt.Errorf("ldap: 不支持的协议 %q，应为 ldap 或 ldaps", u.Scheme)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Conn{conn: conn, r: bufio.NewReader(conn), timeout: timeout}, nil
+}
+
+// Close 发送解绑请求并关闭连接
+func (c *Conn) Close() error {
+	c.msgID++
+	msg := newConstructed(tagSequence, newInteger(tagInteger, c.msgID), newPrimitive(opUnbindRequest, nil))
+	c.setDeadline()
+	_, _ = c.conn.Write(msg.encode())
+	return c.conn.Close()
+}
+
+// Bind 使用 DN 和密码进行简单绑定，dn 和 password 均为空时为匿名绑定
+func (c *Conn) Bind(dn, password string) error {
+	req := newConstructed(opBindRequest,
+		newInteger(tagInteger, protocolVersion),
+		newString(tagOctetString, dn),
+		newString(tagSimpleAuth, password),
+	)
+	resp, _, _, err := c.roundTrip(req, nil)
+	if err != nil {
+		return err
+	}
+	if resp.tag != opBindResponse {
+		return fmt.Errorf("ldap: 意外的绑定响应 (标签 0x%02x)", resp.tag)
+	}
+	return resultError(resp)
+}
+
+// SearchRequest 描述一次子树搜索
+type SearchRequest struct {
+	BaseDN     string
+	Filter     string   // RFC 4515 格式的过滤器，例如 (&(objectClass=person)(mail=*))
+	Attributes []string // 需要返回的属性，为空时返回全部用户属性
+	PageSize   int      // 分页大小，大于 0 时使用分页控制逐页获取全部结果
+}
+
+// Entry 是搜索返回的一个目录条目
+type Entry struct {
+	DN         string
+	Attributes map[string][]string // 键为小写的属性名
+}
+
+// Values 返回属性的全部值，属性名不区分大小写
+func (e *Entry) Values(attribute string) []string {
+	return e.Attributes[strings.ToLower(attribute)]
+}
+
+// Value 返回属性的第一个值，属性不存在时返回空字符串
+func (e *Entry) Value(attribute string) string {
+	if values := e.Values(attribute); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// Search 执行子树搜索并返回全部条目，搜索结果引用（referral）会被忽略
+func (c *Conn) Search(req SearchRequest) ([]Entry, error) {
+	filter, err := compileFilter(req.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	var cookie []byte
+	for {
+		attributes := newConstructed(tagSequence)
+		for _, a := range req.Attributes {
+			attributes.children = append(attributes.children, newString(tagOctetString, a))
+		}
+		op := newConstructed(opSearchRequest,
+			newString(tagOctetString, req.BaseDN),
+			newInteger(tagEnumerated, scopeWholeSubtree),
+			newInteger(tagEnumerated, derefAliasesNever),
+			newInteger(tagInteger, 0), // sizeLimit
+			newInteger(tagInteger, 0), // timeLimit
+			newBoolean(false),         // typesOnly
+			filter,
+			attributes,
+		)
+		var controls *packet
+		if req.PageSize > 0 {
+			controls = newConstructed(tagControls, pagingControl(req.PageSize, cookie))
+		}
+
+		done, responseControls, page, err := c.roundTrip(op, controls)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, page...)
+		if done.tag != opSearchResultDone {
+			return nil, fmt.Errorf("ldap: 意外的搜索响应 (标签 0x%02x)", done.tag)
+		}
+		if err := resultError(done); err != nil {
+			return nil, err
+		}
+
+		if req.PageSize <= 0 {
+			return entries, nil
+		}
+		cookie = responseCookie(responseControls)
+		if len(cookie) == 0 {
+			return entries, nil
+		}
+	}
+}
+
+// pagingControl 构造分页控制，cookie 为上一页响应返回的值，首页为空
+func pagingControl(size int, cookie []byte) *packet {
+	value := newConstructed(tagSequence, newInteger(tagInteger, int64(size)), newPrimitive(tagOctetString, cookie))
+	return newConstructed(tagSequence,
+		newString(tagOctetString, pagedResultsControl),
+		newPrimitive(tagOctetString, value.encode()),
+	)
+}
+
+// roundTrip 发送一个请求并读取响应，直到收到非搜索条目的响应为止。
+// 返回最终响应、最终响应附带的控制（可能为 nil）和期间收到的搜索条目
+func (c *Conn) roundTrip(op *packet, controls *packet) (*packet, *packet, []Entry, error) {
+	c.msgID++
+	msg := newConstructed(tagSequence, newInteger(tagInteger, c.msgID), op)
+	if controls != nil {
+		msg.children = append(msg.children, controls)
+	}
+	c.setDeadline()
+	if _, err := c.conn.Write(msg.encode()); err != nil {
+		return nil, nil, nil, err
+	}
+
+	var entries []Entry
+	for {
+		c.setDeadline()
+		resp, err := readPacket(c.r)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		id, err := messageID(resp)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if id != c.msgID {
+			// 消息ID为 0 的是服务器主动发出的通知（如即将断开连接）
+			if id == 0 {
+				return nil, nil, nil, errors.New("ldap: 服务器发出了未经请求的通知，连接可能已被关闭")
+			}
+			return nil, nil, nil, fmt.Errorf("ldap: 响应的消息ID %d 与请求 %d 不符", id, c.msgID)
+		}
+		body, err := resp.child(1)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		switch body.tag {
+		case opSearchResultEntry:
+			entry, err := parseEntry(body)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			entries = append(entries, entry)
+		case opSearchResultReference:
+			continue
+		default:
+			var responseControls *packet
+			if len(resp.children) > 2 {
+				responseControls = resp.children[2]
+			}
+			return body, responseControls, entries, nil
+		}
+	}
+}
+
+func (c *Conn) setDeadline() {
+	if c.timeout > 0 {
+		_ = c.conn.SetDeadline(time.Now().Add(c.timeout))
+	}
+}
+
+func messageID(resp *packet) (int64, error) {
+	if resp.tag != tagSequence {
+		return 0, fmt.Errorf("ldap: 无效的响应报文 (标签 0x%02x)", resp.tag)
+	}
+	idPacket, err := resp.child(0)
+	if err != nil {
+		return 0, err
+	}
+	return idPacket.int()
+}
+
+// resultError 解析 LDAPResult，结果码非 0 时返回 *Error
+func resultError(result *packet) error {
+	codePacket, err := result.child(0)
+	if err != nil {
+		return err
+	}
+	code, err := codePacket.int()
+	if err != nil {
+		return err
+	}
+	if code == resultSuccess {
+		return nil
+	}
+	e := &Error{ResultCode: int(code)}
+	if len(result.children) > 2 {
+		e.Message = result.children[2].str()
+	}
+	return e
+}
+
+// parseEntry 解析 SearchResultEntry
+func parseEntry(body *packet) (Entry, error) {
+	dn, err := body.child(0)
+	if err != nil {
+		return Entry{}, err
+	}
+	attributes, err := body.child(1)
+	if err != nil {
+		return Entry{}, err
+	}
+	entry := Entry{DN: dn.str(), Attributes: make(map[string][]string, len(attributes.children))}
+	for _, attribute := range attributes.children {
+		name, err := attribute.child(0)
+		if err != nil {
+			return Entry{}, err
+		}
+		values, err := attribute.child(1)
+		if err != nil {
+			return Entry{}, err
+		}
+		key := strings.ToLower(name.str())
+		for _, v := range values.children {
+			entry.Attributes[key] = append(entry.Attributes[key], v.str())
+		}
+	}
+	return entry, nil
+}
+
+// responseCookie 从响应的控制列表中取出分页控制的 cookie，没有时返回 nil
+func responseCookie(controls *packet) []byte {
+	if controls == nil {
+		return nil
+	}
+	for _, control := range controls.children {
+		if len(control.children) < 2 || control.children[0].str() != pagedResultsControl {
+			continue
+		}
+		raw := control.children[len(control.children)-1].value
+		value, err := readPacket(bufio.NewReader(bytes.NewReader(raw)))
+		if err != nil || len(value.children) < 2 {
+			return nil
+		}
+		return value.children[1].value
+	}
+	return nil
+}
@@ -0,0 +1,308 @@
+package ldap
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// stubServer 是测试用的内嵌 LDAP 服务器，支持简单绑定和带分页的搜索，按过滤器筛选内存中的条目
+type stubServer struct {
+	t        *testing.T
+	ln       net.Listener
+	bindDN   string
+	password string
+	entries  []Entry
+
+	mu       sync.Mutex
+	searches int // 收到的搜索请求数（每页一次）
+}
+
+func startStub(t *testing.T, entries []Entry) *stubServer {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &stubServer{t: t, ln: ln, bindDN: "cn=sync,dc=example,dc=com", password: "secret", entries: entries}
+	go s.serve()
+	t.Cleanup(func() { ln.Close() })
+	return s
+}
+
+func (s *stubServer) url() string {
+	return "ldap://" + s.ln.Addr().String()
+}
+
+func (s *stubServer) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *stubServer) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		msg, err := readPacket(r)
+		if err != nil {
+			return
+		}
+		id, _ := msg.children[0].int()
+		op := msg.children[1]
+		switch op.tag {
+		case opBindRequest:
+			code := int64(0)
+			if op.children[1].str() != s.bindDN || op.children[2].str() != s.password {
+				code = 49 // invalidCredentials
+			}
+			s.reply(conn, id, result(opBindResponse, code), nil)
+		case opSearchRequest:
+			s.search(conn, id, msg, op)
+		case opUnbindRequest:
+			return
+		}
+	}
+}
+
+func (s *stubServer) search(conn net.Conn, id int64, msg, op *packet) {
+	s.mu.Lock()
+	s.searches++
+	s.mu.Unlock()
+
+	var matched []Entry
+	for _, e := range s.entries {
+		if matches(op.children[6], e) {
+			matched = append(matched, e)
+		}
+	}
+
+	start, size := 0, len(matched)
+	if len(msg.children) > 2 {
+		control := msg.children[2].children[0]
+		value, _ := readPacket(bufio.NewReader(strings.NewReader(string(control.children[1].value))))
+		n, _ := value.children[0].int()
+		size = int(n)
+		if cookie := value.children[1].str(); cookie != "" {
+			start, _ = strconv.Atoi(cookie)
+		}
+	}
+	end := start + size
+	if end > len(matched) {
+		end = len(matched)
+	}
+
+	for _, e := range matched[start:end] {
+		attributes := newConstructed(tagSequence)
+		for name, values := range e.Attributes {
+			set := newConstructed(tagSet)
+			for _, v := range values {
+				set.children = append(set.children, newString(tagOctetString, v))
+			}
+			attributes.children = append(attributes.children, newConstructed(tagSequence, newString(tagOctetString, name), set))
+		}
+		s.reply(conn, id, newConstructed(opSearchResultEntry, newString(tagOctetString, e.DN), attributes), nil)
+	}
+
+	var controls *packet
+	if len(msg.children) > 2 {
+		next := ""
+		if end < len(matched) {
+			next = strconv.Itoa(end)
+		}
+		controls = newConstructed(tagControls, pagingControl(0, []byte(next)))
+	}
+	s.reply(conn, id, result(opSearchResultDone, 0), controls)
+}
+
+func (s *stubServer) reply(conn net.Conn, id int64, body, controls *packet) {
+	msg := newConstructed(tagSequence, newInteger(tagInteger, id), body)
+	if controls != nil {
+		msg.children = append(msg.children, controls)
+	}
+	if _, err := conn.Write(msg.encode()); err != nil {
+		s.t.Errorf("stub write: %v", err)
+	}
+}
+
+func result(tag byte, code int64) *packet {
+	return newConstructed(tag, newInteger(tagEnumerated, code), newString(tagOctetString, ""), newString(tagOctetString, ""))
+}
+
+// matches 在内存中对条目求值过滤器，属性名和值均不区分大小写
+func matches(f *packet, e Entry) bool {
+	switch f.tag {
+	case filterAnd:
+		for _, c := range f.children {
+			if !matches(c, e) {
+				return false
+			}
+		}
+		return true
+	case filterOr:
+		for _, c := range f.children {
+			if matches(c, e) {
+				return true
+			}
+		}
+		return false
+	case filterNot:
+		return !matches(f.children[0], e)
+	case filterPresent:
+		return len(e.Values(f.str())) > 0
+	case filterEqualityMatch:
+		for _, v := range e.Values(f.children[0].str()) {
+			if strings.EqualFold(v, f.children[1].str()) {
+				return true
+			}
+		}
+		return false
+	case filterSubstrings:
+		for _, v := range e.Values(f.children[0].str()) {
+			v = strings.ToLower(v)
+			ok := true
+			for _, part := range f.children[1].children {
+				p := strings.ToLower(part.str())
+				switch part.tag {
+				case substringInitial:
+					ok = ok && strings.HasPrefix(v, p)
+				case substringFinal:
+					ok = ok && strings.HasSuffix(v, p)
+				default:
+					ok = ok && strings.Contains(v, p)
+				}
+			}
+			if ok {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
+func person(uid, mail string) Entry {
+	e := Entry{
+		DN:         "uid=" + uid + ",ou=people,dc=example,dc=com",
+		Attributes: map[string][]string{"objectclass": {"top", "person"}, "uid": {uid}, "cn": {strings.ToUpper(uid[:1]) + uid[1:]}},
+	}
+	if mail != "" {
+		e.Attributes["mail"] = []string{mail}
+	}
+	return e
+}
+
+func dialStub(t *testing.T, s *stubServer) *Conn {
+	t.Helper()
+	conn, err := Dial(s.url(), 5*time.Second)
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestBind(t *testing.T) {
+	s := startStub(t, nil)
+	conn := dialStub(t, s)
+
+	err := conn.Bind(s.bindDN, "wrong")
+	var ldapErr *Error
+	if !errors.As(err, &ldapErr) || ldapErr.ResultCode != 49 {
+		t.Fatalf("Bind with wrong password = %v, want result code 49", err)
+	}
+	if err := conn.Bind(s.bindDN, s.password); err != nil {
+		t.Fatalf("Bind failed: %v", err)
+	}
+}
+
+func TestSearchPaged(t *testing.T) {
+	s := startStub(t, []Entry{
+		person("alice", "alice@example.com"),
+		person("bob", "bob@example.com"),
+		person("carol", ""),
+		person("dave", "dave@example.com"),
+		person("erin", "erin@example.com"),
+		person("frank", "frank@example.com"),
+	})
+	conn := dialStub(t, s)
+	if err := conn.Bind(s.bindDN, s.password); err != nil {
+		t.Fatalf("Bind failed: %v", err)
+	}
+
+	entries, err := conn.Search(SearchRequest{
+		BaseDN:   "ou=people,dc=example,dc=com",
+		Filter:   "(&(objectClass=person)(mail=*))",
+		PageSize: 2,
+	})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(entries) != 5 {
+		t.Fatalf("Search returned %d entries, want 5", len(entries))
+	}
+	if got := entries[0].Value("MAIL"); got != "alice@example.com" {
+		t.Errorf("Value(MAIL) = %q, want alice@example.com", got)
+	}
+	if s.searches != 3 {
+		t.Errorf("server received %d search requests, want 3 pages", s.searches)
+	}
+}
+
+func TestSearchFilters(t *testing.T) {
+	s := startStub(t, []Entry{
+		person("alice", "alice@example.com"),
+		person("bob", "b*b@example.com"),
+		person("carol", ""),
+	})
+	conn := dialStub(t, s)
+
+	tests := []struct {
+		filter string
+		want   int
+	}{
+		{"(uid=alice)", 1},
+		{"(cn=Al*)", 1},
+		{"(mail=*@example.com)", 2},
+		{"(!(mail=*))", 1},
+		{"(|(uid=alice)(uid=carol))", 2},
+		{`(mail=b\2ab@example.com)`, 1},
+	}
+	for _, tt := range tests {
+		entries, err := conn.Search(SearchRequest{BaseDN: "dc=example,dc=com", Filter: tt.filter})
+		if err != nil {
+			t.Errorf("Search(%s) failed: %v", tt.filter, err)
+			continue
+		}
+		if len(entries) != tt.want {
+			t.Errorf("Search(%s) returned %d entries, want %d", tt.filter, len(entries), tt.want)
+		}
+	}
+}
+
+func TestCompileFilterErrors(t *testing.T) {
+	for _, filter := range []string{"", "uid=alice", "(uid=alice", "(&(uid=a)", "(=x)", `(uid=a\2)`, "(uid:dn:=a)", "(uid=a))"} {
+		if _, err := compileFilter(filter); err == nil {
+			t.Errorf("compileFilter(%q) should fail", filter)
+		}
+	}
+}
+
+func TestEncodeInteger(t *testing.T) {
+	for _, n := range []int64{0, 1, 127, 128, 255, 256, 65535, -1, -128, -129, 1 << 40} {
+		p := newInteger(tagInteger, n)
+		got, err := p.int()
+		if err != nil || got != n {
+			t.Errorf("integer round trip of %d = %d, %v", n, got, err)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package scheduler
+
+import (
+	"log"
+	"time"
+)
+
+// Every 在后台按固定间隔执行 job，job 返回的错误只记录日志，不会中断后续执行。
+// 返回的 stop 函数用于停止该任务。
+func Every(name string, interval time.Duration, job func() error) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				runJob(name, job)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	log.Printf("定时任务 %s 已启动，执行间隔 %s", name, interval)
+	return func() { close(done) }
+}
+
+// runJob 执行一次任务，并捕获 panic 以免影响后续执行
+func runJob(name string, job func() error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("定时任务 %s 发生 panic: %v", name, r)
+		}
+	}()
+	if err := job(); err != nil {
+		log.Printf("定时任务 %s 执行失败: %v", name, err)
+	}
+}
@@ -0,0 +1,54 @@
+package totp
+
+import (
+	"testing"
+	"time"
+)
+
+// RFC 6238 附录 B 中 SHA1 的测试向量，密钥为 ASCII "12345678901234567890"
+func TestHOTPRFC6238Vectors(t *testing.T) {
+	key := []byte("12345678901234567890")
+	vectors := []struct {
+		unix int64
+		code string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+		{20000000000, "65353130"},
+	}
+
+	for _, v := range vectors {
+		got := hotp(key, TimeStep(time.Unix(v.unix, 0)), 8)
+		if got != v.code {
+			t.Errorf("hotp at %d = %s, want %s", v.unix, got, v.code)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret failed: %v", err)
+	}
+	now := time.Unix(1700000000, 0)
+
+	code, err := GenerateCode(secret, now)
+	if err != nil {
+		t.Fatalf("GenerateCode failed: %v", err)
+	}
+	if step, ok := Validate(secret, code, now, 1); !ok || step != TimeStep(now) {
+		t.Errorf("Validate current code = (%d, %v), want (%d, true)", step, ok, TimeStep(now))
+	}
+	if _, ok := Validate(secret, code, now.Add(Period*time.Second), 1); !ok {
+		t.Error("Validate should accept the previous step within skew")
+	}
+	if _, ok := Validate(secret, code, now.Add(3*Period*time.Second), 1); ok {
+		t.Error("Validate should reject a code outside skew")
+	}
+	if _, ok := Validate(secret, "12345", now, 1); ok {
+		t.Error("Validate should reject a code with wrong length")
+	}
+}